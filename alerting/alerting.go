@@ -0,0 +1,146 @@
+// alerting/alerting.go
+package alerting
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+)
+
+// State is a stage of an alert's lifecycle
+type State string
+
+// Alert lifecycle states. A target starts OK; an unhealthy result makes it
+// PENDING while its condition has not held long enough, then FIRING. A healthy
+// result resolves a firing alert, which returns to OK.
+const (
+	StateOK       State = "ok"
+	StatePending  State = "pending"
+	StateFiring   State = "firing"
+	StateResolved State = "resolved"
+)
+
+// Event is a transition of a target's alert from one state to another
+type Event struct {
+	Key    string            `json:"key"`
+	From   State             `json:"from"`
+	To     State             `json:"to"`
+	Since  time.Time         `json:"since"` // When the alert entered From
+	At     time.Time         `json:"at"`
+	Result collectors.Result `json:"result"`
+}
+
+// alert is the tracked state of one target
+type alert struct {
+	state State
+	since time.Time
+}
+
+// Machine tracks the alert state of every target and reports transitions.
+// Targets that are OK are not stored.
+type Machine struct {
+	alerts map[string]*alert
+	mu     sync.Mutex
+}
+
+// NewMachine creates a state machine with every target OK
+func NewMachine() *Machine {
+	return &Machine{alerts: make(map[string]*alert)}
+}
+
+// Evaluate advances the state of each result's target and returns the
+// results and the transitions they caused. An unhealthy result of a target
+// that is not firing stays pending until its condition has held for hold;
+// until then it is returned as healthy, with a message saying it is pending,
+// so it does not alert. A zero hold fires immediately.
+func (m *Machine) Evaluate(results []collectors.Result, hold time.Duration, now time.Time) ([]collectors.Result, []Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]collectors.Result, len(results))
+	var events []Event
+	for i, result := range results {
+		out[i] = result
+
+		key := result.Key()
+		current, tracked := m.alerts[key]
+		from, since := StateOK, now
+		if tracked {
+			from, since = current.state, current.since
+		}
+
+		transition := func(to State) {
+			events = append(events, Event{Key: key, From: from, To: to, Since: since, At: now, Result: out[i]})
+		}
+
+		if result.IsHealthy {
+			switch from {
+			case StateFiring:
+				transition(StateResolved)
+			case StatePending:
+				transition(StateOK)
+			}
+			delete(m.alerts, key)
+			continue
+		}
+
+		if from == StateFiring {
+			continue
+		}
+
+		held := now.Sub(since)
+		if hold > 0 && held < hold {
+			pending := result
+			pending.IsHealthy = true
+			pending.Severity = ""
+			pending.Message = fmt.Sprintf("Pending (%s of %s): %s", held.Round(time.Second), hold, result.Message)
+			out[i] = pending
+
+			if from == StateOK {
+				m.alerts[key] = &alert{state: StatePending, since: now}
+				transition(StatePending)
+			}
+			continue
+		}
+
+		m.alerts[key] = &alert{state: StateFiring, since: now}
+		transition(StateFiring)
+	}
+	return out, events
+}
+
+// State returns the current state of a target
+func (m *Machine) State(key string) State {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if current, ok := m.alerts[key]; ok {
+		return current.state
+	}
+	return StateOK
+}
+
+// Forget drops the state of a collector's targets without a transition, e.g.
+// when the collector is removed
+func (m *Machine) Forget(collector string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key := range m.alerts {
+		if key == collector || strings.HasPrefix(key, collector+"|") {
+			delete(m.alerts, key)
+		}
+	}
+}
+
+// Restore marks a target as firing since the given time without a
+// transition, e.g. for an alert saved before a restart
+func (m *Machine) Restore(key string, since time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.alerts[key] = &alert{state: StateFiring, since: since}
+}
@@ -0,0 +1,87 @@
+// alerting/alerting_test.go
+package alerting
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+)
+
+var start = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// step is one evaluation of a target and what it should produce
+type step struct {
+	offset  time.Duration // After start
+	healthy bool
+	state   State // State of the target afterwards
+	event   State // Transition caused, empty for none
+	alerts  bool  // Whether the returned result is unhealthy
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name  string
+		hold  time.Duration
+		steps []step
+	}{
+		{
+			name: "fires after hold and resolves",
+			hold: time.Minute,
+			steps: []step{
+				{offset: 0, healthy: true, state: StateOK},
+				{offset: 30 * time.Second, state: StatePending, event: StatePending},
+				{offset: time.Minute, state: StatePending},
+				{offset: 90 * time.Second, state: StateFiring, event: StateFiring, alerts: true},
+				{offset: 2 * time.Minute, state: StateFiring, alerts: true},
+				{offset: 150 * time.Second, healthy: true, state: StateOK, event: StateResolved},
+			},
+		},
+		{
+			name: "recovers while pending without firing",
+			hold: time.Minute,
+			steps: []step{
+				{offset: 0, state: StatePending, event: StatePending},
+				{offset: 30 * time.Second, state: StatePending},
+				{offset: 45 * time.Second, healthy: true, state: StateOK, event: StateOK},
+				{offset: 90 * time.Second, healthy: true, state: StateOK},
+			},
+		},
+		{
+			name: "fires immediately without hold",
+			steps: []step{
+				{offset: 0, state: StateFiring, event: StateFiring, alerts: true},
+				{offset: time.Minute, healthy: true, state: StateOK, event: StateResolved},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMachine()
+			for i, s := range tt.steps {
+				result := collectors.Result{Collector: "disk_space", IsHealthy: s.healthy, Severity: collectors.SeverityWarning, Message: "Disk almost full"}
+				if s.healthy {
+					result.Severity, result.Message = "", "Disk usage normal"
+				}
+
+				out, events := m.Evaluate([]collectors.Result{result}, tt.hold, start.Add(s.offset))
+				if got := m.State(result.Key()); got != s.state {
+					t.Errorf("step %d: state %s, want %s", i, got, s.state)
+				}
+				if s.event == "" && len(events) != 0 {
+					t.Errorf("step %d: got events %+v, want none", i, events)
+				}
+				if s.event != "" && (len(events) != 1 || events[0].To != s.event) {
+					t.Errorf("step %d: got events %+v, want a transition to %s", i, events, s.event)
+				}
+				if alerts := !out[0].IsHealthy; alerts != s.alerts {
+					t.Errorf("step %d: result alerts is %v, want %v", i, alerts, s.alerts)
+				}
+				if s.state == StatePending && !strings.HasPrefix(out[0].Message, "Pending (") {
+					t.Errorf("step %d: pending result message %q does not say it is pending", i, out[0].Message)
+				}
+			}
+		})
+	}
+}
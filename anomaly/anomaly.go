@@ -0,0 +1,207 @@
+// anomaly/anomaly.go
+package anomaly
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/config"
+)
+
+// baseline is the learned distribution of one metric of one target
+type baseline interface {
+	// stats returns the mean, standard deviation and number of samples
+	stats() (mean, stddev float64, samples int)
+	// add records a sample
+	add(value float64)
+}
+
+// rolling is a baseline over the last samples in a fixed size window
+type rolling struct {
+	values []float64
+	next   int
+	full   bool
+}
+
+func (r *rolling) stats() (float64, float64, int) {
+	n := r.next
+	if r.full {
+		n = len(r.values)
+	}
+	if n == 0 {
+		return 0, 0, 0
+	}
+
+	var sum float64
+	for _, v := range r.values[:n] {
+		sum += v
+	}
+	mean := sum / float64(n)
+
+	var squares float64
+	for _, v := range r.values[:n] {
+		squares += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(squares / float64(n)), n
+}
+
+func (r *rolling) add(value float64) {
+	r.values[r.next] = value
+	r.next++
+	if r.next == len(r.values) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// ewma is an exponentially weighted moving mean and variance
+type ewma struct {
+	alpha    float64
+	mean     float64
+	variance float64
+	samples  int
+}
+
+func (e *ewma) stats() (float64, float64, int) {
+	return e.mean, math.Sqrt(e.variance), e.samples
+}
+
+func (e *ewma) add(value float64) {
+	e.samples++
+	if e.samples == 1 {
+		e.mean = value
+		return
+	}
+	diff := value - e.mean
+	incr := e.alpha * diff
+	e.mean += incr
+	e.variance = (1 - e.alpha) * (e.variance + diff*incr)
+}
+
+// Detector learns baselines of configured metrics and flags values that
+// deviate from them. Baselines are kept in memory per target and metric, and
+// per weekday and hour for seasonal metrics.
+type Detector struct {
+	baselines map[string]baseline
+	mu        sync.Mutex
+}
+
+// NewDetector creates a detector with no baselines learned yet
+func NewDetector() *Detector {
+	return &Detector{baselines: make(map[string]baseline)}
+}
+
+// Apply evaluates the result's metrics against their baselines, then adds
+// the values to them. A deviating metric adds a tripped "anomaly" threshold
+// at the deviation limit and makes the result unhealthy. Baselines with fewer
+// than MinSamples samples only learn.
+func (d *Detector) Apply(result collectors.Result, anomalies []config.AnomalyConfig) collectors.Result {
+	if len(anomalies) == 0 {
+		return result
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var found []string
+	thresholds := result.Thresholds
+	for _, anomaly := range anomalies {
+		value, ok := result.Metrics[anomaly.Metric]
+		if !ok {
+			continue
+		}
+
+		key := result.Key() + "|" + anomaly.Metric
+		if anomaly.Seasonal {
+			local := result.Timestamp.Local()
+			key += fmt.Sprintf("|%s %02d", local.Weekday(), local.Hour())
+		}
+		b, exists := d.baselines[key]
+		if !exists {
+			b = newBaseline(anomaly)
+			d.baselines[key] = b
+		}
+
+		mean, stddev, samples := b.stats()
+		b.add(value)
+		if samples < anomaly.MinSamples {
+			continue
+		}
+
+		threshold, deviates := check(anomaly, value, mean, stddev)
+		if !deviates {
+			continue
+		}
+		if len(found) == 0 {
+			// Copy before appending so the collector's slice is not shared
+			thresholds = append([]collectors.Threshold{}, result.Thresholds...)
+		}
+		thresholds = append(thresholds, threshold)
+
+		when := ""
+		if anomaly.Seasonal {
+			local := result.Timestamp.Local()
+			when = fmt.Sprintf(" for %s %02d:00", local.Weekday(), local.Hour())
+		}
+		found = append(found, fmt.Sprintf("%s %.2f is unusual%s (baseline %.2f ± %.2f, %.1fσ)",
+			anomaly.Metric, value, when, mean, stddev, math.Abs(value-mean)/stddev))
+	}
+
+	if len(found) == 0 {
+		return result
+	}
+
+	result.Thresholds = thresholds
+	message := "Anomaly: " + strings.Join(found, ", ")
+	if result.IsHealthy {
+		result.IsHealthy = false
+		result.Message = message
+	} else {
+		result.Message += "; " + message
+	}
+	if severity := result.ThresholdSeverity(); !collectors.SeverityAtLeast(result.Severity, severity) {
+		result.Severity = severity
+	}
+	return result
+}
+
+// newBaseline creates an empty baseline for the configured method
+func newBaseline(anomaly config.AnomalyConfig) baseline {
+	if anomaly.Method == "ewma" {
+		return &ewma{alpha: anomaly.Alpha}
+	}
+	return &rolling{values: make([]float64, anomaly.Window)}
+}
+
+// check returns the threshold a value crosses when it deviates from the
+// baseline by more than the configured sigma in a watched direction. A flat
+// baseline (no variation) never flags, as deviations cannot be measured
+// against it.
+func check(anomaly config.AnomalyConfig, value, mean, stddev float64) (collectors.Threshold, bool) {
+	if stddev == 0 {
+		return collectors.Threshold{}, false
+	}
+
+	threshold := collectors.Threshold{
+		Type:     "anomaly",
+		Metric:   anomaly.Metric,
+		Severity: anomaly.Severity,
+	}
+
+	upper := mean + anomaly.Sigma*stddev
+	lower := mean - anomaly.Sigma*stddev
+	switch {
+	case value > upper && anomaly.Direction != "below":
+		threshold.Operator = "greater_than"
+		threshold.Value = upper
+	case value < lower && anomaly.Direction != "above":
+		threshold.Operator = "less_than"
+		threshold.Value = lower
+	default:
+		return threshold, false
+	}
+	return threshold, true
+}
@@ -0,0 +1,119 @@
+// api/api.go
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/devvspaces/simple-monit/config"
+	"github.com/devvspaces/simple-monit/monitor"
+
+	"go.uber.org/zap"
+)
+
+// maxBodyBytes bounds request bodies
+const maxBodyBytes = 1 << 20
+
+// Server is the HTTP API of a running monitor service
+type Server struct {
+	service       *monitor.MonitorService
+	token         string
+	readyFailures int
+	server        *http.Server
+	shutdown      chan struct{} // Closed on shutdown, ending streams
+	logger        *zap.Logger
+}
+
+// New creates the API server for the service. It does not listen yet.
+func New(logger *zap.Logger, cfg config.APIConfig, service *monitor.MonitorService) *Server {
+	s := &Server{
+		service:       service,
+		token:         cfg.Token,
+		readyFailures: cfg.ReadyFailures,
+		shutdown:      make(chan struct{}),
+		logger:        logger,
+	}
+
+	mux := http.NewServeMux()
+	// Probes go without the token, which probes often cannot send
+	mux.HandleFunc("GET /healthz", s.handleHealthz)
+	mux.HandleFunc("GET /readyz", s.handleReadyz)
+	mux.HandleFunc("GET /api/v1/status", s.authorized(compressed(s.handleStatus)))
+	mux.HandleFunc("GET /api/v1/alerts", s.authorized(compressed(s.handleAlerts)))
+	mux.HandleFunc("GET /api/v1/results", s.authorized(compressed(s.handleResults)))
+	mux.HandleFunc("GET /api/v1/query", s.authorized(compressed(s.handleQuery)))
+	mux.HandleFunc("GET /api/v1/availability", s.authorized(compressed(s.handleAvailability)))
+	mux.HandleFunc("GET /api/v1/incidents", s.authorized(compressed(s.handleIncidents)))
+	mux.HandleFunc("GET /api/v1/runs", s.authorized(compressed(s.handleRuns)))
+	mux.HandleFunc("GET /api/v1/stream", s.authorized(s.handleStream))
+	mux.HandleFunc("POST /api/v1/results", s.authorized(s.handlePush))
+	mux.HandleFunc("GET /api/v1/collectors", s.authorized(s.handleCollectors))
+	mux.HandleFunc("POST /api/v1/collectors/{name}/pause", s.authorized(s.handleCollectorAction(s.service.PauseCollector)))
+	mux.HandleFunc("POST /api/v1/collectors/{name}/resume", s.authorized(s.handleCollectorAction(s.service.ResumeCollector)))
+	mux.HandleFunc("POST /api/v1/collectors/{name}/run", s.authorized(s.handleCollectorAction(s.service.TriggerCollector)))
+
+	s.server = &http.Server{
+		Addr:              cfg.Listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	s.server.RegisterOnShutdown(func() { close(s.shutdown) })
+	return s
+}
+
+// Start listens on the configured address and serves in the background
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		s.logger.Error("Failed to listen for API requests", zap.String("listen", s.server.Addr), zap.Error(err))
+		return err
+	}
+	if s.token == "" {
+		s.logger.Warn("API has no token; anyone who can reach it can use it", zap.String("listen", s.server.Addr))
+	}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("API listener failed", zap.String("listen", s.server.Addr), zap.Error(err))
+		}
+	}()
+	s.logger.Info("Serving API", zap.String("listen", listener.Addr().String()))
+	return nil
+}
+
+// Shutdown stops accepting requests and waits for running ones to finish
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// authorized requires the configured bearer token, when one is set
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+				writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,34 @@
+// api/collectors.go
+package api
+
+import (
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// handleCollectors lists the registered collectors and whether they are
+// running and paused
+func (s *Server) handleCollectors(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.service.Collectors())
+}
+
+// handleCollectorAction applies a runtime action (pause, resume or run) to
+// the collector named in the path and answers with its new state
+func (s *Server) handleCollectorAction(action func(name string) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		if !s.service.HasCollector(name) {
+			writeError(w, http.StatusNotFound, "unknown collector "+name)
+			return
+		}
+		if err := action(name); err != nil {
+			s.logger.Warn("Collector action refused", zap.String("collector", name), zap.String("path", r.URL.Path), zap.Error(err))
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+
+		state, _ := s.service.CollectorState(name)
+		writeJSON(w, http.StatusOK, state)
+	}
+}
@@ -0,0 +1,127 @@
+// api/encoding.go
+package api
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/devvspaces/simple-monit/collectors"
+)
+
+// compressed gzips the responses of next for clients that accept it, so
+// dashboards over slow links pull a fraction of the bytes
+func compressed(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			next(w, r)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		writer := gzip.NewWriter(w)
+		defer writer.Close()
+		next(gzipResponseWriter{ResponseWriter: w, writer: writer}, r)
+	}
+}
+
+// gzipResponseWriter writes the body of a response through gzip
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	writer *gzip.Writer
+}
+
+func (w gzipResponseWriter) Write(b []byte) (int, error) {
+	return w.writer.Write(b)
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip, named
+// or through *, with a non-zero quality
+func acceptsGzip(header string) bool {
+	for _, coding := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(coding, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "gzip" && name != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if quality, err := strconv.ParseFloat(q, 64); err == nil && quality == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// resultFields are the JSON fields of a result, which fields may select
+var resultFields = func() map[string]bool {
+	fields := make(map[string]bool)
+	t := reflect.TypeOf(collectors.Result{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		fields[name] = true
+	}
+	return fields
+}()
+
+// Fields whose entries may be selected on their own, e.g. metrics.used_percent
+var mapFields = map[string]bool{"metrics": true, "metadata": true, "tags": true}
+
+// parseFields parses the fields query parameter, a comma-separated list of
+// result fields to keep. It returns nil, meaning every field, when empty.
+func parseFields(raw string) ([]string, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var fields []string
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		name, entry, nested := strings.Cut(field, ".")
+		if !resultFields[name] || (nested && (!mapFields[name] || entry == "")) {
+			return nil, fmt.Errorf("invalid field %q", field)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// selectFields returns the results with only the given fields, or as they
+// are when fields is nil. Selecting metrics.<name> keeps only that metric.
+func selectFields(results []collectors.Result, fields []string) interface{} {
+	if fields == nil {
+		return results
+	}
+	selected := make([]map[string]interface{}, len(results))
+	for i, result := range results {
+		var all map[string]interface{}
+		data, _ := json.Marshal(result)
+		json.Unmarshal(data, &all)
+
+		kept := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			name, entry, nested := strings.Cut(field, ".")
+			value, ok := all[name]
+			if !ok {
+				continue
+			}
+			if !nested {
+				kept[name] = value
+				continue
+			}
+			entries, _ := value.(map[string]interface{})
+			if v, ok := entries[entry]; ok {
+				if _, ok := kept[name].(map[string]interface{}); !ok {
+					kept[name] = make(map[string]interface{})
+				}
+				kept[name].(map[string]interface{})[entry] = v
+			}
+		}
+		selected[i] = kept
+	}
+	return selected
+}
@@ -0,0 +1,31 @@
+// api/health.go
+package api
+
+import "net/http"
+
+// healthResponse is the body of /healthz and /readyz
+type healthResponse struct {
+	Status   string   `json:"status"` // "ok" or "unavailable"
+	Problems []string `json:"problems,omitempty"`
+}
+
+// handleHealthz reports whether the collector tasks are alive, for liveness
+// probes that restart a wedged monitor
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, s.service.Liveness())
+}
+
+// handleReadyz reports whether the collectors are alive and succeeding, for
+// readiness probes
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, s.service.Readiness(s.readyFailures))
+}
+
+// writeHealth answers 200 without problems and 503 with them
+func writeHealth(w http.ResponseWriter, problems []string) {
+	if len(problems) > 0 {
+		writeJSON(w, http.StatusServiceUnavailable, healthResponse{Status: "unavailable", Problems: problems})
+		return
+	}
+	writeJSON(w, http.StatusOK, healthResponse{Status: "ok"})
+}
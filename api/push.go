@@ -0,0 +1,140 @@
+// api/push.go
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"go.uber.org/zap"
+)
+
+// simulatedPrefix starts the message of simulated results
+const simulatedPrefix = "[SIMULATED] "
+
+// pushedResult is a result as pushed by an external check. Thresholds are
+// not accepted; configure them on the collector name instead. Simulated
+// results, sent by the simulate command, may use a built-in collector's name.
+type pushedResult struct {
+	Simulated bool                   `json:"simulated"`
+	Collector string                 `json:"collector"`
+	IsHealthy *bool                  `json:"is_healthy"`
+	Message   string                 `json:"message"`
+	Severity  string                 `json:"severity"`
+	Timestamp time.Time              `json:"timestamp"`
+	Metrics   map[string]float64     `json:"metrics"`
+	Metadata  map[string]interface{} `json:"metadata"`
+}
+
+// handlePush accepts results from external scripts and cron jobs, as one
+// result object or an array of them, and runs them through the same pipeline
+// as collected results
+func (s *Server) handlePush(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+		return
+	}
+
+	results, err := s.parsePush(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.service.Inject(context.WithoutCancel(r.Context()), results); err != nil {
+		// The results were processed; only a notification failed
+		s.logger.Error("Failed to notify pushed results", zap.Error(err))
+	}
+
+	s.logger.Info("Accepted pushed results", zap.Int("results", len(results)), zap.Int("simulated", simulated(results)), zap.String("remote", r.RemoteAddr))
+	writeJSON(w, http.StatusAccepted, map[string]int{"accepted": len(results)})
+}
+
+// parsePush decodes and checks pushed results. Results without a timestamp
+// are stamped now; collector names of built-in collectors are refused, so a
+// push cannot resolve or mask their alerts. Simulated results may use them:
+// they are always marked, and the simulated metadata makes them targets of
+// their own, so they cannot touch the real targets' alerts either.
+func (s *Server) parsePush(body []byte) ([]collectors.Result, error) {
+	var pushed []pushedResult
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &pushed); err != nil {
+			return nil, fmt.Errorf("invalid results: %w", err)
+		}
+	} else {
+		var one pushedResult
+		if err := json.Unmarshal(trimmed, &one); err != nil {
+			return nil, fmt.Errorf("invalid result: %w", err)
+		}
+		pushed = append(pushed, one)
+	}
+	if len(pushed) == 0 {
+		return nil, fmt.Errorf("no results")
+	}
+
+	now := time.Now()
+	results := make([]collectors.Result, len(pushed))
+	for i, p := range pushed {
+		switch {
+		case p.Collector == "":
+			return nil, fmt.Errorf("result %d has no collector", i)
+		case s.service.HasCollector(p.Collector) && !p.Simulated:
+			return nil, fmt.Errorf("result %d: %s is a built-in collector", i, p.Collector)
+		case p.IsHealthy == nil:
+			return nil, fmt.Errorf("result %d has no is_healthy flag", i)
+		case p.Severity != "" && !collectors.ValidSeverity(p.Severity):
+			return nil, fmt.Errorf("result %d: severity must be info, warning or critical", i)
+		}
+
+		result := collectors.Result{
+			IsHealthy: *p.IsHealthy,
+			Collector: p.Collector,
+			Timestamp: p.Timestamp,
+			Message:   p.Message,
+			Metrics:   p.Metrics,
+			Metadata:  p.Metadata,
+		}
+		if result.Timestamp.IsZero() {
+			result.Timestamp = now
+		}
+		if result.Metrics == nil {
+			result.Metrics = map[string]float64{}
+		}
+		if p.Simulated {
+			if result.Metadata == nil {
+				result.Metadata = map[string]interface{}{}
+			}
+			result.Metadata["simulated"] = true
+			if !strings.HasPrefix(result.Message, simulatedPrefix) {
+				result.Message = simulatedPrefix + result.Message
+			}
+		}
+		if !result.IsHealthy {
+			result.Severity = p.Severity
+			if result.Message == "" {
+				result.Message = fmt.Sprintf("%s reported unhealthy", result.Collector)
+			}
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+// simulated counts the simulated results
+func simulated(results []collectors.Result) int {
+	n := 0
+	for _, result := range results {
+		if result.Metadata["simulated"] == true {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,35 @@
+// api/query.go
+package api
+
+import (
+	"net/http"
+
+	"github.com/devvspaces/simple-monit/query"
+)
+
+// handleQuery evaluates the query parameter over the stored results from
+// since (default 1h) up to until (default now), serving a value per target
+func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	if params.Get("query") == "" {
+		writeError(w, http.StatusBadRequest, "missing query")
+		return
+	}
+	q, err := query.Parse(params.Get("query"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid query: "+err.Error())
+		return
+	}
+	since, until, err := parseRange(params, defaultResultsWindow)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	series, err := s.service.Query(q, since, until)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, series)
+}
@@ -0,0 +1,296 @@
+// api/status.go
+package api
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/config"
+	"github.com/devvspaces/simple-monit/monitor"
+	"github.com/devvspaces/simple-monit/storage"
+)
+
+// How far back /api/v1/results, /api/v1/incidents and /api/v1/runs look
+// without since
+const (
+	defaultResultsWindow   = time.Hour
+	defaultIncidentsWindow = 30 * 24 * time.Hour
+	defaultRunsWindow      = 24 * time.Hour
+)
+
+// Results per page when a cursor is given without limit, and the most a
+// page may hold
+const (
+	defaultPageLimit = 500
+	maxPageLimit     = 10000
+)
+
+// statusResponse is the body of /api/v1/status
+type statusResponse struct {
+	Hostname     string                   `json:"hostname"`
+	Healthy      bool                     `json:"healthy"`
+	ActiveAlerts int                      `json:"active_alerts"`
+	Collectors   []monitor.CollectorState `json:"collectors"`
+	Results      interface{}              `json:"results"`
+	NextCursor   string                   `json:"next_cursor,omitempty"` // Of the next page of results
+}
+
+// resultsPage is the body of /api/v1/results when paginated
+type resultsPage struct {
+	Results    interface{} `json:"results"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+// handleStatus serves the latest result of every target, the collectors' run
+// statistics and whether anything is alerting. With limit or cursor, the
+// results are paginated by target key; fields selects their fields.
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	fields, err := parseFields(query.Get("fields"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	cursor, limit, err := parsePage(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, next := s.service.LatestResults(), ""
+	if limit > 0 {
+		after, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid cursor")
+			return
+		}
+		// Results are sorted by key
+		start := sort.Search(len(results), func(i int) bool { return cursor == "" || results[i].Key() > string(after) })
+		results = results[start:]
+		if len(results) > limit {
+			results = results[:limit]
+			next = base64.RawURLEncoding.EncodeToString([]byte(results[limit-1].Key()))
+		}
+	}
+
+	alerts := len(s.service.ActiveAlerts())
+	writeJSON(w, http.StatusOK, statusResponse{
+		Hostname:     s.service.Hostname(),
+		Healthy:      alerts == 0,
+		ActiveAlerts: alerts,
+		Collectors:   s.service.Collectors(),
+		Results:      selectFields(results, fields),
+		NextCursor:   next,
+	})
+}
+
+// handleAlerts serves the targets currently alerting, oldest first
+func (s *Server) handleAlerts(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.service.ActiveAlerts())
+}
+
+// handleResults serves stored results, optionally of one collector, from
+// since (a duration back from now or an RFC 3339 time, default 1h) up to
+// until (default now). With limit or cursor, they are served a page at a
+// time with the cursor of the next one; fields selects their fields.
+func (s *Server) handleResults(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	since, until, err := parseRange(query, defaultResultsWindow)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	fields, err := parseFields(query.Get("fields"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	cursor, limit, err := parsePage(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if limit > 0 {
+		results, next, err := s.service.HistoryPage(query.Get("collector"), since, until, cursor, limit)
+		if errors.Is(err, storage.ErrInvalidCursor) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if results == nil {
+			results = []collectors.Result{}
+		}
+		writeJSON(w, http.StatusOK, resultsPage{Results: selectFields(results, fields), NextCursor: next})
+		return
+	}
+
+	results, err := s.service.History(query.Get("collector"), since, until)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if results == nil {
+		results = []collectors.Result{}
+	}
+	writeJSON(w, http.StatusOK, selectFields(results, fields))
+}
+
+// handleAvailability serves the availability of every current target,
+// optionally of one collector, over the comma-separated windows (default:
+// history.availability_windows)
+func (s *Server) handleAvailability(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	windows := s.service.AvailabilityWindows()
+	if raw := query.Get("windows"); raw != "" {
+		windows = nil
+		for _, name := range strings.Split(raw, ",") {
+			d, err := config.ParseWindow(strings.TrimSpace(name))
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			windows = append(windows, monitor.Window{Name: strings.TrimSpace(name), Duration: d})
+		}
+	}
+
+	availability, err := s.service.Availability(query.Get("collector"), windows)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if availability == nil {
+		availability = []monitor.Availability{}
+	}
+	writeJSON(w, http.StatusOK, availability)
+}
+
+// handleIncidents serves the timeline of alerts opened, acknowledged and
+// resolved, optionally of one collector, from since (default 30 days ago) up
+// to until (default now), as JSON or, with format=csv, as CSV
+func (s *Server) handleIncidents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	since, until, err := parseRange(query, defaultIncidentsWindow)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	format := query.Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		writeError(w, http.StatusBadRequest, "invalid format: expected json or csv")
+		return
+	}
+
+	events, err := s.service.Incidents(query.Get("collector"), since, until)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="incidents.csv"`)
+		monitor.WriteIncidentsCSV(w, events)
+		return
+	}
+	if events == nil {
+		events = []storage.IncidentEvent{}
+	}
+	writeJSON(w, http.StatusOK, events)
+}
+
+// handleRuns serves the audit of collector runs, optionally of one
+// collector, from since (default a day ago) up to until (default now), as
+// JSON or, with format=csv, as CSV
+func (s *Server) handleRuns(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	since, until, err := parseRange(query, defaultRunsWindow)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	format := query.Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		writeError(w, http.StatusBadRequest, "invalid format: expected json or csv")
+		return
+	}
+
+	runs, err := s.service.Runs(query.Get("collector"), since, until)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="runs.csv"`)
+		monitor.WriteRunsCSV(w, runs)
+		return
+	}
+	if runs == nil {
+		runs = []storage.Run{}
+	}
+	writeJSON(w, http.StatusOK, runs)
+}
+
+// parseRange parses the since and until query parameters; since defaults to
+// window back from now and until to zero, meaning now
+func parseRange(query url.Values, window time.Duration) (time.Time, time.Time, error) {
+	now := time.Now()
+
+	since := now.Add(-window)
+	if raw := query.Get("since"); raw != "" {
+		parsed, err := parseTime(raw, now)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid since: %w", err)
+		}
+		since = parsed
+	}
+	var until time.Time
+	if raw := query.Get("until"); raw != "" {
+		parsed, err := parseTime(raw, now)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid until: %w", err)
+		}
+		until = parsed
+	}
+	return since, until, nil
+}
+
+// parsePage parses the cursor and limit query parameters. A zero limit means
+// the response is not paginated; a cursor alone gets the default limit.
+func parsePage(query url.Values) (string, int, error) {
+	cursor, limit := query.Get("cursor"), 0
+	if raw := query.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxPageLimit {
+			return "", 0, fmt.Errorf("invalid limit: expected 1 to %d", maxPageLimit)
+		}
+		limit = parsed
+	} else if cursor != "" {
+		limit = defaultPageLimit
+	}
+	return cursor, limit, nil
+}
+
+// parseTime parses a duration back from now (e.g. 24h) or an RFC 3339 time
+func parseTime(raw string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(raw); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected a duration such as 24h or an RFC 3339 time")
+	}
+	return t, nil
+}
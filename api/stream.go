@@ -0,0 +1,67 @@
+// api/stream.go
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// streamKeepAlive is how often an idle stream gets a comment, so proxies
+// keep the connection open
+const streamKeepAlive = 30 * time.Second
+
+// handleStream streams every result and alert transition as Server-Sent
+// Events, optionally only those of one collector
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+	collector := r.URL.Query().Get("collector")
+
+	events, unsubscribe := s.service.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	s.logger.Info("Stream client connected", zap.String("remote", r.RemoteAddr), zap.String("collector", collector))
+	defer s.logger.Info("Stream client disconnected", zap.String("remote", r.RemoteAddr))
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.shutdown:
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		case event := <-events:
+			if collector != "" && event.Collector() != collector {
+				continue
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				s.logger.Error("Failed to encode stream event", zap.Error(err))
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,12 @@
+// assets.go
+package main
+
+import (
+	_ "embed"
+)
+
+// defaultConfig is the example configuration compiled into the binary, so a
+// copied binary can bootstrap itself on a host with no other files
+//
+//go:embed config.yaml.example
+var defaultConfig []byte
@@ -0,0 +1,76 @@
+// availability.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/devvspaces/simple-monit/monitor"
+)
+
+// runAvailability implements the "availability" subcommand, a report of how
+// much of each window every target was up, read from the service's API
+func runAvailability(args []string) int {
+	fs := flag.NewFlagSet("availability", flag.ContinueOnError)
+	configPath := fs.String("config", "config.yaml", "Path to configuration file")
+	apiURL := fs.String("api", "", "Base URL of the service's API (default: from api.listen in the config)")
+	token := fs.String("token", "", "API token (default: api.token from the config)")
+	collector := fs.String("collector", "", "Only report the targets of this collector")
+	windows := fs.String("windows", "", "Comma-separated windows, e.g. 24h,7d,30d (default: history.availability_windows)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	base, bearer, err := apiEndpoint(*configPath, *apiURL, *token)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	query := url.Values{}
+	if *collector != "" {
+		query.Set("collector", *collector)
+	}
+	if *windows != "" {
+		query.Set("windows", *windows)
+	}
+
+	var targets []monitor.Availability
+	if err := apiRequest(http.MethodGet, base+"/api/v1/availability?"+query.Encode(), bearer, &targets); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get availability: %v\n", err)
+		return 1
+	}
+	if len(targets) == 0 {
+		fmt.Println("No targets")
+		return 0
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprint(w, "TARGET\tSTATUS")
+	for _, window := range targets[0].Windows {
+		fmt.Fprintf(w, "\t%s", window.Window)
+	}
+	fmt.Fprintln(w)
+	for _, target := range targets {
+		status := "up"
+		if target.Down {
+			status = "down"
+		}
+		fmt.Fprintf(w, "%s\t%s", target.Key, status)
+		for _, window := range target.Windows {
+			fmt.Fprintf(w, "\t%.3f%% (%d down, %s)", window.Percent, window.Downtimes, formatDowntime(window.DowntimeSeconds))
+		}
+		fmt.Fprintln(w)
+	}
+	w.Flush()
+	return 0
+}
+
+// formatDowntime formats a downtime in seconds to the second
+func formatDowntime(seconds float64) string {
+	return (time.Duration(seconds) * time.Second).String()
+}
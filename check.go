@@ -0,0 +1,150 @@
+// check.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/devvspaces/simple-monit/monitor"
+
+	"go.uber.org/zap"
+)
+
+// runCheck implements the "check" subcommand. It runs collectors once and
+// prints their results, for cron jobs, CI smoke checks and trying out
+// thresholds. It exits 0 when every result is healthy, 1 when one is not, 2
+// on bad usage and 3 when the check could not run. With -format nagios it
+// behaves as a Nagios plugin instead, exiting with the plugin state, and
+// UNKNOWN on bad usage.
+func runCheck(args []string) int {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	configPath := fs.String("config", "config.yaml", "Path to configuration file")
+	configDir := fs.String("config-dir", "", "Directory of YAML fragments merged into the configuration")
+	profile := fs.String("profile", os.Getenv(envPrefix+"PROFILE"), "Profile whose overlay, e.g. config.prod.yaml, is merged over the configuration file; also "+envPrefix+"PROFILE")
+	format := fs.String("format", "table", "Output format: table, json or nagios")
+	verbose := fs.Bool("v", false, "Log what the collectors do to standard error")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: server-monitor check [flags] [collector...]")
+		fs.PrintDefaults()
+	}
+	// A Nagios plugin reports bad usage as UNKNOWN too, without the flags
+	// having parsed
+	nagios := nagiosRequested(fs, args)
+	if nagios {
+		fs.SetOutput(io.Discard)
+	}
+	if err := fs.Parse(args); err != nil {
+		if nagios {
+			fmt.Printf("UNKNOWN - %s\n", nagiosSanitize(err.Error()))
+			return nagiosUnknown
+		}
+		return 2
+	}
+	if *format != "table" && *format != "json" && *format != "nagios" {
+		if nagios {
+			fmt.Printf("UNKNOWN - unknown format %q: expected table, json or nagios\n", *format)
+			return nagiosUnknown
+		}
+		fmt.Fprintf(os.Stderr, "Unknown format %q: expected table, json or nagios\n", *format)
+		return 2
+	}
+
+	// Nagios reads a plugin's standard output, so failures go there as
+	// UNKNOWN; exit code 3 is UNKNOWN too
+	fail := func(message string) int {
+		if *format == "nagios" {
+			fmt.Printf("UNKNOWN - %s\n", nagiosSanitize(message))
+		} else {
+			fmt.Fprintln(os.Stderr, message)
+		}
+		return 3
+	}
+
+	logger := zap.NewNop()
+	if *verbose {
+		var err error
+		if logger, err = zap.NewDevelopment(); err != nil {
+			return fail(fmt.Sprintf("can't initialize zap logger: %v", err))
+		}
+		defer logger.Sync()
+	}
+
+	cfg, err := loadConfigFiles(logger.Named("config"), *configPath, *profile, *configDir)
+	if err != nil {
+		return fail(fmt.Sprintf("Failed to load configuration: %v", err))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	results, err := monitor.New(cfg, monitor.WithLogger(logger.Named("monitor"))).Check(ctx, fs.Args())
+	if err != nil {
+		return fail(fmt.Sprintf("Check failed: %v", err))
+	}
+
+	if *format == "nagios" {
+		return writeNagios(os.Stdout, results)
+	}
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(results)
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "TARGET\tSTATUS\tSEVERITY\tMESSAGE")
+		for _, result := range results {
+			status, severity := "ok", "-"
+			if !result.IsHealthy {
+				status, severity = "unhealthy", result.EffectiveSeverity()
+			}
+			message := result.Message
+			if message == "" {
+				message = formatMetrics(result.Metrics)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.Key(), status, severity, message)
+		}
+		w.Flush()
+	}
+
+	for _, result := range results {
+		if !result.IsHealthy {
+			return 1
+		}
+	}
+	return 0
+}
+
+// nagiosRequested reports whether the arguments ask for -format nagios
+// among the flags of fs, before they are parsed
+func nagiosRequested(fs *flag.FlagSet, args []string) bool {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || !strings.HasPrefix(arg, "-") {
+			return false
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !hasValue {
+			// Skip the value of a non-boolean flag
+			f := fs.Lookup(name)
+			if f == nil {
+				continue
+			}
+			if boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && boolFlag.IsBoolFlag() {
+				continue
+			}
+			if i++; i < len(args) {
+				value = args[i]
+			}
+		}
+		if name == "format" && value == "nagios" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+// collector.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/devvspaces/simple-monit/config"
+	"github.com/devvspaces/simple-monit/monitor"
+
+	"go.uber.org/zap"
+)
+
+// runCollectorCommand implements the "collector" subcommand, which pauses,
+// resumes or runs collectors of the running service through its HTTP API
+func runCollectorCommand(args []string) int {
+	usage := "usage: server-monitor collector <list|pause|resume|run> [name] [flags]"
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("collector "+action, flag.ContinueOnError)
+	configPath := fs.String("config", "config.yaml", "Path to configuration file")
+	apiURL := fs.String("api", "", "Base URL of the service's API (default: from api.listen in the config)")
+	token := fs.String("token", "", "API token (default: api.token from the config)")
+
+	var name string
+	switch action {
+	case "list":
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+	case "pause", "resume", "run":
+		if len(args) < 2 || strings.HasPrefix(args[1], "-") {
+			fmt.Fprintln(os.Stderr, "usage: server-monitor collector "+action+" <name> [flags]")
+			return 2
+		}
+		name = args[1]
+		if err := fs.Parse(args[2:]); err != nil {
+			return 2
+		}
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	base, bearer, err := apiEndpoint(*configPath, *apiURL, *token)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	if action == "list" {
+		var states []monitor.CollectorState
+		if err := apiRequest(http.MethodGet, base+"/api/v1/collectors", bearer, &states); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to list collectors: %v\n", err)
+			return 1
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "COLLECTOR\tSTATE")
+		for _, state := range states {
+			fmt.Fprintf(w, "%s\t%s\n", state.Name, describeState(state))
+		}
+		w.Flush()
+		return 0
+	}
+
+	var state monitor.CollectorState
+	if err := apiRequest(http.MethodPost, base+"/api/v1/collectors/"+url.PathEscape(name)+"/"+action, bearer, &state); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to %s collector %s: %v\n", action, name, err)
+		return 1
+	}
+	if action == "run" {
+		fmt.Printf("Triggered a run of collector %s\n", name)
+		return 0
+	}
+	fmt.Printf("Collector %s is %s\n", name, describeState(state))
+	return 0
+}
+
+// apiEndpoint returns the API base URL and token, from the flags or else the
+// configuration file
+func apiEndpoint(configPath, apiURL, token string) (string, string, error) {
+	if apiURL != "" {
+		return strings.TrimSuffix(apiURL, "/"), token, nil
+	}
+
+	cfg, err := config.LoadConfig(zap.NewNop(), configPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return configEndpoint(cfg, configPath, token)
+}
+
+// configEndpoint returns the base URL of the API a loaded configuration
+// enables, and token or else the configured one
+func configEndpoint(cfg *config.Config, configPath, token string) (string, string, error) {
+	if !cfg.API.Enabled {
+		return "", "", fmt.Errorf("the API is not enabled in %s; set api.enabled or pass -api", configPath)
+	}
+	if token == "" {
+		token = cfg.API.Token
+	}
+	return "http://" + cfg.API.Listen, token, nil
+}
+
+// apiRequest sends a request to the API and decodes the JSON response into out
+func apiRequest(method, target, token string, out interface{}) error {
+	return apiSend(method, target, token, nil, out)
+}
+
+// apiSend sends a request with body, when not nil, encoded as JSON, and
+// decodes the JSON response into out
+func apiSend(method, target, token string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, target, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error != "" {
+			return fmt.Errorf("%s", apiErr.Error)
+		}
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return json.Unmarshal(data, out)
+}
+
+// describeState describes a collector's runtime state in a word
+func describeState(state monitor.CollectorState) string {
+	switch {
+	case !state.Running:
+		return "disabled"
+	case state.Paused:
+		return "paused"
+	}
+	return "running"
+}
@@ -3,16 +3,45 @@ package collectors
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 )
 
+// Severity levels used by thresholds
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Alert lifecycle transitions a result can carry to the notifiers
+const (
+	TransitionFiring   = "firing"   // The result's alert started firing
+	TransitionResolved = "resolved" // The result recovered a firing alert
+)
+
+// severityRank orders severities from least to most severe
+var severityRank = map[string]int{
+	SeverityInfo:     1,
+	SeverityWarning:  2,
+	SeverityCritical: 3,
+}
+
 // Threshold represents a monitoring threshold
 type Threshold struct {
-	Type     string  // "absolute" or "percentage"
-	Metric   string  // Name of the metric
-	Operator string  // "less_than", "greater_than", "equals"
-	Value    float64 // Threshold value
-	Severity string  // "warning", "critical", etc.
+	Type     string   // "absolute" or "percentage"
+	Metric   string   // Name of the metric
+	Operator string   // "less_than", "greater_than", "equals", "between", "outside_range"
+	Value    float64  // Threshold value; the lower bound of a range
+	Max      float64  // Upper bound of a range
+	Severity string   // "warning", "critical", etc.
+	Clear    *float64 // Level a tripped threshold must cross back over to clear; nil clears at Value
+	Held     bool     // Set while the metric is back past Value but has not reached Clear
+	Message  string   // Describes the problem when tripped; defaults to the comparison
 }
 
 // Result represents the result of a collection operation
@@ -21,9 +50,199 @@ type Result struct {
 	Collector  string                 `json:"collector"`
 	Timestamp  time.Time              `json:"timestamp"`
 	Message    string                 `json:"message"`
+	Severity   string                 `json:"severity,omitempty"` // Set by the collector on unhealthy results
 	Metrics    map[string]float64     `json:"metrics"`
 	Thresholds []Threshold            `json:"thresholds,omitempty"`
 	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	Tags       map[string]string      `json:"tags,omitempty"`       // Labels of where the result comes from, e.g. hostname; not part of the key
+	Transition string                 `json:"transition,omitempty"` // Lifecycle transition the result caused, if any; a resolved result's Severity is the one notified for its alert
+}
+
+// Key returns an identifier for the monitored target of the result, built from
+// the collector name and its metadata, so results for the same target match across runs
+func (r Result) Key() string {
+	keys := make([]string, 0, len(r.Metadata))
+	for k := range r.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var builder strings.Builder
+	builder.WriteString(r.Collector)
+	for _, k := range keys {
+		builder.WriteString(fmt.Sprintf("|%s=%v", k, r.Metadata[k]))
+	}
+	return builder.String()
+}
+
+// Fingerprint returns a short, stable identifier of the result's alert: a
+// hash of its host, collector and target metadata. Every result of a target
+// has the same fingerprint whichever of its thresholds trips, so systems
+// receiving notifications can match the repeats, escalations and recovery of
+// one alert, also across monitors on several hosts.
+func (r Result) Fingerprint() string {
+	sum := sha256.Sum256([]byte(r.Tags["hostname"] + "\x00" + r.Key()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Labels returns the result's identifying labels: the collector name, its
+// effective severity when unhealthy, its tags and every metadata value as a
+// string. Metadata wins over a tag of the same name.
+func (r Result) Labels() map[string]string {
+	labels := make(map[string]string, len(r.Tags)+len(r.Metadata)+2)
+	for k, v := range r.Tags {
+		labels[k] = v
+	}
+	for k, v := range r.Metadata {
+		labels[k] = fmt.Sprint(v)
+	}
+	labels["collector"] = r.Collector
+	if severity := r.EffectiveSeverity(); severity != "" {
+		labels["severity"] = severity
+	}
+	return labels
+}
+
+// EffectiveSeverity returns the severity of an unhealthy result: the one its
+// collector set, or else the one derived from its thresholds. Healthy results
+// have no severity, except a recovery's, which keeps the one of the alert it
+// resolves.
+func (r Result) EffectiveSeverity() string {
+	if r.IsHealthy {
+		if r.Transition == TransitionResolved {
+			return r.Severity
+		}
+		return ""
+	}
+	if r.Severity != "" {
+		return r.Severity
+	}
+	return r.ThresholdSeverity()
+}
+
+// ThresholdSeverity returns the most severe threshold tripped, or held, by
+// the result's metrics. Collectors use it to set Severity. Unhealthy results
+// that trip no declared threshold (e.g. connection failures) are critical;
+// healthy results have no severity.
+func (r Result) ThresholdSeverity() string {
+	if r.IsHealthy {
+		return ""
+	}
+
+	severity := ""
+	for _, threshold := range r.Thresholds {
+		if !threshold.Tripped(r.Metrics) && !threshold.Held {
+			continue
+		}
+		if severityRank[threshold.Severity] > severityRank[severity] {
+			severity = threshold.Severity
+		}
+	}
+
+	if severity == "" {
+		return SeverityCritical
+	}
+	return severity
+}
+
+// ValidSeverity reports whether severity is one of the known levels
+func ValidSeverity(severity string) bool {
+	_, ok := severityRank[severity]
+	return ok
+}
+
+// SeverityAtLeast reports whether severity is at least as severe as min. An
+// empty min is met by every severity.
+func SeverityAtLeast(severity, min string) bool {
+	return severityRank[severity] >= severityRank[min]
+}
+
+// Tripped reports whether the threshold condition holds for the given metrics
+func (t Threshold) Tripped(metrics map[string]float64) bool {
+	value, ok := metrics[t.Metric]
+	if !ok {
+		return false
+	}
+
+	switch t.Operator {
+	case "less_than":
+		return value < t.Value
+	case "greater_than":
+		return value > t.Value
+	case "equals":
+		return value == t.Value
+	case "between":
+		return value >= t.Value && value <= t.Max
+	case "outside_range":
+		return value < t.Value || value > t.Max
+	}
+	return false
+}
+
+// Cleared reports whether a tripped threshold has cleared: the metric is back
+// over the clear level when one is set, or no longer trips the threshold
+func (t Threshold) Cleared(metrics map[string]float64) bool {
+	value, ok := metrics[t.Metric]
+	if !ok {
+		return true
+	}
+	if t.Clear == nil {
+		return !t.Tripped(metrics)
+	}
+
+	switch t.Operator {
+	case "less_than":
+		return value >= *t.Clear
+	case "greater_than":
+		return value <= *t.Clear
+	}
+	return !t.Tripped(metrics)
+}
+
+// ApplyHysteresis keeps a target unhealthy while a threshold that tripped in
+// its previous, unhealthy result has not yet cleared, so a check hovering at
+// the trigger level does not flap. Held thresholds are marked so severity and
+// the next evaluation account for them.
+func ApplyHysteresis(previous, current Result) Result {
+	if previous.IsHealthy {
+		return current
+	}
+
+	var held []string
+	thresholds := make([]Threshold, len(current.Thresholds))
+	copy(thresholds, current.Thresholds)
+
+	for i, threshold := range thresholds {
+		if threshold.Clear == nil || threshold.Tripped(current.Metrics) {
+			continue
+		}
+
+		for _, prev := range previous.Thresholds {
+			if prev.Metric != threshold.Metric || prev.Operator != threshold.Operator {
+				continue
+			}
+			if (prev.Tripped(previous.Metrics) || prev.Held) && !threshold.Cleared(current.Metrics) {
+				thresholds[i].Held = true
+				held = append(held, fmt.Sprintf("%s %.2f (clears at %.2f)",
+					threshold.Metric, current.Metrics[threshold.Metric], *threshold.Clear))
+			}
+			break
+		}
+	}
+
+	if len(held) == 0 {
+		return current
+	}
+
+	current.Thresholds = thresholds
+	if current.IsHealthy {
+		current.IsHealthy = false
+		current.Message = "Not yet cleared: " + strings.Join(held, ", ")
+	}
+	if severity := current.ThresholdSeverity(); !SeverityAtLeast(current.Severity, severity) {
+		current.Severity = severity
+	}
+	return current
 }
 
 // Collector defines the interface that all collectors must implement
@@ -40,3 +259,10 @@ type Collector interface {
 	// Cleanup performs any necessary cleanup operations
 	Cleanup() error
 }
+
+// Validator is implemented by collectors whose Init does more than read its
+// settings, e.g. starts a program. Validate checks the settings the same way
+// without doing so, for the validate command.
+type Validator interface {
+	Validate(settings map[string]interface{}) error
+}
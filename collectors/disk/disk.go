@@ -5,26 +5,36 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
-	"server-monitor/collectors"
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/remote"
 
 	"go.uber.org/zap"
-	"golang.org/x/sys/unix"
 )
 
 // DiskCollector implements the Collector interface for disk space monitoring
 type DiskCollector struct {
 	paths         []PathConfig
+	targets       []remote.Target     // Machines to check: local and/or remote over SSH
+	history       map[string][]sample // Free space readings per host and path, for forecasts
+	skipOverlay   bool                // Skip local paths on overlay filesystems
 	collectorName string
 	logger        *zap.Logger
 }
 
 // PathConfig represents the configuration for a single disk path to monitor
 type PathConfig struct {
-	Path             string  `json:"path"`
-	ThresholdGB      float64 `json:"threshold_gb"`
-	ThresholdPercent float64 `json:"threshold_percent"`
+	Path             string   `json:"path"`
+	ThresholdGB      float64  `json:"threshold_gb"`
+	ThresholdPercent float64  `json:"threshold_percent"`
+	ClearGB          *float64 `json:"clear_gb,omitempty"`
+	ClearPercent     *float64 `json:"clear_percent,omitempty"`
+	// Alert when the path is forecast to be full within this many hours (0 disables)
+	PredictFullHours      float64 `json:"predict_full_hours,omitempty"`
+	PredictionWindowHours float64 `json:"prediction_window_hours,omitempty"`
 }
 
 // NewDiskCollector creates a new disk space collector
@@ -40,8 +50,31 @@ func (c *DiskCollector) Name() string {
 	return c.collectorName
 }
 
+// Schema describes the collector and its settings
+func (c *DiskCollector) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Free space of filesystems, with optional fill-up forecasts",
+		Settings: []collectors.Setting{
+			{Name: "paths", Type: "list", Required: true, Description: "Filesystems to check", Fields: []collectors.Setting{
+				{Name: "path", Type: "string", Required: true, Description: "Mount point of the filesystem"},
+				{Name: "threshold_gb", Type: "float", Default: "5", Description: "Alert when less than this many GB are free"},
+				{Name: "threshold_percent", Type: "float", Default: "90", Description: "Alert when more than this percentage is used"},
+				{Name: "clear_gb", Type: "float", Description: "Free GB the filesystem must be back above to resolve"},
+				{Name: "clear_percent", Type: "float", Description: "Usage the filesystem must be back below to resolve"},
+				{Name: "predict_full_hours", Type: "float", Description: "Alert when the filesystem is forecast to fill up within this many hours"},
+				{Name: "prediction_window_hours", Type: "float", Default: "24", Description: "Hours of history the forecast is based on"},
+			}},
+			{Name: "skip_overlay", Type: "bool", Default: "false", Description: "Skip local paths on an overlay filesystem, such as a container's root, whose free space is the host's image storage; on in container mode"},
+			remote.HostsSetting,
+		},
+	}
+}
+
 // Init initializes the disk collector with configuration
 func (c *DiskCollector) Init(settings map[string]interface{}) error {
+	// Start from a clean slate so re-initialization on reload doesn't duplicate paths
+	c.paths = nil
+
 	// Get paths array from settings
 	pathsRaw, ok := settings["paths"]
 	if !ok {
@@ -92,10 +125,37 @@ func (c *DiskCollector) Init(settings map[string]interface{}) error {
 			thresholdPercent = val
 		}
 
+		// Optional clear levels, for hysteresis
+		clearGB := collectors.GetOptionalFloat(pathMap, "clear_gb")
+		if clearGB != nil && *clearGB < thresholdGB {
+			err := fmt.Errorf("'clear_gb' must not be below 'threshold_gb' for path %s", path)
+			c.logger.Error("Init error", zap.Error(err))
+			return err
+		}
+		clearPercent := collectors.GetOptionalFloat(pathMap, "clear_percent")
+		if clearPercent != nil && *clearPercent > thresholdPercent {
+			err := fmt.Errorf("'clear_percent' must not be above 'threshold_percent' for path %s", path)
+			c.logger.Error("Init error", zap.Error(err))
+			return err
+		}
+
+		// Optional disk-full forecast from the growth rate
+		predictFullHours := collectors.GetFloat(pathMap, "predict_full_hours", 0)
+		predictionWindowHours := collectors.GetFloat(pathMap, "prediction_window_hours", 24)
+		if predictFullHours < 0 || predictionWindowHours <= 0 {
+			err := fmt.Errorf("'predict_full_hours' must not be negative and 'prediction_window_hours' must be positive for path %s", path)
+			c.logger.Error("Init error", zap.Error(err))
+			return err
+		}
+
 		c.paths = append(c.paths, PathConfig{
-			Path:             absPath,
-			ThresholdGB:      thresholdGB,
-			ThresholdPercent: thresholdPercent,
+			Path:                  absPath,
+			ThresholdGB:           thresholdGB,
+			ThresholdPercent:      thresholdPercent,
+			ClearGB:               clearGB,
+			ClearPercent:          clearPercent,
+			PredictFullHours:      predictFullHours,
+			PredictionWindowHours: predictionWindowHours,
 		})
 	}
 
@@ -104,6 +164,29 @@ func (c *DiskCollector) Init(settings map[string]interface{}) error {
 		c.logger.Error("Init error", zap.Error(err))
 		return err
 	}
+	c.skipOverlay = collectors.GetBool(settings, "skip_overlay", false)
+
+	// Check the local machine unless remote hosts are listed
+	remote.CloseAll(c.targets)
+	c.targets = nil
+	targets, err := remote.Targets(settings)
+	if err != nil {
+		c.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+	c.targets = targets
+
+	// Keep the history of paths still configured across re-initialization
+	history := make(map[string][]sample, len(c.paths)*len(c.targets))
+	for _, target := range c.targets {
+		for _, path := range c.paths {
+			if path.PredictFullHours > 0 {
+				key := historyKey(target, path)
+				history[key] = c.history[key]
+			}
+		}
+	}
+	c.history = history
 
 	return nil
 }
@@ -112,101 +195,181 @@ func (c *DiskCollector) Init(settings map[string]interface{}) error {
 func (c *DiskCollector) Collect(ctx context.Context) ([]collectors.Result, error) {
 	var results []collectors.Result
 
-	for _, path := range c.paths {
-		// Check if context is cancelled
-		select {
-		case <-ctx.Done():
-			return results, ctx.Err()
-		default:
-			// Continue processing
+	for _, target := range c.targets {
+		for _, path := range c.paths {
+			// Check if context is cancelled
+			select {
+			case <-ctx.Done():
+				return results, ctx.Err()
+			default:
+				// Continue processing
+			}
+
+			if c.skipOverlay && target.IsLocal() && isOverlay(path.Path) {
+				c.logger.Debug("Skipping path on an overlay filesystem", zap.String("path", path.Path))
+				continue
+			}
+
+			result, err := c.collectPath(ctx, target, path)
+			if err != nil {
+				return results, err
+			}
+			results = append(results, result)
 		}
+	}
+
+	c.logger.Info("Collected disk metrics", zap.Any("results", results))
+	return results, nil
+}
 
+// collectPath checks one path on one target. A remote host that cannot be
+// reached gives an unhealthy result rather than failing the whole run.
+func (c *DiskCollector) collectPath(ctx context.Context, target remote.Target, path PathConfig) (collectors.Result, error) {
+	metadata := map[string]interface{}{
+		"path": path.Path,
+	}
+	location := path.Path
+
+	var totalBytes, freeBytes float64
+	if target.IsLocal() {
 		// Get disk usage stats
-		var stat unix.Statfs_t
-		if err := unix.Statfs(path.Path, &stat); err != nil {
+		var err error
+		totalBytes, freeBytes, err = localUsage(path.Path)
+		if err != nil {
 			c.logger.Error("Failed to get disk stats", zap.String("path", path.Path), zap.Error(err))
-			return results, err
+			return collectors.Result{}, err
 		}
+	} else {
+		metadata["host"] = target.Name
+		location = path.Path + " on host " + target.Name
 
-		// Calculate disk usage metrics
-		totalBytes := float64(stat.Blocks) * float64(stat.Bsize)
-		freeBytes := float64(stat.Bfree) * float64(stat.Bsize)
-		usedBytes := totalBytes - freeBytes
-
-		// Convert to GB
-		totalGB := totalBytes / (1024 * 1024 * 1024)
-		freeGB := freeBytes / (1024 * 1024 * 1024)
-		usedGB := usedBytes / (1024 * 1024 * 1024)
-
-		// Calculate percentages
-		usedPercent := (usedBytes / totalBytes) * 100
-
-		// Create metrics map
-		metrics := map[string]float64{
-			"total_gb":     totalGB,
-			"free_gb":      freeGB,
-			"used_gb":      usedGB,
-			"used_percent": usedPercent,
+		var err error
+		totalBytes, freeBytes, err = remoteUsage(ctx, target.Client, path.Path)
+		if err != nil {
+			c.logger.Error("Failed to get remote disk stats", zap.String("host", target.Name), zap.String("path", path.Path), zap.Error(err))
+			return collectors.Result{
+				IsHealthy: false,
+				Collector: c.Name(),
+				Timestamp: time.Now(),
+				Severity:  collectors.SeverityCritical,
+				Message:   fmt.Sprintf("Could not check disk %s on host %s: %v", path.Path, target.Name, err),
+				Metrics:   map[string]float64{},
+				Metadata:  metadata,
+			}, nil
 		}
+	}
 
-		// Check thresholds
-		isHealthy := true
-		var message string
-
-		if freeGB < path.ThresholdGB {
-			isHealthy = false
-			message = fmt.Sprintf("Low disk space on %s: %.2fGB free (threshold: %.2fGB)",
-				path.Path, freeGB, path.ThresholdGB)
-		} else if usedPercent > path.ThresholdPercent {
-			isHealthy = false
-			message = fmt.Sprintf("High disk usage on %s: %.2f%% used (threshold: %.2f%%)",
-				path.Path, usedPercent, path.ThresholdPercent)
-		}
+	// Calculate disk usage metrics
+	usedBytes := totalBytes - freeBytes
+
+	// Convert to GB
+	totalGB := totalBytes / (1024 * 1024 * 1024)
+	freeGB := freeBytes / (1024 * 1024 * 1024)
+	usedGB := usedBytes / (1024 * 1024 * 1024)
+
+	// Calculate percentages
+	usedPercent := (usedBytes / totalBytes) * 100
+
+	// Create metrics map
+	metrics := map[string]float64{
+		"total_gb":     totalGB,
+		"free_gb":      freeGB,
+		"used_gb":      usedGB,
+		"used_percent": usedPercent,
+	}
+
+	// Declare the thresholds; health is decided by the evaluator
+	thresholds := []collectors.Threshold{
+		{
+			Type:     "absolute",
+			Metric:   "free_gb",
+			Operator: "less_than",
+			Value:    path.ThresholdGB,
+			Severity: "critical",
+			Clear:    path.ClearGB,
+			Message: fmt.Sprintf("Low disk space on %s: %.2fGB free (threshold: %.2fGB)",
+				location, freeGB, path.ThresholdGB),
+		},
+		{
+			Type:     "percentage",
+			Metric:   "used_percent",
+			Operator: "greater_than",
+			Value:    path.ThresholdPercent,
+			Severity: "warning",
+			Clear:    path.ClearPercent,
+			Message: fmt.Sprintf("High disk usage on %s: %.2f%% used (threshold: %.2f%%)",
+				location, usedPercent, path.ThresholdPercent),
+		},
+	}
+
+	// Forecast when the path fills up at its current growth rate
+	if path.PredictFullHours > 0 {
+		history := c.record(path, historyKey(target, path), sample{at: time.Now(), freeGB: freeGB})
+		if hours, growth, ok := forecastFull(history); ok {
+			metrics["hours_until_full"] = hours
+			metrics["growth_gb_per_day"] = growth
 
-		// Add thresholds that were evaluated
-		thresholds := []collectors.Threshold{
-			{
-				Type:     "absolute",
-				Metric:   "free_gb",
+			thresholds = append(thresholds, collectors.Threshold{
+				Type:     "forecast",
+				Metric:   "hours_until_full",
 				Operator: "less_than",
-				Value:    path.ThresholdGB,
-				Severity: "critical",
-			},
-			{
-				Type:     "percentage",
-				Metric:   "used_percent",
-				Operator: "greater_than",
-				Value:    path.ThresholdPercent,
+				Value:    path.PredictFullHours,
 				Severity: "warning",
-			},
+				Message: fmt.Sprintf("Disk %s will be full in ~%s at current growth rate (%.2fGB/day, %.2fGB free)",
+					location, formatHours(hours), growth, freeGB),
+			})
 		}
+	}
 
-		// Create result
-		result := collectors.Result{
-			IsHealthy:  isHealthy,
-			Collector:  c.Name(),
-			Timestamp:  time.Now(),
-			Metrics:    metrics,
-			Thresholds: thresholds,
-			Metadata: map[string]interface{}{
-				"path": path.Path,
-			},
-		}
+	// Create result
+	result := collectors.Result{
+		IsHealthy:  true,
+		Collector:  c.Name(),
+		Timestamp:  time.Now(),
+		Metrics:    metrics,
+		Thresholds: thresholds,
+		Metadata:   metadata,
+	}
 
-		// Add message if unhealthy
-		if !isHealthy {
-			result.Message = message
-		}
+	return result, nil
+}
 
-		results = append(results, result)
+// remoteUsage reads the total and free bytes of a path on a remote host
+// with POSIX df
+func remoteUsage(ctx context.Context, client *remote.Client, path string) (total, free float64, err error) {
+	output, err := client.Run(ctx, "df -Pk -- "+remote.Quote(path))
+	if err != nil {
+		return 0, 0, err
 	}
 
-	c.logger.Info("Collected disk metrics", zap.Any("results", results))
-	return results, nil
+	// Filesystem 1024-blocks Used Available Capacity Mounted-on
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) < 2 {
+		return 0, 0, fmt.Errorf("unexpected df output: %q", string(output))
+	}
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) < 4 {
+		return 0, 0, fmt.Errorf("unexpected df output: %q", string(output))
+	}
+	blocks, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected df output: %q", string(output))
+	}
+	used, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected df output: %q", string(output))
+	}
+	return blocks * 1024, (blocks - used) * 1024, nil
+}
+
+// historyKey identifies the forecast history of a path on a target
+func historyKey(target remote.Target, path PathConfig) string {
+	return target.Name + ":" + path.Path
 }
 
 // Cleanup performs any necessary cleanup
 func (c *DiskCollector) Cleanup() error {
-	// No cleanup needed for disk collector
+	// Close the SSH connections to remote hosts
+	remote.CloseAll(c.targets)
 	return nil
 }
@@ -0,0 +1,80 @@
+// collectors/disk/forecast.go
+package disk
+
+import (
+	"fmt"
+	"time"
+)
+
+// Forecasts need this many samples spanning at least minForecastSpan
+const (
+	minForecastSamples = 5
+	minForecastSpan    = 30 * time.Minute
+)
+
+// sample is one free space reading of a path
+type sample struct {
+	at     time.Time
+	freeGB float64
+}
+
+// record adds a reading to the history under key and drops readings older
+// than the path's prediction window
+func (c *DiskCollector) record(path PathConfig, key string, reading sample) []sample {
+	window := time.Duration(path.PredictionWindowHours * float64(time.Hour))
+	history := append(c.history[key], reading)
+
+	cutoff := reading.at.Add(-window)
+	for len(history) > 0 && history[0].at.Before(cutoff) {
+		history = history[1:]
+	}
+	c.history[key] = history
+	return history
+}
+
+// forecastFull fits a least squares line through the free space history and
+// returns the hours until it reaches zero and the growth in GB per day. ok is
+// false when there is too little history or usage is not growing.
+func forecastFull(history []sample) (hours, growthPerDay float64, ok bool) {
+	if len(history) < minForecastSamples {
+		return 0, 0, false
+	}
+	latest := history[len(history)-1]
+	if latest.at.Sub(history[0].at) < minForecastSpan {
+		return 0, 0, false
+	}
+
+	// Regress free space against hours before the latest reading
+	var sumX, sumY, sumXY, sumXX float64
+	for _, s := range history {
+		x := s.at.Sub(latest.at).Hours()
+		sumX += x
+		sumY += s.freeGB
+		sumXY += x * s.freeGB
+		sumXX += x * x
+	}
+	n := float64(len(history))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, 0, false
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+	if slope >= 0 {
+		return 0, 0, false
+	}
+	intercept := (sumY - slope*sumX) / n
+
+	hours = intercept / -slope
+	if hours < 0 {
+		hours = 0
+	}
+	return hours, -slope * 24, true
+}
+
+// formatHours renders a forecast horizon as hours or days
+func formatHours(hours float64) string {
+	if hours < 48 {
+		return fmt.Sprintf("%.0f hours", hours)
+	}
+	return fmt.Sprintf("%.1f days", hours/24)
+}
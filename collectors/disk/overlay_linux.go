@@ -0,0 +1,11 @@
+// collectors/disk/overlay_linux.go
+package disk
+
+import "golang.org/x/sys/unix"
+
+// isOverlay reports whether path is on an overlay filesystem, as the root of
+// most containers is
+func isOverlay(path string) bool {
+	var stat unix.Statfs_t
+	return unix.Statfs(path, &stat) == nil && stat.Type == unix.OVERLAYFS_SUPER_MAGIC
+}
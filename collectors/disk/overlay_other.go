@@ -0,0 +1,8 @@
+//go:build !linux
+
+// collectors/disk/overlay_other.go
+package disk
+
+// isOverlay reports whether path is on an overlay filesystem, which only
+// Linux has
+func isOverlay(path string) bool { return false }
@@ -0,0 +1,15 @@
+//go:build !windows
+
+// collectors/disk/usage_unix.go
+package disk
+
+import "golang.org/x/sys/unix"
+
+// localUsage returns the total and free bytes of the filesystem holding path
+func localUsage(path string) (float64, float64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+	return float64(stat.Blocks) * float64(stat.Bsize), float64(stat.Bfree) * float64(stat.Bsize), nil
+}
@@ -0,0 +1,20 @@
+//go:build windows
+
+// collectors/disk/usage_windows.go
+package disk
+
+import "golang.org/x/sys/windows"
+
+// localUsage returns the total and free bytes of the volume holding path,
+// e.g. C:\
+func localUsage(path string) (float64, float64, error) {
+	name, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	var available, total, free uint64
+	if err := windows.GetDiskFreeSpaceEx(name, &available, &total, &free); err != nil {
+		return 0, 0, err
+	}
+	return float64(total), float64(free), nil
+}
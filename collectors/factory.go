@@ -0,0 +1,70 @@
+// collectors/factory.go
+package collectors
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Factory creates a collector registered under name, the collector's key in
+// the configuration
+type Factory func(logger *zap.Logger, name string) Collector
+
+var (
+	factories   = make(map[string]Factory)
+	factoriesMu sync.RWMutex
+)
+
+// RegisterFactory makes a collector type available to the configuration. A
+// collector whose type is typeName, or that has no type and is named
+// typeName, is created by factory. Packages outside this repository call it
+// (or MustRegisterFactory) from init, and are compiled in with a blank
+// import.
+func RegisterFactory(typeName string, factory Factory) error {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if typeName == "" {
+		return fmt.Errorf("collector factory has empty type")
+	}
+	if factory == nil {
+		return fmt.Errorf("collector factory '%s' is nil", typeName)
+	}
+	if _, exists := factories[typeName]; exists {
+		return fmt.Errorf("collector factory '%s' already registered", typeName)
+	}
+	factories[typeName] = factory
+	return nil
+}
+
+// MustRegisterFactory is RegisterFactory, panicking on error
+func MustRegisterFactory(typeName string, factory Factory) {
+	if err := RegisterFactory(typeName, factory); err != nil {
+		panic(err)
+	}
+}
+
+// LookupFactory returns the factory registered for a collector type
+func LookupFactory(typeName string) (Factory, bool) {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	factory, exists := factories[typeName]
+	return factory, exists
+}
+
+// FactoryTypes returns the registered collector types, sorted
+func FactoryTypes() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	types := make([]string, 0, len(factories))
+	for typeName := range factories {
+		types = append(types, typeName)
+	}
+	sort.Strings(types)
+	return types
+}
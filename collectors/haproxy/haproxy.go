@@ -0,0 +1,386 @@
+// collectors/haproxy/haproxy.go
+package haproxy
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"go.uber.org/zap"
+)
+
+// HAProxyCollector implements the Collector interface for HAProxy backend monitoring
+type HAProxyCollector struct {
+	url           string
+	socket        string
+	username      string
+	password      string
+	backends      map[string]bool
+	minUpServers  int
+	maxQueue      float64
+	clearUp       *float64
+	clearQueue    *float64
+	timeout       time.Duration
+	client        *http.Client
+	collectorName string
+	logger        *zap.Logger
+}
+
+// backendStats aggregates the stats rows belonging to one backend
+type backendStats struct {
+	name         string
+	status       string
+	queueCurrent float64
+	sessions     float64
+	serversUp    int
+	serversTotal int
+	downServers  []string
+}
+
+// NewHAProxyCollector creates a new HAProxy collector
+func NewHAProxyCollector(logger *zap.Logger) *HAProxyCollector {
+	return &HAProxyCollector{
+		collectorName: "haproxy",
+		logger:        logger,
+	}
+}
+
+// Name returns the name of the collector
+func (c *HAProxyCollector) Name() string {
+	return c.collectorName
+}
+
+// Schema describes the collector and its settings
+func (c *HAProxyCollector) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Health of HAProxy backends",
+		Settings: []collectors.Setting{
+			{Name: "url", Type: "string", Description: "URL of the stats page in CSV format; either url or socket is required"},
+			{Name: "socket", Type: "string", Description: "Path of the stats socket"},
+			{Name: "username", Type: "string", Description: "Username for the stats page"},
+			{Name: "password", Type: "string", Description: "Password for the stats page"},
+			{Name: "password_file", Type: "string", Description: "File to read password from instead"},
+			{Name: "backends", Type: "list", Description: "Backends to check (default: all)"},
+			{Name: "min_up_servers", Type: "int", Default: "1", Description: "Alert when fewer servers of a backend are up"},
+			{Name: "max_queue", Type: "float", Default: "100", Description: "Alert when more requests are queued on a backend"},
+			{Name: "clear_up_servers", Type: "float", Description: "Servers that must be up again to resolve"},
+			{Name: "clear_queue", Type: "float", Description: "Queue length that must be back below to resolve"},
+			{Name: "timeout_seconds", Type: "duration", Default: "10", Description: "Timeout for reading the stats"},
+		},
+	}
+}
+
+// Init initializes the HAProxy collector with configuration
+func (c *HAProxyCollector) Init(settings map[string]interface{}) error {
+	c.url = collectors.GetString(settings, "url", "")
+	c.socket = collectors.GetString(settings, "socket", "")
+	if (c.url == "") == (c.socket == "") {
+		err := fmt.Errorf("exactly one of 'url' or 'socket' must be configured for haproxy collector")
+		c.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+
+	c.username = collectors.GetString(settings, "username", "")
+	password, err := collectors.GetSecret(settings, "password")
+	if err != nil {
+		c.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+	c.password = password
+	c.minUpServers = collectors.GetInt(settings, "min_up_servers", 1)
+	c.maxQueue = collectors.GetFloat(settings, "max_queue", 100)
+
+	// Optional clear levels, for hysteresis
+	c.clearUp = collectors.GetOptionalFloat(settings, "clear_up_servers")
+	if c.clearUp != nil && *c.clearUp < float64(c.minUpServers) {
+		err := fmt.Errorf("'clear_up_servers' must not be below 'min_up_servers'")
+		c.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+	c.clearQueue = collectors.GetOptionalFloat(settings, "clear_queue")
+	if c.clearQueue != nil && *c.clearQueue > c.maxQueue {
+		err := fmt.Errorf("'clear_queue' must not be above 'max_queue'")
+		c.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+
+	timeout, err := collectors.GetDuration(settings, "timeout_seconds", time.Second, 10*time.Second)
+	if err == nil && timeout <= 0 {
+		err = fmt.Errorf("'timeout_seconds' must be greater than 0")
+	}
+	if err != nil {
+		c.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+	c.timeout = timeout
+	c.client = &http.Client{Timeout: c.timeout}
+
+	backends, err := collectors.GetStringSlice(settings, "backends", nil)
+	if err != nil {
+		c.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+	c.backends = make(map[string]bool)
+	for _, backend := range backends {
+		c.backends[backend] = true
+	}
+
+	return nil
+}
+
+// Collect scrapes the HAProxy stats and evaluates each backend
+func (c *HAProxyCollector) Collect(ctx context.Context) ([]collectors.Result, error) {
+	// Check if context is cancelled
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+		// Continue processing
+	}
+
+	source := c.url
+	if source == "" {
+		source = c.socket
+	}
+
+	data, err := c.fetchStats(ctx)
+	if err != nil {
+		c.logger.Warn("Failed to fetch HAProxy stats", zap.String("source", source), zap.Error(err))
+		return []collectors.Result{{
+			IsHealthy: false,
+			Collector: c.Name(),
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("Could not read HAProxy stats from %s: %v", source, err),
+			Severity:  collectors.SeverityCritical,
+			Metrics:   map[string]float64{},
+			Metadata: map[string]interface{}{
+				"source": source,
+			},
+		}}, nil
+	}
+
+	backends, err := parseStats(data)
+	if err != nil {
+		c.logger.Error("Failed to parse HAProxy stats", zap.String("source", source), zap.Error(err))
+		return nil, err
+	}
+
+	// The source is reported healthy whenever it can be read, so an alert
+	// raised while it could not resolves under the same key
+	results := []collectors.Result{{
+		IsHealthy: true,
+		Collector: c.Name(),
+		Timestamp: time.Now(),
+		Message:   fmt.Sprintf("Read HAProxy stats from %s", source),
+		Metrics:   map[string]float64{"backends": float64(len(backends))},
+		Metadata: map[string]interface{}{
+			"source": source,
+		},
+	}}
+	for _, backend := range backends {
+		if len(c.backends) > 0 && !c.backends[backend.name] {
+			continue
+		}
+
+		metrics := map[string]float64{
+			"servers_up":       float64(backend.serversUp),
+			"servers_total":    float64(backend.serversTotal),
+			"queue_current":    backend.queueCurrent,
+			"sessions_current": backend.sessions,
+		}
+
+		up := 1.0
+		if strings.HasPrefix(backend.status, "DOWN") {
+			up = 0
+		}
+		metrics["up"] = up
+
+		downServers := ""
+		if len(backend.downServers) > 0 {
+			downServers = " (down servers: " + strings.Join(backend.downServers, ", ") + ")"
+		}
+
+		// Declare the thresholds; health is decided by the evaluator
+		thresholds := []collectors.Threshold{
+			{
+				Type:     "absolute",
+				Metric:   "up",
+				Operator: "equals",
+				Value:    0,
+				Severity: "critical",
+				Message:  fmt.Sprintf("HAProxy: backend %s is DOWN", backend.name),
+			},
+			{
+				Type:     "absolute",
+				Metric:   "servers_up",
+				Operator: "less_than",
+				Value:    float64(c.minUpServers),
+				Severity: "critical",
+				Clear:    c.clearUp,
+				Message: fmt.Sprintf("HAProxy: backend %s has %d/%d servers UP (minimum: %d)%s",
+					backend.name, backend.serversUp, backend.serversTotal, c.minUpServers, downServers),
+			},
+			{
+				Type:     "absolute",
+				Metric:   "queue_current",
+				Operator: "greater_than",
+				Value:    c.maxQueue,
+				Severity: "warning",
+				Clear:    c.clearQueue,
+				Message: fmt.Sprintf("HAProxy: backend %s queue depth is %.0f (threshold: %.0f)",
+					backend.name, backend.queueCurrent, c.maxQueue),
+			},
+		}
+
+		result := collectors.Result{
+			IsHealthy:  true,
+			Collector:  c.Name(),
+			Timestamp:  time.Now(),
+			Metrics:    metrics,
+			Thresholds: thresholds,
+			// Metadata is part of the key, so it only names the backend; its
+			// status and down servers change as it degrades and go in the
+			// message, or each change would open a new alert
+			Metadata: map[string]interface{}{
+				"backend": backend.name,
+			},
+		}
+
+		results = append(results, result)
+	}
+
+	c.logger.Info("Collected HAProxy metrics", zap.Any("results", results))
+	return results, nil
+}
+
+// fetchStats reads the CSV stats from the HTTP endpoint or the stats socket
+func (c *HAProxyCollector) fetchStats(ctx context.Context) ([]byte, error) {
+	if c.socket != "" {
+		dialer := net.Dialer{Timeout: c.timeout}
+		conn, err := dialer.DialContext(ctx, "unix", c.socket)
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+
+		if err := conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+			return nil, err
+		}
+		if _, err := conn.Write([]byte("show stat\n")); err != nil {
+			return nil, err
+		}
+		return io.ReadAll(conn)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// parseStats parses HAProxy CSV stats into per-backend aggregates, in input order
+func parseStats(data []byte) ([]*backendStats, error) {
+	text := strings.TrimPrefix(strings.TrimSpace(string(data)), "# ")
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty stats output")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[name] = i
+	}
+	for _, required := range []string{"pxname", "svname", "status", "qcur", "scur"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("stats output is missing column '%s'", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if idx := columns[name]; idx < len(record) {
+			return record[idx]
+		}
+		return ""
+	}
+	number := func(record []string, name string) float64 {
+		val, _ := strconv.ParseFloat(field(record, name), 64)
+		return val
+	}
+
+	var ordered []*backendStats
+	byName := make(map[string]*backendStats)
+	get := func(name string) *backendStats {
+		backend, ok := byName[name]
+		if !ok {
+			backend = &backendStats{name: name}
+			byName[name] = backend
+			ordered = append(ordered, backend)
+		}
+		return backend
+	}
+
+	for _, record := range records[1:] {
+		pxname, svname := field(record, "pxname"), field(record, "svname")
+		switch svname {
+		case "FRONTEND":
+			continue
+		case "BACKEND":
+			backend := get(pxname)
+			backend.status = field(record, "status")
+			backend.queueCurrent = number(record, "qcur")
+			backend.sessions = number(record, "scur")
+		default:
+			backend := get(pxname)
+			backend.serversTotal++
+			status := field(record, "status")
+			if strings.HasPrefix(status, "UP") || status == "no check" {
+				backend.serversUp++
+			} else {
+				backend.downServers = append(backend.downServers, svname)
+			}
+		}
+	}
+
+	// Proxies without a BACKEND row (frontends only) are not backends
+	var backends []*backendStats
+	for _, backend := range ordered {
+		if backend.status != "" {
+			backends = append(backends, backend)
+		}
+	}
+	return backends, nil
+}
+
+// Cleanup performs any necessary cleanup
+func (c *HAProxyCollector) Cleanup() error {
+	// No cleanup needed for haproxy collector
+	return nil
+}
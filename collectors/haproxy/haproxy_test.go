@@ -0,0 +1,65 @@
+// collectors/haproxy/haproxy_test.go
+package haproxy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/devvspaces/simple-monit/alerting"
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"go.uber.org/zap"
+)
+
+const stats = `# pxname,svname,qcur,scur,status
+web,web1,0,3,UP
+web,BACKEND,0,3,UP
+`
+
+func TestUnreadableStatsAlertResolves(t *testing.T) {
+	var available atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !available.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, stats)
+	}))
+	defer server.Close()
+
+	collector := NewHAProxyCollector(zap.NewNop())
+	if err := collector.Init(map[string]interface{}{"url": server.URL}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	machine := alerting.NewMachine()
+	source := collectors.Result{Collector: "haproxy", Metadata: map[string]interface{}{"source": server.URL}}.Key()
+	run := func() []alerting.Event {
+		t.Helper()
+		results, err := collector.Collect(context.Background())
+		if err != nil {
+			t.Fatalf("Collect: %v", err)
+		}
+		_, events := machine.Evaluate(results, 0, time.Now())
+		return events
+	}
+
+	events := run()
+	if len(events) != 1 || events[0].Key != source || events[0].To != alerting.StateFiring {
+		t.Fatalf("got events %+v, want %s firing", events, source)
+	}
+
+	available.Store(true)
+	events = run()
+	if len(events) != 1 || events[0].Key != source || events[0].To != alerting.StateResolved {
+		t.Fatalf("got events %+v, want %s resolved", events, source)
+	}
+	if state := machine.State(source); state != alerting.StateOK {
+		t.Errorf("source is %s after recovering, want ok", state)
+	}
+}
@@ -0,0 +1,77 @@
+// collectors/memory/cgroup.go
+package memory
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// cgroupRoot is where the cgroup filesystem is mounted; in a container it
+// shows the container's own cgroup
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupMemory reads the total, used and free bytes of the cgroup at
+// cgroupRoot: its memory limit, and its usage less the inactive page cache
+// the kernel reclaims before hitting the limit, as docker stats reports it.
+// Without a limit the total is hostTotal. cgroup v2 is tried first, then v1.
+func cgroupMemory(hostTotal float64) (total, used, free float64, err error) {
+	limitFile, usageFile, statFile, inactiveKey := "memory.max", "memory.current", "memory.stat", "inactive_file"
+	dir := cgroupRoot
+	if _, err := os.Stat(filepath.Join(dir, usageFile)); err != nil {
+		limitFile, usageFile, inactiveKey = "memory.limit_in_bytes", "memory.usage_in_bytes", "total_inactive_file"
+		dir = filepath.Join(cgroupRoot, "memory")
+	}
+
+	usage, err := readCgroupValue(filepath.Join(dir, usageFile))
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	limit, err := readCgroupValue(filepath.Join(dir, limitFile))
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	// v2 writes "max" and v1 a huge number when there is no limit
+	total = hostTotal
+	if limit > 0 && limit < hostTotal {
+		total = limit
+	}
+
+	stat, err := os.ReadFile(filepath.Join(dir, statFile))
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(stat))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == inactiveKey {
+			if inactive, err := strconv.ParseFloat(fields[1], 64); err == nil && inactive < usage {
+				usage -= inactive
+			}
+			break
+		}
+	}
+	return total, usage, max(total-usage, 0), nil
+}
+
+// readCgroupValue reads a cgroup file holding a number of bytes, or "max"
+// for no limit, returned as 0
+func readCgroupValue(path string) (float64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	raw := strings.TrimSpace(string(data))
+	if raw == "max" {
+		return 0, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected contents of %s: %q", path, raw)
+	}
+	return value, nil
+}
@@ -2,11 +2,16 @@
 package memory
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
-	"server-monitor/collectors"
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/remote"
 
 	"github.com/shirou/gopsutil/v3/mem"
 	"go.uber.org/zap"
@@ -15,6 +20,9 @@ import (
 // MemoryCollector implements the Collector interface for memory monitoring
 type MemoryCollector struct {
 	thresholdPercent float64
+	clearPercent     *float64
+	source           string          // host or cgroup, for the local machine
+	targets          []remote.Target // Machines to check: local and/or remote over SSH
 	collectorName    string
 	logger           *zap.Logger
 }
@@ -32,6 +40,19 @@ func (c *MemoryCollector) Name() string {
 	return c.collectorName
 }
 
+// Schema describes the collector and its settings
+func (c *MemoryCollector) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Memory usage",
+		Settings: []collectors.Setting{
+			{Name: "threshold_percent", Type: "float", Default: "90", Description: "Alert when more than this percentage is used"},
+			{Name: "clear_percent", Type: "float", Description: "Usage must be back below this percentage to resolve"},
+			{Name: "source", Type: "string", Default: "host", Description: "What to measure locally: host, the machine's memory, or cgroup, the usage and limit of this process's cgroup, e.g. its container; cgroup in container mode"},
+			remote.HostsSetting,
+		},
+	}
+}
+
 // Init initializes the memory collector with configuration
 func (c *MemoryCollector) Init(settings map[string]interface{}) error {
 	// Set default threshold
@@ -42,6 +63,31 @@ func (c *MemoryCollector) Init(settings map[string]interface{}) error {
 		c.thresholdPercent = val
 	}
 
+	// Optional clear level below the threshold, for hysteresis
+	c.clearPercent = collectors.GetOptionalFloat(settings, "clear_percent")
+	if c.clearPercent != nil && *c.clearPercent > c.thresholdPercent {
+		err := fmt.Errorf("'clear_percent' must not be above 'threshold_percent'")
+		c.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+
+	c.source = collectors.GetString(settings, "source", "host")
+	if c.source != "host" && c.source != "cgroup" {
+		err := fmt.Errorf("'source' must be host or cgroup")
+		c.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+
+	// Check the local machine unless remote hosts are listed
+	remote.CloseAll(c.targets)
+	c.targets = nil
+	targets, err := remote.Targets(settings)
+	if err != nil {
+		c.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+	c.targets = targets
+
 	return nil
 }
 
@@ -55,35 +101,75 @@ func (c *MemoryCollector) Collect(ctx context.Context) ([]collectors.Result, err
 		// Continue processing
 	}
 
-	// Get memory stats
-	memStats, err := mem.VirtualMemory()
-	if err != nil {
-		c.logger.Error("Failed to get memory stats", zap.Error(err))
-		return nil, err
+	var results []collectors.Result
+	for _, target := range c.targets {
+		result, err := c.collectTarget(ctx, target)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
 	}
 
-	// Calculate memory usage
-	usedPercent := memStats.UsedPercent
+	c.logger.Info("Memory metrics collected", zap.Any("results", results))
+	return results, nil
+}
+
+// collectTarget checks the memory of one target. A remote host that cannot
+// be reached gives an unhealthy result rather than failing the whole run.
+func (c *MemoryCollector) collectTarget(ctx context.Context, target remote.Target) (collectors.Result, error) {
+	var metadata map[string]interface{}
+	var total, used, free, usedPercent float64
+	subject := "High memory usage"
+
+	if target.IsLocal() {
+		// Get memory stats
+		memStats, err := mem.VirtualMemory()
+		if err != nil {
+			c.logger.Error("Failed to get memory stats", zap.Error(err))
+			return collectors.Result{}, err
+		}
+		total, used, free = float64(memStats.Total), float64(memStats.Used), float64(memStats.Free)
+		usedPercent = memStats.UsedPercent
+
+		if c.source == "cgroup" {
+			subject = "High container memory usage"
+			total, used, free, err = cgroupMemory(total)
+			if err != nil {
+				c.logger.Error("Failed to get cgroup memory stats", zap.Error(err))
+				return collectors.Result{}, err
+			}
+			usedPercent = used / total * 100
+		}
+	} else {
+		metadata = map[string]interface{}{"host": target.Name}
+		subject = "High memory usage on host " + target.Name
+
+		var err error
+		total, used, free, err = remoteMemory(ctx, target.Client)
+		if err != nil {
+			c.logger.Error("Failed to get remote memory stats", zap.String("host", target.Name), zap.Error(err))
+			return collectors.Result{
+				IsHealthy: false,
+				Collector: c.Name(),
+				Timestamp: time.Now(),
+				Severity:  collectors.SeverityCritical,
+				Message:   fmt.Sprintf("Could not check memory on host %s: %v", target.Name, err),
+				Metrics:   map[string]float64{},
+				Metadata:  metadata,
+			}, nil
+		}
+		usedPercent = used / total * 100
+	}
 
 	// Create metrics map
 	metrics := map[string]float64{
-		"total_gb":     float64(memStats.Total) / (1024 * 1024 * 1024),
-		"used_gb":      float64(memStats.Used) / (1024 * 1024 * 1024),
-		"free_gb":      float64(memStats.Free) / (1024 * 1024 * 1024),
+		"total_gb":     total / (1024 * 1024 * 1024),
+		"used_gb":      used / (1024 * 1024 * 1024),
+		"free_gb":      free / (1024 * 1024 * 1024),
 		"used_percent": usedPercent,
 	}
 
-	// Check threshold
-	isHealthy := true
-	var message string
-
-	if usedPercent > c.thresholdPercent {
-		isHealthy = false
-		message = fmt.Sprintf("High memory usage: %.2f%% used (threshold: %.2f%%)",
-			usedPercent, c.thresholdPercent)
-	}
-
-	// Add thresholds that were evaluated
+	// Declare the thresholds; health is decided by the evaluator
 	thresholds := []collectors.Threshold{
 		{
 			Type:     "percentage",
@@ -91,29 +177,57 @@ func (c *MemoryCollector) Collect(ctx context.Context) ([]collectors.Result, err
 			Operator: "greater_than",
 			Value:    c.thresholdPercent,
 			Severity: "warning",
+			Clear:    c.clearPercent,
+			Message: fmt.Sprintf("%s: %.2f%% used (threshold: %.2f%%)",
+				subject, usedPercent, c.thresholdPercent),
 		},
 	}
 
 	// Create result
 	result := collectors.Result{
-		IsHealthy:  isHealthy,
+		IsHealthy:  true,
 		Collector:  c.Name(),
 		Timestamp:  time.Now(),
 		Metrics:    metrics,
 		Thresholds: thresholds,
+		Metadata:   metadata,
 	}
+	return result, nil
+}
 
-	// Add message if unhealthy
-	if !isHealthy {
-		result.Message = message
+// remoteMemory reads total, used and free bytes from /proc/meminfo on a
+// remote Linux host. Used memory excludes what is available for reuse, like
+// buffers and caches.
+func remoteMemory(ctx context.Context, client *remote.Client) (total, used, free float64, err error) {
+	output, err := client.Run(ctx, "cat /proc/meminfo")
+	if err != nil {
+		return 0, 0, 0, err
 	}
 
-	c.logger.Info("Memory metrics collected", zap.Any("result", result))
-	return []collectors.Result{result}, nil
+	// Lines look like "MemTotal:       16318400 kB"
+	values := make(map[string]float64)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		if value, err := strconv.ParseFloat(fields[1], 64); err == nil {
+			values[strings.TrimSuffix(fields[0], ":")] = value * 1024
+		}
+	}
+
+	total, free = values["MemTotal"], values["MemFree"]
+	available, ok := values["MemAvailable"]
+	if total == 0 || !ok {
+		return 0, 0, 0, fmt.Errorf("unexpected /proc/meminfo output")
+	}
+	return total, total - available, free, nil
 }
 
 // Cleanup performs any necessary cleanup
 func (c *MemoryCollector) Cleanup() error {
-	// No cleanup needed for memory collector
+	// Close the SSH connections to remote hosts
+	remote.CloseAll(c.targets)
 	return nil
 }
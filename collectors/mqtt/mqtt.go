@@ -0,0 +1,280 @@
+// collectors/mqtt/mqtt.go
+package mqtt
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"go.uber.org/zap"
+)
+
+// MQTTCollector implements the Collector interface for MQTT broker health checks.
+// Each collection connects to the broker, subscribes to a canary topic and
+// publishes a unique payload to it, measuring the round trip.
+type MQTTCollector struct {
+	broker             string
+	clientID           string
+	username           string
+	password           string
+	topic              string
+	qos                byte
+	timeout            time.Duration
+	latencyThresholdMs float64
+	latencyClearMs     *float64
+	insecureSkipVerify bool
+	collectorName      string
+	logger             *zap.Logger
+}
+
+// NewMQTTCollector creates a new MQTT broker collector
+func NewMQTTCollector(logger *zap.Logger) *MQTTCollector {
+	return &MQTTCollector{
+		collectorName: "mqtt",
+		logger:        logger,
+	}
+}
+
+// Name returns the name of the collector
+func (c *MQTTCollector) Name() string {
+	return c.collectorName
+}
+
+// Schema describes the collector and its settings
+func (c *MQTTCollector) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Round trip of a canary message through an MQTT broker",
+		Settings: []collectors.Setting{
+			{Name: "broker", Type: "string", Required: true, Description: "Broker URL, e.g. tcp://localhost:1883 or ssl://broker:8883"},
+			{Name: "client_id", Type: "string", Default: "simple-monit-<hostname>", Description: "Client ID"},
+			{Name: "username", Type: "string", Description: "Username"},
+			{Name: "password", Type: "string", Description: "Password"},
+			{Name: "password_file", Type: "string", Description: "File to read password from instead"},
+			{Name: "topic", Type: "string", Default: "simple-monit/canary", Description: "Topic the canary message is published to"},
+			{Name: "qos", Type: "int", Default: "1", Description: "QoS level: 0, 1 or 2"},
+			{Name: "timeout_seconds", Type: "duration", Default: "10", Description: "Timeout for connecting and the round trip"},
+			{Name: "latency_threshold_ms", Type: "float", Default: "1000", Description: "Alert when the round trip takes longer"},
+			{Name: "latency_clear_ms", Type: "float", Description: "Round trip the latency must be back below to resolve"},
+			{Name: "insecure_skip_verify", Type: "bool", Default: "false", Description: "Skip verifying the broker's TLS certificate"},
+		},
+	}
+}
+
+// Init initializes the MQTT collector with configuration
+func (c *MQTTCollector) Init(settings map[string]interface{}) error {
+	c.broker = collectors.GetString(settings, "broker", "")
+	if c.broker == "" {
+		err := fmt.Errorf("missing 'broker' configuration for mqtt collector")
+		c.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+
+	hostname, _ := os.Hostname()
+	c.clientID = collectors.GetString(settings, "client_id", "simple-monit-"+hostname)
+	c.username = collectors.GetString(settings, "username", "")
+	password, err := collectors.GetSecret(settings, "password")
+	if err != nil {
+		c.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+	c.password = password
+	c.topic = collectors.GetString(settings, "topic", "simple-monit/canary")
+	c.insecureSkipVerify = collectors.GetBool(settings, "insecure_skip_verify", false)
+
+	qos := collectors.GetInt(settings, "qos", 1)
+	if qos < 0 || qos > 2 {
+		err := fmt.Errorf("'qos' must be 0, 1 or 2")
+		c.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+	c.qos = byte(qos)
+
+	timeout, err := collectors.GetDuration(settings, "timeout_seconds", time.Second, 10*time.Second)
+	if err == nil && timeout <= 0 {
+		err = fmt.Errorf("'timeout_seconds' must be greater than 0")
+	}
+	if err != nil {
+		c.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+	c.timeout = timeout
+
+	c.latencyThresholdMs = collectors.GetFloat(settings, "latency_threshold_ms", 1000)
+	c.latencyClearMs = collectors.GetOptionalFloat(settings, "latency_clear_ms")
+	if c.latencyClearMs != nil && *c.latencyClearMs > c.latencyThresholdMs {
+		err := fmt.Errorf("'latency_clear_ms' must not be above 'latency_threshold_ms'")
+		c.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Collect connects to the broker and measures the canary round trip
+func (c *MQTTCollector) Collect(ctx context.Context) ([]collectors.Result, error) {
+	// Check if context is cancelled
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+		// Continue processing
+	}
+
+	thresholds := []collectors.Threshold{
+		{
+			Type:     "absolute",
+			Metric:   "round_trip_ms",
+			Operator: "greater_than",
+			Value:    c.latencyThresholdMs,
+			Severity: "warning",
+			Clear:    c.latencyClearMs,
+		},
+	}
+
+	result := collectors.Result{
+		IsHealthy:  true,
+		Collector:  c.Name(),
+		Timestamp:  time.Now(),
+		Metrics:    map[string]float64{"connected": 0},
+		Thresholds: thresholds,
+		Metadata: map[string]interface{}{
+			"broker": c.broker,
+			"topic":  c.topic,
+		},
+	}
+
+	opts := paho.NewClientOptions().
+		AddBroker(c.broker).
+		SetClientID(c.clientID).
+		SetConnectTimeout(c.timeout).
+		SetAutoReconnect(false).
+		SetConnectRetry(false).
+		SetCleanSession(true)
+	if c.username != "" {
+		opts.SetUsername(c.username)
+		opts.SetPassword(c.password)
+	}
+	if c.insecureSkipVerify {
+		opts.SetTLSConfig(&tls.Config{InsecureSkipVerify: true})
+	}
+
+	client := paho.NewClient(opts)
+
+	connectStart := time.Now()
+	if err := c.wait(ctx, client.Connect()); err != nil {
+		result.IsHealthy = false
+		result.Severity = collectors.SeverityCritical
+		if isAuthError(err) {
+			result.Message = fmt.Sprintf("MQTT authentication to %s failed: %v", c.broker, err)
+		} else {
+			result.Message = fmt.Sprintf("MQTT connection to %s failed: %v", c.broker, err)
+		}
+		c.logger.Warn("MQTT connect failed", zap.String("broker", c.broker), zap.Error(err))
+		return []collectors.Result{result}, nil
+	}
+	defer client.Disconnect(250)
+
+	result.Metrics["connected"] = 1
+	result.Metrics["connect_ms"] = float64(time.Since(connectStart).Microseconds()) / 1000
+
+	// Subscribe to the canary topic and wait for our own payload to come back
+	nonce, err := newNonce()
+	if err != nil {
+		c.logger.Error("Failed to generate canary payload", zap.Error(err))
+		return nil, err
+	}
+
+	received := make(chan time.Time, 1)
+	handler := func(_ paho.Client, msg paho.Message) {
+		if string(msg.Payload()) == nonce {
+			select {
+			case received <- time.Now():
+			default:
+			}
+		}
+	}
+
+	if err := c.wait(ctx, client.Subscribe(c.topic, c.qos, handler)); err != nil {
+		result.IsHealthy = false
+		result.Severity = collectors.SeverityCritical
+		result.Message = fmt.Sprintf("MQTT subscribe to %s on %s failed: %v", c.topic, c.broker, err)
+		return []collectors.Result{result}, nil
+	}
+	defer client.Unsubscribe(c.topic)
+
+	publishedAt := time.Now()
+	if err := c.wait(ctx, client.Publish(c.topic, c.qos, false, nonce)); err != nil {
+		result.IsHealthy = false
+		result.Severity = collectors.SeverityCritical
+		result.Message = fmt.Sprintf("MQTT publish to %s on %s failed: %v", c.topic, c.broker, err)
+		return []collectors.Result{result}, nil
+	}
+
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+		result.IsHealthy = false
+		result.Severity = collectors.SeverityCritical
+		result.Message = fmt.Sprintf("MQTT canary message on %s was not received from %s within %s",
+			c.topic, c.broker, c.timeout)
+	case at := <-received:
+		roundTripMs := float64(at.Sub(publishedAt).Microseconds()) / 1000
+		result.Metrics["round_trip_ms"] = roundTripMs
+
+		// The evaluator alerts on the latency threshold
+		result.Thresholds[0].Message = fmt.Sprintf("High MQTT round-trip latency on %s: %.2fms (threshold: %.2fms)",
+			c.broker, roundTripMs, c.latencyThresholdMs)
+	}
+
+	c.logger.Info("MQTT metrics collected", zap.Any("result", result))
+	return []collectors.Result{result}, nil
+}
+
+// wait blocks until the token completes, the timeout expires or the context is cancelled
+func (c *MQTTCollector) wait(ctx context.Context, token paho.Token) error {
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-timer.C:
+		return fmt.Errorf("timed out after %s", c.timeout)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// isAuthError reports whether the broker refused the connection due to credentials
+func isAuthError(err error) bool {
+	return errors.Is(err, packets.ErrorRefusedBadUsernameOrPassword) ||
+		errors.Is(err, packets.ErrorRefusedNotAuthorised)
+}
+
+// newNonce returns a random payload used to recognise our own canary message
+func newNonce() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Cleanup performs any necessary cleanup
+func (c *MQTTCollector) Cleanup() error {
+	// Connections are opened per collection, nothing to clean up
+	return nil
+}
@@ -0,0 +1,299 @@
+// collectors/plugin/plugin.go
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"go.uber.org/zap"
+)
+
+// Timeouts of the messages other than collect, which uses the run's context
+const (
+	initTimeout    = 10 * time.Second
+	cleanupTimeout = 5 * time.Second
+)
+
+// maxMessageBytes bounds a single response line from a plugin
+const maxMessageBytes = 4 << 20
+
+// request is a message sent to a plugin, one JSON object per line on stdin
+type request struct {
+	Type      string                 `json:"type"` // "init", "collect" or "cleanup"
+	Collector string                 `json:"collector"`
+	Settings  map[string]interface{} `json:"settings,omitempty"`
+}
+
+// response is a plugin's answer to a request, one JSON object per line on
+// stdout
+type response struct {
+	Results []collectors.Result `json:"results,omitempty"`
+	Error   string              `json:"error,omitempty"`
+}
+
+// PluginCollector runs an external program as a collector. The program is
+// started on Init and kept running; each request is answered with one line of
+// JSON. A program that exits or stops answering is restarted, with a fresh
+// init, on the next run, so a crashing plugin cannot take the monitor down.
+type PluginCollector struct {
+	name     string
+	command  string
+	args     []string
+	settings map[string]interface{}
+	process  *process
+	mu       sync.Mutex
+	logger   *zap.Logger
+}
+
+// process is a running plugin program
+type process struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	responses chan []byte // Closed when stdout ends
+}
+
+// NewPluginCollector creates a plugin collector registered under name
+func NewPluginCollector(logger *zap.Logger, name string) *PluginCollector {
+	return &PluginCollector{name: name, logger: logger}
+}
+
+// Name returns the name of the collector
+func (c *PluginCollector) Name() string {
+	return c.name
+}
+
+// Schema describes the collector and its settings
+func (c *PluginCollector) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Results of an external plugin program",
+		Settings: []collectors.Setting{
+			{Name: "command", Type: "string", Required: true, Description: "Path of the plugin executable"},
+			{Name: "args", Type: "list", Description: "Arguments passed to the plugin"},
+		},
+		Open: true,
+	}
+}
+
+// Validate checks that the command is set and can be found, without starting
+// it; the program's own settings are only checked by the program on init
+func (c *PluginCollector) Validate(settings map[string]interface{}) error {
+	command := collectors.GetString(settings, "command", "")
+	if command == "" {
+		return fmt.Errorf("plugin collector %s has no command", c.name)
+	}
+	if _, err := exec.LookPath(command); err != nil {
+		return fmt.Errorf("invalid 'command': %w", err)
+	}
+	return nil
+}
+
+// Init starts the plugin program and sends it the collector's settings. The
+// command and args settings are filled in from the collector's configuration;
+// the program receives the other settings.
+func (c *PluginCollector) Init(settings map[string]interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.command = collectors.GetString(settings, "command", "")
+	if c.command == "" {
+		err := fmt.Errorf("plugin collector %s has no command", c.name)
+		c.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+	c.args = nil
+	if raw, ok := settings["args"].([]interface{}); ok {
+		for _, arg := range raw {
+			c.args = append(c.args, fmt.Sprint(arg))
+		}
+	}
+	c.settings = make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		if key != "command" && key != "args" {
+			c.settings[key] = value
+		}
+	}
+
+	// Re-initialization starts a fresh program with the new settings
+	c.stop()
+	if err := c.start(); err != nil {
+		c.logger.Error("Init error", zap.String("command", c.command), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Collect asks the plugin for results. The collector name and timestamp are
+// filled in when the plugin leaves them out.
+func (c *PluginCollector) Collect(ctx context.Context) ([]collectors.Result, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.process == nil {
+		c.logger.Warn("Restarting plugin", zap.String("command", c.command))
+		if err := c.start(); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := c.request(ctx, request{Type: "collect", Collector: c.name})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("plugin %s: %s", c.name, resp.Error)
+	}
+
+	now := time.Now()
+	for i := range resp.Results {
+		if resp.Results[i].Collector == "" {
+			resp.Results[i].Collector = c.name
+		}
+		if resp.Results[i].Timestamp.IsZero() {
+			resp.Results[i].Timestamp = now
+		}
+		if resp.Results[i].Metrics == nil {
+			resp.Results[i].Metrics = map[string]float64{}
+		}
+	}
+	return resp.Results, nil
+}
+
+// Cleanup asks the plugin to clean up and stops it
+func (c *PluginCollector) Cleanup() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.process == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+	defer cancel()
+
+	var err error
+	if resp, rerr := c.request(ctx, request{Type: "cleanup", Collector: c.name}); rerr != nil {
+		err = rerr
+	} else if resp.Error != "" {
+		err = fmt.Errorf("plugin %s: %s", c.name, resp.Error)
+	}
+	c.stop()
+	return err
+}
+
+// start starts the program and initializes it; c.mu must be held
+func (c *PluginCollector) start() error {
+	cmd := exec.Command(c.command, c.args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start plugin %s: %w", c.command, err)
+	}
+
+	p := &process{cmd: cmd, stdin: stdin, responses: make(chan []byte)}
+	go func() {
+		defer close(p.responses)
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), maxMessageBytes)
+		for scanner.Scan() {
+			p.responses <- append([]byte(nil), scanner.Bytes()...)
+		}
+	}()
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			c.logger.Warn("Plugin stderr", zap.String("collector", c.name), zap.String("line", scanner.Text()))
+		}
+	}()
+	c.process = p
+
+	ctx, cancel := context.WithTimeout(context.Background(), initTimeout)
+	defer cancel()
+	resp, err := c.request(ctx, request{Type: "init", Collector: c.name, Settings: c.settings})
+	if err != nil {
+		return err
+	}
+	if resp.Error != "" {
+		c.stop()
+		return fmt.Errorf("plugin %s failed to initialize: %s", c.name, resp.Error)
+	}
+	return nil
+}
+
+// request sends a message and waits for the answer. A plugin that exits, does
+// not answer in time or answers garbage is stopped, as its replies can no
+// longer be matched to requests. c.mu must be held.
+func (c *PluginCollector) request(ctx context.Context, req request) (response, error) {
+	p := c.process
+	line, err := json.Marshal(req)
+	if err != nil {
+		return response{}, err
+	}
+	if _, err := p.stdin.Write(append(line, '\n')); err != nil {
+		c.stop()
+		return response{}, fmt.Errorf("plugin %s is not running: %w", c.name, err)
+	}
+
+	select {
+	case <-ctx.Done():
+		c.stop()
+		return response{}, fmt.Errorf("plugin %s did not answer %s: %w", c.name, req.Type, ctx.Err())
+	case raw, ok := <-p.responses:
+		if !ok {
+			c.stop()
+			return response{}, fmt.Errorf("plugin %s exited", c.name)
+		}
+		var resp response
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			c.stop()
+			return response{}, fmt.Errorf("plugin %s sent an invalid %s response: %w", c.name, req.Type, err)
+		}
+		return resp, nil
+	}
+}
+
+// stop closes the program's stdin and kills it if it does not exit; c.mu
+// must be held
+func (c *PluginCollector) stop() {
+	p := c.process
+	if p == nil {
+		return
+	}
+	c.process = nil
+
+	// Drain stdout so the reader finishes, as Wait requires
+	go func() {
+		for range p.responses {
+		}
+	}()
+
+	p.stdin.Close()
+	exited := make(chan error, 1)
+	go func() { exited <- p.cmd.Wait() }()
+	select {
+	case err := <-exited:
+		if err != nil {
+			c.logger.Warn("Plugin exited", zap.String("collector", c.name), zap.Error(err))
+		}
+	case <-time.After(cleanupTimeout):
+		p.cmd.Process.Kill()
+		<-exited
+		c.logger.Warn("Killed plugin that did not exit", zap.String("collector", c.name))
+	}
+}
@@ -0,0 +1,208 @@
+// collectors/selfmonitor/selfmonitor.go
+package selfmonitor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"github.com/shirou/gopsutil/v3/process"
+	"go.uber.org/zap"
+)
+
+// CollectionStats describes the runs of one collector since startup
+type CollectionStats struct {
+	Runs              int
+	Failures          int
+	LastRun           time.Time
+	LastDuration      time.Duration
+	LastError         string
+	ConsecutiveErrors int
+}
+
+// NotificationStats counts one notifier's deliveries since startup
+type NotificationStats struct {
+	Sent     int
+	Failures int
+}
+
+// Snapshot is the monitor's own health at one point in time
+type Snapshot struct {
+	Collections   map[string]CollectionStats
+	Notifications map[string]NotificationStats
+}
+
+// Source provides the monitor's health, e.g. the monitor service itself
+type Source interface {
+	Snapshot() Snapshot
+}
+
+// SelfMonitorCollector implements the Collector interface for the monitor's
+// own health, so a degrading watcher is noticed too
+type SelfMonitorCollector struct {
+	source                  Source
+	maxGoroutines           float64
+	maxRSSMB                float64
+	maxConsecutiveErrors    float64
+	maxCollectionMs         float64
+	maxNotificationFailures float64
+	lastFailures            map[string]int
+	logger                  *zap.Logger
+}
+
+// NewSelfMonitorCollector creates a new self-monitoring collector reading
+// the monitor's stats from source
+func NewSelfMonitorCollector(logger *zap.Logger, source Source) *SelfMonitorCollector {
+	return &SelfMonitorCollector{
+		source: source,
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (c *SelfMonitorCollector) Name() string {
+	return "selfmonitor"
+}
+
+// Schema describes the collector and its settings
+func (c *SelfMonitorCollector) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Health of the monitor itself",
+		Settings: []collectors.Setting{
+			{Name: "max_goroutines", Type: "float", Default: "1000", Description: "Alert when more goroutines are running"},
+			{Name: "max_rss_mb", Type: "float", Default: "512", Description: "Alert when the resident memory grows beyond this many MB"},
+			{Name: "max_collection_ms", Type: "float", Default: "20000", Description: "Alert when a collection cycle takes longer"},
+			{Name: "max_consecutive_errors", Type: "float", Default: "3", Description: "Alert when a collector fails this many times in a row"},
+			{Name: "max_notification_failures", Type: "float", Default: "0", Description: "Alert when more notifications failed since the last cycle (0 disables)"},
+		},
+	}
+}
+
+// Init initializes the collector with configuration
+func (c *SelfMonitorCollector) Init(settings map[string]interface{}) error {
+	c.maxGoroutines = collectors.GetFloat(settings, "max_goroutines", 1000)
+	c.maxRSSMB = collectors.GetFloat(settings, "max_rss_mb", 512)
+	c.maxConsecutiveErrors = collectors.GetFloat(settings, "max_consecutive_errors", 3)
+	c.maxCollectionMs = collectors.GetFloat(settings, "max_collection_ms", 20000)
+	c.maxNotificationFailures = collectors.GetFloat(settings, "max_notification_failures", 0)
+	c.lastFailures = make(map[string]int)
+	return nil
+}
+
+// Collect reports the process health, then one result per collector and per
+// notifier the monitor has stats for
+func (c *SelfMonitorCollector) Collect(ctx context.Context) ([]collectors.Result, error) {
+	// Check if context is cancelled
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+		// Continue processing
+	}
+
+	now := time.Now()
+	results := []collectors.Result{c.processResult(now)}
+
+	snapshot := c.source.Snapshot()
+	for _, name := range sortedKeys(snapshot.Collections) {
+		results = append(results, c.collectionResult(name, snapshot.Collections[name], now))
+	}
+	for _, name := range sortedKeys(snapshot.Notifications) {
+		results = append(results, c.notificationResult(name, snapshot.Notifications[name], now))
+	}
+
+	c.logger.Info("Self-monitoring metrics collected", zap.Int("results", len(results)))
+	return results, nil
+}
+
+// processResult reports the goroutine count and resident memory of the monitor
+func (c *SelfMonitorCollector) processResult(now time.Time) collectors.Result {
+	metrics := map[string]float64{
+		"goroutines": float64(runtime.NumGoroutine()),
+	}
+	if proc, err := process.NewProcess(int32(os.Getpid())); err == nil {
+		if mem, err := proc.MemoryInfo(); err == nil {
+			metrics["rss_mb"] = float64(mem.RSS) / (1024 * 1024)
+		}
+	}
+
+	return describe(collectors.Result{
+		Collector: c.Name(),
+		Timestamp: now,
+		Metrics:   metrics,
+		Thresholds: []collectors.Threshold{
+			{Type: "absolute", Metric: "goroutines", Operator: "greater_than", Value: c.maxGoroutines, Severity: "warning"},
+			{Type: "absolute", Metric: "rss_mb", Operator: "greater_than", Value: c.maxRSSMB, Severity: "warning"},
+		},
+		Metadata: map[string]interface{}{"component": "process"},
+	}, "Monitor process")
+}
+
+// collectionResult reports a collector's last run duration and errors
+func (c *SelfMonitorCollector) collectionResult(name string, stats CollectionStats, now time.Time) collectors.Result {
+	return describe(collectors.Result{
+		Collector: c.Name(),
+		Timestamp: now,
+		Metrics: map[string]float64{
+			"duration_ms":        float64(stats.LastDuration.Microseconds()) / 1000,
+			"consecutive_errors": float64(stats.ConsecutiveErrors),
+		},
+		Thresholds: []collectors.Threshold{
+			{Type: "absolute", Metric: "duration_ms", Operator: "greater_than", Value: c.maxCollectionMs, Severity: "warning"},
+			{Type: "absolute", Metric: "consecutive_errors", Operator: "greater_than", Value: c.maxConsecutiveErrors, Severity: "critical"},
+		},
+		Metadata: map[string]interface{}{"component": "collector", "name": name},
+	}, "Collector "+name)
+}
+
+// notificationResult reports a notifier's failures since the previous run
+func (c *SelfMonitorCollector) notificationResult(name string, stats NotificationStats, now time.Time) collectors.Result {
+	recent := stats.Failures - c.lastFailures[name]
+	c.lastFailures[name] = stats.Failures
+
+	return describe(collectors.Result{
+		Collector: c.Name(),
+		Timestamp: now,
+		Metrics: map[string]float64{
+			"sent":            float64(stats.Sent),
+			"failures":        float64(stats.Failures),
+			"recent_failures": float64(recent),
+		},
+		Thresholds: []collectors.Threshold{
+			{Type: "absolute", Metric: "recent_failures", Operator: "greater_than", Value: c.maxNotificationFailures, Severity: "warning"},
+		},
+		Metadata: map[string]interface{}{"component": "notifier", "name": name},
+	}, "Notifier "+name)
+}
+
+// describe sets each threshold's message, naming the subject, and declares
+// the result healthy; the evaluator decides from the thresholds
+func describe(result collectors.Result, subject string) collectors.Result {
+	for i, threshold := range result.Thresholds {
+		result.Thresholds[i].Message = fmt.Sprintf("%s degraded: %s %.0f (threshold: %.0f)",
+			subject, threshold.Metric, result.Metrics[threshold.Metric], threshold.Value)
+	}
+	result.IsHealthy = true
+	return result
+}
+
+// sortedKeys returns the map's keys in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Cleanup performs any necessary cleanup
+func (c *SelfMonitorCollector) Cleanup() error {
+	// No cleanup needed for self-monitoring collector
+	return nil
+}
@@ -0,0 +1,287 @@
+// collectors/settings.go
+package collectors
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetString returns the string setting for key, or def if it is missing
+func GetString(settings map[string]interface{}, key, def string) string {
+	if val, ok := settings[key].(string); ok {
+		return val
+	}
+	return def
+}
+
+// GetFloat returns the numeric setting for key as a float64, or def if it is missing.
+// YAML decodes whole numbers as int, so both int and float64 values are accepted.
+func GetFloat(settings map[string]interface{}, key string, def float64) float64 {
+	switch val := settings[key].(type) {
+	case float64:
+		return val
+	case int:
+		return float64(val)
+	case int64:
+		return float64(val)
+	}
+	return def
+}
+
+// GetOptionalFloat returns the numeric setting for key as a float64, or nil if it is missing
+func GetOptionalFloat(settings map[string]interface{}, key string) *float64 {
+	if _, exists := settings[key]; !exists {
+		return nil
+	}
+	val := GetFloat(settings, key, 0)
+	return &val
+}
+
+// GetInt returns the numeric setting for key as an int, or def if it is missing
+func GetInt(settings map[string]interface{}, key string, def int) int {
+	switch val := settings[key].(type) {
+	case int:
+		return val
+	case int64:
+		return int(val)
+	case float64:
+		return int(val)
+	}
+	return def
+}
+
+// GetBool returns the boolean setting for key, or def if it is missing
+func GetBool(settings map[string]interface{}, key string, def bool) bool {
+	if val, ok := settings[key].(bool); ok {
+		return val
+	}
+	return def
+}
+
+// GetStringSlice returns the list setting for key as strings, or def if it is missing
+func GetStringSlice(settings map[string]interface{}, key string, def []string) ([]string, error) {
+	raw, exists := settings[key]
+	if !exists {
+		return def, nil
+	}
+
+	switch val := raw.(type) {
+	case []string:
+		return val, nil
+	case []interface{}:
+		out := make([]string, 0, len(val))
+		for _, item := range val {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("'%s' should be an array of strings", key)
+			}
+			out = append(out, s)
+		}
+		return out, nil
+	}
+
+	return nil, fmt.Errorf("'%s' should be an array of strings", key)
+}
+
+// GetSecret returns a credential setting: the string setting for key, or
+// the contents of the file named by key_file, e.g. a Docker or Kubernetes
+// secret mount. Setting both is an error.
+func GetSecret(settings map[string]interface{}, key string) (string, error) {
+	value := GetString(settings, key, "")
+	path := GetString(settings, key+"_file", "")
+	if path == "" {
+		return value, nil
+	}
+	if value != "" {
+		return "", fmt.Errorf("only one of '%s' and '%s_file' may be set", key, key)
+	}
+	secret, err := ReadSecretFile(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid '%s_file': %w", key, err)
+	}
+	return secret, nil
+}
+
+// ReadSecretFile reads a secret from a file, without the surrounding
+// whitespace, such as the trailing newline, secret files often have
+func ReadSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	secret := strings.TrimSpace(string(data))
+	if secret == "" {
+		return "", fmt.Errorf("%s is empty", path)
+	}
+	return secret, nil
+}
+
+// Setting describes a key a component accepts in its settings
+type Setting struct {
+	Name        string    `json:"name"`
+	Type        string    `json:"type"` // string, int, float, bool, duration, list or map
+	Default     string    `json:"default,omitempty"`
+	Required    bool      `json:"required,omitempty"`
+	Description string    `json:"description"`
+	Fields      []Setting `json:"fields,omitempty"` // Keys of each entry of a list of objects
+}
+
+// Schema describes a component and the settings it accepts
+type Schema struct {
+	Description string    `json:"description"`
+	Settings    []Setting `json:"settings"`
+	Open        bool      `json:"-"` // Settings not listed are accepted too, e.g. a plugin program's own
+}
+
+// Describer is implemented by collectors, notifiers and outputs that
+// describe the settings they accept, for the list command and to reject
+// unknown settings
+type Describer interface {
+	Schema() Schema
+}
+
+// UnknownSettings returns an error naming the settings a closed schema
+// doesn't list, including the keys of entries of lists of objects, or nil
+func UnknownSettings(schema Schema, settings map[string]interface{}) error {
+	if schema.Open {
+		return nil
+	}
+	var unknown []string
+	unknownKeys(schema.Settings, settings, "", &unknown)
+	if len(unknown) == 0 {
+		return nil
+	}
+	if len(unknown) == 1 {
+		return fmt.Errorf("unknown setting %s", unknown[0])
+	}
+	return fmt.Errorf("unknown settings %s", strings.Join(unknown, ", "))
+}
+
+// unknownKeys appends the keys of settings not listed in known, with their
+// place below prefix and the closest known key if one looks like a typo
+func unknownKeys(known []Setting, settings map[string]interface{}, prefix string, unknown *[]string) {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		setting, exists := findSetting(known, key)
+		if !exists {
+			message := fmt.Sprintf("'%s%s'", prefix, key)
+			if suggestion := closestSetting(known, key); suggestion != "" {
+				message += fmt.Sprintf(" (did you mean '%s'?)", suggestion)
+			}
+			*unknown = append(*unknown, message)
+			continue
+		}
+		if len(setting.Fields) == 0 {
+			continue
+		}
+		entries, _ := settings[key].([]interface{})
+		for i, entry := range entries {
+			if fields, ok := entry.(map[string]interface{}); ok {
+				unknownKeys(setting.Fields, fields, fmt.Sprintf("%s%s[%d].", prefix, key, i), unknown)
+			}
+		}
+	}
+}
+
+// findSetting returns the setting named key
+func findSetting(known []Setting, key string) (Setting, bool) {
+	for _, setting := range known {
+		if setting.Name == key {
+			return setting, true
+		}
+	}
+	return Setting{}, false
+}
+
+// closestSetting returns the known setting within two edits of key, or ""
+func closestSetting(known []Setting, key string) string {
+	best, bestDistance := "", 3
+	for _, setting := range known {
+		if d := editDistance(key, setting.Name); d < bestDistance {
+			best, bestDistance = setting.Name, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+// GetDuration returns the setting for key as a duration: a number of unit,
+// or a duration string such as "30s", "5m" or "1h". It returns def if the
+// setting is missing.
+func GetDuration(settings map[string]interface{}, key string, unit, def time.Duration) (time.Duration, error) {
+	switch val := settings[key].(type) {
+	case nil:
+		return def, nil
+	case int:
+		return time.Duration(val) * unit, nil
+	case int64:
+		return time.Duration(val) * unit, nil
+	case float64:
+		return time.Duration(val * float64(unit)), nil
+	case string:
+		d, err := ParseDuration(val, unit)
+		if err != nil {
+			return 0, fmt.Errorf("'%s': %w", key, err)
+		}
+		return d, nil
+	}
+	return 0, fmt.Errorf("'%s' should be a number or a duration such as 30s", key)
+}
+
+// ParseDuration parses a number of unit, or a duration string accepted by
+// time.ParseDuration such as "30s", "5m" or "1h"
+func ParseDuration(raw string, unit time.Duration) (time.Duration, error) {
+	raw = strings.TrimSpace(raw)
+	if n, err := strconv.Atoi(raw); err == nil {
+		return time.Duration(n) * unit, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q, expected a number of %s or a duration such as 30s, 5m or 1h", raw, UnitName(unit))
+	}
+	return d, nil
+}
+
+// UnitName returns the plural name of a duration unit, e.g. "seconds"
+func UnitName(unit time.Duration) string {
+	switch unit {
+	case time.Millisecond:
+		return "milliseconds"
+	case time.Second:
+		return "seconds"
+	case time.Minute:
+		return "minutes"
+	case time.Hour:
+		return "hours"
+	}
+	return unit.String()
+}
@@ -2,11 +2,23 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"gopkg.in/yaml.v3"
 )
 
@@ -15,38 +27,639 @@ type Config struct {
 	Monitor       MonitorConfig              `yaml:"monitor"`
 	Collectors    map[string]CollectorConfig `yaml:"collectors"`
 	Notifications NotificationsConfig        `yaml:"notifications"`
+	Outputs       map[string]OutputConfig    `yaml:"outputs"`
+	Mutes         MutesConfig                `yaml:"mutes"`
+	InhibitRules  []InhibitRule              `yaml:"inhibit_rules,omitempty"`
+	Maintenance   MaintenanceConfig          `yaml:"maintenance,omitempty"`
+	Routes        []Route                    `yaml:"routes,omitempty"`
+	Hosts         map[string]HostConfig      `yaml:"hosts,omitempty"`
+	API           APIConfig                  `yaml:"api,omitempty"`
+	GRPC          GRPCConfig                 `yaml:"grpc,omitempty"`
+	Debug         DebugConfig                `yaml:"debug,omitempty"`
+	History       HistoryConfig              `yaml:"history,omitempty"`
+	Secrets       SecretsConfig              `yaml:"secrets,omitempty"`
+	Logging       LoggingConfig              `yaml:"logging,omitempty"`
+}
+
+// LoggingConfig contains settings for the service's own logs
+type LoggingConfig struct {
+	Level            string          `yaml:"level,omitempty"`              // debug, info, warn or error. Default: info
+	Encoding         string          `yaml:"encoding,omitempty"`           // json or console. Default: json
+	OutputPaths      []string        `yaml:"output_paths,omitempty"`       // stdout, stderr or files. Default: stderr, or none with a log file
+	ErrorOutputPaths []string        `yaml:"error_output_paths,omitempty"` // For the logger's own errors. Default: stderr
+	Sampling         LoggingSampling `yaml:"sampling,omitempty"`
+	File             LoggingFile     `yaml:"file,omitempty"` // Also log to a rotated file
+}
+
+// LoggingFile is a log file rotated by size, with old files removed by age
+// and count
+type LoggingFile struct {
+	Path       string `yaml:"path,omitempty"`
+	MaxSizeMB  int    `yaml:"max_size_mb,omitempty"`  // Rotate when the file grows beyond this. Default: 100
+	MaxAgeDays int    `yaml:"max_age_days,omitempty"` // Remove rotated files older than this. Default: keep them
+	MaxBackups int    `yaml:"max_backups,omitempty"`  // Rotated files to keep. Default: all
+	Compress   bool   `yaml:"compress,omitempty"`     // Gzip rotated files
+}
+
+// LoggingSampling limits repeated log messages: each second, the first
+// Initial entries with the same level and message are logged, then every
+// Thereafter-th
+type LoggingSampling struct {
+	Disabled   bool `yaml:"disabled,omitempty"`
+	Initial    int  `yaml:"initial,omitempty"`    // Default: 100
+	Thereafter int  `yaml:"thereafter,omitempty"` // Default: 100
+}
+
+// SecretsConfig contains the secrets providers that secret://<provider>/...
+// references in collector, notifier and output settings are resolved from
+type SecretsConfig struct {
+	RefreshSeconds Seconds     `yaml:"refresh_seconds,omitempty"` // How often referenced secrets are read again. Default: 300
+	Vault          VaultConfig `yaml:"vault,omitempty"`
+}
+
+// VaultConfig contains settings for reading secrets from HashiCorp Vault
+type VaultConfig struct {
+	Enabled            bool    `yaml:"enabled"`
+	Address            string  `yaml:"address,omitempty"`    // Default: $VAULT_ADDR
+	Token              string  `yaml:"token,omitempty"`      // Default: $VAULT_TOKEN
+	TokenFile          string  `yaml:"token_file,omitempty"` // e.g. written by Vault Agent
+	Namespace          string  `yaml:"namespace,omitempty"`
+	KVVersion          int     `yaml:"kv_version,omitempty"` // 1 or 2. Default: 2
+	CAFile             string  `yaml:"ca_file,omitempty"`
+	InsecureSkipVerify bool    `yaml:"insecure_skip_verify,omitempty"`
+	TimeoutSeconds     Seconds `yaml:"timeout_seconds,omitempty"`
+}
+
+// HistoryConfig contains settings for storing every result in an embedded
+// database
+type HistoryConfig struct {
+	Enabled             bool     `yaml:"enabled"`
+	Path                string   `yaml:"path,omitempty"`
+	RetentionHours      Hours    `yaml:"retention_hours,omitempty"`
+	AvailabilityWindows []string `yaml:"availability_windows,omitempty"` // e.g. 24h, 7d, 30d
+}
+
+// ParseWindow parses a period such as 90m, 24h or 30d: a Go duration, or a
+// whole number of days
+func ParseWindow(raw string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(raw, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid window '%s'", raw)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid window '%s', expected e.g. 24h or 7d", raw)
+	}
+	return d, nil
+}
+
+// APIConfig contains settings for the HTTP API, e.g. the push endpoint for
+// results of external checks
+type APIConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	Listen    string `yaml:"listen,omitempty"`     // Default: 127.0.0.1:8080
+	Token     string `yaml:"token,omitempty"`      // Bearer token required on every request
+	TokenFile string `yaml:"token_file,omitempty"` // File to read the token from instead
+
+	ReadyFailures int `yaml:"ready_failures,omitempty"` // Consecutive failed runs of a collector that fail /readyz. Default: 3
+}
+
+// GRPCConfig contains settings for the gRPC API, the gRPC counterpart of
+// the HTTP API's status, control and streaming endpoints
+type GRPCConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	Listen    string `yaml:"listen,omitempty"`     // Default: 127.0.0.1:9090
+	Token     string `yaml:"token,omitempty"`      // Bearer token required in the authorization metadata
+	TokenFile string `yaml:"token_file,omitempty"` // File to read the token from instead
+}
+
+// DebugConfig contains settings for the pprof and expvar debug listener
+type DebugConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Listen  string `yaml:"listen,omitempty"` // Default: 127.0.0.1:6060
+}
+
+// HostConfig is a remote machine that collectors can check over SSH by
+// listing its name in their "hosts" setting
+type HostConfig struct {
+	Address               string  `yaml:"address"` // host or host:port (default port 22)
+	User                  string  `yaml:"user"`
+	KeyFile               string  `yaml:"key_file"`
+	KnownHostsFile        string  `yaml:"known_hosts_file,omitempty"` // Default: ~/.ssh/known_hosts
+	InsecureIgnoreHostKey bool    `yaml:"insecure_ignore_host_key,omitempty"`
+	TimeoutSeconds        Seconds `yaml:"timeout_seconds,omitempty"`
 }
 
 // MonitorConfig contains global monitoring settings
 type MonitorConfig struct {
-	DefaultIntervalSeconds int `yaml:"default_interval_seconds"`
+	DefaultIntervalSeconds Seconds           `yaml:"default_interval_seconds"`
+	MaxAlertLatencySeconds Seconds           `yaml:"max_alert_latency_seconds,omitempty"`
+	OnCheckRemoved         string            `yaml:"on_check_removed,omitempty"`
+	MaxSeriesPerCollector  int               `yaml:"max_series_per_collector,omitempty"`
+	RenotifyAfterSeconds   Seconds           `yaml:"renotify_after_seconds,omitempty"`
+	SplaySeconds           Seconds           `yaml:"splay_seconds,omitempty"`
+	HeartbeatSeconds       Seconds           `yaml:"heartbeat_seconds,omitempty"`   // Interval of "all OK" notifications
+	HeartbeatNotifiers     []string          `yaml:"heartbeat_notifiers,omitempty"` // Notifiers receiving them
+	Hostname               string            `yaml:"hostname,omitempty"`            // Shown in place of the system's, e.g. db-primary-eu
+	Tags                   map[string]string `yaml:"tags,omitempty"`                // Stamped on every result, with the hostname
+	RunAudit               RunAuditConfig    `yaml:"run_audit,omitempty"`
+	Mode                   string            `yaml:"mode,omitempty"`                   // host, container or auto
+	ShutdownGraceSeconds   Seconds           `yaml:"shutdown_grace_seconds,omitempty"` // Longest a shutdown may take; 0 waits for it to finish
+}
+
+// RunAuditConfig records every collector run, with when it started, how
+// long it took, how many results it produced and any error
+type RunAuditConfig struct {
+	Log     bool   `yaml:"log,omitempty"`     // Log each run, as the "runs" logger
+	Path    string `yaml:"path,omitempty"`    // JSON lines file each run is appended to, rotated at 100 MB
+	History bool   `yaml:"history,omitempty"` // Store each run in the result history
 }
 
 // CollectorConfig represents a generic collector configuration
 type CollectorConfig struct {
+	Enabled              bool                   `yaml:"enabled"`
+	Type                 string                 `yaml:"type,omitempty"`    // "plugin" runs Command as an external collector, or a type registered with collectors.RegisterFactory; empty for built-in collectors
+	Command              string                 `yaml:"command,omitempty"` // Plugin program
+	Args                 []string               `yaml:"args,omitempty"`    // Plugin program arguments
+	Interval             Seconds                `yaml:"interval_seconds,omitempty"`
+	Schedule             string                 `yaml:"schedule,omitempty"` // Cron expression used instead of the interval
+	MaxSeries            int                    `yaml:"max_series,omitempty"`
+	RenotifyAfterSeconds Seconds                `yaml:"renotify_after_seconds,omitempty"`
+	SplaySeconds         Seconds                `yaml:"splay_seconds,omitempty"`
+	ForSeconds           Seconds                `yaml:"for_seconds,omitempty"` // How long a condition must hold before alerting
+	DependsOn            []Dependency           `yaml:"depends_on,omitempty"`
+	Thresholds           []ThresholdConfig      `yaml:"thresholds,omitempty"`
+	Anomaly              []AnomalyConfig        `yaml:"anomaly,omitempty"`
+	Tags                 map[string]string      `yaml:"tags,omitempty"` // Stamped on the collector's results, e.g. the owning team
+	Settings             map[string]interface{} `yaml:"settings,omitempty"`
+}
+
+// CollectorTypePlugin is the collector type of external plugin programs
+const CollectorTypePlugin = "plugin"
+
+// ThresholdConfig declares a threshold on a metric of a collector, evaluated
+// centrally for every result of the collector that reports the metric, in
+// addition to the collector's own thresholds
+type ThresholdConfig struct {
+	Metric   string   `yaml:"metric"`
+	Operator string   `yaml:"operator"` // less_than, greater_than, equals, between, outside_range or rate_of_change
+	Value    float64  `yaml:"value,omitempty"`
+	Min      *float64 `yaml:"min,omitempty"` // Range bounds for between and outside_range
+	Max      *float64 `yaml:"max,omitempty"`
+	Clear    *float64 `yaml:"clear,omitempty"` // Clear level for less_than and greater_than, see hysteresis
+	Severity string   `yaml:"severity,omitempty"`
+}
+
+// AnomalyConfig enables anomaly detection for one metric of a collector. A
+// baseline of the metric is learned per target, and a value deviating from it
+// by more than Sigma standard deviations makes the result unhealthy.
+type AnomalyConfig struct {
+	Metric     string  `yaml:"metric"`
+	Method     string  `yaml:"method,omitempty"` // "rolling" (default) or "ewma"
+	Window     int     `yaml:"window,omitempty"` // Samples in the rolling window (default: 100)
+	Alpha      float64 `yaml:"alpha,omitempty"`  // EWMA smoothing factor (default: 0.1)
+	Sigma      float64 `yaml:"sigma,omitempty"`  // Deviation in standard deviations (default: 3)
+	MinSamples int     `yaml:"min_samples,omitempty"`
+	Direction  string  `yaml:"direction,omitempty"` // "above", "below" or "both" (default)
+	Seasonal   bool    `yaml:"seasonal,omitempty"`  // Separate baselines per weekday and hour
+	Severity   string  `yaml:"severity,omitempty"`
+}
+
+// Dependency names a check another check relies on. While the dependency has
+// an active alert, with equal values for the Equal labels, the dependent
+// check's notifications are suppressed as a consequence of the same cause.
+type Dependency struct {
+	Collector string   `yaml:"collector"`
+	Equal     []string `yaml:"equal,omitempty"`
+}
+
+// OutputConfig represents a generic result output configuration
+type OutputConfig struct {
 	Enabled  bool                   `yaml:"enabled"`
-	Interval int                    `yaml:"interval_seconds,omitempty"`
 	Settings map[string]interface{} `yaml:"settings,omitempty"`
 }
 
+// MutesConfig contains rules that mute notifications for known-noisy conditions
+type MutesConfig struct {
+	File  string     `yaml:"file,omitempty"`
+	Rules []MuteRule `yaml:"rules,omitempty"`
+}
+
+// MuteRule mutes notifications for matching results until it expires.
+// All non-empty matchers must match for the rule to apply.
+type MuteRule struct {
+	ID           string            `yaml:"id" json:"id"`
+	Collector    string            `yaml:"collector,omitempty" json:"collector,omitempty"`
+	MessageRegex string            `yaml:"message_regex,omitempty" json:"message_regex,omitempty"`
+	Metadata     map[string]string `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+	Expires      time.Time         `yaml:"expires,omitempty" json:"expires,omitempty"`
+	Comment      string            `yaml:"comment,omitempty" json:"comment,omitempty"`
+}
+
+// MaintenanceConfig contains windows during which notifications are suppressed
+type MaintenanceConfig struct {
+	Windows []MaintenanceWindow `yaml:"windows,omitempty"`
+}
+
+// MaintenanceWindow suppresses notifications for matching results, either
+// once between Start and End or on a recurring schedule. Results match when
+// their collector is listed (or no collectors are) and every Match label
+// (collector, severity or metadata key) has the given value.
+type MaintenanceWindow struct {
+	Name       string            `yaml:"name"`
+	Collectors []string          `yaml:"collectors,omitempty"`
+	Match      map[string]string `yaml:"match,omitempty"`
+	Start      time.Time         `yaml:"start,omitempty"`
+	End        time.Time         `yaml:"end,omitempty"`
+	Recurring  *RecurringWindow  `yaml:"recurring,omitempty"`
+}
+
+// RecurringWindow is a maintenance window repeating on the given days (every
+// day when empty) at a local start time, e.g. "02:00"
+type RecurringWindow struct {
+	Days            []string `yaml:"days,omitempty"`
+	Start           string   `yaml:"start"`
+	DurationMinutes Minutes  `yaml:"duration_minutes"`
+	Timezone        string   `yaml:"timezone,omitempty"`
+}
+
+// Route sends the results it matches to a subset of the notifiers. A result
+// matches when its collector is listed (or no collectors are), its severity is
+// listed (or none are) and its labels satisfy match and match_re.
+type Route struct {
+	Name       string            `yaml:"name,omitempty"`
+	Collectors []string          `yaml:"collectors,omitempty"`
+	Severities []string          `yaml:"severities,omitempty"`
+	Match      map[string]string `yaml:"match,omitempty"`
+	MatchRE    map[string]string `yaml:"match_re,omitempty"`
+	Notifiers  []string          `yaml:"notifiers"`
+	Continue   bool              `yaml:"continue,omitempty"` // Keep evaluating later routes after a match
+}
+
+// InhibitRule suppresses notifications for results matching the target matchers
+// while an alert matching the source matchers is active with the same values for
+// the equal labels. Labels are the collector name, severity and metadata keys.
+type InhibitRule struct {
+	Name          string            `yaml:"name,omitempty"`
+	SourceMatch   map[string]string `yaml:"source_match,omitempty"`
+	SourceMatchRE map[string]string `yaml:"source_match_re,omitempty"`
+	TargetMatch   map[string]string `yaml:"target_match,omitempty"`
+	TargetMatchRE map[string]string `yaml:"target_match_re,omitempty"`
+	Equal         []string          `yaml:"equal,omitempty"`
+}
+
 // NotificationsConfig contains all notification methods
 type NotificationsConfig struct {
-	Email EmailConfig `yaml:"email"`
+	Workers      int                `yaml:"workers,omitempty"`
+	TemplatesDir string             `yaml:"templates_dir,omitempty"`
+	Retry        RetryConfig        `yaml:"retry,omitempty"`
+	Queue        QueueConfig        `yaml:"queue,omitempty"`
+	Digest       DigestConfig       `yaml:"digest,omitempty"`
+	QuietHours   QuietHoursConfig   `yaml:"quiet_hours,omitempty"`
+	Email        EmailConfig        `yaml:"email"`
+	Ntfy         NtfyConfig         `yaml:"ntfy"`
+	Chaos        ChaosConfig        `yaml:"chaos"`
+	MQTT         MQTTConfig         `yaml:"mqtt"`
+	SNS          SNSConfig          `yaml:"sns"`
+	RocketChat   RocketChatConfig   `yaml:"rocketchat"`
+	Signal       SignalConfig       `yaml:"signal"`
+	File         FileConfig         `yaml:"file"`
+	Alertmanager AlertmanagerConfig `yaml:"alertmanager"`
+	SplunkOnCall SplunkOnCallConfig `yaml:"splunk_oncall"`
+	Webex        WebexConfig        `yaml:"webex"`
+	Jira         JiraConfig         `yaml:"jira"`
+	EmailAPI     EmailAPIConfig     `yaml:"email_api"`
+	Heartbeat    HeartbeatConfig    `yaml:"heartbeat"`
+
+	PluginDir string                          `yaml:"plugin_dir,omitempty"` // Where plugin notifiers' programs are found. Default: plugins
+	Plugins   map[string]PluginNotifierConfig `yaml:"plugins,omitempty"`    // Plugin notifiers by name
+}
+
+// PluginNotifierConfig configures a notifier implemented by a go-plugin
+// program
+type PluginNotifierConfig struct {
+	Enabled     bool                   `yaml:"enabled"`
+	Command     string                 `yaml:"command,omitempty"` // Default: <plugin_dir>/<name>
+	MinSeverity string                 `yaml:"min_severity,omitempty"`
+	GroupBy     []string               `yaml:"group_by,omitempty"`
+	Settings    map[string]interface{} `yaml:"settings,omitempty"`
+}
+
+// GroupBys returns the labels each notifier groups results by, by notifier
+// name. An empty list sends every result on its own.
+func (n NotificationsConfig) GroupBys() map[string][]string {
+	groupBys := map[string][]string{
+		"email":         n.Email.GroupBy,
+		"ntfy":          n.Ntfy.GroupBy,
+		"chaos":         n.Chaos.GroupBy,
+		"mqtt":          n.MQTT.GroupBy,
+		"sns":           n.SNS.GroupBy,
+		"rocketchat":    n.RocketChat.GroupBy,
+		"signal":        n.Signal.GroupBy,
+		"file":          n.File.GroupBy,
+		"alertmanager":  n.Alertmanager.GroupBy,
+		"splunk_oncall": n.SplunkOnCall.GroupBy,
+		"webex":         n.Webex.GroupBy,
+		"jira":          n.Jira.GroupBy,
+		"email_api":     n.EmailAPI.GroupBy,
+	}
+	for name, plugin := range n.Plugins {
+		groupBys[name] = plugin.GroupBy
+	}
+	return groupBys
+}
+
+// Templates returns the configured templates of each notifier that renders
+// messages from templates, by notifier name
+func (n NotificationsConfig) Templates() map[string]map[string]string {
+	return map[string]map[string]string{
+		"email":         n.Email.Templates,
+		"ntfy":          n.Ntfy.Templates,
+		"sns":           n.SNS.Templates,
+		"rocketchat":    n.RocketChat.Templates,
+		"signal":        n.Signal.Templates,
+		"alertmanager":  n.Alertmanager.Templates,
+		"splunk_oncall": n.SplunkOnCall.Templates,
+		"webex":         n.Webex.Templates,
+		"jira":          n.Jira.Templates,
+		"email_api":     n.EmailAPI.Templates,
+	}
+}
+
+// MinSeverities returns each notifier's minimum severity by notifier name.
+// An empty value lets every severity through.
+func (n NotificationsConfig) MinSeverities() map[string]string {
+	minSeverities := map[string]string{
+		"email":         n.Email.MinSeverity,
+		"ntfy":          n.Ntfy.MinSeverity,
+		"chaos":         n.Chaos.MinSeverity,
+		"mqtt":          n.MQTT.MinSeverity,
+		"sns":           n.SNS.MinSeverity,
+		"rocketchat":    n.RocketChat.MinSeverity,
+		"signal":        n.Signal.MinSeverity,
+		"file":          n.File.MinSeverity,
+		"alertmanager":  n.Alertmanager.MinSeverity,
+		"splunk_oncall": n.SplunkOnCall.MinSeverity,
+		"webex":         n.Webex.MinSeverity,
+		"jira":          n.Jira.MinSeverity,
+		"email_api":     n.EmailAPI.MinSeverity,
+	}
+	for name, plugin := range n.Plugins {
+		minSeverities[name] = plugin.MinSeverity
+	}
+	return minSeverities
+}
+
+// RetryConfig controls how failed notifications are retried with exponential backoff
+type RetryConfig struct {
+	MaxAttempts      int          `yaml:"max_attempts,omitempty"`
+	InitialBackoffMs Milliseconds `yaml:"initial_backoff_ms,omitempty"`
+	MaxBackoffMs     Milliseconds `yaml:"max_backoff_ms,omitempty"`
+	Multiplier       float64      `yaml:"multiplier,omitempty"`
+	Jitter           float64      `yaml:"jitter,omitempty"`
+}
+
+// QueueConfig contains settings for the persistent queue of notifications
+// awaiting delivery
+type QueueConfig struct {
+	Enabled              bool    `yaml:"enabled"`
+	Path                 string  `yaml:"path,omitempty"`
+	TTLSeconds           Seconds `yaml:"ttl_seconds,omitempty"`
+	MaxSize              int     `yaml:"max_size,omitempty"`
+	RetryIntervalSeconds Seconds `yaml:"retry_interval_seconds,omitempty"`
+}
+
+// QuietHoursConfig contains the daily period during which only critical
+// notifications are delivered immediately
+type QuietHoursConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Start    string `yaml:"start"` // HH:MM
+	End      string `yaml:"end"`   // HH:MM
+	Timezone string `yaml:"timezone,omitempty"`
+}
+
+// DigestConfig contains settings for batching notifications into digests
+type DigestConfig struct {
+	Enabled       bool    `yaml:"enabled"`
+	WindowSeconds Seconds `yaml:"window_seconds,omitempty"`
 }
 
 // EmailConfig contains email notification settings
 type EmailConfig struct {
-	Enabled    bool     `yaml:"enabled"`
-	From       string   `yaml:"from"`
-	To         []string `yaml:"to"`
-	SMTPServer string   `yaml:"smtp_server"`
-	SMTPPort   int      `yaml:"smtp_port"`
-	Username   string   `yaml:"username"`
-	Password   string   `yaml:"password"`
+	Enabled      bool              `yaml:"enabled"`
+	MinSeverity  string            `yaml:"min_severity,omitempty"`
+	GroupBy      []string          `yaml:"group_by,omitempty"`
+	Templates    map[string]string `yaml:"templates,omitempty"`
+	From         string            `yaml:"from"`
+	To           []string          `yaml:"to"`
+	SMTPServer   string            `yaml:"smtp_server"`
+	SMTPPort     int               `yaml:"smtp_port"`
+	Username     string            `yaml:"username"`
+	Password     string            `yaml:"password"`
+	PasswordFile string            `yaml:"password_file"`
+
+	// TLSMode is none, starttls or implicit; empty picks one from the port
+	TLSMode            string `yaml:"tls_mode"`
+	TLSServerName      string `yaml:"tls_server_name"`
+	CAFile             string `yaml:"ca_file"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+
+	// HTMLTemplate overrides the embedded html/template used for the HTML body
+	HTMLTemplate  string `yaml:"html_template"`
+	PlainTextOnly bool   `yaml:"plain_text_only"`
+}
+
+// NtfyConfig contains ntfy push notification settings
+type NtfyConfig struct {
+	Enabled      bool              `yaml:"enabled"`
+	MinSeverity  string            `yaml:"min_severity,omitempty"`
+	GroupBy      []string          `yaml:"group_by,omitempty"`
+	Templates    map[string]string `yaml:"templates,omitempty"`
+	Server       string            `yaml:"server"`
+	Topic        string            `yaml:"topic"`
+	Token        string            `yaml:"token"`
+	TokenFile    string            `yaml:"token_file"`
+	Username     string            `yaml:"username"`
+	Password     string            `yaml:"password"`
+	PasswordFile string            `yaml:"password_file"`
+	Priority     int               `yaml:"priority"`
+	Tags         []string          `yaml:"tags"`
+	ClickURL     string            `yaml:"click_url"`
+}
+
+// ChaosConfig contains settings for the failure-injecting test notifier
+type ChaosConfig struct {
+	Enabled       bool         `yaml:"enabled"`
+	MinSeverity   string       `yaml:"min_severity,omitempty"`
+	GroupBy       []string     `yaml:"group_by,omitempty"`
+	FailRate      float64      `yaml:"fail_rate"`
+	DelayRate     float64      `yaml:"delay_rate"`
+	MaxDelayMs    Milliseconds `yaml:"max_delay_ms"`
+	DuplicateRate float64      `yaml:"duplicate_rate"`
+	Seed          int64        `yaml:"seed"`
+	URL           string       `yaml:"url"`
+}
+
+// MQTTConfig contains settings for publishing results to an MQTT broker
+type MQTTConfig struct {
+	Enabled            bool     `yaml:"enabled"`
+	MinSeverity        string   `yaml:"min_severity,omitempty"`
+	GroupBy            []string `yaml:"group_by,omitempty"`
+	Broker             string   `yaml:"broker"`
+	ClientID           string   `yaml:"client_id"`
+	Username           string   `yaml:"username"`
+	Password           string   `yaml:"password"`
+	PasswordFile       string   `yaml:"password_file"`
+	Topic              string   `yaml:"topic"`
+	QoS                int      `yaml:"qos"`
+	Retained           bool     `yaml:"retained"`
+	TimeoutSeconds     Seconds  `yaml:"timeout_seconds"`
+	InsecureSkipVerify bool     `yaml:"insecure_skip_verify"`
+}
+
+// SNSConfig contains Amazon SNS notification settings. Credentials come
+// from the standard AWS credential chain.
+type SNSConfig struct {
+	Enabled     bool              `yaml:"enabled"`
+	MinSeverity string            `yaml:"min_severity,omitempty"`
+	GroupBy     []string          `yaml:"group_by,omitempty"`
+	Templates   map[string]string `yaml:"templates,omitempty"`
+	TopicARN    string            `yaml:"topic_arn"`
+	Region      string            `yaml:"region"`
+	Profile     string            `yaml:"profile"`
+}
+
+// RocketChatConfig contains Rocket.Chat incoming webhook settings
+type RocketChatConfig struct {
+	Enabled        bool              `yaml:"enabled"`
+	MinSeverity    string            `yaml:"min_severity,omitempty"`
+	GroupBy        []string          `yaml:"group_by,omitempty"`
+	Templates      map[string]string `yaml:"templates,omitempty"`
+	WebhookURL     string            `yaml:"webhook_url"`
+	WebhookURLFile string            `yaml:"webhook_url_file"`
+	Channel        string            `yaml:"channel"`
+	Alias          string            `yaml:"alias"`
+	Emoji          string            `yaml:"emoji"`
+	Avatar         string            `yaml:"avatar"`
+}
+
+// SignalConfig contains settings for Signal messages via a signal-cli daemon
+type SignalConfig struct {
+	Enabled     bool              `yaml:"enabled"`
+	MinSeverity string            `yaml:"min_severity,omitempty"`
+	GroupBy     []string          `yaml:"group_by,omitempty"`
+	Templates   map[string]string `yaml:"templates,omitempty"`
+	API         string            `yaml:"api"`
+	URL         string            `yaml:"url"`
+	Number      string            `yaml:"number"`
+	Recipients  []string          `yaml:"recipients"`
+	Groups      []string          `yaml:"groups"`
+}
+
+// FileConfig contains settings for the JSON lines audit file notifier
+type FileConfig struct {
+	Enabled        bool     `yaml:"enabled"`
+	MinSeverity    string   `yaml:"min_severity,omitempty"`
+	GroupBy        []string `yaml:"group_by,omitempty"`
+	Path           string   `yaml:"path"`
+	IncludeResults bool     `yaml:"include_results"`
+	MaxSizeMB      int      `yaml:"max_size_mb"`
+	MaxBackups     int      `yaml:"max_backups"`
+}
+
+// AlertmanagerConfig contains Prometheus Alertmanager notification settings
+type AlertmanagerConfig struct {
+	Enabled         bool              `yaml:"enabled"`
+	MinSeverity     string            `yaml:"min_severity,omitempty"`
+	GroupBy         []string          `yaml:"group_by,omitempty"`
+	Templates       map[string]string `yaml:"templates,omitempty"`
+	URLs            []string          `yaml:"urls"`
+	Username        string            `yaml:"username"`
+	Password        string            `yaml:"password"`
+	PasswordFile    string            `yaml:"password_file"`
+	BearerToken     string            `yaml:"bearer_token"`
+	BearerTokenFile string            `yaml:"bearer_token_file"`
+	Labels          map[string]string `yaml:"labels"`
+	GeneratorURL    string            `yaml:"generator_url"`
+	ResendInterval  Seconds           `yaml:"resend_interval_seconds,omitempty"` // Default: 60
+}
+
+// SplunkOnCallConfig contains Splunk On-Call (VictorOps) REST endpoint settings
+type SplunkOnCallConfig struct {
+	Enabled     bool              `yaml:"enabled"`
+	MinSeverity string            `yaml:"min_severity,omitempty"`
+	GroupBy     []string          `yaml:"group_by,omitempty"`
+	Templates   map[string]string `yaml:"templates,omitempty"`
+	APIKey      string            `yaml:"api_key"`
+	APIKeyFile  string            `yaml:"api_key_file"`
+	RoutingKey  string            `yaml:"routing_key"`
+	URL         string            `yaml:"url"`
+}
+
+// WebexConfig contains Cisco Webex bot settings. Rooms maps a severity to
+// the room its alerts go to; other severities use RoomID.
+type WebexConfig struct {
+	Enabled      bool              `yaml:"enabled"`
+	MinSeverity  string            `yaml:"min_severity,omitempty"`
+	GroupBy      []string          `yaml:"group_by,omitempty"`
+	Templates    map[string]string `yaml:"templates,omitempty"`
+	BotToken     string            `yaml:"bot_token"`
+	BotTokenFile string            `yaml:"bot_token_file"`
+	RoomID       string            `yaml:"room_id"`
+	Rooms        map[string]string `yaml:"rooms"`
+	APIURL       string            `yaml:"api_url"`
+}
+
+// JiraConfig contains settings for tracking alerts as Jira issues
+type JiraConfig struct {
+	Enabled                 bool              `yaml:"enabled"`
+	MinSeverity             string            `yaml:"min_severity,omitempty"`
+	GroupBy                 []string          `yaml:"group_by,omitempty"`
+	Templates               map[string]string `yaml:"templates,omitempty"`
+	URL                     string            `yaml:"url"`
+	Username                string            `yaml:"username"`
+	APIToken                string            `yaml:"api_token"`
+	APITokenFile            string            `yaml:"api_token_file"`
+	PersonalAccessToken     string            `yaml:"personal_access_token"`
+	PersonalAccessTokenFile string            `yaml:"personal_access_token_file"`
+	Project                 string            `yaml:"project"`
+	IssueType               string            `yaml:"issue_type"`
+	Labels                  []string          `yaml:"labels"`
+	ResolveTransition       string            `yaml:"resolve_transition"`
+}
+
+// EmailAPIConfig contains settings for sending email through the SendGrid,
+// Mailgun or Amazon SES HTTP APIs
+type EmailAPIConfig struct {
+	Enabled       bool              `yaml:"enabled"`
+	MinSeverity   string            `yaml:"min_severity,omitempty"`
+	GroupBy       []string          `yaml:"group_by,omitempty"`
+	Templates     map[string]string `yaml:"templates,omitempty"`
+	Provider      string            `yaml:"provider"`
+	APIKey        string            `yaml:"api_key"`
+	APIKeyFile    string            `yaml:"api_key_file"`
+	Domain        string            `yaml:"domain"`
+	Region        string            `yaml:"region"`
+	From          string            `yaml:"from"`
+	To            []string          `yaml:"to"`
+	HTMLTemplate  string            `yaml:"html_template"`
+	PlainTextOnly bool              `yaml:"plain_text_only"`
+}
+
+// HeartbeatConfig contains settings for the healthchecks.io / Dead Man's
+// Snitch heartbeat that watches the monitor itself
+type HeartbeatConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Provider string `yaml:"provider"`
+	URL      string `yaml:"url"`
+	FailURL  string `yaml:"fail_url"`
 }
 
 // LoadConfig loads the configuration from the specified file path
-func LoadConfig(logger *zap.Logger, path string) (*Config, error) {
+func LoadConfig(logger *zap.Logger, path string, overrides ...Override) (*Config, error) {
 	// Read configuration file
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -54,30 +667,425 @@ func LoadConfig(logger *zap.Logger, path string) (*Config, error) {
 		return nil, err
 	}
 
+	return ParseConfig(logger, data, path, overrides...)
+}
+
+// Override changes a configuration after it is parsed and before it is
+// validated, e.g. with a value from a command line flag
+type Override func(config *Config) error
+
+// ParseConfig parses and validates configuration data. The source names the
+// data in log messages, e.g. the file it was read from. Unknown keys are
+// errors, so a misspelled option doesn't silently leave its default.
+func ParseConfig(logger *zap.Logger, data []byte, source string, overrides ...Override) (*Config, error) {
 	// Parse configuration
 	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		logger.Error("Error parsing config file", zap.String("path", path), zap.Error(err))
+	if err := decodeStrict(data, &config); err != nil {
+		logger.Error("Error parsing config file", zap.String("path", source), zap.Error(err))
+		return nil, err
+	}
+	if err := applyOverrides(logger, &config, overrides); err != nil {
 		return nil, err
 	}
 
 	// Validate configuration
 	if err := validateConfig(logger.Named("validate"), &config); err != nil {
-		logger.Error("Invalid configuration", zap.String("path", path), zap.Error(err))
+		logger.Error("Invalid configuration", zap.String("path", source), zap.Error(err))
 		return nil, err
 	}
 
 	return &config, nil
 }
 
+// applyOverrides applies overrides in order
+func applyOverrides(logger *zap.Logger, config *Config, overrides []Override) error {
+	for _, override := range overrides {
+		if err := override(config); err != nil {
+			logger.Error("Invalid configuration override", zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
+// validateLogging checks the logging section and fills in its defaults
+func validateLogging(logger *zap.Logger, logging *LoggingConfig) error {
+	if logging.Level == "" {
+		logging.Level = "info"
+	}
+	if _, err := zapcore.ParseLevel(logging.Level); err != nil {
+		logger.Error("Invalid log level", zap.String("level", logging.Level))
+		return fmt.Errorf("logging.level must be debug, info, warn or error")
+	}
+	if logging.Encoding == "" {
+		logging.Encoding = "json"
+	}
+	if logging.Encoding != "json" && logging.Encoding != "console" {
+		logger.Error("Invalid log encoding", zap.String("encoding", logging.Encoding))
+		return fmt.Errorf("logging.encoding must be json or console")
+	}
+	if len(logging.OutputPaths) == 0 && logging.File.Path == "" {
+		logging.OutputPaths = []string{"stderr"}
+	}
+	if len(logging.ErrorOutputPaths) == 0 {
+		logging.ErrorOutputPaths = []string{"stderr"}
+	}
+	if logging.Sampling.Initial < 0 || logging.Sampling.Thereafter < 0 {
+		logger.Error("Invalid log sampling", zap.Int("initial", logging.Sampling.Initial), zap.Int("thereafter", logging.Sampling.Thereafter))
+		return fmt.Errorf("logging.sampling.initial and thereafter must not be negative")
+	}
+	if logging.Sampling.Initial == 0 {
+		logging.Sampling.Initial = 100
+	}
+	if logging.Sampling.Thereafter == 0 {
+		logging.Sampling.Thereafter = 100
+	}
+	if file := &logging.File; file.Path != "" {
+		if file.MaxSizeMB < 0 || file.MaxAgeDays < 0 || file.MaxBackups < 0 {
+			logger.Error("Invalid log file rotation", zap.Int("max_size_mb", file.MaxSizeMB), zap.Int("max_age_days", file.MaxAgeDays), zap.Int("max_backups", file.MaxBackups))
+			return fmt.Errorf("logging.file max_size_mb, max_age_days and max_backups must not be negative")
+		}
+		if file.MaxSizeMB == 0 {
+			file.MaxSizeMB = 100
+		}
+	}
+	return nil
+}
+
+// decodeStrict decodes YAML data into out, failing on keys out has no field
+// for. Empty data decodes to nothing.
+func decodeStrict(data []byte, out interface{}) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// Validate checks a configuration built in code rather than parsed from a
+// file, filling in defaults the same way ParseConfig does
+func Validate(logger *zap.Logger, config *Config) error {
+	return validateConfig(logger, config)
+}
+
+// validateTags checks the names of result tags. Tags are labels; collector
+// and severity are set from the result itself.
+func validateTags(logger *zap.Logger, path string, tags map[string]string) error {
+	for key := range tags {
+		if key == "" || key == "collector" || key == "severity" {
+			logger.Error("Invalid tag name", zap.String("path", path), zap.String("tag", key))
+			return fmt.Errorf("%s: '%s' is not a valid tag name", path, key)
+		}
+	}
+	return nil
+}
+
+// secretFile is a credential that may be read from a file, such as a Docker
+// or Kubernetes secret mount, instead of being written in the configuration
+type secretFile struct {
+	path    string // Of the credential in the configuration
+	enabled bool
+	value   *string
+	file    string
+}
+
+// resolveSecretFiles reads the credentials of enabled sections that are
+// given as *_file settings into their plain settings
+func resolveSecretFiles(logger *zap.Logger, config *Config) error {
+	n := &config.Notifications
+	secrets := []secretFile{
+		{"api.token", config.API.Enabled, &config.API.Token, config.API.TokenFile},
+		{"grpc.token", config.GRPC.Enabled, &config.GRPC.Token, config.GRPC.TokenFile},
+		{"notifications.email.password", n.Email.Enabled, &n.Email.Password, n.Email.PasswordFile},
+		{"notifications.ntfy.token", n.Ntfy.Enabled, &n.Ntfy.Token, n.Ntfy.TokenFile},
+		{"notifications.ntfy.password", n.Ntfy.Enabled, &n.Ntfy.Password, n.Ntfy.PasswordFile},
+		{"notifications.mqtt.password", n.MQTT.Enabled, &n.MQTT.Password, n.MQTT.PasswordFile},
+		{"notifications.rocketchat.webhook_url", n.RocketChat.Enabled, &n.RocketChat.WebhookURL, n.RocketChat.WebhookURLFile},
+		{"notifications.alertmanager.password", n.Alertmanager.Enabled, &n.Alertmanager.Password, n.Alertmanager.PasswordFile},
+		{"notifications.alertmanager.bearer_token", n.Alertmanager.Enabled, &n.Alertmanager.BearerToken, n.Alertmanager.BearerTokenFile},
+		{"notifications.splunk_oncall.api_key", n.SplunkOnCall.Enabled, &n.SplunkOnCall.APIKey, n.SplunkOnCall.APIKeyFile},
+		{"notifications.webex.bot_token", n.Webex.Enabled, &n.Webex.BotToken, n.Webex.BotTokenFile},
+		{"notifications.jira.api_token", n.Jira.Enabled, &n.Jira.APIToken, n.Jira.APITokenFile},
+		{"notifications.jira.personal_access_token", n.Jira.Enabled, &n.Jira.PersonalAccessToken, n.Jira.PersonalAccessTokenFile},
+		{"notifications.email_api.api_key", n.EmailAPI.Enabled, &n.EmailAPI.APIKey, n.EmailAPI.APIKeyFile},
+	}
+
+	for _, secret := range secrets {
+		if !secret.enabled || secret.file == "" {
+			continue
+		}
+		if *secret.value != "" {
+			logger.Error("Credential set twice", zap.String("path", secret.path))
+			return fmt.Errorf("only one of %s and %s_file may be set", secret.path, secret.path)
+		}
+		value, err := collectors.ReadSecretFile(secret.file)
+		if err != nil {
+			logger.Error("Failed to read credential file", zap.String("path", secret.path+"_file"), zap.Error(err))
+			return fmt.Errorf("%s_file: %w", secret.path, err)
+		}
+		*secret.value = value
+	}
+	return nil
+}
+
 // validateConfig performs basic validation on the configuration
 func validateConfig(logger *zap.Logger, config *Config) error {
+	if err := resolveSecretFiles(logger, config); err != nil {
+		return err
+	}
+
 	// Ensure we have a valid default interval
 	if config.Monitor.DefaultIntervalSeconds <= 0 {
-		logger.Error("Invalid default interval", zap.Int("default_interval_seconds", config.Monitor.DefaultIntervalSeconds))
+		logger.Error("Invalid default interval", zap.Int("default_interval_seconds", int(config.Monitor.DefaultIntervalSeconds)))
 		return fmt.Errorf("monitor.default_interval_seconds must be greater than 0")
 	}
 
+	// Resolve the operating mode; container mode logs to standard output
+	// and bounds the shutdown below common container stop timeouts
+	switch config.Monitor.Mode {
+	case "":
+		config.Monitor.Mode = ModeHost
+	case ModeAuto:
+		config.Monitor.Mode = ModeHost
+		if InContainer() {
+			config.Monitor.Mode = ModeContainer
+		}
+	case ModeHost, ModeContainer:
+	default:
+		logger.Error("Invalid operating mode", zap.String("mode", config.Monitor.Mode))
+		return fmt.Errorf("monitor.mode must be host, container or auto")
+	}
+	if config.Monitor.ShutdownGraceSeconds < 0 {
+		logger.Error("Invalid shutdown grace period", zap.Int("shutdown_grace_seconds", int(config.Monitor.ShutdownGraceSeconds)))
+		return fmt.Errorf("monitor.shutdown_grace_seconds must not be negative")
+	}
+	if config.Monitor.Mode == ModeContainer {
+		if config.Monitor.ShutdownGraceSeconds == 0 {
+			config.Monitor.ShutdownGraceSeconds = 8
+		}
+		if len(config.Logging.OutputPaths) == 0 && config.Logging.File.Path == "" {
+			config.Logging.OutputPaths = []string{"stdout"}
+		}
+	}
+
+	if config.Monitor.MaxAlertLatencySeconds < 0 {
+		logger.Error("Invalid alert latency bound", zap.Int("max_alert_latency_seconds", int(config.Monitor.MaxAlertLatencySeconds)))
+		return fmt.Errorf("monitor.max_alert_latency_seconds must not be negative")
+	}
+
+	// Cap the distinct targets a collector may emit per run
+	if config.Monitor.MaxSeriesPerCollector < 0 {
+		logger.Error("Invalid series limit", zap.Int("max_series_per_collector", config.Monitor.MaxSeriesPerCollector))
+		return fmt.Errorf("monitor.max_series_per_collector must not be negative")
+	}
+	if config.Monitor.MaxSeriesPerCollector == 0 {
+		config.Monitor.MaxSeriesPerCollector = 1000
+	}
+
+	config.Monitor.Hostname = strings.TrimSpace(config.Monitor.Hostname)
+	if strings.ContainsAny(config.Monitor.Hostname, "\r\n") {
+		logger.Error("Invalid hostname", zap.String("hostname", config.Monitor.Hostname))
+		return fmt.Errorf("monitor.hostname must be a single line")
+	}
+	if err := validateTags(logger, "monitor.tags", config.Monitor.Tags); err != nil {
+		return err
+	}
+	for name, collector := range config.Collectors {
+		if err := validateTags(logger, "collectors."+name+".tags", collector.Tags); err != nil {
+			return err
+		}
+	}
+
+	// Decide what happens to active alerts of checks removed by a reload
+	switch config.Monitor.OnCheckRemoved {
+	case "":
+		config.Monitor.OnCheckRemoved = "resolve"
+	case "resolve", "orphan":
+	default:
+		logger.Error("Invalid check removal policy", zap.String("on_check_removed", config.Monitor.OnCheckRemoved))
+		return fmt.Errorf("monitor.on_check_removed must be 'resolve' or 'orphan'")
+	}
+
+	// Validate repeat notification cooldowns and collector timing
+	if config.Monitor.SplaySeconds < 0 {
+		logger.Error("Invalid collector splay", zap.Int("splay_seconds", int(config.Monitor.SplaySeconds)))
+		return fmt.Errorf("monitor.splay_seconds must not be negative")
+	}
+	if config.Monitor.RenotifyAfterSeconds < 0 {
+		logger.Error("Invalid renotify cooldown", zap.Int("renotify_after_seconds", int(config.Monitor.RenotifyAfterSeconds)))
+		return fmt.Errorf("monitor.renotify_after_seconds must not be negative")
+	}
+	for name, collector := range config.Collectors {
+		if collector.Schedule != "" {
+			if _, err := cron.ParseStandard(collector.Schedule); err != nil {
+				logger.Error("Invalid collector schedule", zap.String("collector", name), zap.String("schedule", collector.Schedule), zap.Error(err))
+				return fmt.Errorf("collectors.%s.schedule is not a valid cron expression: %w", name, err)
+			}
+		}
+		if collector.ForSeconds < 0 {
+			logger.Error("Invalid alert hold duration", zap.String("collector", name), zap.Int("for_seconds", int(collector.ForSeconds)))
+			return fmt.Errorf("collectors.%s.for_seconds must not be negative", name)
+		}
+		if collector.SplaySeconds < 0 {
+			logger.Error("Invalid collector splay", zap.String("collector", name), zap.Int("splay_seconds", int(collector.SplaySeconds)))
+			return fmt.Errorf("collectors.%s.splay_seconds must not be negative", name)
+		}
+		if collector.RenotifyAfterSeconds < 0 {
+			logger.Error("Invalid renotify cooldown", zap.String("collector", name), zap.Int("renotify_after_seconds", int(collector.RenotifyAfterSeconds)))
+			return fmt.Errorf("collectors.%s.renotify_after_seconds must not be negative", name)
+		}
+	}
+
+	// Default and validate result history
+	history := &config.History
+	if history.RetentionHours < 0 {
+		logger.Error("Invalid history retention", zap.Int("retention_hours", int(history.RetentionHours)))
+		return fmt.Errorf("history.retention_hours must not be negative")
+	}
+	if history.Path == "" {
+		history.Path = "history.db"
+	}
+	if history.RetentionHours == 0 {
+		history.RetentionHours = 168
+	}
+	if len(history.AvailabilityWindows) == 0 {
+		history.AvailabilityWindows = []string{"24h", "7d", "30d"}
+	}
+	for _, window := range history.AvailabilityWindows {
+		if _, err := ParseWindow(window); err != nil {
+			logger.Error("Invalid availability window", zap.String("window", window), zap.Error(err))
+			return fmt.Errorf("history.availability_windows: %w", err)
+		}
+	}
+
+	if config.Monitor.RunAudit.History && !history.Enabled {
+		logger.Error("Run audit stores runs in the result history, which is not enabled")
+		return fmt.Errorf("monitor.run_audit.history requires history.enabled")
+	}
+
+	// Default and validate the secrets providers
+	if config.Secrets.RefreshSeconds < 0 {
+		logger.Error("Invalid secrets refresh interval", zap.Int("refresh_seconds", int(config.Secrets.RefreshSeconds)))
+		return fmt.Errorf("secrets.refresh_seconds must not be negative")
+	}
+	if config.Secrets.RefreshSeconds == 0 {
+		config.Secrets.RefreshSeconds = 300
+	}
+	if vault := &config.Secrets.Vault; vault.Enabled {
+		if vault.KVVersion == 0 {
+			vault.KVVersion = 2
+		}
+		if vault.KVVersion != 1 && vault.KVVersion != 2 {
+			logger.Error("Invalid Vault KV version", zap.Int("kv_version", vault.KVVersion))
+			return fmt.Errorf("secrets.vault.kv_version must be 1 or 2")
+		}
+		if vault.TimeoutSeconds < 0 {
+			logger.Error("Invalid Vault timeout", zap.Int("timeout_seconds", int(vault.TimeoutSeconds)))
+			return fmt.Errorf("secrets.vault.timeout_seconds must not be negative")
+		}
+	}
+
+	if err := validateLogging(logger, &config.Logging); err != nil {
+		return err
+	}
+
+	// Default the API listener
+	if config.API.Enabled && config.API.Listen == "" {
+		config.API.Listen = "127.0.0.1:8080"
+	}
+	if config.API.ReadyFailures <= 0 {
+		config.API.ReadyFailures = 3
+	}
+
+	// Default the gRPC listener
+	if config.GRPC.Enabled && config.GRPC.Listen == "" {
+		config.GRPC.Listen = "127.0.0.1:9090"
+	}
+
+	// Default the debug listener
+	if config.Debug.Enabled && config.Debug.Listen == "" {
+		config.Debug.Listen = "127.0.0.1:6060"
+	}
+
+	// Validate remote hosts and the collectors' references to them
+	for name, host := range config.Hosts {
+		if name == "local" {
+			logger.Error("Reserved host name", zap.String("host", name))
+			return fmt.Errorf("hosts: 'local' is reserved for the machine the monitor runs on")
+		}
+		if host.Address == "" || host.User == "" || host.KeyFile == "" {
+			logger.Error("Incomplete host", zap.String("host", name))
+			return fmt.Errorf("hosts.%s needs an address, user and key_file", name)
+		}
+		if host.TimeoutSeconds < 0 {
+			logger.Error("Invalid host timeout", zap.String("host", name), zap.Int("timeout_seconds", int(host.TimeoutSeconds)))
+			return fmt.Errorf("hosts.%s.timeout_seconds must not be negative", name)
+		}
+	}
+	for name, collector := range config.Collectors {
+		hosts, ok := collector.Settings["hosts"].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, host := range hosts {
+			hostName, _ := host.(string)
+			if _, defined := config.Hosts[hostName]; !defined && hostName != "local" {
+				logger.Error("Collector names an unknown host", zap.String("collector", name), zap.Any("host", host))
+				return fmt.Errorf("collectors.%s.settings.hosts: unknown host '%v'", name, host)
+			}
+		}
+	}
+
+	// Validate collector types: plugin, or one registered with
+	// collectors.RegisterFactory
+	for name, collector := range config.Collectors {
+		switch collector.Type {
+		case "":
+		case CollectorTypePlugin:
+			if collector.Command == "" {
+				logger.Error("Plugin collector has no command", zap.String("collector", name))
+				return fmt.Errorf("collectors.%s: plugin collectors need a command", name)
+			}
+		default:
+			if _, exists := collectors.LookupFactory(collector.Type); !exists {
+				logger.Error("Invalid collector type", zap.String("collector", name), zap.String("type", collector.Type))
+				return fmt.Errorf("collectors.%s.type: unknown type '%s', registered types are: %s",
+					name, collector.Type, strings.Join(collectors.FactoryTypes(), ", "))
+			}
+		}
+	}
+
+	// Default and validate configured thresholds and anomaly detection
+	for name, collector := range config.Collectors {
+		for i := range collector.Thresholds {
+			if err := ValidateThreshold(&collector.Thresholds[i]); err != nil {
+				logger.Error("Invalid threshold", zap.String("collector", name), zap.Int("index", i), zap.Error(err))
+				return fmt.Errorf("collectors.%s.thresholds[%d]: %w", name, i, err)
+			}
+		}
+		for i := range collector.Anomaly {
+			if err := ValidateAnomaly(&collector.Anomaly[i]); err != nil {
+				logger.Error("Invalid anomaly detection", zap.String("collector", name), zap.Int("index", i), zap.Error(err))
+				return fmt.Errorf("collectors.%s.anomaly[%d]: %w", name, i, err)
+			}
+		}
+	}
+
+	// Validate check dependencies
+	for name, collector := range config.Collectors {
+		for _, dep := range collector.DependsOn {
+			if _, exists := config.Collectors[dep.Collector]; !exists || dep.Collector == name {
+				logger.Error("Invalid check dependency", zap.String("collector", name), zap.String("depends_on", dep.Collector))
+				return fmt.Errorf("collectors.%s.depends_on: '%s' is not another configured collector", name, dep.Collector)
+			}
+		}
+	}
+	if cycle := dependencyCycle(config.Collectors); cycle != "" {
+		logger.Error("Check dependencies form a cycle", zap.String("cycle", cycle))
+		return fmt.Errorf("collector dependencies form a cycle: %s", cycle)
+	}
+
 	// Set default intervals for collectors if not specified
 	for name, collector := range config.Collectors {
 		if collector.Enabled && collector.Interval <= 0 {
@@ -86,6 +1094,92 @@ func validateConfig(logger *zap.Logger, config *Config) error {
 		}
 	}
 
+	// Default the number of notification workers
+	if config.Notifications.Workers < 0 {
+		logger.Error("Invalid notification workers", zap.Int("workers", config.Notifications.Workers))
+		return fmt.Errorf("notifications.workers must not be negative")
+	}
+	if config.Notifications.Workers == 0 {
+		config.Notifications.Workers = 4
+	}
+
+	// Default and validate the notification retry policy
+	retry := &config.Notifications.Retry
+	if retry.MaxAttempts < 0 || retry.InitialBackoffMs < 0 || retry.MaxBackoffMs < 0 {
+		logger.Error("Invalid notification retry settings")
+		return fmt.Errorf("notifications.retry values must not be negative")
+	}
+	if retry.Jitter < 0 || retry.Jitter > 1 {
+		logger.Error("Invalid notification retry jitter", zap.Float64("jitter", retry.Jitter))
+		return fmt.Errorf("notifications.retry.jitter must be between 0 and 1")
+	}
+	if retry.Multiplier != 0 && retry.Multiplier < 1 {
+		logger.Error("Invalid notification retry multiplier", zap.Float64("multiplier", retry.Multiplier))
+		return fmt.Errorf("notifications.retry.multiplier must be at least 1")
+	}
+	if retry.MaxAttempts == 0 {
+		retry.MaxAttempts = 3
+	}
+	if retry.InitialBackoffMs == 0 {
+		retry.InitialBackoffMs = 1000
+	}
+	if retry.MaxBackoffMs == 0 {
+		retry.MaxBackoffMs = 10000
+	}
+	if retry.Multiplier == 0 {
+		retry.Multiplier = 2
+	}
+
+	// Default and validate the notification queue
+	queue := &config.Notifications.Queue
+	if queue.TTLSeconds < 0 || queue.MaxSize < 0 || queue.RetryIntervalSeconds < 0 {
+		logger.Error("Invalid notification queue settings")
+		return fmt.Errorf("notifications.queue values must not be negative")
+	}
+	if queue.Path == "" {
+		queue.Path = "notification-queue.db"
+	}
+	if queue.TTLSeconds == 0 {
+		queue.TTLSeconds = 86400
+	}
+	if queue.MaxSize == 0 {
+		queue.MaxSize = 1000
+	}
+	if queue.RetryIntervalSeconds == 0 {
+		queue.RetryIntervalSeconds = 30
+	}
+
+	// Default and validate the digest window
+	if config.Notifications.Digest.WindowSeconds < 0 {
+		logger.Error("Invalid digest window", zap.Int("window_seconds", int(config.Notifications.Digest.WindowSeconds)))
+		return fmt.Errorf("notifications.digest.window_seconds must not be negative")
+	}
+	if config.Notifications.Digest.WindowSeconds == 0 {
+		config.Notifications.Digest.WindowSeconds = 600
+	}
+
+	// Validate quiet hours if enabled
+	if quiet := config.Notifications.QuietHours; quiet.Enabled {
+		start, err := time.Parse("15:04", quiet.Start)
+		if err != nil {
+			logger.Error("Invalid quiet hours start", zap.String("start", quiet.Start), zap.Error(err))
+			return fmt.Errorf("notifications.quiet_hours.start must be HH:MM: %w", err)
+		}
+		end, err := time.Parse("15:04", quiet.End)
+		if err != nil {
+			logger.Error("Invalid quiet hours end", zap.String("end", quiet.End), zap.Error(err))
+			return fmt.Errorf("notifications.quiet_hours.end must be HH:MM: %w", err)
+		}
+		if start.Equal(end) {
+			logger.Error("Quiet hours start and end are equal", zap.String("start", quiet.Start))
+			return fmt.Errorf("notifications.quiet_hours start and end must differ")
+		}
+		if _, err := time.LoadLocation(quiet.Timezone); err != nil {
+			logger.Error("Invalid quiet hours timezone", zap.String("timezone", quiet.Timezone), zap.Error(err))
+			return fmt.Errorf("notifications.quiet_hours.timezone is invalid: %w", err)
+		}
+	}
+
 	// Validate email configuration if enabled
 	if config.Notifications.Email.Enabled {
 		if config.Notifications.Email.From == "" {
@@ -100,15 +1194,540 @@ func validateConfig(logger *zap.Logger, config *Config) error {
 			logger.Error("Email SMTP server is empty")
 			return fmt.Errorf("email notification enabled but 'smtp_server' is empty")
 		}
+		switch config.Notifications.Email.TLSMode {
+		case "", "none", "starttls", "implicit":
+		default:
+			logger.Error("Invalid email TLS mode", zap.String("tls_mode", config.Notifications.Email.TLSMode))
+			return fmt.Errorf("email 'tls_mode' must be none, starttls or implicit")
+		}
+
 		if config.Notifications.Email.SMTPPort <= 0 {
 			logger.Error("Email SMTP port is invalid")
 			return fmt.Errorf("email notification enabled but 'smtp_port' is invalid")
 		}
 	}
 
+	// Validate ntfy configuration if enabled
+	if config.Notifications.Ntfy.Enabled {
+		if config.Notifications.Ntfy.Topic == "" {
+			logger.Error("Ntfy topic is empty")
+			return fmt.Errorf("ntfy notification enabled but 'topic' is empty")
+		}
+		if p := config.Notifications.Ntfy.Priority; p < 0 || p > 5 {
+			logger.Error("Ntfy priority is invalid", zap.Int("priority", p))
+			return fmt.Errorf("ntfy notification 'priority' must be between 1 and 5")
+		}
+	}
+
+	// Validate chaos notifier rates if enabled
+	if chaos := config.Notifications.Chaos; chaos.Enabled {
+		for name, rate := range map[string]float64{
+			"fail_rate":      chaos.FailRate,
+			"delay_rate":     chaos.DelayRate,
+			"duplicate_rate": chaos.DuplicateRate,
+		} {
+			if rate < 0 || rate > 1 {
+				logger.Error("Chaos notifier rate is invalid", zap.String("rate", name), zap.Float64("value", rate))
+				return fmt.Errorf("chaos notification '%s' must be between 0 and 1", name)
+			}
+		}
+	}
+
+	// Validate MQTT notifier configuration if enabled
+	if config.Notifications.MQTT.Enabled {
+		if config.Notifications.MQTT.Broker == "" {
+			logger.Error("MQTT notifier broker is empty")
+			return fmt.Errorf("mqtt notification enabled but 'broker' is empty")
+		}
+		if qos := config.Notifications.MQTT.QoS; qos < 0 || qos > 2 {
+			logger.Error("MQTT notifier QoS is invalid", zap.Int("qos", qos))
+			return fmt.Errorf("mqtt notification 'qos' must be 0, 1 or 2")
+		}
+	}
+
+	// Validate SNS configuration if enabled
+	if config.Notifications.SNS.Enabled && config.Notifications.SNS.TopicARN == "" {
+		logger.Error("SNS topic ARN is empty")
+		return fmt.Errorf("sns notification enabled but 'topic_arn' is empty")
+	}
+
+	// Validate Rocket.Chat configuration if enabled
+	if config.Notifications.RocketChat.Enabled && config.Notifications.RocketChat.WebhookURL == "" {
+		logger.Error("Rocket.Chat webhook URL is empty")
+		return fmt.Errorf("rocketchat notification enabled but 'webhook_url' is empty")
+	}
+
+	// Validate Signal configuration if enabled
+	if signal := config.Notifications.Signal; signal.Enabled {
+		if signal.URL == "" || signal.Number == "" {
+			logger.Error("Signal daemon URL or number is empty")
+			return fmt.Errorf("signal notification enabled but 'url' or 'number' is empty")
+		}
+		if len(signal.Recipients) == 0 && len(signal.Groups) == 0 {
+			logger.Error("Signal has no recipients")
+			return fmt.Errorf("signal notification enabled but no 'recipients' or 'groups'")
+		}
+	}
+
+	// Validate file notifier configuration if enabled
+	if config.Notifications.File.Enabled && config.Notifications.File.Path == "" {
+		logger.Error("Audit file path is empty")
+		return fmt.Errorf("file notification enabled but 'path' is empty")
+	}
+
+	// Validate Alertmanager configuration if enabled
+	if config.Notifications.Alertmanager.Enabled && len(config.Notifications.Alertmanager.URLs) == 0 {
+		logger.Error("Alertmanager URLs are empty")
+		return fmt.Errorf("alertmanager notification enabled but 'urls' is empty")
+	}
+
+	// Validate Splunk On-Call configuration if enabled
+	if oncall := config.Notifications.SplunkOnCall; oncall.Enabled && (oncall.APIKey == "" || oncall.RoutingKey == "") {
+		logger.Error("Splunk On-Call API key or routing key is empty")
+		return fmt.Errorf("splunk_oncall notification enabled but 'api_key' or 'routing_key' is empty")
+	}
+
+	// Validate Webex configuration if enabled
+	if webex := config.Notifications.Webex; webex.Enabled {
+		if webex.BotToken == "" {
+			logger.Error("Webex bot token is empty")
+			return fmt.Errorf("webex notification enabled but 'bot_token' is empty")
+		}
+		if webex.RoomID == "" && len(webex.Rooms) == 0 {
+			logger.Error("Webex has no rooms")
+			return fmt.Errorf("webex notification enabled but no 'room_id' or 'rooms'")
+		}
+	}
+
+	// Validate Jira configuration if enabled
+	if jira := config.Notifications.Jira; jira.Enabled {
+		if jira.URL == "" || jira.Project == "" {
+			logger.Error("Jira URL or project is empty")
+			return fmt.Errorf("jira notification enabled but 'url' or 'project' is empty")
+		}
+		if jira.PersonalAccessToken == "" && (jira.Username == "" || jira.APIToken == "") {
+			logger.Error("Jira credentials are missing")
+			return fmt.Errorf("jira notification needs 'username' and 'api_token', or 'personal_access_token'")
+		}
+	}
+
+	// Validate email API configuration if enabled
+	if emailAPI := config.Notifications.EmailAPI; emailAPI.Enabled {
+		switch emailAPI.Provider {
+		case "sendgrid", "mailgun", "ses":
+		default:
+			logger.Error("Invalid email API provider", zap.String("provider", emailAPI.Provider))
+			return fmt.Errorf("email_api 'provider' must be sendgrid, mailgun or ses")
+		}
+		if emailAPI.From == "" || len(emailAPI.To) == 0 {
+			logger.Error("Email API sender or recipients are empty")
+			return fmt.Errorf("email_api notification enabled but 'from' or 'to' is empty")
+		}
+	}
+
+	// Validate heartbeat configuration if enabled
+	if config.Notifications.Heartbeat.Enabled && config.Notifications.Heartbeat.URL == "" {
+		logger.Error("Heartbeat URL is empty")
+		return fmt.Errorf("heartbeat notification enabled but 'url' is empty")
+	}
+
+	// Validate plugin notifiers and default their commands
+	builtin := NotificationsConfig{}.MinSeverities()
+	if config.Notifications.PluginDir == "" {
+		config.Notifications.PluginDir = "plugins"
+	}
+	for name, plugin := range config.Notifications.Plugins {
+		if _, clash := builtin[name]; clash || name == "heartbeat" {
+			logger.Error("Plugin notifier name is taken by a built-in notifier", zap.String("notifier", name))
+			return fmt.Errorf("notifications.plugins.%s: '%s' is a built-in notifier", name, name)
+		}
+		if plugin.Command == "" {
+			plugin.Command = filepath.Join(config.Notifications.PluginDir, name)
+			config.Notifications.Plugins[name] = plugin
+		}
+	}
+
+	// Validate per-notifier minimum severities
+	for name, minSeverity := range config.Notifications.MinSeverities() {
+		switch minSeverity {
+		case "", "info", "warning", "critical":
+		default:
+			logger.Error("Invalid notifier minimum severity", zap.String("notifier", name), zap.String("min_severity", minSeverity))
+			return fmt.Errorf("notifications.%s.min_severity must be info, warning or critical", name)
+		}
+	}
+
+	// Validate per-notifier grouping labels
+	for name, labels := range config.Notifications.GroupBys() {
+		for _, label := range labels {
+			if label == "" {
+				logger.Error("Invalid notifier grouping label", zap.String("notifier", name), zap.String("label", label))
+				return fmt.Errorf("notifications.%s.group_by labels must not be empty", name)
+			}
+		}
+	}
+
+	// Validate the notification templates directory if set
+	// Configured templates name a field or a field's file, not both
+	for notifier, fields := range config.Notifications.Templates() {
+		for key, value := range fields {
+			if field, isFile := strings.CutSuffix(key, "_file"); isFile {
+				if _, both := fields[field]; both {
+					logger.Error("Template is set inline and as a file", zap.String("notifier", notifier), zap.String("field", field))
+					return fmt.Errorf("notifications.%s.templates: set %s or %s, not both", notifier, field, key)
+				}
+				if _, err := os.Stat(value); err != nil {
+					logger.Error("Template file is not readable", zap.String("notifier", notifier), zap.String("path", value), zap.Error(err))
+					return fmt.Errorf("notifications.%s.templates.%s: %w", notifier, key, err)
+				}
+			}
+		}
+	}
+
+	if dir := config.Notifications.TemplatesDir; dir != "" {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			logger.Error("Notification templates directory not found", zap.String("path", dir))
+			return fmt.Errorf("notifications 'templates_dir' %s is not a directory", dir)
+		}
+	}
+
+	// Validate inhibition rules
+	for i, rule := range config.InhibitRules {
+		if len(rule.SourceMatch)+len(rule.SourceMatchRE) == 0 || len(rule.TargetMatch)+len(rule.TargetMatchRE) == 0 {
+			logger.Error("Inhibit rule needs source and target matchers", zap.Int("index", i))
+			return fmt.Errorf("inhibit_rules[%d]: source and target matchers are required", i)
+		}
+		for _, matchers := range []map[string]string{rule.SourceMatchRE, rule.TargetMatchRE} {
+			for label, pattern := range matchers {
+				if _, err := regexp.Compile(pattern); err != nil {
+					logger.Error("Invalid inhibit rule regex", zap.Int("index", i), zap.String("label", label), zap.Error(err))
+					return fmt.Errorf("inhibit_rules[%d]: invalid regex for label '%s': %w", i, label, err)
+				}
+			}
+		}
+	}
+
+	known := config.Notifications.MinSeverities()
+
+	// Validate the "all OK" heartbeat
+	if config.Monitor.HeartbeatSeconds < 0 {
+		logger.Error("Invalid heartbeat interval", zap.Int("heartbeat_seconds", int(config.Monitor.HeartbeatSeconds)))
+		return fmt.Errorf("monitor.heartbeat_seconds must not be negative")
+	}
+	if config.Monitor.HeartbeatSeconds > 0 && len(config.Monitor.HeartbeatNotifiers) == 0 {
+		logger.Error("Heartbeat has no notifiers")
+		return fmt.Errorf("monitor.heartbeat_notifiers are required when monitor.heartbeat_seconds is set")
+	}
+	for _, name := range config.Monitor.HeartbeatNotifiers {
+		if _, ok := known[name]; !ok {
+			logger.Error("Heartbeat names an unknown notifier", zap.String("notifier", name))
+			return fmt.Errorf("monitor.heartbeat_notifiers: unknown notifier '%s'", name)
+		}
+	}
+
+	// Validate routes
+	for i, route := range config.Routes {
+		if len(route.Notifiers) == 0 {
+			logger.Error("Route has no notifiers", zap.Int("index", i))
+			return fmt.Errorf("routes[%d]: notifiers are required", i)
+		}
+		for _, name := range route.Notifiers {
+			if _, ok := known[name]; !ok {
+				logger.Error("Route names an unknown notifier", zap.Int("index", i), zap.String("notifier", name))
+				return fmt.Errorf("routes[%d]: unknown notifier '%s'", i, name)
+			}
+		}
+		for _, severity := range route.Severities {
+			switch severity {
+			case "info", "warning", "critical":
+			default:
+				logger.Error("Invalid route severity", zap.Int("index", i), zap.String("severity", severity))
+				return fmt.Errorf("routes[%d]: severities must be info, warning or critical", i)
+			}
+		}
+		for label, pattern := range route.MatchRE {
+			if _, err := regexp.Compile(pattern); err != nil {
+				logger.Error("Invalid route regex", zap.Int("index", i), zap.String("label", label), zap.Error(err))
+				return fmt.Errorf("routes[%d]: invalid regex for label '%s': %w", i, label, err)
+			}
+		}
+	}
+
+	// Validate mute rules
+	for i, rule := range config.Mutes.Rules {
+		if err := ValidateMuteRule(rule); err != nil {
+			logger.Error("Invalid mute rule", zap.Int("index", i), zap.Error(err))
+			return fmt.Errorf("mutes.rules[%d]: %w", i, err)
+		}
+	}
+
+	names := make(map[string]bool)
+	for i, window := range config.Maintenance.Windows {
+		if err := ValidateMaintenanceWindow(window); err != nil {
+			logger.Error("Invalid maintenance window", zap.Int("index", i), zap.Error(err))
+			return fmt.Errorf("maintenance.windows[%d]: %w", i, err)
+		}
+		if names[window.Name] {
+			logger.Error("Duplicate maintenance window", zap.String("name", window.Name))
+			return fmt.Errorf("maintenance.windows[%d]: duplicate window name '%s'", i, window.Name)
+		}
+		names[window.Name] = true
+	}
+
 	return nil
 }
 
+// ValidateAnomaly fills in anomaly detection defaults and checks the settings
+func ValidateAnomaly(anomaly *AnomalyConfig) error {
+	if anomaly.Metric == "" {
+		return fmt.Errorf("anomaly detection needs a metric")
+	}
+
+	switch anomaly.Method {
+	case "":
+		anomaly.Method = "rolling"
+	case "rolling", "ewma":
+	default:
+		return fmt.Errorf("method must be rolling or ewma")
+	}
+	switch anomaly.Direction {
+	case "":
+		anomaly.Direction = "both"
+	case "above", "below", "both":
+	default:
+		return fmt.Errorf("direction must be above, below or both")
+	}
+	switch anomaly.Severity {
+	case "":
+		anomaly.Severity = "warning"
+	case "info", "warning", "critical":
+	default:
+		return fmt.Errorf("severity must be info, warning or critical")
+	}
+
+	if anomaly.Window < 0 || anomaly.Sigma < 0 || anomaly.MinSamples < 0 || anomaly.Alpha < 0 || anomaly.Alpha > 1 {
+		return fmt.Errorf("window, sigma and min_samples must not be negative and alpha must be between 0 and 1")
+	}
+	if anomaly.Window == 0 {
+		anomaly.Window = 100
+	}
+	if anomaly.Alpha == 0 {
+		anomaly.Alpha = 0.1
+	}
+	if anomaly.Sigma == 0 {
+		anomaly.Sigma = 3
+	}
+	if anomaly.MinSamples == 0 {
+		anomaly.MinSamples = 30
+	}
+	if anomaly.Method == "rolling" && anomaly.MinSamples > anomaly.Window {
+		return fmt.Errorf("min_samples must not exceed window")
+	}
+	return nil
+}
+
+// ValidateThreshold fills in threshold defaults and checks the settings
+func ValidateThreshold(threshold *ThresholdConfig) error {
+	if threshold.Metric == "" {
+		return fmt.Errorf("threshold needs a metric")
+	}
+
+	switch threshold.Operator {
+	case "less_than", "greater_than", "equals":
+	case "between", "outside_range":
+		if threshold.Min == nil || threshold.Max == nil || *threshold.Min > *threshold.Max {
+			return fmt.Errorf("%s needs min and max, with min not above max", threshold.Operator)
+		}
+	case "rate_of_change":
+		if threshold.Value <= 0 {
+			return fmt.Errorf("rate_of_change needs a positive value, the largest change per minute")
+		}
+	default:
+		return fmt.Errorf("operator must be less_than, greater_than, equals, between, outside_range or rate_of_change")
+	}
+	if threshold.Clear != nil && threshold.Operator != "less_than" && threshold.Operator != "greater_than" {
+		return fmt.Errorf("clear is only supported for less_than and greater_than")
+	}
+
+	switch threshold.Severity {
+	case "":
+		threshold.Severity = "warning"
+	case "info", "warning", "critical":
+	default:
+		return fmt.Errorf("severity must be info, warning or critical")
+	}
+	return nil
+}
+
+// dependencyCycle returns a description of a dependency cycle between
+// collectors, or "" when there is none
+func dependencyCycle(collectorConfigs map[string]CollectorConfig) string {
+	names := make([]string, 0, len(collectorConfigs))
+	for name := range collectorConfigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(names))
+	var path []string
+
+	var visit func(name string) string
+	visit = func(name string) string {
+		switch state[name] {
+		case visiting:
+			return strings.Join(append(path, name), " -> ")
+		case done:
+			return ""
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range collectorConfigs[name].DependsOn {
+			if cycle := visit(dep.Collector); cycle != "" {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = done
+		return ""
+	}
+
+	for _, name := range names {
+		if cycle := visit(name); cycle != "" {
+			return cycle
+		}
+	}
+	return ""
+}
+
+// ValidateMaintenanceWindow checks that a maintenance window has a name and
+// exactly one valid schedule
+func ValidateMaintenanceWindow(window MaintenanceWindow) error {
+	if window.Name == "" {
+		return fmt.Errorf("maintenance window needs a name")
+	}
+
+	oneOff := !window.Start.IsZero() || !window.End.IsZero()
+	if oneOff == (window.Recurring != nil) {
+		return fmt.Errorf("maintenance window '%s' needs either start and end, or recurring", window.Name)
+	}
+
+	if oneOff {
+		if window.Start.IsZero() || window.End.IsZero() || !window.End.After(window.Start) {
+			return fmt.Errorf("maintenance window '%s' needs a start before its end", window.Name)
+		}
+		return nil
+	}
+
+	recurring := window.Recurring
+	if _, err := time.Parse("15:04", recurring.Start); err != nil {
+		return fmt.Errorf("maintenance window '%s' start must be HH:MM: %w", window.Name, err)
+	}
+	if recurring.DurationMinutes <= 0 {
+		return fmt.Errorf("maintenance window '%s' needs a positive duration_minutes", window.Name)
+	}
+	if _, err := time.LoadLocation(recurring.Timezone); err != nil {
+		return fmt.Errorf("maintenance window '%s' has an invalid timezone: %w", window.Name, err)
+	}
+	for _, day := range recurring.Days {
+		if _, ok := Weekdays[strings.ToLower(day)]; !ok {
+			return fmt.Errorf("maintenance window '%s' has an invalid day '%s'", window.Name, day)
+		}
+	}
+	return nil
+}
+
+// Weekdays maps the day names accepted in schedules to weekdays
+var Weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// ValidateMuteRule checks that a mute rule has at least one valid matcher
+func ValidateMuteRule(rule MuteRule) error {
+	if rule.Collector == "" && rule.MessageRegex == "" && len(rule.Metadata) == 0 {
+		return fmt.Errorf("mute rule '%s' needs a collector, message_regex or metadata matcher", rule.ID)
+	}
+	if rule.MessageRegex != "" {
+		if _, err := regexp.Compile(rule.MessageRegex); err != nil {
+			return fmt.Errorf("mute rule '%s' has an invalid message_regex: %w", rule.ID, err)
+		}
+	}
+	return nil
+}
+
+// CollectorSettings returns the settings a collector is initialized with.
+// Host names in its "hosts" setting are replaced by the host definitions, so
+// the collector can reach them without knowing the hosts section.
+func (c *Config) CollectorSettings(collectorName string) map[string]interface{} {
+	settings := make(map[string]interface{}, len(c.Collectors[collectorName].Settings))
+	for key, value := range c.Collectors[collectorName].Settings {
+		settings[key] = value
+	}
+
+	if c.Monitor.Mode == ModeContainer {
+		for key, value := range containerDefaults[collectorName] {
+			if _, set := settings[key]; !set {
+				settings[key] = value
+			}
+		}
+	}
+
+	if collector := c.Collectors[collectorName]; collector.Type == CollectorTypePlugin {
+		args := make([]interface{}, len(collector.Args))
+		for i, arg := range collector.Args {
+			args[i] = arg
+		}
+		settings["command"] = collector.Command
+		settings["args"] = args
+	}
+
+	names, ok := settings["hosts"].([]interface{})
+	if !ok {
+		return settings
+	}
+	hosts := make([]interface{}, len(names))
+	for i, name := range names {
+		hosts[i] = name
+		hostName, _ := name.(string)
+		host, defined := c.Hosts[hostName]
+		if !defined {
+			continue
+		}
+		timeout := host.TimeoutSeconds
+		if timeout == 0 {
+			timeout = 10
+		}
+		hosts[i] = map[string]interface{}{
+			"name":                     hostName,
+			"address":                  host.Address,
+			"user":                     host.User,
+			"key_file":                 host.KeyFile,
+			"known_hosts_file":         host.KnownHostsFile,
+			"insecure_ignore_host_key": host.InsecureIgnoreHostKey,
+			"timeout_seconds":          float64(timeout),
+		}
+	}
+	settings["hosts"] = hosts
+	return settings
+}
+
+// GetCollectorMaxSeries returns the maximum number of distinct targets a collector may emit per run
+func (c *Config) GetCollectorMaxSeries(collectorName string) int {
+	if collector, exists := c.Collectors[collectorName]; exists && collector.MaxSeries > 0 {
+		return collector.MaxSeries
+	}
+	return c.Monitor.MaxSeriesPerCollector
+}
+
 // GetCollectorInterval returns the interval for a collector in duration
 func (c *Config) GetCollectorInterval(collectorName string) time.Duration {
 	collector, exists := c.Collectors[collectorName]
@@ -123,3 +1742,30 @@ func (c *Config) GetCollectorInterval(collectorName string) time.Duration {
 
 	return time.Duration(interval) * time.Second
 }
+
+// GetFor returns how long a collector's unhealthy condition must hold before
+// it alerts. Zero alerts on the first unhealthy evaluation.
+func (c *Config) GetFor(collectorName string) time.Duration {
+	return time.Duration(c.Collectors[collectorName].ForSeconds) * time.Second
+}
+
+// GetSplay returns the maximum random delay spreading out a collector's runs,
+// falling back to the monitor default
+func (c *Config) GetSplay(collectorName string) time.Duration {
+	seconds := c.Monitor.SplaySeconds
+	if collector, exists := c.Collectors[collectorName]; exists && collector.SplaySeconds > 0 {
+		seconds = collector.SplaySeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// GetRenotifyAfter returns how long repeat notifications for a collector's
+// alerts are suppressed, falling back to the monitor default. Zero notifies
+// on every evaluation.
+func (c *Config) GetRenotifyAfter(collectorName string) time.Duration {
+	seconds := c.Monitor.RenotifyAfterSeconds
+	if collector, exists := c.Collectors[collectorName]; exists && collector.RenotifyAfterSeconds > 0 {
+		seconds = collector.RenotifyAfterSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,86 @@
+// config/duration.go
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Seconds is a number of seconds. Like the other duration types below, it is
+// written in the configuration as a whole number of its unit, as before, or
+// as a duration string such as "30s", "5m" or "1h".
+type Seconds int
+
+// Milliseconds is a number of milliseconds
+type Milliseconds int
+
+// Minutes is a number of minutes
+type Minutes int
+
+// Hours is a number of hours
+type Hours int
+
+// Duration returns the number of seconds as a duration
+func (s Seconds) Duration() time.Duration { return time.Duration(s) * time.Second }
+
+// Duration returns the number of milliseconds as a duration
+func (m Milliseconds) Duration() time.Duration { return time.Duration(m) * time.Millisecond }
+
+// Duration returns the number of minutes as a duration
+func (m Minutes) Duration() time.Duration { return time.Duration(m) * time.Minute }
+
+// Duration returns the number of hours as a duration
+func (h Hours) Duration() time.Duration { return time.Duration(h) * time.Hour }
+
+// UnmarshalYAML accepts a number of seconds or a duration string
+func (s *Seconds) UnmarshalYAML(value *yaml.Node) error {
+	n, err := decodeDuration(value, time.Second)
+	*s = Seconds(n)
+	return err
+}
+
+// UnmarshalYAML accepts a number of milliseconds or a duration string
+func (m *Milliseconds) UnmarshalYAML(value *yaml.Node) error {
+	n, err := decodeDuration(value, time.Millisecond)
+	*m = Milliseconds(n)
+	return err
+}
+
+// UnmarshalYAML accepts a number of minutes or a duration string
+func (m *Minutes) UnmarshalYAML(value *yaml.Node) error {
+	n, err := decodeDuration(value, time.Minute)
+	*m = Minutes(n)
+	return err
+}
+
+// UnmarshalYAML accepts a number of hours or a duration string
+func (h *Hours) UnmarshalYAML(value *yaml.Node) error {
+	n, err := decodeDuration(value, time.Hour)
+	*h = Hours(n)
+	return err
+}
+
+// decodeDuration decodes a YAML value as a whole number of unit. Errors are
+// returned as type errors, so they are reported with the line like any other
+// value of the wrong type.
+func decodeDuration(value *yaml.Node, unit time.Duration) (int, error) {
+	if value.Kind != yaml.ScalarNode || (value.Tag != "!!int" && value.Tag != "!!str") {
+		return 0, &yaml.TypeError{Errors: []string{
+			fmt.Sprintf("line %d: cannot unmarshal %s into a duration, expected a number of %s or a duration such as 30s, 5m or 1h", value.Line, value.ShortTag(), collectors.UnitName(unit)),
+		}}
+	}
+	d, err := collectors.ParseDuration(value.Value, unit)
+	if err != nil {
+		return 0, &yaml.TypeError{Errors: []string{fmt.Sprintf("line %d: %v", value.Line, err)}}
+	}
+	if d%unit != 0 {
+		return 0, &yaml.TypeError{Errors: []string{
+			fmt.Sprintf("line %d: duration %q is not a whole number of %s", value.Line, value.Value, collectors.UnitName(unit)),
+		}}
+	}
+	return int(d / unit), nil
+}
@@ -0,0 +1,76 @@
+// config/duration_test.go
+package config
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestDecodeDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		unit    time.Duration
+		want    int
+		wantErr bool
+	}{
+		{value: "30", unit: time.Second, want: 30},
+		{value: `"30s"`, unit: time.Second, want: 30},
+		{value: "5m", unit: time.Second, want: 300},
+		{value: "5m", unit: time.Minute, want: 5},
+		{value: "1h", unit: time.Minute, want: 60},
+		{value: "500ms", unit: time.Millisecond, want: 500},
+		{value: "250", unit: time.Millisecond, want: 250},
+		{value: "2", unit: time.Hour, want: 2},
+		{value: "500ms", unit: time.Second, wantErr: true},
+		{value: "90s", unit: time.Minute, wantErr: true},
+		{value: "soon", unit: time.Second, wantErr: true},
+		{value: "1.5", unit: time.Second, wantErr: true},
+		{value: "true", unit: time.Second, wantErr: true},
+		{value: "[30]", unit: time.Second, wantErr: true},
+		{value: "{seconds: 30}", unit: time.Second, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value+" "+tt.unit.String(), func(t *testing.T) {
+			var node yaml.Node
+			if err := yaml.Unmarshal([]byte(tt.value), &node); err != nil {
+				t.Fatalf("Unmarshal(%q): %v", tt.value, err)
+			}
+
+			got, err := decodeDuration(node.Content[0], tt.unit)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("decodeDuration(%s) = %d, want an error", tt.value, got)
+				}
+				if _, ok := err.(*yaml.TypeError); !ok {
+					t.Errorf("decodeDuration(%s) returned %T, want a *yaml.TypeError", tt.value, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeDuration(%s): %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("decodeDuration(%s) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSecondsField(t *testing.T) {
+	var settings struct {
+		Interval Seconds `yaml:"interval_seconds"`
+	}
+	if err := yaml.Unmarshal([]byte("interval_seconds: 2m\n"), &settings); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if settings.Interval.Duration() != 2*time.Minute {
+		t.Errorf("interval is %s, want 2m0s", settings.Interval.Duration())
+	}
+
+	err := yaml.Unmarshal([]byte("interval_seconds: 500ms\n"), &settings)
+	if err == nil {
+		t.Fatal("500ms decoded into Seconds without an error")
+	}
+}
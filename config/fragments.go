@@ -0,0 +1,207 @@
+// config/fragments.go
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// Sections whose entries fragments may add to: each entry is defined once,
+// in any file
+var namedSections = map[string]bool{
+	"":                      true,
+	"collectors":            true,
+	"outputs":               true,
+	"hosts":                 true,
+	"notifications":         true,
+	"notifications.plugins": true,
+	"mutes":                 true,
+	"maintenance":           true,
+}
+
+// Lists that fragments append to, in file order
+var appendedLists = map[string]bool{
+	"routes":              true,
+	"inhibit_rules":       true,
+	"mutes.rules":         true,
+	"maintenance.windows": true,
+}
+
+// FragmentFiles returns the YAML files of a configuration directory, in the
+// order they are merged: sorted by name
+func FragmentFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// LoadConfigDir loads the configuration file at path, if any, merged with
+// the fragments in dir, a conf.d style directory. Fragments are merged in
+// name order after the file. They may add collectors, outputs, hosts,
+// notifiers, plugin notifiers and top-level sections not set elsewhere, and
+// append routes, inhibition rules, mute rules and maintenance windows.
+// Anything else defined twice is an error naming both files.
+func LoadConfigDir(logger *zap.Logger, path, dir string, overrides ...Override) (*Config, error) {
+	var layers []layer
+	if path != "" {
+		layers = append(layers, layer{file: path})
+	}
+	fragments, err := fragmentLayers(logger, dir)
+	if err != nil {
+		return nil, err
+	}
+	return loadLayers(logger, append(layers, fragments...), dir, overrides)
+}
+
+// layer is a file merged into the configuration: a fragment, which may
+// only add to it, or an overlay, which may also change it
+type layer struct {
+	file    string
+	overlay bool
+}
+
+// fragmentLayers returns the fragments of a configuration directory
+func fragmentLayers(logger *zap.Logger, dir string) ([]layer, error) {
+	files, err := FragmentFiles(dir)
+	if err != nil {
+		logger.Error("Error reading config directory", zap.String("dir", dir), zap.Error(err))
+		return nil, err
+	}
+	layers := make([]layer, len(files))
+	for i, file := range files {
+		layers[i] = layer{file: file}
+	}
+	return layers, nil
+}
+
+// loadLayers merges the layers in order, then decodes and validates the
+// result. source names the configuration in log messages.
+func loadLayers(logger *zap.Logger, layers []layer, source string, overrides []Override) (*Config, error) {
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	origins := make(map[string]string)
+	for _, layer := range layers {
+		file := layer.file
+		data, err := os.ReadFile(file)
+		if err != nil {
+			logger.Error("Error reading config file", zap.String("path", file), zap.Error(err))
+			return nil, err
+		}
+		// Each file is decoded on its own first, so unknown keys and
+		// values of the wrong type are reported with its lines
+		var doc yaml.Node
+		err = yaml.Unmarshal(data, &doc)
+		if err == nil {
+			err = decodeStrict(data, &Config{})
+		}
+		if err != nil {
+			logger.Error("Error parsing config file", zap.String("path", file), zap.Error(err))
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if len(doc.Content) == 0 {
+			continue // Empty file
+		}
+		root := doc.Content[0]
+		if root.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("%s: expected a mapping at the top level", file)
+		}
+		if layer.overlay {
+			overlayFile(merged, root, "", file, origins)
+			continue
+		}
+		if err := mergeFragment(merged, root, "", file, origins); err != nil {
+			logger.Error("Error merging config file", zap.String("path", file), zap.Error(err))
+			return nil, err
+		}
+	}
+
+	var config Config
+	if err := merged.Decode(&config); err != nil {
+		logger.Error("Error parsing merged config", zap.String("path", source), zap.Error(err))
+		return nil, err
+	}
+	if err := applyOverrides(logger, &config, overrides); err != nil {
+		return nil, err
+	}
+	if err := validateConfig(logger.Named("validate"), &config); err != nil {
+		logger.Error("Invalid configuration", zap.String("path", source), zap.Error(err))
+		return nil, err
+	}
+	return &config, nil
+}
+
+// mergeFragment merges the mapping src of file into dst. origins records
+// the file each merged key came from, by its dotted path.
+func mergeFragment(dst, src *yaml.Node, path, file string, origins map[string]string) error {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		child := key.Value
+		if path != "" {
+			child = path + "." + key.Value
+		}
+
+		existing := mappingValue(dst, key.Value)
+		switch {
+		case existing == nil:
+			dst.Content = append(dst.Content, key, value)
+			recordOrigins(value, child, file, origins)
+		case appendedLists[child] && existing.Kind == yaml.SequenceNode && value.Kind == yaml.SequenceNode:
+			existing.Content = append(existing.Content, value.Content...)
+		case namedSections[child] && existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			if err := mergeFragment(existing, value, child, file, origins); err != nil {
+				return err
+			}
+		case isNull(existing):
+			// e.g. "collectors:" with nothing below it
+			*existing = *value
+			recordOrigins(value, child, file, origins)
+		case isNull(value):
+		default:
+			return fmt.Errorf("%s is defined in both %s and %s", child, origins[child], file)
+		}
+	}
+	return nil
+}
+
+// recordOrigins records file as the origin of a merged value and, within
+// named sections, of each of its entries
+func recordOrigins(value *yaml.Node, path, file string, origins map[string]string) {
+	origins[path] = file
+	if !namedSections[path] || value.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		recordOrigins(value.Content[i+1], path+"."+value.Content[i].Value, file, origins)
+	}
+}
+
+// mappingValue returns the value of key in a mapping node, or nil
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// isNull reports whether a node is an empty YAML value
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
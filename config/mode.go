@@ -0,0 +1,46 @@
+// config/mode.go
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// Operating modes, set with monitor.mode
+const (
+	ModeHost      = "host"      // The default: the service watches the machine it runs on
+	ModeContainer = "container" // The service runs in a container and watches it
+	ModeAuto      = "auto"      // Container mode when a container is detected
+)
+
+// containerDefaults are the collector settings container mode uses unless
+// a collector sets them: memory is measured against the container's limit
+// and the container's own root filesystem is not checked
+var containerDefaults = map[string]map[string]interface{}{
+	"memory":     {"source": "cgroup"},
+	"disk_space": {"skip_overlay": true},
+}
+
+// InContainer reports whether the process appears to run in a container:
+// the runtime left its marker file, Kubernetes set its environment, PID 1
+// is in a container runtime's cgroup, or the process is at the root of its
+// own cgroup namespace
+func InContainer() bool {
+	for _, marker := range []string{"/.dockerenv", "/run/.containerenv"} {
+		if _, err := os.Stat(marker); err == nil {
+			return true
+		}
+	}
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return true
+	}
+	if data, err := os.ReadFile("/proc/1/cgroup"); err == nil {
+		for _, runtime := range []string{"docker", "kubepods", "containerd", "libpod", "lxc"} {
+			if strings.Contains(string(data), runtime) {
+				return true
+			}
+		}
+	}
+	data, err := os.ReadFile("/proc/self/cgroup")
+	return err == nil && strings.TrimSpace(string(data)) == "0::/"
+}
@@ -0,0 +1,64 @@
+// config/profiles.go
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// ProfilePath returns the overlay file of a profile, next to the
+// configuration file: config.prod.yaml for config.yaml and the prod profile
+func ProfilePath(path, profile string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + profile + ext
+}
+
+// LoadConfigProfile loads the configuration file at path with the overlay of
+// profile merged over it, then the fragments in dir, if given, as
+// LoadConfigDir does. The overlay only needs the keys that differ: its
+// mappings merge into the file's, and its other values, lists included,
+// replace the file's.
+func LoadConfigProfile(logger *zap.Logger, path, profile, dir string, overrides ...Override) (*Config, error) {
+	if profile == "" || strings.ContainsAny(profile, `/\`) || strings.HasPrefix(profile, ".") {
+		logger.Error("Invalid profile", zap.String("profile", profile))
+		return nil, fmt.Errorf("invalid profile %q", profile)
+	}
+	layers := []layer{{file: path}, {file: ProfilePath(path, profile), overlay: true}}
+	if dir != "" {
+		fragments, err := fragmentLayers(logger, dir)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, fragments...)
+	}
+	return loadLayers(logger, layers, ProfilePath(path, profile), overrides)
+}
+
+// overlayFile merges the mapping src of an overlay file into dst: mappings
+// are merged key by key, anything else replaces the value in dst. origins
+// records the file each replaced value came from, by its dotted path.
+func overlayFile(dst, src *yaml.Node, path, file string, origins map[string]string) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		child := key.Value
+		if path != "" {
+			child = path + "." + key.Value
+		}
+
+		existing := mappingValue(dst, key.Value)
+		switch {
+		case existing == nil:
+			dst.Content = append(dst.Content, key, value)
+			recordOrigins(value, child, file, origins)
+		case existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			overlayFile(existing, value, child, file, origins)
+		default:
+			*existing = *value
+			recordOrigins(value, child, file, origins)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// debug.go
+package main
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+
+	"github.com/devvspaces/simple-monit/config"
+	"github.com/devvspaces/simple-monit/monitor"
+
+	"go.uber.org/zap"
+)
+
+// startDebugServer serves net/http/pprof under /debug/pprof/ and expvar under
+// /debug/vars, with the goroutine count, the collectors' run timings and the
+// notifiers' delivery latencies published next to the runtime's memstats.
+// It uses its own mux, so the profiles are never reachable through the API.
+func startDebugServer(logger *zap.Logger, cfg config.DebugConfig, service *monitor.MonitorService) (*http.Server, error) {
+	expvar.Publish("goroutines", expvar.Func(func() interface{} { return runtime.NumGoroutine() }))
+	expvar.Publish("collectors", expvar.Func(func() interface{} { return service.Collectors() }))
+	expvar.Publish("alert_latency", expvar.Func(func() interface{} { return service.AlertLatencyStats() }))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	listener, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		logger.Error("Failed to listen for debug requests", zap.String("listen", cfg.Listen), zap.Error(err))
+		return nil, err
+	}
+	if host, _, _ := net.SplitHostPort(cfg.Listen); !isLoopback(host) {
+		logger.Warn("Debug listener is not on a loopback address; profiles and command line are exposed unauthenticated", zap.String("listen", cfg.Listen))
+	}
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Debug listener failed", zap.String("listen", cfg.Listen), zap.Error(err))
+		}
+	}()
+	logger.Info("Serving debug endpoints", zap.String("listen", listener.Addr().String()))
+	return server, nil
+}
+
+// stopDebugServer closes the debug listener, ending running profiles
+func stopDebugServer(logger *zap.Logger, server *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		server.Close()
+	}
+	logger.Debug("Debug listener stopped")
+}
+
+// isLoopback reports whether host is localhost or a loopback address
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,108 @@
+// describe.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/devvspaces/simple-monit/collectors"
+)
+
+// runDescribe implements the "describe" subcommand, which prints the
+// settings schema of one collector, notifier or output compiled into this
+// binary, with a configuration snippet to start from
+func runDescribe(args []string) int {
+	usage := "usage: server-monitor describe <collector|notifier|output> <name> [flags]"
+	if len(args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+	catalog, section, ok := componentCatalog(args[0])
+	if !ok {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("describe "+args[0], flag.ContinueOnError)
+	format := fs.String("format", "table", "Output format: table or json")
+	if err := fs.Parse(args[2:]); err != nil {
+		return 2
+	}
+	if *format != "table" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "Unknown format %q: expected table or json\n", *format)
+		return 2
+	}
+
+	component, ok := findComponent(catalog, args[1])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "No %s named %q is compiled into this binary; see \"server-monitor list %s\"\n", kindName(args[0]), args[1], section)
+		return 1
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(component)
+		return 0
+	}
+
+	title := component.Name
+	if component.Description != "" {
+		title += " - " + component.Description
+	}
+	fmt.Println(title)
+	fmt.Println()
+	if len(component.Settings) == 0 {
+		fmt.Println("No settings described")
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY\tTYPE\tDEFAULT\tREQUIRED\tDESCRIPTION")
+		describeSettings(w, component.Settings, "")
+		w.Flush()
+	}
+
+	fmt.Println()
+	switch {
+	case component.Type:
+		fmt.Printf("Collectors of type %s go under collectors:, by a name of your choice, with type: %s.\n", component.Name, component.Name)
+	case section == "notifiers" && component.Name == "plugins":
+		fmt.Println("Plugin notifiers go under notifications.plugins:, by a name of your choice.")
+	default:
+		var b bytes.Buffer
+		writeComponent(&b, component, "  ", section != "notifiers")
+		fmt.Println("Example:")
+		fmt.Printf("%s:\n%s", configSection(section), bytes.TrimRight(b.Bytes(), "\n"))
+		fmt.Println()
+	}
+	return 0
+}
+
+// describeSettings writes a line per setting, with the keys of lists of
+// objects indented below them
+func describeSettings(w io.Writer, settings []collectors.Setting, indent string) {
+	for _, setting := range settings {
+		value, required := setting.Default, "no"
+		if value == "" {
+			value = "-"
+		}
+		if setting.Required {
+			required = "yes"
+		}
+		fmt.Fprintf(w, "%s%s\t%s\t%s\t%s\t%s\n", indent, setting.Name, setting.Type, value, required, setting.Description)
+		describeSettings(w, setting.Fields, indent+"  ")
+	}
+}
+
+// configSection returns the configuration section components of a catalog
+// go under
+func configSection(section string) string {
+	if section == "notifiers" {
+		return "notifications"
+	}
+	return section
+}
@@ -0,0 +1,156 @@
+// evaluator/evaluator.go
+package evaluator
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/config"
+)
+
+// sample is the previous value of a metric, for rates of change
+type sample struct {
+	value float64
+	at    time.Time
+}
+
+// Evaluator decides the health of results from their metrics and
+// thresholds: the ones declared by the collector plus the ones configured
+// for it. Collectors only report metrics and thresholds, or mark a result
+// unhealthy for failures no threshold describes (e.g. a refused connection),
+// so every operator is available for every collector.
+type Evaluator struct {
+	previous map[string]sample
+	mu       sync.Mutex
+}
+
+// New creates an evaluator with no rate history
+func New() *Evaluator {
+	return &Evaluator{previous: make(map[string]sample)}
+}
+
+// Evaluate adds the configured thresholds to the result and marks it
+// unhealthy when any threshold trips, with a message listing them and the
+// most severe tripped severity. A result already unhealthy keeps its
+// message. rate_of_change thresholds add a "<metric>_per_minute" metric,
+// starting from the second result of the target.
+func (e *Evaluator) Evaluate(result collectors.Result, rules []config.ThresholdConfig) collectors.Result {
+	if len(rules) > 0 {
+		result.Thresholds = append([]collectors.Threshold{}, result.Thresholds...)
+		metrics := make(map[string]float64, len(result.Metrics))
+		for name, value := range result.Metrics {
+			metrics[name] = value
+		}
+		result.Metrics = metrics
+
+		for _, rule := range rules {
+			if threshold, ok := e.threshold(result, rule); ok {
+				result.Thresholds = append(result.Thresholds, threshold)
+			}
+		}
+	}
+
+	var tripped []string
+	for _, threshold := range result.Thresholds {
+		if threshold.Tripped(result.Metrics) {
+			tripped = append(tripped, Describe(threshold, result.Metrics))
+		}
+	}
+	if len(tripped) == 0 {
+		return result
+	}
+
+	if result.IsHealthy {
+		result.IsHealthy = false
+		result.Message = strings.Join(tripped, "; ")
+	}
+	if severity := result.ThresholdSeverity(); !collectors.SeverityAtLeast(result.Severity, severity) {
+		result.Severity = severity
+	}
+	return result
+}
+
+// threshold converts a configured threshold for the result. It reports false
+// when the result lacks the metric, or a rate has no previous sample yet.
+func (e *Evaluator) threshold(result collectors.Result, rule config.ThresholdConfig) (collectors.Threshold, bool) {
+	value, ok := result.Metrics[rule.Metric]
+	if !ok {
+		return collectors.Threshold{}, false
+	}
+
+	threshold := collectors.Threshold{
+		Type:     "configured",
+		Metric:   rule.Metric,
+		Operator: rule.Operator,
+		Value:    rule.Value,
+		Severity: rule.Severity,
+		Clear:    rule.Clear,
+	}
+
+	switch rule.Operator {
+	case "between", "outside_range":
+		threshold.Value, threshold.Max = *rule.Min, *rule.Max
+	case "rate_of_change":
+		rate, ok := e.rate(result, rule.Metric, value)
+		if !ok {
+			return collectors.Threshold{}, false
+		}
+		name := rule.Metric + "_per_minute"
+		result.Metrics[name] = rate
+		threshold = collectors.Threshold{
+			Type:     "rate",
+			Metric:   name,
+			Operator: "outside_range",
+			Value:    -rule.Value,
+			Max:      rule.Value,
+			Severity: rule.Severity,
+			Message: fmt.Sprintf("%s changing by %.2f per minute (limit: ±%.2f)",
+				rule.Metric, rate, rule.Value),
+		}
+	}
+	return threshold, true
+}
+
+// rate returns the metric's change per minute since the target's previous
+// result, and records the current value
+func (e *Evaluator) rate(result collectors.Result, metric string, value float64) (float64, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	key := result.Key() + "|" + metric
+	prev, ok := e.previous[key]
+	e.previous[key] = sample{value: value, at: result.Timestamp}
+	if !ok {
+		return 0, false
+	}
+
+	elapsed := result.Timestamp.Sub(prev.at).Minutes()
+	if elapsed <= 0 {
+		return 0, false
+	}
+	return (value - prev.value) / elapsed, true
+}
+
+// Describe explains a tripped threshold: its own message when the collector
+// set one, or else the comparison, e.g. "used_percent 95.00 > 90.00"
+func Describe(threshold collectors.Threshold, metrics map[string]float64) string {
+	if threshold.Message != "" {
+		return threshold.Message
+	}
+
+	value := metrics[threshold.Metric]
+	switch threshold.Operator {
+	case "between":
+		return fmt.Sprintf("%s %.2f within %.2f to %.2f", threshold.Metric, value, threshold.Value, threshold.Max)
+	case "outside_range":
+		return fmt.Sprintf("%s %.2f outside %.2f to %.2f", threshold.Metric, value, threshold.Value, threshold.Max)
+	case "less_than":
+		return fmt.Sprintf("%s %.2f < %.2f", threshold.Metric, value, threshold.Value)
+	case "greater_than":
+		return fmt.Sprintf("%s %.2f > %.2f", threshold.Metric, value, threshold.Value)
+	}
+	return fmt.Sprintf("%s %.2f = %.2f", threshold.Metric, value, threshold.Value)
+}
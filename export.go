@@ -0,0 +1,111 @@
+// export.go
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/devvspaces/simple-monit/monitor"
+	"github.com/devvspaces/simple-monit/storage"
+)
+
+// exportable are the records the export subcommand exports, with the
+// default start of their range and how they are read from the API and
+// written as CSV
+var exportable = map[string]struct {
+	since string
+	read  func(base, bearer, query string) (interface{}, func(io.Writer) error, error)
+}{
+	"incidents": {since: "720h", read: func(base, bearer, query string) (interface{}, func(io.Writer) error, error) {
+		var events []storage.IncidentEvent
+		err := apiRequest(http.MethodGet, base+"/api/v1/incidents?"+query, bearer, &events)
+		return events, func(w io.Writer) error { return monitor.WriteIncidentsCSV(w, events) }, err
+	}},
+	"runs": {since: "24h", read: func(base, bearer, query string) (interface{}, func(io.Writer) error, error) {
+		var runs []storage.Run
+		err := apiRequest(http.MethodGet, base+"/api/v1/runs?"+query, bearer, &runs)
+		return runs, func(w io.Writer) error { return monitor.WriteRunsCSV(w, runs) }, err
+	}},
+}
+
+// runExport implements the "export" subcommand, which exports records kept
+// by the running service through its HTTP API: the incident timeline or the
+// audit of collector runs
+func runExport(args []string) int {
+	usage := "usage: server-monitor export incidents|runs [flags]"
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+	kind, ok := exportable[args[0]]
+	if !ok {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("export "+args[0], flag.ContinueOnError)
+	configPath := fs.String("config", "config.yaml", "Path to configuration file")
+	apiURL := fs.String("api", "", "Base URL of the service's API (default: from api.listen in the config)")
+	token := fs.String("token", "", "API token (default: api.token from the config)")
+	collector := fs.String("collector", "", "Only export the "+args[0]+" of this collector")
+	since := fs.String("since", kind.since, "Start of the range, a duration back from now or an RFC 3339 time")
+	until := fs.String("until", "", "End of the range, a duration back from now or an RFC 3339 time (default: now)")
+	format := fs.String("format", "json", "Output format: json or csv")
+	output := fs.String("output", "", "File to write to (default: standard output)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if *format != "json" && *format != "csv" {
+		fmt.Fprintf(os.Stderr, "Unknown format %q: expected json or csv\n", *format)
+		return 2
+	}
+
+	base, bearer, err := apiEndpoint(*configPath, *apiURL, *token)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	query := url.Values{}
+	query.Set("since", *since)
+	if *until != "" {
+		query.Set("until", *until)
+	}
+	if *collector != "" {
+		query.Set("collector", *collector)
+	}
+
+	records, writeCSV, err := kind.read(base, bearer, query.Encode())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get %s: %v\n", args[0], err)
+		return 1
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create %s: %v\n", *output, err)
+			return 1
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if *format == "csv" {
+		err = writeCSV(out)
+	} else {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(records)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write %s: %v\n", args[0], err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,300 @@
+// grpcapi/grpcapi.go
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/config"
+	"github.com/devvspaces/simple-monit/grpcapi/monitorpb"
+	"github.com/devvspaces/simple-monit/monitor"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server is the gRPC API of a running monitor service
+type Server struct {
+	monitorpb.UnimplementedMonitorServer
+
+	service  *monitor.MonitorService
+	listen   string
+	token    string
+	server   *grpc.Server
+	shutdown chan struct{} // Closed on shutdown, ending streams
+	logger   *zap.Logger
+}
+
+// New creates the gRPC server for the service. It does not listen yet.
+func New(logger *zap.Logger, cfg config.GRPCConfig, service *monitor.MonitorService) *Server {
+	s := &Server{
+		service:  service,
+		listen:   cfg.Listen,
+		token:    cfg.Token,
+		shutdown: make(chan struct{}),
+		logger:   logger,
+	}
+	s.server = grpc.NewServer(
+		grpc.UnaryInterceptor(s.authorizeUnary),
+		grpc.StreamInterceptor(s.authorizeStream),
+	)
+	monitorpb.RegisterMonitorServer(s.server, s)
+	return s
+}
+
+// Start listens on the configured address and serves in the background
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.listen)
+	if err != nil {
+		s.logger.Error("Failed to listen for gRPC requests", zap.String("listen", s.listen), zap.Error(err))
+		return err
+	}
+	if s.token == "" {
+		s.logger.Warn("gRPC API has no token; anyone who can reach it can use it", zap.String("listen", s.listen))
+	}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil {
+			s.logger.Error("gRPC listener failed", zap.String("listen", s.listen), zap.Error(err))
+		}
+	}()
+	s.logger.Info("Serving gRPC API", zap.String("listen", listener.Addr().String()))
+	return nil
+}
+
+// Shutdown ends streams, stops accepting requests and waits for running ones
+// to finish, cancelling them when ctx is done first
+func (s *Server) Shutdown(ctx context.Context) error {
+	close(s.shutdown)
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
+
+// authorizeUnary requires the configured bearer token on unary calls
+func (s *Server) authorizeUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// authorizeStream requires the configured bearer token on streaming calls
+func (s *Server) authorizeStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.authorize(stream.Context()); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// authorize checks the "authorization: Bearer <token>" metadata, when a token
+// is set
+func (s *Server) authorize(ctx context.Context) error {
+	if s.token == "" {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		token, ok := strings.CutPrefix(value, "Bearer ")
+		if ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+}
+
+// GetStatus returns the collectors, the latest result of every target and
+// the active alerts
+func (s *Server) GetStatus(ctx context.Context, req *monitorpb.GetStatusRequest) (*monitorpb.GetStatusResponse, error) {
+	alerts := s.service.ActiveAlerts()
+	resp := &monitorpb.GetStatusResponse{
+		Hostname: s.service.Hostname(),
+		Healthy:  len(alerts) == 0,
+		Alerts:   toAlerts(alerts),
+	}
+	for _, state := range s.service.Collectors() {
+		resp.Collectors = append(resp.Collectors, toCollectorStatus(state))
+	}
+	for _, result := range s.service.LatestResults() {
+		resp.Results = append(resp.Results, toResult(result))
+	}
+	return resp, nil
+}
+
+// ListAlerts returns the active alerts, oldest first
+func (s *Server) ListAlerts(ctx context.Context, req *monitorpb.ListAlertsRequest) (*monitorpb.ListAlertsResponse, error) {
+	return &monitorpb.ListAlertsResponse{Alerts: toAlerts(s.service.ActiveAlerts())}, nil
+}
+
+// RunCollector triggers a run of a running collector and returns its state
+func (s *Server) RunCollector(ctx context.Context, req *monitorpb.RunCollectorRequest) (*monitorpb.RunCollectorResponse, error) {
+	if !s.service.HasCollector(req.GetName()) {
+		return nil, status.Errorf(codes.NotFound, "unknown collector %q", req.GetName())
+	}
+	if err := s.service.TriggerCollector(req.GetName()); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	state, _ := s.service.CollectorState(req.GetName())
+	return &monitorpb.RunCollectorResponse{Collector: toCollectorStatus(state)}, nil
+}
+
+// Silence adds a mute rule to the mute rules file
+func (s *Server) Silence(ctx context.Context, req *monitorpb.SilenceRequest) (*monitorpb.SilenceResponse, error) {
+	rule := config.MuteRule{
+		Collector:    req.GetCollector(),
+		MessageRegex: req.GetMessageRegex(),
+		Comment:      req.GetComment(),
+	}
+	if len(req.GetMetadata()) > 0 {
+		rule.Metadata = req.GetMetadata()
+	}
+	if req.GetDuration() != nil {
+		duration := req.GetDuration().AsDuration()
+		if duration <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "duration must be positive")
+		}
+		rule.Expires = time.Now().Add(duration).Truncate(time.Second)
+	}
+	if err := config.ValidateMuteRule(rule); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	rule, err := s.service.Silence(rule)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	resp := &monitorpb.SilenceResponse{Id: rule.ID}
+	if !rule.Expires.IsZero() {
+		resp.Expires = timestamppb.New(rule.Expires)
+	}
+	return resp, nil
+}
+
+// StreamResults sends every result and alert transition, optionally of one
+// collector, until the client goes away or the server shuts down
+func (s *Server) StreamResults(req *monitorpb.StreamResultsRequest, stream monitorpb.Monitor_StreamResultsServer) error {
+	collector := req.GetCollector()
+	if collector != "" && !s.service.HasCollector(collector) {
+		return status.Errorf(codes.NotFound, "unknown collector %q", collector)
+	}
+
+	events, unsubscribe := s.service.Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-s.shutdown:
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if collector != "" && event.Collector() != collector {
+				continue
+			}
+			if err := stream.Send(toEvent(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toResult converts a result; scalar metadata values are formatted as
+// strings and other values dropped
+func toResult(result collectors.Result) *monitorpb.Result {
+	pb := &monitorpb.Result{
+		Collector:   result.Collector,
+		Key:         result.Key(),
+		Fingerprint: result.Fingerprint(),
+		Healthy:     result.IsHealthy,
+		Message:     result.Message,
+		Timestamp:   timestamppb.New(result.Timestamp),
+		Metrics:     result.Metrics,
+		Tags:        result.Tags,
+	}
+	if !result.IsHealthy {
+		pb.Severity = result.EffectiveSeverity()
+	}
+	if len(result.Metadata) > 0 {
+		pb.Metadata = make(map[string]string, len(result.Metadata))
+		for key, value := range result.Metadata {
+			switch value.(type) {
+			case string, bool, int, int64, float64:
+				pb.Metadata[key] = fmt.Sprint(value)
+			}
+		}
+	}
+	return pb
+}
+
+// toAlerts converts active alerts
+func toAlerts(alerts []monitor.ActiveAlert) []*monitorpb.Alert {
+	pb := make([]*monitorpb.Alert, 0, len(alerts))
+	for _, alert := range alerts {
+		pb = append(pb, &monitorpb.Alert{
+			Result:      toResult(alert.Result),
+			Since:       timestamppb.New(alert.Since),
+			InhibitedBy: alert.InhibitedBy,
+		})
+	}
+	return pb
+}
+
+// toCollectorStatus converts a collector's state
+func toCollectorStatus(state monitor.CollectorState) *monitorpb.CollectorStatus {
+	pb := &monitorpb.CollectorStatus{
+		Name:              state.Name,
+		Running:           state.Running,
+		Paused:            state.Paused,
+		Runs:              int64(state.Runs),
+		Failures:          int64(state.Failures),
+		ConsecutiveErrors: int64(state.ConsecutiveErrors),
+		LastDurationMs:    state.LastDurationMs,
+		LastError:         state.LastError,
+	}
+	if state.LastRun != nil {
+		pb.LastRun = timestamppb.New(*state.LastRun)
+	}
+	if state.NextRun != nil {
+		pb.NextRun = timestamppb.New(*state.NextRun)
+	}
+	return pb
+}
+
+// toEvent converts a streamed result or transition
+func toEvent(event monitor.StreamEvent) *monitorpb.Event {
+	if event.Result != nil {
+		return &monitorpb.Event{Event: &monitorpb.Event_Result{Result: toResult(*event.Result)}}
+	}
+	t := event.Transition
+	return &monitorpb.Event{Event: &monitorpb.Event_Transition{Transition: &monitorpb.Transition{
+		Key:    t.Key,
+		From:   string(t.From),
+		To:     string(t.To),
+		Since:  timestamppb.New(t.Since),
+		At:     timestamppb.New(t.At),
+		Result: toResult(t.Result),
+	}}}
+}
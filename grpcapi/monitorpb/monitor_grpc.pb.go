@@ -0,0 +1,302 @@
+// grpcapi/monitorpb/monitor.proto
+//
+// gRPC control and results API of the monitor service. Regenerate the Go code
+// with `make proto`.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v25.3.0
+// source: monitor.proto
+
+package monitorpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Monitor_GetStatus_FullMethodName     = "/simplemonit.v1.Monitor/GetStatus"
+	Monitor_ListAlerts_FullMethodName    = "/simplemonit.v1.Monitor/ListAlerts"
+	Monitor_RunCollector_FullMethodName  = "/simplemonit.v1.Monitor/RunCollector"
+	Monitor_Silence_FullMethodName       = "/simplemonit.v1.Monitor/Silence"
+	Monitor_StreamResults_FullMethodName = "/simplemonit.v1.Monitor/StreamResults"
+)
+
+// MonitorClient is the client API for Monitor service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MonitorClient interface {
+	// GetStatus returns the collectors, the latest result of every target and
+	// the active alerts
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error)
+	// ListAlerts returns the active alerts, oldest first
+	ListAlerts(ctx context.Context, in *ListAlertsRequest, opts ...grpc.CallOption) (*ListAlertsResponse, error)
+	// RunCollector runs a collector now, without moving its schedule
+	RunCollector(ctx context.Context, in *RunCollectorRequest, opts ...grpc.CallOption) (*RunCollectorResponse, error)
+	// Silence adds a mute rule to the mute rules file
+	Silence(ctx context.Context, in *SilenceRequest, opts ...grpc.CallOption) (*SilenceResponse, error)
+	// StreamResults streams every result and alert transition
+	StreamResults(ctx context.Context, in *StreamResultsRequest, opts ...grpc.CallOption) (Monitor_StreamResultsClient, error)
+}
+
+type monitorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMonitorClient(cc grpc.ClientConnInterface) MonitorClient {
+	return &monitorClient{cc}
+}
+
+func (c *monitorClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error) {
+	out := new(GetStatusResponse)
+	err := c.cc.Invoke(ctx, Monitor_GetStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorClient) ListAlerts(ctx context.Context, in *ListAlertsRequest, opts ...grpc.CallOption) (*ListAlertsResponse, error) {
+	out := new(ListAlertsResponse)
+	err := c.cc.Invoke(ctx, Monitor_ListAlerts_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorClient) RunCollector(ctx context.Context, in *RunCollectorRequest, opts ...grpc.CallOption) (*RunCollectorResponse, error) {
+	out := new(RunCollectorResponse)
+	err := c.cc.Invoke(ctx, Monitor_RunCollector_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorClient) Silence(ctx context.Context, in *SilenceRequest, opts ...grpc.CallOption) (*SilenceResponse, error) {
+	out := new(SilenceResponse)
+	err := c.cc.Invoke(ctx, Monitor_Silence_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorClient) StreamResults(ctx context.Context, in *StreamResultsRequest, opts ...grpc.CallOption) (Monitor_StreamResultsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Monitor_ServiceDesc.Streams[0], Monitor_StreamResults_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &monitorStreamResultsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Monitor_StreamResultsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type monitorStreamResultsClient struct {
+	grpc.ClientStream
+}
+
+func (x *monitorStreamResultsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MonitorServer is the server API for Monitor service.
+// All implementations must embed UnimplementedMonitorServer
+// for forward compatibility
+type MonitorServer interface {
+	// GetStatus returns the collectors, the latest result of every target and
+	// the active alerts
+	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
+	// ListAlerts returns the active alerts, oldest first
+	ListAlerts(context.Context, *ListAlertsRequest) (*ListAlertsResponse, error)
+	// RunCollector runs a collector now, without moving its schedule
+	RunCollector(context.Context, *RunCollectorRequest) (*RunCollectorResponse, error)
+	// Silence adds a mute rule to the mute rules file
+	Silence(context.Context, *SilenceRequest) (*SilenceResponse, error)
+	// StreamResults streams every result and alert transition
+	StreamResults(*StreamResultsRequest, Monitor_StreamResultsServer) error
+	mustEmbedUnimplementedMonitorServer()
+}
+
+// UnimplementedMonitorServer must be embedded to have forward compatible implementations.
+type UnimplementedMonitorServer struct {
+}
+
+func (UnimplementedMonitorServer) GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedMonitorServer) ListAlerts(context.Context, *ListAlertsRequest) (*ListAlertsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAlerts not implemented")
+}
+func (UnimplementedMonitorServer) RunCollector(context.Context, *RunCollectorRequest) (*RunCollectorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunCollector not implemented")
+}
+func (UnimplementedMonitorServer) Silence(context.Context, *SilenceRequest) (*SilenceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Silence not implemented")
+}
+func (UnimplementedMonitorServer) StreamResults(*StreamResultsRequest, Monitor_StreamResultsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamResults not implemented")
+}
+func (UnimplementedMonitorServer) mustEmbedUnimplementedMonitorServer() {}
+
+// UnsafeMonitorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MonitorServer will
+// result in compilation errors.
+type UnsafeMonitorServer interface {
+	mustEmbedUnimplementedMonitorServer()
+}
+
+func RegisterMonitorServer(s grpc.ServiceRegistrar, srv MonitorServer) {
+	s.RegisterService(&Monitor_ServiceDesc, srv)
+}
+
+func _Monitor_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Monitor_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Monitor_ListAlerts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAlertsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServer).ListAlerts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Monitor_ListAlerts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServer).ListAlerts(ctx, req.(*ListAlertsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Monitor_RunCollector_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunCollectorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServer).RunCollector(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Monitor_RunCollector_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServer).RunCollector(ctx, req.(*RunCollectorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Monitor_Silence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SilenceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServer).Silence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Monitor_Silence_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServer).Silence(ctx, req.(*SilenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Monitor_StreamResults_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamResultsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MonitorServer).StreamResults(m, &monitorStreamResultsServer{stream})
+}
+
+type Monitor_StreamResultsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type monitorStreamResultsServer struct {
+	grpc.ServerStream
+}
+
+func (x *monitorStreamResultsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Monitor_ServiceDesc is the grpc.ServiceDesc for Monitor service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Monitor_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "simplemonit.v1.Monitor",
+	HandlerType: (*MonitorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStatus",
+			Handler:    _Monitor_GetStatus_Handler,
+		},
+		{
+			MethodName: "ListAlerts",
+			Handler:    _Monitor_ListAlerts_Handler,
+		},
+		{
+			MethodName: "RunCollector",
+			Handler:    _Monitor_RunCollector_Handler,
+		},
+		{
+			MethodName: "Silence",
+			Handler:    _Monitor_Silence_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamResults",
+			Handler:       _Monitor_StreamResults_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "monitor.proto",
+}
@@ -0,0 +1,193 @@
+// init.go
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/monitor"
+)
+
+// runInit implements the "init" subcommand, which writes a commented sample
+// configuration listing every collector, notifier and output compiled into
+// this binary with the settings each accepts. It is generated from the
+// components' schemas, so it describes exactly what the binary supports.
+func runInit(args []string) int {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	output := fs.String("o", "config.yaml", "File to write the configuration to, or - for standard output")
+	force := fs.Bool("force", false, "Overwrite the file if it exists")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	data := sampleConfig()
+	if *output == "-" {
+		os.Stdout.Write(data)
+		return 0
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if *force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	file, err := os.OpenFile(*output, flags, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		fmt.Fprintf(os.Stderr, "%s already exists; pass -force to overwrite it\n", *output)
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create %s: %v\n", *output, err)
+		return 1
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		fmt.Fprintf(os.Stderr, "Failed to write %s: %v\n", *output, err)
+		return 1
+	}
+	if err := file.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write %s: %v\n", *output, err)
+		return 1
+	}
+	fmt.Printf("Wrote %s\n", *output)
+	return 0
+}
+
+// sampleConfig generates the sample configuration. Every component is
+// disabled; required settings are written with an empty value to fill in,
+// optional ones are commented out with their default.
+func sampleConfig() []byte {
+	var b bytes.Buffer
+	b.WriteString(`# simple-monit configuration generated by "server-monitor init"
+#
+# Every collector, notifier and output compiled into this binary is listed,
+# disabled. Enable the ones you need and fill in their required settings;
+# optional settings are commented out with their defaults. Check the result
+# with "server-monitor validate".
+
+monitor:
+  default_interval_seconds: 60 # Also accepts durations such as 30s or 5m
+
+`)
+
+	b.WriteString("collectors:\n")
+	var typed []string
+	for _, component := range monitor.CollectorCatalog() {
+		if component.Type {
+			typed = append(typed, component.Name)
+			continue
+		}
+		writeComponent(&b, component, "  ", true)
+	}
+	if len(typed) > 0 {
+		fmt.Fprintf(&b, "  # Collectors of type %s are configured with type: under a name of your\n", strings.Join(typed, ", "))
+		b.WriteString("  # choice; see \"server-monitor list collectors <type>\"\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString("notifications:\n")
+	for _, component := range monitor.NotifierCatalog() {
+		if component.Name == "plugins" {
+			continue
+		}
+		writeComponent(&b, component, "  ", false)
+	}
+	b.WriteString("  # Plugin notifiers go under plugins:, by name; see \"server-monitor list notifiers plugins\"\n\n")
+
+	b.WriteString("outputs:\n")
+	for _, component := range monitor.OutputCatalog() {
+		writeComponent(&b, component, "  ", true)
+	}
+	return b.Bytes()
+}
+
+// writeComponent writes a disabled component and its settings. Collectors
+// and outputs nest their settings under settings:, notifiers don't.
+func writeComponent(b *bytes.Buffer, component monitor.Component, indent string, nested bool) {
+	if component.Description != "" {
+		fmt.Fprintf(b, "%s# %s\n", indent, component.Description)
+	}
+	fmt.Fprintf(b, "%s%s:\n", indent, component.Name)
+	fmt.Fprintf(b, "%s  enabled: false\n", indent)
+	if len(component.Settings) == 0 {
+		b.WriteString("\n")
+		return
+	}
+	settingsIndent := indent + "  "
+	if nested {
+		fmt.Fprintf(b, "%ssettings:\n", settingsIndent)
+		settingsIndent += "  "
+	}
+	writeSampleSettings(b, component.Settings, settingsIndent, settingsIndent)
+	b.WriteString("\n")
+}
+
+// writeSampleSettings writes a commented line per setting. The first key is
+// written after first, which differs from indent for the first key of a list
+// entry ("- ").
+func writeSampleSettings(b *bytes.Buffer, settings []collectors.Setting, first, indent string) {
+	for i, setting := range settings {
+		prefix := indent
+		if i == 0 {
+			prefix = first
+		}
+		comment := setting.Description
+		if setting.Required {
+			comment += " (required)"
+		}
+		if comment != "" {
+			// The comment goes above the key, except on the first key of
+			// a list entry where it would split the "- " from the key
+			if i == 0 && first != indent {
+				fmt.Fprintf(b, "%s%s: %s # %s\n", prefix, setting.Name, sampleValue(setting), comment)
+				continue
+			}
+			fmt.Fprintf(b, "%s# %s\n", prefix, comment)
+		}
+
+		switch {
+		case setting.Required && len(setting.Fields) > 0:
+			fmt.Fprintf(b, "%s%s:\n", prefix, setting.Name)
+			writeSampleSettings(b, setting.Fields, indent+"  - ", indent+"    ")
+		case setting.Required:
+			fmt.Fprintf(b, "%s%s: %s\n", prefix, setting.Name, sampleValue(setting))
+		default:
+			fmt.Fprintf(b, "%s# %s: %s\n", prefix, setting.Name, sampleValue(setting))
+		}
+	}
+}
+
+// sampleValue returns the value a setting is written with: its default
+// where that is a plain value of its type, or an empty one
+func sampleValue(setting collectors.Setting) string {
+	switch setting.Type {
+	case "int", "duration":
+		if _, err := strconv.Atoi(setting.Default); err == nil {
+			return setting.Default
+		}
+		return "0"
+	case "float":
+		if _, err := strconv.ParseFloat(setting.Default, 64); err == nil {
+			return setting.Default
+		}
+		return "0"
+	case "bool":
+		if _, err := strconv.ParseBool(setting.Default); err == nil {
+			return setting.Default
+		}
+		return "false"
+	case "list":
+		return "[]"
+	case "map":
+		return "{}"
+	}
+	if setting.Default != "" {
+		return strconv.Quote(setting.Default)
+	}
+	return `""`
+}
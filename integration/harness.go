@@ -0,0 +1,340 @@
+//go:build integration
+
+// integration/harness.go
+//
+// Package integration provides an end-to-end test harness for simple-monit. It
+// starts dependencies such as MailHog and Mosquitto in Docker via
+// testcontainers, mock HTTP receivers for webhook notifiers, and a
+// monitor service wired from a config, so collect→alert→notify paths can be
+// exercised for real. Plug-in authors can use it to test their own collectors
+// and notifiers; everything here requires the "integration" build tag:
+//
+//	go test -tags integration ./...
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/config"
+	"github.com/devvspaces/simple-monit/evaluator"
+	"github.com/devvspaces/simple-monit/monitor"
+	"github.com/devvspaces/simple-monit/notifiers"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+// Harness holds the per-test context, logger and cleanup of started services
+type Harness struct {
+	T      *testing.T
+	Ctx    context.Context
+	Logger *zap.Logger
+
+	stops map[*monitor.MonitorService]func()
+}
+
+// New creates a harness for a test. Everything started through the harness
+// is torn down when the test ends.
+func New(t *testing.T) *Harness {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	t.Cleanup(cancel)
+
+	return &Harness{
+		T:      t,
+		Ctx:    ctx,
+		Logger: zaptest.NewLogger(t),
+		stops:  make(map[*monitor.MonitorService]func()),
+	}
+}
+
+// skipWithoutDocker skips the test when no Docker daemon can be reached.
+// testcontainers panics instead of failing when it finds no Docker host.
+func skipWithoutDocker(t *testing.T) {
+	t.Helper()
+	defer func() {
+		if r := recover(); r != nil {
+			t.Skipf("Docker is not available: %v", r)
+		}
+	}()
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+}
+
+// StartContainer starts a container and terminates it when the test ends.
+// The test is skipped when no Docker provider is available.
+func (h *Harness) StartContainer(req testcontainers.ContainerRequest) testcontainers.Container {
+	h.T.Helper()
+	skipWithoutDocker(h.T)
+
+	container, err := testcontainers.GenericContainer(h.Ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		h.T.Fatalf("failed to start container %s: %v", req.Image, err)
+	}
+	h.T.Cleanup(func() {
+		if err := container.Terminate(context.Background()); err != nil {
+			h.T.Logf("failed to terminate container %s: %v", req.Image, err)
+		}
+	})
+	return container
+}
+
+// Endpoint returns the host and mapped port of a container port, e.g. "1025/tcp"
+func (h *Harness) Endpoint(container testcontainers.Container, port string) (string, int) {
+	h.T.Helper()
+
+	host, err := container.Host(h.Ctx)
+	if err != nil {
+		h.T.Fatalf("failed to get container host: %v", err)
+	}
+	mapped, err := container.MappedPort(h.Ctx, nat.Port(port))
+	if err != nil {
+		h.T.Fatalf("failed to get mapped port %s: %v", port, err)
+	}
+	return host, mapped.Int()
+}
+
+// MailHog is a running MailHog SMTP server with its message API
+type MailHog struct {
+	SMTPHost string
+	SMTPPort int
+	apiURL   string
+}
+
+// MailMessage is a message captured by MailHog
+type MailMessage struct {
+	From    string
+	To      []string
+	Subject string
+	Body    string
+}
+
+// MailHog starts a MailHog container accepting SMTP without authentication
+func (h *Harness) MailHog() *MailHog {
+	h.T.Helper()
+
+	container := h.StartContainer(testcontainers.ContainerRequest{
+		Image:        "mailhog/mailhog:v1.0.1",
+		ExposedPorts: []string{"1025/tcp", "8025/tcp"},
+		WaitingFor:   wait.ForHTTP("/api/v2/messages").WithPort("8025/tcp"),
+	})
+
+	smtpHost, smtpPort := h.Endpoint(container, "1025/tcp")
+	apiHost, apiPort := h.Endpoint(container, "8025/tcp")
+	return &MailHog{
+		SMTPHost: smtpHost,
+		SMTPPort: smtpPort,
+		apiURL:   fmt.Sprintf("http://%s:%d", apiHost, apiPort),
+	}
+}
+
+// Messages returns the messages MailHog has received
+func (m *MailHog) Messages() ([]MailMessage, error) {
+	resp, err := http.Get(m.apiURL + "/api/v2/messages")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var page struct {
+		Items []struct {
+			Raw struct {
+				From string   `json:"From"`
+				To   []string `json:"To"`
+			} `json:"Raw"`
+			Content struct {
+				Headers map[string][]string `json:"Headers"`
+				Body    string              `json:"Body"`
+			} `json:"Content"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode MailHog messages: %w", err)
+	}
+
+	messages := make([]MailMessage, 0, len(page.Items))
+	for _, item := range page.Items {
+		msg := MailMessage{
+			From: item.Raw.From,
+			To:   item.Raw.To,
+			Body: item.Content.Body,
+		}
+		if subject := item.Content.Headers["Subject"]; len(subject) > 0 {
+			msg.Subject = subject[0]
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+// Mosquitto starts an MQTT broker allowing anonymous clients and returns its URL
+func (h *Harness) Mosquitto() string {
+	h.T.Helper()
+
+	container := h.StartContainer(testcontainers.ContainerRequest{
+		Image:        "eclipse-mosquitto:2",
+		ExposedPorts: []string{"1883/tcp"},
+		Cmd:          []string{"mosquitto", "-c", "/mosquitto-no-auth.conf"},
+		WaitingFor:   wait.ForListeningPort("1883/tcp"),
+	})
+
+	host, port := h.Endpoint(container, "1883/tcp")
+	return fmt.Sprintf("tcp://%s:%d", host, port)
+}
+
+// Webhook is a mock HTTP receiver that records every request it gets
+type Webhook struct {
+	URL      string
+	response string
+	requests []WebhookRequest
+	mu       sync.Mutex
+}
+
+// WebhookRequest is a request received by a mock webhook
+type WebhookRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+// Webhook starts a mock webhook receiver answering 200 with an empty body
+func (h *Harness) Webhook() *Webhook {
+	return h.mockReceiver("")
+}
+
+// mockReceiver starts a recording HTTP server with a fixed response body
+func (h *Harness) mockReceiver(response string) *Webhook {
+	w := &Webhook{response: response}
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.mu.Lock()
+		w.requests = append(w.requests, WebhookRequest{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Header: r.Header.Clone(),
+			Body:   body,
+		})
+		w.mu.Unlock()
+		io.WriteString(rw, w.response)
+	}))
+	h.T.Cleanup(server.Close)
+
+	w.URL = server.URL
+	return w
+}
+
+// Requests returns a copy of the requests received so far
+func (w *Webhook) Requests() []WebhookRequest {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]WebhookRequest(nil), w.requests...)
+}
+
+// Config returns a minimal valid configuration with no collectors, notifiers
+// or outputs enabled, for tests to fill in
+func Config() *config.Config {
+	return &config.Config{
+		Monitor: config.MonitorConfig{
+			DefaultIntervalSeconds: 60,
+			OnCheckRemoved:         "resolve",
+			MaxSeriesPerCollector:  1000,
+		},
+		Collectors:    map[string]config.CollectorConfig{},
+		Outputs:       map[string]config.OutputConfig{},
+		Notifications: config.NotificationsConfig{Workers: 1},
+	}
+}
+
+// Service builds and prepares a monitor service from a configuration without
+// starting its schedules; drive it with RunCollector or Inject. The service is
+// stopped, draining queued notifications, when the test ends, or earlier
+// with Stop.
+func (h *Harness) Service(cfg *config.Config) *monitor.MonitorService {
+	h.T.Helper()
+
+	service := monitor.NewMonitorService(h.Logger.Named("monitor"), cfg)
+	if err := service.Prepare(); err != nil {
+		h.T.Fatalf("failed to prepare monitor service: %v", err)
+	}
+	stop := sync.OnceFunc(service.Stop)
+	h.stops[service] = stop
+	h.T.Cleanup(stop)
+	return service
+}
+
+// Stop stops a service built by Service once every notification it queued
+// has been delivered, so a test can assert on what was, or was not, sent
+func (h *Harness) Stop(service *monitor.MonitorService) {
+	h.T.Helper()
+
+	stop, ok := h.stops[service]
+	if !ok {
+		h.T.Fatalf("service was not built by the harness")
+	}
+	stop()
+}
+
+// Collect initializes a collector with settings, runs one collection and
+// cleans it up, returning the results with their health evaluated as the
+// monitor would
+func (h *Harness) Collect(collector collectors.Collector, settings map[string]interface{}) []collectors.Result {
+	h.T.Helper()
+
+	if err := collector.Init(settings); err != nil {
+		h.T.Fatalf("failed to initialize collector %s: %v", collector.Name(), err)
+	}
+	defer collector.Cleanup()
+
+	results, err := collector.Collect(h.Ctx)
+	if err != nil {
+		h.T.Fatalf("collector %s failed: %v", collector.Name(), err)
+	}
+
+	eval := evaluator.New()
+	for i, result := range results {
+		results[i] = eval.Evaluate(result, nil)
+	}
+	return results
+}
+
+// Notify initializes a notifier with config and delivers results through it
+func (h *Harness) Notify(notifier notifiers.Notifier, cfg map[string]interface{}, results []collectors.Result) error {
+	h.T.Helper()
+
+	if err := notifier.Init(cfg); err != nil {
+		h.T.Fatalf("failed to initialize notifier %s: %v", notifier.Name(), err)
+	}
+	defer notifier.Close()
+
+	return notifier.Notify(h.Ctx, results)
+}
+
+// Eventually polls cond until it returns true, failing the test after timeout
+func (h *Harness) Eventually(timeout time.Duration, cond func() bool, msg string) {
+	h.T.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			h.T.Fatalf("timed out after %s: %s", timeout, msg)
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
@@ -0,0 +1,154 @@
+//go:build integration
+
+// integration/integration_test.go
+package integration
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/collectors/mqtt"
+	"github.com/devvspaces/simple-monit/config"
+)
+
+// diskAlwaysAlerting is a disk collector config whose thresholds always trip
+func diskAlwaysAlerting() config.CollectorConfig {
+	return config.CollectorConfig{
+		Enabled: true,
+		Settings: map[string]interface{}{
+			"paths": []interface{}{
+				map[string]interface{}{"path": "/", "threshold_percent": 0.0001},
+			},
+		},
+	}
+}
+
+func TestDiskAlertIsEmailed(t *testing.T) {
+	h := New(t)
+	mail := h.MailHog()
+
+	cfg := Config()
+	cfg.Collectors["disk_space"] = diskAlwaysAlerting()
+	cfg.Notifications.Email = config.EmailConfig{
+		Enabled:    true,
+		From:       "monitor@example.com",
+		To:         []string{"oncall@example.com"},
+		SMTPServer: mail.SMTPHost,
+		SMTPPort:   mail.SMTPPort,
+	}
+
+	service := h.Service(cfg)
+	if err := service.RunCollector(h.Ctx, "disk_space"); err != nil {
+		t.Fatalf("disk collector run failed: %v", err)
+	}
+
+	var messages []MailMessage
+	h.Eventually(30*time.Second, func() bool {
+		var err error
+		messages, err = mail.Messages()
+		return err == nil && len(messages) > 0
+	}, "no email received by MailHog")
+
+	if !strings.Contains(messages[0].Subject, "Server Alert") {
+		t.Errorf("unexpected subject %q", messages[0].Subject)
+	}
+	if !strings.Contains(messages[0].Body, "disk_space") {
+		t.Errorf("email body does not mention the collector: %q", messages[0].Body)
+	}
+}
+
+func TestHealthyRunSendsNoEmail(t *testing.T) {
+	h := New(t)
+	mail := h.MailHog()
+
+	cfg := Config()
+	cfg.Collectors["disk_space"] = config.CollectorConfig{
+		Enabled: true,
+		Settings: map[string]interface{}{
+			"paths": []interface{}{
+				map[string]interface{}{"path": "/", "threshold_percent": 100},
+			},
+		},
+	}
+	cfg.Notifications.Email = config.EmailConfig{
+		Enabled:    true,
+		From:       "monitor@example.com",
+		To:         []string{"oncall@example.com"},
+		SMTPServer: mail.SMTPHost,
+		SMTPPort:   mail.SMTPPort,
+	}
+
+	service := h.Service(cfg)
+	if err := service.RunCollector(h.Ctx, "disk_space"); err != nil {
+		t.Fatalf("disk collector run failed: %v", err)
+	}
+	// Anything the run dispatched has been delivered once the service stops
+	h.Stop(service)
+
+	messages, err := mail.Messages()
+	if err != nil {
+		t.Fatalf("failed to read MailHog messages: %v", err)
+	}
+	if len(messages) != 0 {
+		t.Errorf("expected no email for a healthy run, got %d", len(messages))
+	}
+}
+
+func TestWebhookReceivesAlerts(t *testing.T) {
+	h := New(t)
+	hook := h.Webhook()
+
+	cfg := Config()
+	cfg.Collectors["disk_space"] = diskAlwaysAlerting()
+	cfg.Notifications.Chaos = config.ChaosConfig{Enabled: true, URL: hook.URL}
+
+	service := h.Service(cfg)
+	if err := service.RunCollector(h.Ctx, "disk_space"); err != nil {
+		t.Fatalf("disk collector run failed: %v", err)
+	}
+
+	h.Eventually(30*time.Second, func() bool { return len(hook.Requests()) > 0 }, "webhook received nothing")
+
+	var delivery struct {
+		Results []collectors.Result `json:"results"`
+	}
+	if err := json.Unmarshal(hook.Requests()[0].Body, &delivery); err != nil {
+		t.Fatalf("invalid webhook body: %v", err)
+	}
+	if len(delivery.Results) == 0 || delivery.Results[0].Collector != "disk_space" {
+		t.Errorf("unexpected webhook results: %+v", delivery.Results)
+	}
+}
+
+func TestMQTTCollectorAgainstBroker(t *testing.T) {
+	h := New(t)
+	broker := h.Mosquitto()
+
+	results := h.Collect(mqtt.NewMQTTCollector(h.Logger), map[string]interface{}{
+		"broker":               broker,
+		"latency_threshold_ms": 5000,
+	})
+
+	if len(results) != 1 {
+		t.Fatalf("expected one result, got %d", len(results))
+	}
+	if !results[0].IsHealthy {
+		t.Errorf("expected a healthy round trip, got: %s", results[0].Message)
+	}
+}
+
+func TestMQTTCollectorAlertsOnUnreachableBroker(t *testing.T) {
+	h := New(t)
+
+	results := h.Collect(mqtt.NewMQTTCollector(h.Logger), map[string]interface{}{
+		"broker":          "tcp://127.0.0.1:1",
+		"timeout_seconds": 2,
+	})
+
+	if len(results) != 1 || results[0].IsHealthy {
+		t.Fatalf("expected one unhealthy result, got %+v", results)
+	}
+}
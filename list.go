@@ -0,0 +1,130 @@
+// list.go
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/monitor"
+)
+
+// runList implements the "list" subcommand, which describes the collectors,
+// notifiers or outputs compiled into this binary and the settings each
+// accepts
+func runList(args []string) int {
+	usage := "usage: server-monitor list <collectors|notifiers|outputs> [flags] [name...]"
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	catalog, _, ok := componentCatalog(args[0])
+	if !ok || !strings.HasSuffix(args[0], "s") {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("list "+args[0], flag.ContinueOnError)
+	format := fs.String("format", "table", "Output format: table or json")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if *format != "table" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "Unknown format %q: expected table or json\n", *format)
+		return 2
+	}
+
+	// Only describe the named components
+	if names := fs.Args(); len(names) > 0 {
+		all := catalog
+		catalog = nil
+		for _, name := range names {
+			component, ok := findComponent(all, name)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "No %s named %q is compiled into this binary\n", kindName(args[0]), name)
+				return 1
+			}
+			catalog = append(catalog, component)
+		}
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(catalog)
+		return 0
+	}
+
+	for i, component := range catalog {
+		if i > 0 {
+			fmt.Println()
+		}
+		title := component.Name
+		if component.Type {
+			title += " (type: " + component.Name + ")"
+		}
+		if component.Description != "" {
+			title += " - " + component.Description
+		}
+		fmt.Println(title)
+
+		if len(component.Settings) == 0 {
+			fmt.Println("  (no settings described)")
+			continue
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		writeSettings(w, component.Settings, "  ")
+		w.Flush()
+	}
+	return 0
+}
+
+// componentCatalog returns the catalog of a kind of component, named in the
+// singular or plural, and the plural name
+func componentCatalog(kind string) ([]monitor.Component, string, bool) {
+	switch kind {
+	case "collector", "collectors":
+		return monitor.CollectorCatalog(), "collectors", true
+	case "notifier", "notifiers":
+		return monitor.NotifierCatalog(), "notifiers", true
+	case "output", "outputs":
+		return monitor.OutputCatalog(), "outputs", true
+	}
+	return nil, "", false
+}
+
+// findComponent returns the component of a catalog with the given name
+func findComponent(catalog []monitor.Component, name string) (monitor.Component, bool) {
+	for _, component := range catalog {
+		if component.Name == name {
+			return component, true
+		}
+	}
+	return monitor.Component{}, false
+}
+
+// kindName returns the singular name of a kind of component
+func kindName(kind string) string {
+	return strings.TrimSuffix(kind, "s")
+}
+
+// writeSettings writes a line per setting, with the keys of lists of objects
+// indented below them
+func writeSettings(w io.Writer, settings []collectors.Setting, indent string) {
+	for _, setting := range settings {
+		value := setting.Default
+		if setting.Required {
+			value = "required"
+		} else if value == "" {
+			value = "-"
+		}
+		fmt.Fprintf(w, "%s%s\t%s\t%s\t%s\n", indent, setting.Name, setting.Type, value, setting.Description)
+		writeSettings(w, setting.Fields, indent+"  ")
+	}
+}
@@ -0,0 +1,76 @@
+// logging.go
+package main
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/devvspaces/simple-monit/config"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// newLogger builds the service's logger from the logging section. Its level
+// is read from level, so a reload can change it. With a log file, entries are
+// also written to it, rotated by lumberjack. extra options are applied after
+// the configured ones.
+func newLogger(logging config.LoggingConfig, level zap.AtomicLevel, extra ...zap.Option) (*zap.Logger, error) {
+	parsed, err := zapcore.ParseLevel(logging.Level)
+	if err != nil {
+		return nil, err
+	}
+	level.SetLevel(parsed)
+
+	zapConfig := zap.NewProductionConfig()
+	zapConfig.Level = level
+	zapConfig.Encoding = logging.Encoding
+	zapConfig.OutputPaths = logging.OutputPaths
+	zapConfig.ErrorOutputPaths = logging.ErrorOutputPaths
+	zapConfig.Sampling = nil
+	if !logging.Sampling.Disabled {
+		zapConfig.Sampling = &zap.SamplingConfig{
+			Initial:    logging.Sampling.Initial,
+			Thereafter: logging.Sampling.Thereafter,
+		}
+	}
+	if logging.Encoding == "console" {
+		zapConfig.EncoderConfig = zap.NewDevelopmentEncoderConfig()
+	}
+
+	var options []zap.Option
+	if file := logging.File; file.Path != "" {
+		encoder := zapcore.NewJSONEncoder(zapConfig.EncoderConfig)
+		if logging.Encoding == "console" {
+			encoder = zapcore.NewConsoleEncoder(zapConfig.EncoderConfig)
+		}
+		var fileCore zapcore.Core = zapcore.NewCore(encoder, zapcore.AddSync(&lumberjack.Logger{
+			Filename:   file.Path,
+			MaxSize:    file.MaxSizeMB,
+			MaxAge:     file.MaxAgeDays,
+			MaxBackups: file.MaxBackups,
+			Compress:   file.Compress,
+		}), level)
+		if sampling := zapConfig.Sampling; sampling != nil {
+			fileCore = zapcore.NewSamplerWithOptions(fileCore, time.Second, sampling.Initial, sampling.Thereafter)
+		}
+		options = append(options, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(core, fileCore)
+		}))
+	}
+	return zapConfig.Build(append(options, extra...)...)
+}
+
+// reloadLogging applies the log level of a reloaded configuration. Other
+// logging changes require a restart.
+func reloadLogging(logger *zap.Logger, level zap.AtomicLevel, old, logging config.LoggingConfig) {
+	if parsed, err := zapcore.ParseLevel(logging.Level); err == nil && parsed != level.Level() {
+		level.SetLevel(parsed)
+		logger.Info("Log level changed", zap.String("level", logging.Level))
+	}
+	old.Level = logging.Level
+	if !reflect.DeepEqual(old, logging) {
+		logger.Warn("Logging changes other than the level require a restart; keeping the running settings")
+	}
+}
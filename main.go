@@ -2,35 +2,121 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
-	"server-monitor/config"
-	"server-monitor/monitor"
+	"github.com/devvspaces/simple-monit/api"
+	"github.com/devvspaces/simple-monit/config"
+	"github.com/devvspaces/simple-monit/grpcapi"
+	"github.com/devvspaces/simple-monit/monitor"
 
 	"go.uber.org/zap"
 )
 
 func main() {
+	// Dispatch subcommands before parsing the service flags
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "mute":
+			os.Exit(runMute(os.Args[2:]))
+		case "simulate":
+			os.Exit(runSimulate(os.Args[2:]))
+		case "collector":
+			os.Exit(runCollectorCommand(os.Args[2:]))
+		case "top":
+			os.Exit(runTop(os.Args[2:]))
+		case "availability":
+			os.Exit(runAvailability(os.Args[2:]))
+		case "export":
+			os.Exit(runExport(os.Args[2:]))
+		case "validate":
+			os.Exit(runValidate(os.Args[2:]))
+		case "check":
+			os.Exit(runCheck(os.Args[2:]))
+		case "list":
+			os.Exit(runList(os.Args[2:]))
+		case "init":
+			os.Exit(runInit(os.Args[2:]))
+		case "query":
+			os.Exit(runQuery(os.Args[2:]))
+		case "describe":
+			os.Exit(runDescribe(os.Args[2:]))
+		case "service":
+			os.Exit(runService(os.Args[2:]))
+		}
+	}
+
 	// Parse command line arguments
 	configPath := flag.String("config", "config.yaml", "Path to configuration file")
+	configDir := flag.String("config-dir", "", "Directory of YAML fragments merged into the configuration, e.g. /etc/simple-monit/conf.d")
+	profile := flag.String("profile", os.Getenv(envPrefix+"PROFILE"), "Profile whose overlay, e.g. config.prod.yaml, is merged over the configuration file; also "+envPrefix+"PROFILE")
+	printDefaultConfig := flag.Bool("print-default-config", false, "Print the built-in example configuration and exit")
+	watch := flag.Bool("watch-config", false, "Reload the configuration automatically when the file or directory changes")
+	output := flag.String("output", "", "Also write every result to standard output; \"json\" writes newline-delimited JSON")
+	registerOverrideFlags()
 	flag.Parse()
 
-	// Set up logging
-	logger, err := zap.NewProduction()
+	if *printDefaultConfig {
+		os.Stdout.Write(defaultConfig)
+		return
+	}
+
+	// Started by the Windows service manager, which stops the service
+	// in place of signals
+	if isWindowsService() {
+		os.Exit(runWindowsService(func(control serviceControl) {
+			serve(*configPath, *profile, *configDir, *watch, *output, control)
+		}))
+	}
+	serve(*configPath, *profile, *configDir, *watch, *output, serviceControl{})
+}
+
+// serviceControl lets a service manager, rather than signals, drive the
+// service. The zero value leaves it to signals.
+type serviceControl struct {
+	stop    <-chan struct{} // Closed to shut down
+	reload  <-chan struct{} // Receives to reload the configuration
+	ready   func()          // Called once the service is running
+	options []zap.Option    // For the logger, e.g. to also log to the event log
+}
+
+// serve runs the monitoring service until it is told to stop
+func serve(configPath, profile, configDir string, watch bool, output string, control serviceControl) {
+	// Log with the defaults until the configuration is loaded
+	bootstrap, err := zap.NewProduction(control.options...)
 	if err != nil {
 		log.Fatalf("can't initialize zap logger: %v", err)
 	}
-	defer logger.Sync()
 
-	// Load configuration
-	cfg, err := config.LoadConfig(logger.Named("config"), *configPath)
+	// Load configuration, falling back to the built-in defaults when no
+	// -config was given and config.yaml does not exist
+	cfg, err := loadConfig(bootstrap.Named("config"), configPath, profile, configDir, flagPassed("config"))
+	bootstrap.Sync()
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
+	if err := applyOutputFlag(cfg, output); err != nil {
+		log.Fatalf("Invalid -output: %v", err)
+	}
+
+	// Set up logging as configured
+	level := zap.NewAtomicLevel()
+	logger, err := newLogger(cfg.Logging, level, control.options...)
+	if err != nil {
+		log.Fatalf("Failed to set up logging: %v", err)
+	}
+	defer logger.Sync()
+	logging := cfg.Logging
+	grace := cfg.Monitor.ShutdownGraceSeconds.Duration()
+	logger.Info("Operating mode", zap.String("mode", cfg.Monitor.Mode))
 
 	// Create and start the monitoring service
 	monitorService := monitor.NewMonitorService(logger.Named("monitor"), cfg)
@@ -39,15 +125,189 @@ func main() {
 		panic(err)
 	}
 
-	// Handle graceful shutdown
+	// Serve the HTTP API, e.g. for pushed results
+	var apiServer *api.Server
+	if cfg.API.Enabled {
+		apiServer = api.New(logger.Named("api"), cfg.API, monitorService)
+		if err := apiServer.Start(); err != nil {
+			monitorService.Stop()
+			logger.Fatal("Failed to start API", zap.Error(err))
+		}
+	}
+
+	// Serve the gRPC API
+	var grpcServer *grpcapi.Server
+	if cfg.GRPC.Enabled {
+		grpcServer = grpcapi.New(logger.Named("grpc"), cfg.GRPC, monitorService)
+		if err := grpcServer.Start(); err != nil {
+			monitorService.Stop()
+			logger.Fatal("Failed to start gRPC API", zap.Error(err))
+		}
+	}
+
+	// Serve pprof and expvar for diagnosing long-running deployments
+	if cfg.Debug.Enabled {
+		debugServer, err := startDebugServer(logger.Named("debug"), cfg.Debug, monitorService)
+		if err != nil {
+			monitorService.Stop()
+			logger.Fatal("Failed to start debug listener", zap.Error(err))
+		}
+		defer stopDebugServer(logger.Named("debug"), debugServer)
+	}
+
+	// Handle graceful shutdown and configuration reloads
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+
+	// Optionally reload the configuration when the file changes, too
+	var changes <-chan struct{}
+	if watch {
+		watcher, err := watchConfig(logger.Named("watch"), configPath, profile, configDir)
+		if err != nil {
+			monitorService.Stop()
+			logger.Fatal("Failed to watch configuration", zap.Error(err))
+		}
+		defer watcher.Close()
+		changes = watcher.Changes()
+	}
+
+	reload := func() {
+		newCfg, err := loadConfig(logger.Named("config"), configPath, profile, configDir, flagPassed("config"))
+		if err != nil {
+			logger.Error("Keeping previous configuration", zap.Error(err))
+			return
+		}
+		// -output still applies, so the reload does not see its output removed
+		if err := applyOutputFlag(newCfg, output); err != nil {
+			logger.Error("Keeping previous configuration", zap.Error(err))
+			return
+		}
+		reloadLogging(logger.Named("logging"), level, logging, newCfg.Logging)
+		logging.Level = newCfg.Logging.Level
+		if err := monitorService.Reload(newCfg); err != nil {
+			logger.Error("Configuration reload failed", zap.Error(err))
+			return
+		}
+		grace = newCfg.Monitor.ShutdownGraceSeconds.Duration()
+	}
+
+	if control.ready != nil {
+		control.ready()
+	}
+
+	// Wait for termination signal, reloading the configuration on SIGHUP
+	// or a change of the file
+wait:
+	for {
+		select {
+		case <-changes:
+			logger.Info("Configuration file changed, reloading configuration", zap.String("path", configPath))
+			reload()
+		case <-control.reload:
+			logger.Info("Service manager requested a reload, reloading configuration", zap.String("path", configPath))
+			reload()
+		case <-control.stop:
+			logger.Info("Service manager requested a stop, shutting down")
+			break wait
+		case sig := <-sigChan:
+			if sig == syscall.SIGHUP {
+				logger.Info("Received SIGHUP, reloading configuration", zap.String("path", configPath))
+				reload()
+				continue
+			}
+
+			logger.Info("Received signal, shutting down", zap.String("signal", sig.String()))
+			break wait
+		}
+	}
+
+	// Stop taking API requests, then stop the monitoring service; with a
+	// grace period, give up waiting once it has passed, before a container
+	// runtime follows SIGTERM with SIGKILL
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		if apiServer != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			if err := apiServer.Shutdown(ctx); err != nil {
+				logger.Error("Failed to stop API", zap.Error(err))
+			}
+			cancel()
+		}
+		if grpcServer != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			if err := grpcServer.Shutdown(ctx); err != nil {
+				logger.Error("Failed to stop gRPC API", zap.Error(err))
+			}
+			cancel()
+		}
+		monitorService.Stop()
+		logger.Info("Monitoring service stopped")
+	}()
+
+	var deadline <-chan time.Time
+	if grace > 0 {
+		deadline = time.After(grace)
+	}
+	select {
+	case <-stopped:
+	case <-deadline:
+		logger.Warn("Shutdown grace period passed, exiting before the service stopped", zap.Duration("grace", grace))
+	}
+}
+
+// loadConfig loads the configuration file, merged with the overlay of the
+// profile and the fragments in dir when given. When neither the path, a
+// profile nor a directory was given and the file does not exist, the
+// embedded default configuration is used so a lone binary still runs.
+func loadConfig(logger *zap.Logger, path, profile, dir string, explicit bool) (*config.Config, error) {
+	if _, err := os.Stat(path); !explicit && profile == "" && dir == "" && errors.Is(err, fs.ErrNotExist) {
+		logger.Warn("Configuration file not found, using built-in defaults", zap.String("path", path))
+		return config.ParseConfig(logger, defaultConfig, "built-in defaults", configOverrides()...)
+	}
+	return loadConfigFiles(logger, path, profile, dir)
+}
 
-	// Wait for termination signal
-	sig := <-sigChan
-	logger.Info("Received signal, shutting down", zap.String("signal", sig.String()))
+// loadConfigFiles loads the configuration file, merged with the overlay of
+// the profile and the fragments in dir when given. With a directory and no
+// profile, the file is optional. Settings overridden in the environment or
+// with flags are applied before validation.
+func loadConfigFiles(logger *zap.Logger, path, profile, dir string) (*config.Config, error) {
+	if profile != "" {
+		return config.LoadConfigProfile(logger, path, profile, dir, configOverrides()...)
+	}
+	if dir != "" {
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			path = ""
+		}
+		return config.LoadConfigDir(logger, path, dir, configOverrides()...)
+	}
+	return config.LoadConfig(logger, path, configOverrides()...)
+}
+
+// applyOutputFlag enables the output selected with -output, writing to
+// standard output in place of any configured destination
+func applyOutputFlag(cfg *config.Config, output string) error {
+	switch output {
+	case "":
+		return nil
+	case "json":
+		if cfg.Outputs == nil {
+			cfg.Outputs = make(map[string]config.OutputConfig)
+		}
+		cfg.Outputs["json"] = config.OutputConfig{Enabled: true, Settings: map[string]interface{}{"path": "-"}}
+		return nil
+	}
+	return fmt.Errorf("unknown output %q, expected json", output)
+}
 
-	// Stop the monitoring service
-	monitorService.Stop()
-	logger.Info("Monitoring service stopped")
+// flagPassed reports whether a command line flag was set explicitly
+func flagPassed(name string) bool {
+	passed := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			passed = true
+		}
+	})
+	return passed
 }
@@ -0,0 +1,206 @@
+// maintenance/maintenance.go
+package maintenance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/config"
+
+	"go.uber.org/zap"
+)
+
+// window is a compiled maintenance window and what it suppressed so far
+type window struct {
+	config.MaintenanceWindow
+	collectors map[string]bool
+	days       map[time.Weekday]bool
+	startHour  int
+	startMin   int
+	duration   time.Duration
+	location   *time.Location
+
+	wasActive  bool
+	suppressed int
+	targets    map[string]string // key -> collector
+}
+
+// active reports whether the window is open at now
+func (w *window) active(now time.Time) bool {
+	if w.Recurring == nil {
+		return !now.Before(w.Start) && now.Before(w.End)
+	}
+
+	// An occurrence that started yesterday may still be open past midnight
+	local := now.In(w.location)
+	for _, offset := range []int{0, -1} {
+		day := local.AddDate(0, 0, offset)
+		if len(w.days) > 0 && !w.days[day.Weekday()] {
+			continue
+		}
+		start := time.Date(day.Year(), day.Month(), day.Day(), w.startHour, w.startMin, 0, 0, w.location)
+		if !local.Before(start) && local.Before(start.Add(w.duration)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether the window covers the result
+func (w *window) matches(result collectors.Result) bool {
+	if len(w.collectors) > 0 && !w.collectors[result.Collector] {
+		return false
+	}
+	if len(w.Match) == 0 {
+		return true
+	}
+
+	labels := result.Labels()
+	for key, want := range w.Match {
+		if labels[key] != want {
+			return false
+		}
+	}
+	return true
+}
+
+// Summary describes what a maintenance window suppressed once it has ended
+type Summary struct {
+	Window     string
+	Suppressed int            // Notifications suppressed
+	Targets    int            // Distinct targets among them
+	Collectors map[string]int // Targets per collector
+}
+
+// Result turns the summary into an informational result for the notifiers
+func (s Summary) Result(now time.Time) collectors.Result {
+	names := make([]string, 0, len(s.Collectors))
+	for name := range s.Collectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %d", name, s.Collectors[name]))
+	}
+
+	return collectors.Result{
+		IsHealthy: false,
+		Collector: "maintenance",
+		Severity:  collectors.SeverityInfo,
+		Timestamp: now,
+		Message: fmt.Sprintf("Maintenance window %s ended: %d notification(s) for %d target(s) were suppressed during maintenance (%s)",
+			s.Window, s.Suppressed, s.Targets, strings.Join(parts, ", ")),
+		Metrics: map[string]float64{
+			"suppressed": float64(s.Suppressed),
+			"targets":    float64(s.Targets),
+		},
+		// The summary is informational, not an alert
+		Thresholds: []collectors.Threshold{{
+			Type:     "absolute",
+			Metric:   "suppressed",
+			Operator: "greater_than",
+			Value:    0,
+			Severity: collectors.SeverityInfo,
+		}},
+		Metadata: map[string]interface{}{
+			"window": s.Window,
+		},
+	}
+}
+
+// Scheduler decides whether notifications are suppressed by a maintenance
+// window and keeps count of what each window suppressed
+type Scheduler struct {
+	windows []*window
+	mu      sync.Mutex
+	logger  *zap.Logger
+}
+
+// NewScheduler compiles the configured maintenance windows
+func NewScheduler(logger *zap.Logger, cfg config.MaintenanceConfig) (*Scheduler, error) {
+	s := &Scheduler{logger: logger}
+
+	for _, wc := range cfg.Windows {
+		if err := config.ValidateMaintenanceWindow(wc); err != nil {
+			logger.Error("Invalid maintenance window", zap.Error(err))
+			return nil, err
+		}
+
+		w := &window{
+			MaintenanceWindow: wc,
+			collectors:        make(map[string]bool),
+			targets:           make(map[string]string),
+		}
+		for _, name := range wc.Collectors {
+			w.collectors[name] = true
+		}
+
+		if wc.Recurring != nil {
+			start, _ := time.Parse("15:04", wc.Recurring.Start)
+			w.startHour, w.startMin = start.Hour(), start.Minute()
+			w.duration = time.Duration(wc.Recurring.DurationMinutes) * time.Minute
+			w.location, _ = time.LoadLocation(wc.Recurring.Timezone)
+			w.days = make(map[time.Weekday]bool)
+			for _, day := range wc.Recurring.Days {
+				w.days[config.Weekdays[strings.ToLower(day)]] = true
+			}
+		}
+
+		s.windows = append(s.windows, w)
+	}
+
+	return s, nil
+}
+
+// Suppress returns the name of the open window covering the result, or "" if
+// none does. Suppressed results are counted for the window's summary.
+func (s *Scheduler) Suppress(result collectors.Result, now time.Time) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, w := range s.windows {
+		if !w.active(now) || !w.matches(result) {
+			continue
+		}
+		w.wasActive = true
+		w.suppressed++
+		w.targets[result.Key()] = result.Collector
+		return w.Name
+	}
+	return ""
+}
+
+// Ended returns a summary for every window that closed since the last call
+// and suppressed something while open
+func (s *Scheduler) Ended(now time.Time) []Summary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var summaries []Summary
+	for _, w := range s.windows {
+		active := w.active(now)
+		if w.wasActive && !active && w.suppressed > 0 {
+			summary := Summary{
+				Window:     w.Name,
+				Suppressed: w.suppressed,
+				Targets:    len(w.targets),
+				Collectors: make(map[string]int),
+			}
+			for _, collector := range w.targets {
+				summary.Collectors[collector]++
+			}
+			summaries = append(summaries, summary)
+
+			w.suppressed = 0
+			w.targets = make(map[string]string)
+		}
+		w.wasActive = active
+	}
+	return summaries
+}
@@ -0,0 +1,200 @@
+// monitor/alerts.go
+package monitor
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/storage"
+)
+
+// ActiveAlert is a target that is currently unhealthy
+type ActiveAlert struct {
+	Result         collectors.Result `json:"result"`
+	Since          time.Time         `json:"since"`
+	InhibitedBy    string            `json:"inhibited_by,omitempty"`
+	AcknowledgedBy string            `json:"acknowledged_by,omitempty"` // ID of the mute rule that silenced it
+
+	notifiedAt       time.Time
+	notifiedSeverity string
+}
+
+// activeAlerts tracks the latest unhealthy result of every target currently alerting
+type activeAlerts struct {
+	alerts   map[string]*ActiveAlert
+	resolved map[string]string // Severity last notified of recovered targets, until their recovery is
+	mu       sync.Mutex
+}
+
+// newActiveAlerts creates an empty active alert tracker
+func newActiveAlerts() *activeAlerts {
+	return &activeAlerts{
+		alerts:   make(map[string]*ActiveAlert),
+		resolved: make(map[string]string),
+	}
+}
+
+// update records unhealthy results as active and clears recovered targets
+func (a *activeAlerts) update(results []collectors.Result) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, result := range results {
+		key := result.Key()
+		if result.IsHealthy {
+			if alert, ok := a.alerts[key]; ok && !alert.notifiedAt.IsZero() {
+				a.resolved[key] = alert.notifiedSeverity
+			}
+			delete(a.alerts, key)
+			continue
+		}
+		delete(a.resolved, key)
+
+		if alert, ok := a.alerts[key]; ok {
+			alert.Result = result
+			alert.InhibitedBy = ""
+		} else {
+			a.alerts[key] = &ActiveAlert{Result: result, Since: result.Timestamp}
+		}
+	}
+}
+
+// shouldNotify reports whether an active alert is due a notification: the
+// first time its target alerts, when its severity escalates past the one last
+// notified, and again once renotifyAfter has passed. A zero renotifyAfter
+// notifies on every evaluation. A due alert is recorded as notified at now.
+func (a *activeAlerts) shouldNotify(result collectors.Result, renotifyAfter time.Duration, now time.Time) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	alert, ok := a.alerts[result.Key()]
+	if !ok {
+		return true
+	}
+
+	severity := result.EffectiveSeverity()
+	due := alert.notifiedAt.IsZero() ||
+		renotifyAfter <= 0 ||
+		now.Sub(alert.notifiedAt) >= renotifyAfter ||
+		!collectors.SeverityAtLeast(alert.notifiedSeverity, severity)
+	if due {
+		alert.notifiedAt = now
+		alert.notifiedSeverity = severity
+	}
+	return due
+}
+
+// recoveryDue reports whether a recovered target's alert had been notified,
+// and with which severity, so its recovery should be. It reports each
+// recovery once.
+func (a *activeAlerts) recoveryDue(key string) (string, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	severity, ok := a.resolved[key]
+	delete(a.resolved, key)
+	return severity, ok
+}
+
+// applyHysteresis holds results of alerting targets unhealthy until their
+// thresholds clear; see collectors.ApplyHysteresis
+func (a *activeAlerts) applyHysteresis(results []collectors.Result) []collectors.Result {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	out := make([]collectors.Result, len(results))
+	for i, result := range results {
+		if alert, ok := a.alerts[result.Key()]; ok {
+			result = collectors.ApplyHysteresis(alert.Result, result)
+		}
+		out[i] = result
+	}
+	return out
+}
+
+// markInhibited records which rule and source alert inhibit an active alert
+func (a *activeAlerts) markInhibited(key, by string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if alert, ok := a.alerts[key]; ok {
+		alert.InhibitedBy = by
+	}
+}
+
+// acknowledge records that a mute rule silenced an active alert, returning
+// the alert and true the first time that rule does
+func (a *activeAlerts) acknowledge(key, by string) (ActiveAlert, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	alert, ok := a.alerts[key]
+	if !ok || alert.AcknowledgedBy == by {
+		return ActiveAlert{}, false
+	}
+	alert.AcknowledgedBy = by
+	return *alert, true
+}
+
+// forCollector returns the active alerts raised by a collector
+func (a *activeAlerts) forCollector(name string) []collectors.Result {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var out []collectors.Result
+	for _, alert := range a.alerts {
+		if alert.Result.Collector == name {
+			out = append(out, alert.Result)
+		}
+	}
+	return out
+}
+
+// list returns a copy of every active alert, oldest first
+func (a *activeAlerts) list() []ActiveAlert {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	out := make([]ActiveAlert, 0, len(a.alerts))
+	for _, alert := range a.alerts {
+		out = append(out, *alert)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Since.Before(out[j].Since) })
+	return out
+}
+
+// snapshot returns the state of every active alert, for saving
+func (a *activeAlerts) snapshot() []storage.AlertState {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	states := make([]storage.AlertState, 0, len(a.alerts))
+	for _, alert := range a.alerts {
+		states = append(states, storage.AlertState{
+			Result:           alert.Result,
+			Since:            alert.Since,
+			NotifiedAt:       alert.notifiedAt,
+			NotifiedSeverity: alert.notifiedSeverity,
+			AcknowledgedBy:   alert.AcknowledgedBy,
+		})
+	}
+	return states
+}
+
+// restore adds saved alerts, keeping when they were last notified
+func (a *activeAlerts) restore(states []storage.AlertState) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, state := range states {
+		a.alerts[state.Result.Key()] = &ActiveAlert{
+			Result:           state.Result,
+			Since:            state.Since,
+			notifiedAt:       state.NotifiedAt,
+			notifiedSeverity: state.NotifiedSeverity,
+			AcknowledgedBy:   state.AcknowledgedBy,
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// monitor/availability.go
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/devvspaces/simple-monit/config"
+	"github.com/devvspaces/simple-monit/storage"
+)
+
+// Window is a period availability is reported over, e.g. the last 7d
+type Window struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Availability is how much of each window a target was up
+type Availability struct {
+	Key       string               `json:"key"`
+	Collector string               `json:"collector"`
+	Down      bool                 `json:"down"` // In a downtime now
+	Windows   []WindowAvailability `json:"windows"`
+}
+
+// WindowAvailability is a target's availability over one window
+type WindowAvailability struct {
+	Window          string  `json:"window"`
+	Percent         float64 `json:"availability_percent"`
+	DowntimeSeconds float64 `json:"downtime_seconds"`
+	Downtimes       int     `json:"downtimes"`
+}
+
+// AvailabilityWindows returns the configured windows availability is
+// reported over by default
+func (s *MonitorService) AvailabilityWindows() []Window {
+	var windows []Window
+	for _, name := range s.config.History.AvailabilityWindows {
+		if d, err := config.ParseWindow(name); err == nil {
+			windows = append(windows, Window{Name: name, Duration: d})
+		}
+	}
+	return windows
+}
+
+// Availability returns the availability of every current target, or those
+// of one collector, over each window: the share of the window not covered by
+// a downtime. Time before the target's first result counts as up.
+func (s *MonitorService) Availability(collector string, windows []Window) ([]Availability, error) {
+	if s.history == nil {
+		return nil, fmt.Errorf("result history is not enabled")
+	}
+
+	now := time.Now()
+	longest := time.Duration(0)
+	for _, window := range windows {
+		longest = max(longest, window.Duration)
+	}
+	downtimes, err := s.history.Downtimes(collector, now.Add(-longest), now)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string][]storage.Downtime)
+	for _, downtime := range downtimes {
+		byKey[downtime.Key] = append(byKey[downtime.Key], downtime)
+	}
+
+	var out []Availability
+	for _, result := range s.latest.list() {
+		if collector != "" && result.Collector != collector {
+			continue
+		}
+		key := result.Key()
+		availability := Availability{Key: key, Collector: result.Collector, Windows: []WindowAvailability{}}
+		for _, downtime := range byKey[key] {
+			if downtime.End.IsZero() {
+				availability.Down = true
+			}
+		}
+
+		for _, window := range windows {
+			start := now.Add(-window.Duration)
+			wa := WindowAvailability{Window: window.Name}
+			var down time.Duration
+			for _, downtime := range byKey[key] {
+				from, end := downtime.Start, downtime.End
+				if from.Before(start) {
+					from = start
+				}
+				if end.IsZero() {
+					end = now
+				}
+				if end.After(from) {
+					down += end.Sub(from)
+					wa.Downtimes++
+				}
+			}
+			wa.DowntimeSeconds = down.Seconds()
+			wa.Percent = 100 * (1 - down.Seconds()/window.Duration.Seconds())
+			availability.Windows = append(availability.Windows, wa)
+		}
+		out = append(out, availability)
+	}
+	return out, nil
+}
@@ -0,0 +1,34 @@
+// monitor/cardinality.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/collectors"
+)
+
+// limitCardinality keeps the results of at most max distinct targets, in the
+// order the collector emitted them, and returns how many targets were dropped.
+// A max of zero or less disables the limit.
+func limitCardinality(results []collectors.Result, max int) ([]collectors.Result, int) {
+	if max <= 0 || len(results) <= max {
+		return results, 0
+	}
+
+	seen := make(map[string]bool, max)
+	dropped := make(map[string]bool)
+	kept := results[:0:0]
+	for _, result := range results {
+		key := result.Key()
+		if seen[key] {
+			kept = append(kept, result)
+			continue
+		}
+		if len(seen) >= max {
+			dropped[key] = true
+			continue
+		}
+		seen[key] = true
+		kept = append(kept, result)
+	}
+
+	return kept, len(dropped)
+}
@@ -0,0 +1,85 @@
+// monitor/catalog.go
+package monitor
+
+import (
+	"sort"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/collectors/selfmonitor"
+
+	"go.uber.org/zap"
+)
+
+// Component describes a collector, notifier or output compiled into this
+// binary, for the list command
+type Component struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	Settings    []collectors.Setting `json:"settings"`
+	Type        bool                 `json:"type,omitempty"` // Configured with type: rather than by name
+}
+
+// describe returns the component for a collector, notifier or output.
+// Components that don't implement collectors.Describer have no description.
+func describe(name string, component interface{}) Component {
+	described := Component{Name: name, Settings: []collectors.Setting{}}
+	if describer, ok := component.(collectors.Describer); ok {
+		schema := describer.Schema()
+		described.Description = schema.Description
+		if schema.Settings != nil {
+			described.Settings = schema.Settings
+		}
+	}
+	return described
+}
+
+// CollectorCatalog describes the collectors compiled in, sorted by name
+func CollectorCatalog() []Component {
+	logger := zap.NewNop()
+	var catalog []Component
+	for _, factory := range collectorFactories {
+		collector := factory.create(logger)
+		catalog = append(catalog, describe(collector.Name(), collector))
+	}
+	self := selfmonitor.NewSelfMonitorCollector(logger, newSelfStats())
+	catalog = append(catalog, describe(self.Name(), self))
+	for _, typeName := range collectors.FactoryTypes() {
+		factory, _ := collectors.LookupFactory(typeName)
+		component := describe(typeName, factory(logger, typeName))
+		component.Type = true
+		catalog = append(catalog, component)
+	}
+	return sortCatalog(catalog)
+}
+
+// NotifierCatalog describes the notifiers compiled in, sorted by name. The
+// plugin notifier is listed as "plugins", its section in the configuration.
+func NotifierCatalog() []Component {
+	logger := zap.NewNop()
+	var catalog []Component
+	for _, factory := range notifierFactories {
+		notifier := factory.create(logger)
+		catalog = append(catalog, describe(notifier.Name(), notifier))
+	}
+	if newPluginNotifier != nil {
+		catalog = append(catalog, describe("plugins", newPluginNotifier(logger, "plugins")))
+	}
+	return sortCatalog(catalog)
+}
+
+// OutputCatalog describes the outputs compiled in, sorted by name
+func OutputCatalog() []Component {
+	logger := zap.NewNop()
+	var catalog []Component
+	for _, factory := range outputFactories {
+		output := factory.create(logger)
+		catalog = append(catalog, describe(output.Name(), output))
+	}
+	return sortCatalog(catalog)
+}
+
+// sortCatalog sorts components by name
+func sortCatalog(catalog []Component) []Component {
+	sort.Slice(catalog, func(i, j int) bool { return catalog[i].Name < catalog[j].Name })
+	return catalog
+}
@@ -0,0 +1,95 @@
+// monitor/check.go
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/pipeline"
+
+	"go.uber.org/zap"
+)
+
+// Check runs the named collectors, or every enabled one when names is empty,
+// once and returns their results with tags and thresholds applied. Nothing
+// else of the pipeline runs: there is no for_seconds wait, and nothing is
+// written to outputs or notified. A collector that fails to initialize or
+// collect is reported as a critical result of its own. Named collectors run
+// even if disabled. The collectors are cleaned up before Check returns.
+func (s *MonitorService) Check(ctx context.Context, names []string) ([]collectors.Result, error) {
+	if err := s.registerCollectors(); err != nil {
+		return nil, err
+	}
+	defer s.closeSecrets()
+	if len(names) == 0 {
+		for name, collectorCfg := range s.config.Collectors {
+			if collectorCfg.Enabled {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+	}
+
+	check := pipeline.New(s.logger.Named("check"),
+		pipeline.Func(StageEnrich, s.enrichStage),
+		pipeline.Func(StageEvaluate, s.evaluateStage),
+	)
+
+	var results []collectors.Result
+	for _, name := range names {
+		collector, exists := s.collectorRegistry.Get(name)
+		if !exists {
+			return nil, fmt.Errorf("collector %s is not registered", name)
+		}
+		if _, configured := s.config.Collectors[name]; !configured {
+			return nil, fmt.Errorf("collector %s is not configured", name)
+		}
+
+		batch := &pipeline.Batch{Results: s.checkCollector(ctx, collector), EvaluatedAt: time.Now()}
+		if err := check.Publish(ctx, batch); err != nil {
+			return nil, err
+		}
+		results = append(results, batch.Results...)
+	}
+	return results, nil
+}
+
+// checkCollector initializes a collector, collects once and cleans it up
+func (s *MonitorService) checkCollector(ctx context.Context, collector collectors.Collector) []collectors.Result {
+	name := collector.Name()
+	failed := func(err error) []collectors.Result {
+		return []collectors.Result{{
+			Collector: name,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("Collector %s failed: %v", name, err),
+			Severity:  collectors.SeverityCritical,
+			Metrics:   map[string]float64{},
+			Metadata:  map[string]interface{}{"failure": "check"},
+		}}
+	}
+
+	settings, err := s.collectorSettings(s.config, name)
+	if err != nil {
+		return failed(err)
+	}
+	if err := collector.Init(settings); err != nil {
+		return failed(fmt.Errorf("init: %w", err))
+	}
+	defer func() {
+		if err := collector.Cleanup(); err != nil {
+			s.logger.Warn("Collector cleanup failed", zap.String("collector", name), zap.Error(err))
+		}
+	}()
+
+	collectionCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	results, err := safeCollect(collectionCtx, collector)
+	if err != nil {
+		return failed(err)
+	}
+	results, _ = limitCardinality(results, s.config.GetCollectorMaxSeries(name))
+	return results
+}
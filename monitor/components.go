@@ -0,0 +1,61 @@
+// monitor/components.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/collectors/disk"
+	"github.com/devvspaces/simple-monit/collectors/memory"
+	"github.com/devvspaces/simple-monit/notifiers"
+	"github.com/devvspaces/simple-monit/notifiers/email"
+	"github.com/devvspaces/simple-monit/outputs"
+	"github.com/devvspaces/simple-monit/outputs/jsonlines"
+
+	"go.uber.org/zap"
+)
+
+// The built-in components compiled into the binary. Optional families add
+// themselves from files guarded by build tags (see components_*.go), so a
+// binary built with e.g. -tags minimal only contains the core components.
+var (
+	collectorFactories []collectorFactory
+	notifierFactories  []notifierFactory
+	outputFactories    []outputFactory
+
+	// newPluginNotifier creates a notifier served by a go-plugin program; nil
+	// when plugin notifiers are not compiled in
+	newPluginNotifier func(logger *zap.Logger, name string) notifiers.Notifier
+)
+
+// collectorFactory creates a built-in collector
+type collectorFactory struct {
+	loggerName string
+	create     func(logger *zap.Logger) collectors.Collector
+}
+
+// notifierFactory creates a built-in notifier
+type notifierFactory struct {
+	loggerName string
+	create     func(logger *zap.Logger) notifiers.Notifier
+}
+
+// outputFactory creates a built-in output
+type outputFactory struct {
+	loggerName string
+	create     func(logger *zap.Logger) outputs.Output
+}
+
+// Core components are always compiled in
+func init() {
+	collectorFactories = append(collectorFactories,
+		collectorFactory{"diskCollector", func(l *zap.Logger) collectors.Collector { return disk.NewDiskCollector(l) }},
+		collectorFactory{"memoryCollector", func(l *zap.Logger) collectors.Collector { return memory.NewMemoryCollector(l) }},
+	)
+
+	notifierFactories = append(notifierFactories,
+		notifierFactory{"emailNotifier", func(l *zap.Logger) notifiers.Notifier { return email.NewEmailNotifier(l) }},
+	)
+
+	outputFactories = append(outputFactories,
+		outputFactory{"jsonOutput", func(l *zap.Logger) outputs.Output { return jsonlines.NewJSONLinesOutput(l) }},
+	)
+}
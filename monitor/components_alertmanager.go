@@ -0,0 +1,18 @@
+//go:build !noalertmanager && !minimal
+
+// monitor/components_alertmanager.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/notifiers"
+	"github.com/devvspaces/simple-monit/notifiers/alertmanager"
+
+	"go.uber.org/zap"
+)
+
+// Alertmanager components; exclude with -tags noalertmanager
+func init() {
+	notifierFactories = append(notifierFactories,
+		notifierFactory{"alertmanagerNotifier", func(l *zap.Logger) notifiers.Notifier { return alertmanager.NewAlertmanagerNotifier(l) }},
+	)
+}
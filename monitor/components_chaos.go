@@ -0,0 +1,18 @@
+//go:build !nochaos && !minimal
+
+// monitor/components_chaos.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/notifiers"
+	"github.com/devvspaces/simple-monit/notifiers/chaos"
+
+	"go.uber.org/zap"
+)
+
+// Chaos testing components; exclude with -tags nochaos
+func init() {
+	notifierFactories = append(notifierFactories,
+		notifierFactory{"chaosNotifier", func(l *zap.Logger) notifiers.Notifier { return chaos.NewChaosNotifier(l) }},
+	)
+}
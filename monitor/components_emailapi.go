@@ -0,0 +1,18 @@
+//go:build !noemailapi && !minimal
+
+// monitor/components_emailapi.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/notifiers"
+	"github.com/devvspaces/simple-monit/notifiers/emailapi"
+
+	"go.uber.org/zap"
+)
+
+// HTTP API email components; exclude with -tags noemailapi
+func init() {
+	notifierFactories = append(notifierFactories,
+		notifierFactory{"emailAPINotifier", func(l *zap.Logger) notifiers.Notifier { return emailapi.NewEmailAPINotifier(l) }},
+	)
+}
@@ -0,0 +1,18 @@
+//go:build !nofile && !minimal
+
+// monitor/components_file.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/notifiers"
+	"github.com/devvspaces/simple-monit/notifiers/file"
+
+	"go.uber.org/zap"
+)
+
+// File audit components; exclude with -tags nofile
+func init() {
+	notifierFactories = append(notifierFactories,
+		notifierFactory{"fileNotifier", func(l *zap.Logger) notifiers.Notifier { return file.NewFileNotifier(l) }},
+	)
+}
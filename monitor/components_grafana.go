@@ -0,0 +1,18 @@
+//go:build !nografana && !minimal
+
+// monitor/components_grafana.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/outputs"
+	"github.com/devvspaces/simple-monit/outputs/grafana"
+
+	"go.uber.org/zap"
+)
+
+// Grafana components; exclude with -tags nografana
+func init() {
+	outputFactories = append(outputFactories,
+		outputFactory{"grafanaOutput", func(l *zap.Logger) outputs.Output { return grafana.NewGrafanaOutput(l) }},
+	)
+}
@@ -0,0 +1,18 @@
+//go:build !nohaproxy && !minimal
+
+// monitor/components_haproxy.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/collectors/haproxy"
+
+	"go.uber.org/zap"
+)
+
+// HAProxy components; exclude with -tags nohaproxy
+func init() {
+	collectorFactories = append(collectorFactories,
+		collectorFactory{"haproxyCollector", func(l *zap.Logger) collectors.Collector { return haproxy.NewHAProxyCollector(l) }},
+	)
+}
@@ -0,0 +1,18 @@
+//go:build !noheartbeat && !minimal
+
+// monitor/components_heartbeat.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/notifiers"
+	"github.com/devvspaces/simple-monit/notifiers/heartbeat"
+
+	"go.uber.org/zap"
+)
+
+// Heartbeat components; exclude with -tags noheartbeat
+func init() {
+	notifierFactories = append(notifierFactories,
+		notifierFactory{"heartbeatNotifier", func(l *zap.Logger) notifiers.Notifier { return heartbeat.NewHeartbeatNotifier(l) }},
+	)
+}
@@ -0,0 +1,18 @@
+//go:build !noinfluxdb && !minimal
+
+// monitor/components_influxdb.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/outputs"
+	"github.com/devvspaces/simple-monit/outputs/influxdb"
+
+	"go.uber.org/zap"
+)
+
+// InfluxDB components; exclude with -tags noinfluxdb
+func init() {
+	outputFactories = append(outputFactories,
+		outputFactory{"influxdbOutput", func(l *zap.Logger) outputs.Output { return influxdb.NewInfluxDBOutput(l) }},
+	)
+}
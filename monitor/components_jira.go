@@ -0,0 +1,18 @@
+//go:build !nojira && !minimal
+
+// monitor/components_jira.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/notifiers"
+	"github.com/devvspaces/simple-monit/notifiers/jira"
+
+	"go.uber.org/zap"
+)
+
+// Jira components; exclude with -tags nojira
+func init() {
+	notifierFactories = append(notifierFactories,
+		notifierFactory{"jiraNotifier", func(l *zap.Logger) notifiers.Notifier { return jira.NewJiraNotifier(l) }},
+	)
+}
@@ -0,0 +1,24 @@
+//go:build !nomqtt && !minimal
+
+// monitor/components_mqtt.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/collectors/mqtt"
+	"github.com/devvspaces/simple-monit/notifiers"
+	mqttnotifier "github.com/devvspaces/simple-monit/notifiers/mqtt"
+
+	"go.uber.org/zap"
+)
+
+// MQTT components; exclude with -tags nomqtt
+func init() {
+	collectorFactories = append(collectorFactories,
+		collectorFactory{"mqttCollector", func(l *zap.Logger) collectors.Collector { return mqtt.NewMQTTCollector(l) }},
+	)
+
+	notifierFactories = append(notifierFactories,
+		notifierFactory{"mqttNotifier", func(l *zap.Logger) notifiers.Notifier { return mqttnotifier.NewMQTTNotifier(l) }},
+	)
+}
@@ -0,0 +1,18 @@
+//go:build !nontfy && !minimal
+
+// monitor/components_ntfy.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/notifiers"
+	"github.com/devvspaces/simple-monit/notifiers/ntfy"
+
+	"go.uber.org/zap"
+)
+
+// ntfy components; exclude with -tags nontfy
+func init() {
+	notifierFactories = append(notifierFactories,
+		notifierFactory{"ntfyNotifier", func(l *zap.Logger) notifiers.Notifier { return ntfy.NewNtfyNotifier(l) }},
+	)
+}
@@ -0,0 +1,24 @@
+//go:build !noplugin && !minimal
+
+// monitor/components_plugin.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/collectors"
+	collectorplugin "github.com/devvspaces/simple-monit/collectors/plugin"
+	"github.com/devvspaces/simple-monit/config"
+	"github.com/devvspaces/simple-monit/notifiers"
+	notifierplugin "github.com/devvspaces/simple-monit/notifiers/plugin"
+
+	"go.uber.org/zap"
+)
+
+// Plugin collectors and notifiers; exclude with -tags noplugin
+func init() {
+	collectors.MustRegisterFactory(config.CollectorTypePlugin, func(l *zap.Logger, name string) collectors.Collector {
+		return collectorplugin.NewPluginCollector(l, name)
+	})
+	newPluginNotifier = func(l *zap.Logger, name string) notifiers.Notifier {
+		return notifierplugin.NewPluginNotifier(l, name)
+	}
+}
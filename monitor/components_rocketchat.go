@@ -0,0 +1,18 @@
+//go:build !norocketchat && !minimal
+
+// monitor/components_rocketchat.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/notifiers"
+	"github.com/devvspaces/simple-monit/notifiers/rocketchat"
+
+	"go.uber.org/zap"
+)
+
+// Rocket.Chat components; exclude with -tags norocketchat
+func init() {
+	notifierFactories = append(notifierFactories,
+		notifierFactory{"rocketchatNotifier", func(l *zap.Logger) notifiers.Notifier { return rocketchat.NewRocketChatNotifier(l) }},
+	)
+}
@@ -0,0 +1,18 @@
+//go:build !nosignal && !minimal
+
+// monitor/components_signal.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/notifiers"
+	"github.com/devvspaces/simple-monit/notifiers/signal"
+
+	"go.uber.org/zap"
+)
+
+// Signal components; exclude with -tags nosignal
+func init() {
+	notifierFactories = append(notifierFactories,
+		notifierFactory{"signalNotifier", func(l *zap.Logger) notifiers.Notifier { return signal.NewSignalNotifier(l) }},
+	)
+}
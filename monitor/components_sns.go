@@ -0,0 +1,18 @@
+//go:build !nosns && !minimal
+
+// monitor/components_sns.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/notifiers"
+	"github.com/devvspaces/simple-monit/notifiers/sns"
+
+	"go.uber.org/zap"
+)
+
+// Amazon SNS components; exclude with -tags nosns
+func init() {
+	notifierFactories = append(notifierFactories,
+		notifierFactory{"snsNotifier", func(l *zap.Logger) notifiers.Notifier { return sns.NewSNSNotifier(l) }},
+	)
+}
@@ -0,0 +1,18 @@
+//go:build !nosplunkoncall && !minimal
+
+// monitor/components_splunkoncall.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/notifiers"
+	"github.com/devvspaces/simple-monit/notifiers/splunkoncall"
+
+	"go.uber.org/zap"
+)
+
+// Splunk On-Call components; exclude with -tags nosplunkoncall
+func init() {
+	notifierFactories = append(notifierFactories,
+		notifierFactory{"splunkOnCallNotifier", func(l *zap.Logger) notifiers.Notifier { return splunkoncall.NewSplunkOnCallNotifier(l) }},
+	)
+}
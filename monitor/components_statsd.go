@@ -0,0 +1,18 @@
+//go:build !nostatsd && !minimal
+
+// monitor/components_statsd.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/outputs"
+	"github.com/devvspaces/simple-monit/outputs/statsd"
+
+	"go.uber.org/zap"
+)
+
+// StatsD components; exclude with -tags nostatsd
+func init() {
+	outputFactories = append(outputFactories,
+		outputFactory{"statsdOutput", func(l *zap.Logger) outputs.Output { return statsd.NewStatsDOutput(l) }},
+	)
+}
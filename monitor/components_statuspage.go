@@ -0,0 +1,20 @@
+//go:build !nostatuspage && !minimal
+
+// monitor/components_statuspage.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/outputs"
+	"github.com/devvspaces/simple-monit/outputs/statuspage"
+	"github.com/devvspaces/simple-monit/outputs/statuspush"
+
+	"go.uber.org/zap"
+)
+
+// Status page components; exclude with -tags nostatuspage
+func init() {
+	outputFactories = append(outputFactories,
+		outputFactory{"statusPushOutput", func(l *zap.Logger) outputs.Output { return statuspush.NewStatusPushOutput(l) }},
+		outputFactory{"statusPageOutput", func(l *zap.Logger) outputs.Output { return statuspage.NewStatusPageOutput(l) }},
+	)
+}
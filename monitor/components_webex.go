@@ -0,0 +1,18 @@
+//go:build !nowebex && !minimal
+
+// monitor/components_webex.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/notifiers"
+	"github.com/devvspaces/simple-monit/notifiers/webex"
+
+	"go.uber.org/zap"
+)
+
+// Webex components; exclude with -tags nowebex
+func init() {
+	notifierFactories = append(notifierFactories,
+		notifierFactory{"webexNotifier", func(l *zap.Logger) notifiers.Notifier { return webex.NewWebexNotifier(l) }},
+	)
+}
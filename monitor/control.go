@@ -0,0 +1,152 @@
+// monitor/control.go
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/devvspaces/simple-monit/config"
+	"github.com/devvspaces/simple-monit/mutes"
+
+	"go.uber.org/zap"
+)
+
+// CollectorState is the runtime state of a registered collector and its run
+// statistics since startup
+type CollectorState struct {
+	Name              string     `json:"name"`
+	Running           bool       `json:"running"` // Enabled, with a running task
+	Paused            bool       `json:"paused"`
+	Runs              int        `json:"runs"`
+	Failures          int        `json:"failures"`
+	ConsecutiveErrors int        `json:"consecutive_errors"`
+	LastRun           *time.Time `json:"last_run,omitempty"`
+	LastDurationMs    int64      `json:"last_duration_ms"`
+	LastError         string     `json:"last_error,omitempty"`
+	NextRun           *time.Time `json:"next_run,omitempty"` // Next scheduled run, while running
+}
+
+// Collectors returns the state of every registered collector, by name
+func (s *MonitorService) Collectors() []CollectorState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var states []CollectorState
+	for _, collector := range s.collectorRegistry.GetAll() {
+		states = append(states, s.stateLocked(collector.Name()))
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Name < states[j].Name })
+	return states
+}
+
+// CollectorState returns the state of a registered collector
+func (s *MonitorService) CollectorState(name string) (CollectorState, bool) {
+	if !s.HasCollector(name) {
+		return CollectorState{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stateLocked(name), true
+}
+
+// stateLocked returns a collector's state; s.mu must be held
+func (s *MonitorService) stateLocked(name string) CollectorState {
+	task, running := s.collectorTasks[name]
+	stats := s.selfStats.collection(name)
+	state := CollectorState{
+		Name:              name,
+		Running:           running,
+		Paused:            s.paused[name],
+		Runs:              stats.Runs,
+		Failures:          stats.Failures,
+		ConsecutiveErrors: stats.ConsecutiveErrors,
+		LastDurationMs:    stats.LastDuration.Milliseconds(),
+		LastError:         stats.LastError,
+	}
+	if !stats.LastRun.IsZero() {
+		state.LastRun = &stats.LastRun
+	}
+	if running {
+		if next := task.next(); !next.IsZero() {
+			state.NextRun = &next
+		}
+	}
+	return state
+}
+
+// PauseCollector stops scheduled runs of a running collector until it is
+// resumed. Its active alerts stay as they are. The pause survives reloads
+// but not restarts.
+func (s *MonitorService) PauseCollector(name string) error {
+	if err := s.setPaused(name, true); err != nil {
+		return err
+	}
+	s.logger.Named("audit").Info("Collector paused", zap.String("collector", name))
+	return nil
+}
+
+// ResumeCollector resumes scheduled runs of a paused collector
+func (s *MonitorService) ResumeCollector(name string) error {
+	if err := s.setPaused(name, false); err != nil {
+		return err
+	}
+	s.logger.Named("audit").Info("Collector resumed", zap.String("collector", name))
+	return nil
+}
+
+// TriggerCollector asks a running collector's task to run it now, paused or
+// not, without moving its schedule. It returns without waiting for the run;
+// a trigger while one is already pending is merged into it.
+func (s *MonitorService) TriggerCollector(name string) error {
+	s.mu.Lock()
+	task, running := s.collectorTasks[name]
+	s.mu.Unlock()
+
+	if !running {
+		return fmt.Errorf("collector %s is not running", name)
+	}
+	select {
+	case task.trigger <- struct{}{}:
+	default:
+	}
+	s.logger.Named("audit").Info("Collector run triggered", zap.String("collector", name))
+	return nil
+}
+
+// Silence adds a mute rule to the mute rules file, giving it an ID when it
+// has none, and returns the rule as added
+func (s *MonitorService) Silence(rule config.MuteRule) (config.MuteRule, error) {
+	if rule.ID == "" {
+		rule.ID = mutes.NewID()
+	}
+	if err := s.muter.Add(rule); err != nil {
+		return config.MuteRule{}, err
+	}
+	s.logger.Named("audit").Info("Mute rule added", zap.String("id", rule.ID), zap.String("collector", rule.Collector),
+		zap.String("message_regex", rule.MessageRegex), zap.Time("expires", rule.Expires), zap.String("comment", rule.Comment))
+	return rule, nil
+}
+
+// setPaused records whether a running collector is paused
+func (s *MonitorService) setPaused(name string, paused bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, running := s.collectorTasks[name]; !running {
+		return fmt.Errorf("collector %s is not running", name)
+	}
+	if paused {
+		s.paused[name] = true
+	} else {
+		delete(s.paused, name)
+	}
+	return nil
+}
+
+// isPaused reports whether a collector's scheduled runs are paused
+func (s *MonitorService) isPaused(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.paused[name]
+}
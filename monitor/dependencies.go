@@ -0,0 +1,36 @@
+// monitor/dependencies.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/collectors"
+)
+
+// failingDependency returns the active alert of a check the result's check
+// depends on, if any, so alerts caused by the same failure, such as endpoint
+// checks behind a failing gateway, are not notified separately. A label
+// missing on both sides counts as equal.
+func (s *MonitorService) failingDependency(result collectors.Result) *collectors.Result {
+	deps := s.currentConfig().Collectors[result.Collector].DependsOn
+	if len(deps) == 0 {
+		return nil
+	}
+
+	labels := result.Labels()
+	for _, dep := range deps {
+		for _, source := range s.activeAlerts.forCollector(dep.Collector) {
+			sourceLabels := source.Labels()
+
+			equal := true
+			for _, label := range dep.Equal {
+				if sourceLabels[label] != labels[label] {
+					equal = false
+					break
+				}
+			}
+			if equal {
+				return &source
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// monitor/digest.go
+package monitor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"go.uber.org/zap"
+)
+
+// digest accumulates normal lane results over a window and delivers them as
+// one combined notification, instead of one per collector run. The window
+// starts with the first result buffered after a flush. Each target keeps only
+// its latest result, so a flapping check appears once per digest.
+type digest struct {
+	window      func(now time.Time) time.Duration
+	deliver     deliverFunc
+	results     map[string]collectors.Result
+	order       []string
+	evaluatedAt time.Time
+	timer       *time.Timer
+	mu          sync.Mutex
+	logger      *zap.Logger
+}
+
+// newDigest creates a digest that flushes to deliver every window
+func newDigest(logger *zap.Logger, window time.Duration, deliver deliverFunc) *digest {
+	return newDigestUntil(logger, func(time.Time) time.Duration { return window }, deliver)
+}
+
+// newDigestUntil creates a digest whose window length is decided when it
+// starts, e.g. to end at a fixed time of day
+func newDigestUntil(logger *zap.Logger, window func(now time.Time) time.Duration, deliver deliverFunc) *digest {
+	return &digest{
+		window:  window,
+		deliver: deliver,
+		results: make(map[string]collectors.Result),
+		logger:  logger,
+	}
+}
+
+// add buffers results for the next digest. Critical results queued behind
+// earlier ones on the normal lane are not held for the window: they replace
+// the buffered state of their target and are delivered straight away, and
+// only their delivery can fail.
+func (d *digest) add(ctx context.Context, results []collectors.Result, evaluatedAt time.Time) error {
+	var critical []collectors.Result
+	d.mu.Lock()
+	for _, result := range results {
+		if result.EffectiveSeverity() == collectors.SeverityCritical {
+			critical = append(critical, result)
+			continue
+		}
+		if d.timer == nil {
+			d.evaluatedAt = evaluatedAt
+			d.timer = time.AfterFunc(d.window(time.Now()), d.flushOnTimer)
+		}
+		key := result.Key()
+		if _, exists := d.results[key]; !exists {
+			d.order = append(d.order, key)
+		}
+		d.results[key] = result
+	}
+	d.mu.Unlock()
+
+	if len(critical) == 0 {
+		return nil
+	}
+	d.supersede(critical)
+	return d.deliver(ctx, critical, evaluatedAt)
+}
+
+// supersede drops buffered results for targets that have a newer state
+// delivered outside the digest, e.g. on the fast lane, so the digest cannot
+// deliver an older state after it
+func (d *digest) supersede(results []collectors.Result) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, result := range results {
+		delete(d.results, result.Key())
+	}
+}
+
+// take returns the buffered results in arrival order and resets the buffer
+func (d *digest) take() ([]collectors.Result, time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+
+	results := make([]collectors.Result, 0, len(d.results))
+	for _, key := range d.order {
+		if result, ok := d.results[key]; ok {
+			results = append(results, result)
+			delete(d.results, key)
+		}
+	}
+
+	d.results = make(map[string]collectors.Result)
+	d.order = nil
+	return results, d.evaluatedAt
+}
+
+// flushOnTimer delivers the digest when its window ends
+func (d *digest) flushOnTimer() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := d.flush(ctx); err != nil {
+		d.logger.Error("Digest delivery failed", zap.Error(err))
+	}
+}
+
+// flush delivers everything buffered as one notification
+func (d *digest) flush(ctx context.Context) error {
+	results, evaluatedAt := d.take()
+	if len(results) == 0 {
+		return nil
+	}
+
+	d.logger.Info("Delivering notification digest", zap.Int("results", len(results)), zap.Time("since", evaluatedAt))
+	return d.deliver(ctx, results, evaluatedAt)
+}
+
+// close delivers what is left in the digest
+func (d *digest) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := d.flush(ctx); err != nil {
+		d.logger.Error("Final digest delivery failed", zap.Error(err))
+	}
+}
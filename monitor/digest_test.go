@@ -0,0 +1,123 @@
+// monitor/digest_test.go
+package monitor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"go.uber.org/zap"
+)
+
+// deliveries records the batches a delivery function received
+type deliveries struct {
+	mu      sync.Mutex
+	batches [][]collectors.Result
+	arrived chan struct{}
+}
+
+func newDeliveries() *deliveries {
+	return &deliveries{arrived: make(chan struct{}, 16)}
+}
+
+func (d *deliveries) deliver(ctx context.Context, results []collectors.Result, evaluatedAt time.Time) error {
+	d.mu.Lock()
+	d.batches = append(d.batches, results)
+	d.mu.Unlock()
+	d.arrived <- struct{}{}
+	return nil
+}
+
+func (d *deliveries) received() [][]collectors.Result {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([][]collectors.Result(nil), d.batches...)
+}
+
+// alertOf returns an unhealthy disk result for path with severity
+func alertOf(path, severity string) collectors.Result {
+	return collectors.Result{
+		Collector: "disk_space",
+		Timestamp: time.Now(),
+		Severity:  severity,
+		Message:   severity + " on " + path,
+		Metadata:  map[string]interface{}{"path": path},
+	}
+}
+
+func TestDigestSendsCriticalBehindWarningImmediately(t *testing.T) {
+	sent := newDeliveries()
+	d := newDigest(zap.NewNop(), time.Hour, sent.deliver)
+	ctx := context.Background()
+
+	if err := d.add(ctx, []collectors.Result{alertOf("/", collectors.SeverityWarning), alertOf("/data", collectors.SeverityWarning)}, time.Now()); err != nil {
+		t.Fatalf("add warnings: %v", err)
+	}
+	if got := sent.received(); len(got) != 0 {
+		t.Fatalf("warnings were delivered before the window ended: %v", got)
+	}
+
+	critical := alertOf("/", collectors.SeverityCritical)
+	if err := d.add(ctx, []collectors.Result{critical}, time.Now()); err != nil {
+		t.Fatalf("add critical: %v", err)
+	}
+	got := sent.received()
+	if len(got) != 1 || len(got[0]) != 1 || got[0][0].Message != critical.Message {
+		t.Fatalf("got deliveries %v, want the critical alone", got)
+	}
+
+	// The critical superseded the warning of its target; the other target's
+	// warning is still held for the digest
+	if err := d.flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	got = sent.received()
+	if len(got) != 2 || len(got[1]) != 1 || got[1][0].Key() != alertOf("/data", "").Key() {
+		t.Fatalf("got digest %v, want only the /data warning", got[len(got)-1])
+	}
+}
+
+func TestCriticalQueuedBehindWarningSkipsDigestWindow(t *testing.T) {
+	fast, sent := newDeliveries(), newDeliveries()
+	d := newDigest(zap.NewNop(), time.Hour, sent.deliver)
+	defer d.close()
+
+	// Hold the normal lane while the warning is in flight, so the critical
+	// for the same target is queued behind it instead of taking the fast lane
+	gate := make(chan struct{})
+	var once sync.Once
+	normal := func(ctx context.Context, results []collectors.Result, evaluatedAt time.Time) error {
+		once.Do(func() { <-gate })
+		return d.add(ctx, results, evaluatedAt)
+	}
+	dispatcher := newDispatcher(zap.NewNop(), 1, fast.deliver, normal)
+	defer dispatcher.close()
+
+	ctx := context.Background()
+	if err := dispatcher.dispatch(ctx, []collectors.Result{alertOf("/", collectors.SeverityWarning)}, time.Now()); err != nil {
+		t.Fatalf("dispatch warning: %v", err)
+	}
+	if err := dispatcher.dispatch(ctx, []collectors.Result{alertOf("/", collectors.SeverityCritical)}, time.Now()); err != nil {
+		t.Fatalf("dispatch critical: %v", err)
+	}
+	if got := fast.received(); len(got) != 0 {
+		t.Fatalf("critical overtook the warning of its target on the fast lane: %v", got)
+	}
+	close(gate)
+
+	select {
+	case <-sent.arrived:
+	case <-time.After(5 * time.Second):
+		t.Fatal("critical was held for the digest window")
+	}
+	got := sent.received()
+	if len(got) != 1 || len(got[0]) != 1 || got[0][0].Severity != collectors.SeverityCritical {
+		t.Fatalf("got deliveries %v, want the critical alone", got)
+	}
+	if results, _ := d.take(); len(results) != 0 {
+		t.Errorf("digest still holds %v for the target", results)
+	}
+}
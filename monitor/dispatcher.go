@@ -0,0 +1,168 @@
+// monitor/dispatcher.go
+package monitor
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"go.uber.org/zap"
+)
+
+// shardQueueSize is the number of pending notifications each worker buffers
+const shardQueueSize = 64
+
+// notification is a batch of unhealthy results waiting for delivery
+type notification struct {
+	results     []collectors.Result
+	evaluatedAt time.Time
+}
+
+// deliverFunc delivers a batch of results to the notifiers
+type deliverFunc func(ctx context.Context, results []collectors.Result, evaluatedAt time.Time) error
+
+// dispatcher routes notifications onto priority lanes. Critical results take the
+// fast lane and are delivered immediately by the caller, bypassing any queueing,
+// batching or rate limiting. Everything else goes through the normal lane, which
+// is drained by a pool of workers and handed to its own delivery function,
+// which may batch it further (see digest).
+//
+// Delivery is ordered per alert key: the normal lane is sharded by collector so
+// each collector's notifications are delivered by one worker in FIFO order, and
+// a critical result only takes the fast lane when nothing for the same key is
+// queued or in flight. Otherwise it is queued behind the earlier notifications,
+// so a later state can never overtake the one it follows.
+type dispatcher struct {
+	deliver deliverFunc
+	normal  deliverFunc
+	shards  []chan notification
+	pending map[string]int
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	logger  *zap.Logger
+}
+
+// newDispatcher creates a dispatcher and starts its normal lane workers.
+// deliver sends fast lane results; normal receives the normal lane.
+func newDispatcher(logger *zap.Logger, workers int, deliver, normal deliverFunc) *dispatcher {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	d := &dispatcher{
+		deliver: deliver,
+		normal:  normal,
+		shards:  make([]chan notification, workers),
+		pending: make(map[string]int),
+		logger:  logger,
+	}
+
+	for i := range d.shards {
+		d.shards[i] = make(chan notification, shardQueueSize)
+		d.wg.Add(1)
+		go d.run(d.shards[i])
+	}
+	return d
+}
+
+// dispatch splits results by severity and hands them to the matching lane.
+// Only fast lane delivery errors are returned; normal lane errors are logged
+// by the workers.
+func (d *dispatcher) dispatch(ctx context.Context, results []collectors.Result, evaluatedAt time.Time) error {
+	var fast, queued []collectors.Result
+
+	d.mu.Lock()
+	for _, result := range results {
+		key := result.Key()
+		if result.EffectiveSeverity() == collectors.SeverityCritical && d.pending[key] == 0 {
+			fast = append(fast, result)
+		} else {
+			queued = append(queued, result)
+		}
+		d.pending[key]++
+	}
+	d.mu.Unlock()
+
+	// Results of one run share a collector, but injected batches may not
+	var order []string
+	byCollector := make(map[string][]collectors.Result)
+	for _, result := range queued {
+		if _, seen := byCollector[result.Collector]; !seen {
+			order = append(order, result.Collector)
+		}
+		byCollector[result.Collector] = append(byCollector[result.Collector], result)
+	}
+
+	for _, collector := range order {
+		group := byCollector[collector]
+		select {
+		case d.shardFor(collector) <- notification{results: group, evaluatedAt: evaluatedAt}:
+		case <-ctx.Done():
+			d.logger.Warn("Dropped normal lane notification on shutdown", zap.Int("results", len(group)))
+			d.release(group)
+		}
+	}
+
+	if len(fast) == 0 {
+		return nil
+	}
+
+	d.logger.Debug("Delivering critical results on the fast lane", zap.Int("results", len(fast)))
+	defer d.release(fast)
+	return d.deliver(ctx, fast, evaluatedAt)
+}
+
+// shardFor returns the worker queue responsible for a collector
+func (d *dispatcher) shardFor(collector string) chan notification {
+	h := fnv.New32a()
+	h.Write([]byte(collector))
+	return d.shards[h.Sum32()%uint32(len(d.shards))]
+}
+
+// release marks results as delivered (or dropped) for ordering purposes
+func (d *dispatcher) release(results []collectors.Result) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, result := range results {
+		key := result.Key()
+		if d.pending[key]--; d.pending[key] <= 0 {
+			delete(d.pending, key)
+		}
+	}
+}
+
+// forget drops the ordering state of targets that no longer exist
+func (d *dispatcher) forget(keys []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, key := range keys {
+		delete(d.pending, key)
+	}
+}
+
+// run drains one normal lane shard until it is closed
+func (d *dispatcher) run(queue chan notification) {
+	defer d.wg.Done()
+
+	for n := range queue {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := d.normal(ctx, n.results, n.evaluatedAt); err != nil {
+			d.logger.Error("Normal lane delivery failed", zap.Int("results", len(n.results)), zap.Error(err))
+		}
+		cancel()
+		d.release(n.results)
+	}
+}
+
+// close stops accepting notifications and waits for the normal lane to drain
+func (d *dispatcher) close() {
+	for _, queue := range d.shards {
+		close(queue)
+	}
+	d.wg.Wait()
+}
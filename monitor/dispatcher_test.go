@@ -0,0 +1,76 @@
+// monitor/dispatcher_test.go
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"go.uber.org/zap"
+)
+
+func TestDispatcherLanes(t *testing.T) {
+	tests := []struct {
+		name     string
+		critical string // Path of the critical result sent behind the queued warnings
+		fast     bool   // Whether it overtakes them
+	}{
+		{name: "critical for a new target overtakes queued results", critical: "/data", fast: true},
+		{name: "critical for a queued target waits its turn", critical: "/", fast: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fast, normal := newDeliveries(), newDeliveries()
+			gate := make(chan struct{})
+			d := newDispatcher(zap.NewNop(), 1, fast.deliver, func(ctx context.Context, results []collectors.Result, evaluatedAt time.Time) error {
+				<-gate
+				return normal.deliver(ctx, results, evaluatedAt)
+			})
+			ctx := context.Background()
+
+			// The normal lane is held at the gate, so its results stay queued
+			if err := d.dispatch(ctx, []collectors.Result{alertOf("/", collectors.SeverityWarning)}, time.Now()); err != nil {
+				t.Fatalf("dispatch warning: %v", err)
+			}
+			if err := d.dispatch(ctx, []collectors.Result{alertOf("/", collectors.SeverityWarning)}, time.Now()); err != nil {
+				t.Fatalf("dispatch warning: %v", err)
+			}
+			critical := alertOf(tt.critical, collectors.SeverityCritical)
+			if err := d.dispatch(ctx, []collectors.Result{critical}, time.Now()); err != nil {
+				t.Fatalf("dispatch critical: %v", err)
+			}
+
+			got := fast.received()
+			if tt.fast && (len(got) != 1 || got[0][0].Message != critical.Message) {
+				t.Fatalf("got fast lane deliveries %v, want the critical while the normal lane is queued", got)
+			}
+			if !tt.fast && len(got) != 0 {
+				t.Fatalf("got fast lane deliveries %v, want none ahead of the queued warnings", got)
+			}
+
+			close(gate)
+			d.close()
+
+			var messages []string
+			for _, batch := range normal.received() {
+				for _, result := range batch {
+					messages = append(messages, result.Message)
+				}
+			}
+			want := []string{"warning on /", "warning on /"}
+			if !tt.fast {
+				want = append(want, critical.Message)
+			}
+			if len(messages) != len(want) {
+				t.Fatalf("got normal lane deliveries %v, want %v", messages, want)
+			}
+			for i := range want {
+				if messages[i] != want[i] {
+					t.Fatalf("got normal lane deliveries %v, want %v", messages, want)
+				}
+			}
+		})
+	}
+}
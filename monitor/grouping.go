@@ -0,0 +1,113 @@
+// monitor/grouping.go
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/devvspaces/simple-monit/collectors"
+)
+
+// groupResults rolls up results that share the values of every label in
+// groupBy into one result per group, so e.g. several failing disk paths
+// arrive as a single notification. Groups of one, and recoveries, are passed
+// through unchanged, and without labels nothing is grouped. Groups keep the
+// order of their first result.
+func groupResults(results []collectors.Result, groupBy []string) []collectors.Result {
+	if len(groupBy) == 0 || len(results) < 2 {
+		return results
+	}
+
+	var order []string
+	groups := make(map[string][]collectors.Result)
+	for _, result := range results {
+		labels := result.Labels()
+		parts := make([]string, len(groupBy))
+		for i, label := range groupBy {
+			parts[i] = label + "=" + labels[label]
+		}
+		key := strings.Join(parts, "|")
+		if result.IsHealthy {
+			key = "resolved|" + result.Key()
+		}
+		if _, exists := groups[key]; !exists {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], result)
+	}
+
+	out := make([]collectors.Result, 0, len(order))
+	for _, key := range order {
+		members := groups[key]
+		if len(members) == 1 {
+			out = append(out, members[0])
+			continue
+		}
+		out = append(out, rollUp(members, groupBy))
+	}
+	return out
+}
+
+// rollUp combines the results of a group into one unhealthy result with the
+// most severe member's severity, the earliest timestamp, the grouping labels
+// as metadata, the tags every member shares and a summary message listing
+// every member. It is firing when a member started to.
+func rollUp(members []collectors.Result, groupBy []string) collectors.Result {
+	first := members[0]
+	labels := first.Labels()
+
+	rolled := collectors.Result{
+		IsHealthy: false,
+		Collector: first.Collector,
+		Timestamp: first.Timestamp,
+		Metrics:   map[string]float64{"grouped": float64(len(members))},
+		Metadata:  map[string]interface{}{},
+		Tags:      map[string]string{},
+	}
+	for key, value := range first.Tags {
+		rolled.Tags[key] = value
+	}
+	for _, label := range groupBy {
+		if label != "collector" && labels[label] != "" {
+			rolled.Metadata[label] = labels[label]
+		}
+	}
+
+	names := map[string]bool{}
+	lines := make([]string, 0, len(members))
+	for _, member := range members {
+		severity := member.EffectiveSeverity()
+		if !collectors.SeverityAtLeast(rolled.Severity, severity) {
+			rolled.Severity = severity
+		}
+		if member.Timestamp.Before(rolled.Timestamp) {
+			rolled.Timestamp = member.Timestamp
+		}
+		rolled.Metrics[severity]++
+		if member.Transition == collectors.TransitionFiring {
+			rolled.Transition = collectors.TransitionFiring
+		}
+		names[member.Collector] = true
+		for key, value := range rolled.Tags {
+			if member.Tags[key] != value {
+				delete(rolled.Tags, key)
+			}
+		}
+		lines = append(lines, fmt.Sprintf("[%s] %s", severity, member.Message))
+	}
+
+	what := first.Collector
+	if len(names) > 1 {
+		collectorNames := make([]string, 0, len(names))
+		for name := range names {
+			collectorNames = append(collectorNames, name)
+		}
+		sort.Strings(collectorNames)
+		what = strings.Join(collectorNames, ", ")
+		rolled.Collector = "group"
+	}
+
+	rolled.Message = fmt.Sprintf("%d %s targets unhealthy: %s", len(members), what, strings.Join(lines, "; "))
+	return rolled
+}
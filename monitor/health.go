@@ -0,0 +1,58 @@
+// monitor/health.go
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// livenessGrace is how long past its scheduled time a collector task may be
+// before it counts as wedged, e.g. stuck in a collector or the pipeline. It
+// leaves room for the 30 second collection timeout.
+const livenessGrace = 2 * time.Minute
+
+// Liveness returns why the service is wedged: collector tasks that exited, or
+// whose scheduled run is overdue by more than two minutes. None means live.
+func (s *MonitorService) Liveness() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var problems []string
+	now := time.Now()
+	for name, task := range s.collectorTasks {
+		select {
+		case <-task.done:
+			problems = append(problems, fmt.Sprintf("collector %s: task exited", name))
+			continue
+		default:
+		}
+		if next := task.next(); !next.IsZero() && now.Sub(next) > livenessGrace {
+			problems = append(problems, fmt.Sprintf("collector %s: run overdue by %s", name, now.Sub(next).Round(time.Second)))
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// Readiness returns why the service is not ready: the problems Liveness
+// reports, plus running collectors whose last failures runs all failed.
+// Paused collectors are left out. None means ready.
+func (s *MonitorService) Readiness(failures int) []string {
+	problems := s.Liveness()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var failing []string
+	for name := range s.collectorTasks {
+		if s.paused[name] {
+			continue
+		}
+		if stats := s.selfStats.collection(name); stats.ConsecutiveErrors >= failures {
+			failing = append(failing, fmt.Sprintf("collector %s: last %d runs failed: %s", name, stats.ConsecutiveErrors, stats.LastError))
+		}
+	}
+	sort.Strings(failing)
+	return append(problems, failing...)
+}
@@ -0,0 +1,96 @@
+// monitor/heartbeat.go
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"go.uber.org/zap"
+)
+
+// runHeartbeat sends an "all OK" notification to the heartbeat notifiers
+// every interval while no target is alerting and no collector is failing, so
+// their recipients notice when the notifications stop arriving. It runs
+// until the service stops.
+func (s *MonitorService) runHeartbeat(interval time.Duration, names []string) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			result, healthy := s.heartbeatResult(now)
+			if !healthy {
+				s.logger.Debug("Heartbeat skipped; the monitor is not all OK")
+				continue
+			}
+			s.sendHeartbeat(s.ctx, names, result)
+		}
+	}
+}
+
+// heartbeatResult builds the "all OK" notification, reporting false while any
+// target is alerting or a collector's latest run failed
+func (s *MonitorService) heartbeatResult(now time.Time) (collectors.Result, bool) {
+	if alerts := s.activeAlerts.list(); len(alerts) > 0 {
+		return collectors.Result{}, false
+	}
+
+	snapshot := s.selfStats.Snapshot()
+	for _, stats := range snapshot.Collections {
+		if stats.ConsecutiveErrors > 0 {
+			return collectors.Result{}, false
+		}
+	}
+
+	s.mu.Lock()
+	running := len(s.collectorTasks)
+	s.mu.Unlock()
+
+	return collectors.Result{
+		IsHealthy: false,
+		Collector: "heartbeat",
+		Severity:  collectors.SeverityInfo,
+		Timestamp: now,
+		Message:   fmt.Sprintf("All OK: %d collector(s) running, no active alerts", running),
+		Metrics: map[string]float64{
+			"collectors": float64(running),
+		},
+		// The heartbeat is informational, not an alert
+		Thresholds: []collectors.Threshold{{
+			Type:     "absolute",
+			Metric:   "collectors",
+			Operator: "greater_than",
+			Value:    -1,
+			Severity: collectors.SeverityInfo,
+		}},
+	}, true
+}
+
+// sendHeartbeat delivers the heartbeat to the named notifiers directly,
+// bypassing routes, digests and quiet hours so its cadence stays regular
+func (s *MonitorService) sendHeartbeat(ctx context.Context, names []string, result collectors.Result) {
+	wanted := toSet(names)
+	for _, notifier := range s.enabledNotifiers {
+		if !wanted[notifier.Name()] {
+			continue
+		}
+
+		notifyCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		attempts, err := s.notifyWithRetry(notifyCtx, notifier, []collectors.Result{result})
+		cancel()
+		s.selfStats.recordNotification(notifier.Name(), err)
+		if err != nil {
+			s.logger.Error("Failed to send heartbeat", zap.String("notifier", notifier.Name()), zap.Int("attempts", attempts), zap.Error(err))
+			continue
+		}
+		s.logger.Info("Heartbeat sent", zap.String("notifier", notifier.Name()))
+	}
+}
@@ -0,0 +1,167 @@
+// monitor/history.go
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/config"
+	"github.com/devvspaces/simple-monit/pipeline"
+	"github.com/devvspaces/simple-monit/query"
+	"github.com/devvspaces/simple-monit/storage"
+
+	"go.uber.org/zap"
+)
+
+// How often active alerts are saved, and old results pruned, while history
+// is enabled
+const (
+	historySaveInterval  = time.Minute
+	historyPruneInterval = time.Hour
+)
+
+// openHistory opens the result history and restores the alerts saved by the
+// previous run, so their recoveries still notify and their repeat
+// notifications stay deduplicated. Alerts of collectors no longer enabled
+// are dropped.
+func (s *MonitorService) openHistory(cfg config.HistoryConfig) error {
+	store, err := storage.Open(s.logger.Named("history"), cfg.Path)
+	if err != nil {
+		s.logger.Error("Failed to open result history", zap.String("path", cfg.Path), zap.Error(err))
+		return err
+	}
+	s.history = store
+
+	saved, err := store.LoadAlerts()
+	if err != nil {
+		s.logger.Error("Failed to load saved alerts", zap.Error(err))
+		return err
+	}
+	var restored []storage.AlertState
+	for _, alert := range saved {
+		if !s.config.Collectors[alert.Result.Collector].Enabled {
+			continue
+		}
+		restored = append(restored, alert)
+		s.alerting.Restore(alert.Result.Key(), alert.Since)
+	}
+	s.activeAlerts.restore(restored)
+	if len(restored) > 0 {
+		s.logger.Info("Restored active alerts", zap.Int("alerts", len(restored)), zap.String("path", cfg.Path))
+	}
+	return nil
+}
+
+// historyStage stores every result in the history. Failing to store them is
+// logged rather than holding up notifications.
+func (s *MonitorService) historyStage(ctx context.Context, batch *pipeline.Batch) error {
+	if s.history == nil {
+		return nil
+	}
+	if err := s.history.Record(batch.Results); err != nil {
+		s.logger.Error("Failed to store results in history", zap.Int("results", len(batch.Results)), zap.Error(err))
+	}
+	s.recordIncidentEvents(incidentEvents(batch.Events))
+	return nil
+}
+
+// runHistory periodically saves the active alerts and prunes results older
+// than the retention
+func (s *MonitorService) runHistory(retention time.Duration) {
+	defer s.wg.Done()
+
+	save := time.NewTicker(historySaveInterval)
+	defer save.Stop()
+	prune := time.NewTicker(historyPruneInterval)
+	defer prune.Stop()
+
+	s.pruneHistory(retention)
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-save.C:
+			s.saveAlerts()
+		case <-prune.C:
+			s.pruneHistory(retention)
+		}
+	}
+}
+
+// pruneHistory deletes results and collector runs older than the retention,
+// and downtimes that ended before the retention or the longest availability
+// window
+func (s *MonitorService) pruneHistory(retention time.Duration) {
+	pruned, err := s.history.Prune(time.Now().Add(-retention))
+	if err != nil {
+		s.logger.Error("Failed to prune result history", zap.Error(err))
+		return
+	}
+	if pruned > 0 {
+		s.logger.Info("Pruned result history", zap.Int("results", pruned), zap.Duration("retention", retention))
+	}
+	if err := s.history.PruneRuns(time.Now().Add(-retention)); err != nil {
+		s.logger.Error("Failed to prune collector runs", zap.Error(err))
+	}
+
+	keep := retention
+	for _, window := range s.AvailabilityWindows() {
+		keep = max(keep, window.Duration)
+	}
+	if err := s.history.PruneDowntimes(time.Now().Add(-keep)); err != nil {
+		s.logger.Error("Failed to prune downtimes", zap.Error(err))
+	}
+	if err := s.history.PruneIncidentEvents(time.Now().Add(-keep)); err != nil {
+		s.logger.Error("Failed to prune incident events", zap.Error(err))
+	}
+}
+
+// saveAlerts saves the active alerts for the next run
+func (s *MonitorService) saveAlerts() {
+	if err := s.history.SaveAlerts(s.activeAlerts.snapshot()); err != nil {
+		s.logger.Error("Failed to save active alerts", zap.Error(err))
+	}
+}
+
+// closeHistory saves the active alerts and closes the history
+func (s *MonitorService) closeHistory() {
+	s.saveAlerts()
+	if err := s.history.Close(); err != nil {
+		s.logger.Error("Error closing result history", zap.Error(err))
+	}
+}
+
+// History returns a collector's stored results from since up to until,
+// oldest first, or those of every collector when collector is empty. A zero
+// until means up to now.
+func (s *MonitorService) History(collector string, since, until time.Time) ([]collectors.Result, error) {
+	if s.history == nil {
+		return nil, fmt.Errorf("result history is not enabled")
+	}
+	return s.history.Query(collector, since, until)
+}
+
+// Query evaluates a query over the stored results from since up to until.
+// A zero until means up to now.
+func (s *MonitorService) Query(q *query.Query, since, until time.Time) ([]query.Series, error) {
+	if s.history == nil {
+		return nil, fmt.Errorf("result history is not enabled")
+	}
+	evaluator := query.NewEvaluator(q)
+	if err := s.history.Each(q.Collector(), since, until, evaluator.Add); err != nil {
+		s.logger.Error("Failed to query result history", zap.Error(err))
+		return nil, err
+	}
+	return evaluator.Result(), nil
+}
+
+// HistoryPage returns up to limit stored results like History, continuing
+// after cursor, and the cursor of the next page, empty after the last one
+func (s *MonitorService) HistoryPage(collector string, since, until time.Time, cursor string, limit int) ([]collectors.Result, string, error) {
+	if s.history == nil {
+		return nil, "", fmt.Errorf("result history is not enabled")
+	}
+	return s.history.QueryPage(collector, since, until, cursor, limit)
+}
@@ -0,0 +1,112 @@
+// monitor/incidents.go
+package monitor
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/devvspaces/simple-monit/alerting"
+	"github.com/devvspaces/simple-monit/storage"
+
+	"go.uber.org/zap"
+)
+
+// incidentColumns are the CSV columns of an incident timeline
+var incidentColumns = []string{"time", "event", "key", "collector", "fingerprint", "severity", "opened", "duration_seconds", "acknowledged_by", "message"}
+
+// incidentEvents returns the incident events of lifecycle transitions: an
+// alert opens when its target starts firing and resolves when it recovers
+func incidentEvents(transitions []alerting.Event) []storage.IncidentEvent {
+	var events []storage.IncidentEvent
+	for _, transition := range transitions {
+		event := storage.IncidentEvent{
+			Time:        transition.At,
+			Key:         transition.Key,
+			Collector:   transition.Result.Collector,
+			Fingerprint: transition.Result.Fingerprint(),
+			Message:     transition.Result.Message,
+		}
+		switch transition.To {
+		case alerting.StateFiring:
+			event.Event = storage.IncidentOpened
+			event.Severity = transition.Result.EffectiveSeverity()
+			event.Opened = transition.At
+		case alerting.StateResolved:
+			event.Event = storage.IncidentResolved
+			event.Opened = transition.Since
+			event.DurationSeconds = transition.At.Sub(transition.Since).Seconds()
+		default:
+			continue
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// acknowledgedEvent returns the incident event of a mute rule silencing an
+// active alert
+func acknowledgedEvent(alert ActiveAlert, at time.Time) storage.IncidentEvent {
+	return storage.IncidentEvent{
+		Time:            at,
+		Event:           storage.IncidentAcknowledged,
+		Key:             alert.Result.Key(),
+		Collector:       alert.Result.Collector,
+		Fingerprint:     alert.Result.Fingerprint(),
+		Severity:        alert.Result.EffectiveSeverity(),
+		Message:         alert.Result.Message,
+		Opened:          alert.Since,
+		DurationSeconds: at.Sub(alert.Since).Seconds(),
+		AcknowledgedBy:  alert.AcknowledgedBy,
+	}
+}
+
+// recordIncidentEvents stores incident events in the history, if enabled.
+// Failing to store them is logged but does not stop the batch.
+func (s *MonitorService) recordIncidentEvents(events []storage.IncidentEvent) {
+	if s.history == nil || len(events) == 0 {
+		return
+	}
+	if err := s.history.RecordIncidentEvents(events); err != nil {
+		s.logger.Error("Failed to store incident events", zap.Int("events", len(events)), zap.Error(err))
+	}
+}
+
+// Incidents returns the timeline of alerts opened, acknowledged and resolved
+// from since up to until, oldest first, optionally of one collector. A zero
+// until means up to now.
+func (s *MonitorService) Incidents(collector string, since, until time.Time) ([]storage.IncidentEvent, error) {
+	if s.history == nil {
+		return nil, fmt.Errorf("result history is not enabled")
+	}
+	return s.history.IncidentEvents(collector, since, until)
+}
+
+// WriteIncidentsCSV writes an incident timeline as CSV with a header row
+func WriteIncidentsCSV(w io.Writer, events []storage.IncidentEvent) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(incidentColumns); err != nil {
+		return err
+	}
+	for _, event := range events {
+		err := cw.Write([]string{
+			event.Time.Format(time.RFC3339),
+			event.Event,
+			event.Key,
+			event.Collector,
+			event.Fingerprint,
+			event.Severity,
+			event.Opened.Format(time.RFC3339),
+			strconv.FormatFloat(event.DurationSeconds, 'f', 0, 64),
+			event.AcknowledgedBy,
+			event.Message,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
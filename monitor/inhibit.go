@@ -0,0 +1,130 @@
+// monitor/inhibit.go
+package monitor
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/config"
+)
+
+// labelMatcher matches a set of labels by exact value and by regex
+type labelMatcher struct {
+	equal map[string]string
+	regex map[string]*regexp.Regexp
+}
+
+// newLabelMatcher compiles exact and regex label matchers
+func newLabelMatcher(equal, patterns map[string]string) (labelMatcher, error) {
+	m := labelMatcher{
+		equal: equal,
+		regex: make(map[string]*regexp.Regexp, len(patterns)),
+	}
+	for label, pattern := range patterns {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return m, fmt.Errorf("invalid regex for label '%s': %w", label, err)
+		}
+		m.regex[label] = re
+	}
+	return m, nil
+}
+
+// matches reports whether every matcher holds for the labels
+func (m labelMatcher) matches(labels map[string]string) bool {
+	for label, want := range m.equal {
+		if labels[label] != want {
+			return false
+		}
+	}
+	for label, re := range m.regex {
+		if !re.MatchString(labels[label]) {
+			return false
+		}
+	}
+	return true
+}
+
+// inhibitRule is a compiled inhibition rule
+type inhibitRule struct {
+	name   string
+	source labelMatcher
+	target labelMatcher
+	equal  []string
+}
+
+// compileInhibitRules compiles the configured inhibition rules
+func compileInhibitRules(rules []config.InhibitRule) ([]inhibitRule, error) {
+	compiled := make([]inhibitRule, 0, len(rules))
+	for i, rule := range rules {
+		source, err := newLabelMatcher(rule.SourceMatch, rule.SourceMatchRE)
+		if err != nil {
+			return nil, fmt.Errorf("inhibit_rules[%d]: %w", i, err)
+		}
+		target, err := newLabelMatcher(rule.TargetMatch, rule.TargetMatchRE)
+		if err != nil {
+			return nil, fmt.Errorf("inhibit_rules[%d]: %w", i, err)
+		}
+
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("inhibit_rules[%d]", i)
+		}
+		compiled = append(compiled, inhibitRule{
+			name:   name,
+			source: source,
+			target: target,
+			equal:  rule.Equal,
+		})
+	}
+	return compiled, nil
+}
+
+// inhibitedBy returns the rule and active alert that inhibit the result, if any.
+// Like Alertmanager, an alert never inhibits itself, and a label missing on both
+// sides counts as equal.
+func (s *MonitorService) inhibitedBy(result collectors.Result) (string, *collectors.Result) {
+	if len(s.inhibitRules) == 0 {
+		return "", nil
+	}
+
+	targetKey := result.Key()
+	targetLabels := result.Labels()
+
+	var sources []ActiveAlert
+	for _, rule := range s.inhibitRules {
+		if !rule.target.matches(targetLabels) {
+			continue
+		}
+
+		if sources == nil {
+			sources = s.activeAlerts.list()
+		}
+
+		for _, source := range sources {
+			if source.InhibitedBy != "" || source.Result.Key() == targetKey {
+				continue
+			}
+
+			sourceLabels := source.Result.Labels()
+			if !rule.source.matches(sourceLabels) {
+				continue
+			}
+
+			equal := true
+			for _, label := range rule.equal {
+				if sourceLabels[label] != targetLabels[label] {
+					equal = false
+					break
+				}
+			}
+			if equal {
+				src := source.Result
+				return rule.name, &src
+			}
+		}
+	}
+
+	return "", nil
+}
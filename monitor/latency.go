@@ -0,0 +1,120 @@
+// monitor/latency.go
+package monitor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+)
+
+// maxDeliveryHistory bounds the number of recent deliveries kept in memory
+const maxDeliveryHistory = 200
+
+// DeliveryRecord is the timeline of a single alert delivered by a notifier
+type DeliveryRecord struct {
+	Collector   string        `json:"collector"`
+	Key         string        `json:"key"`
+	Notifier    string        `json:"notifier"`
+	CollectedAt time.Time     `json:"collected_at"`
+	EvaluatedAt time.Time     `json:"evaluated_at"`
+	DeliveredAt time.Time     `json:"delivered_at"`
+	Latency     time.Duration `json:"latency"`
+}
+
+// LatencyStats summarizes detection-to-delivery latency for one notifier
+type LatencyStats struct {
+	Deliveries  int           `json:"deliveries"`
+	Last        time.Duration `json:"last"`
+	Max         time.Duration `json:"max"`
+	Average     time.Duration `json:"average"`
+	OverBound   int           `json:"over_bound"`
+	LastUpdated time.Time     `json:"last_updated"`
+}
+
+// latencyTracker records alert delivery timelines and per-notifier latency stats
+type latencyTracker struct {
+	bound   time.Duration
+	stats   map[string]*LatencyStats
+	total   map[string]time.Duration
+	history []DeliveryRecord
+	mu      sync.Mutex
+}
+
+// newLatencyTracker creates a tracker that flags deliveries slower than bound.
+// A zero bound disables the check.
+func newLatencyTracker(bound time.Duration) *latencyTracker {
+	return &latencyTracker{
+		bound: bound,
+		stats: make(map[string]*LatencyStats),
+		total: make(map[string]time.Duration),
+	}
+}
+
+// record stores the delivery of results by a notifier and returns the records
+// whose latency exceeded the bound
+func (t *latencyTracker) record(notifier string, results []collectors.Result, evaluatedAt, deliveredAt time.Time) []DeliveryRecord {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats, ok := t.stats[notifier]
+	if !ok {
+		stats = &LatencyStats{}
+		t.stats[notifier] = stats
+	}
+
+	var slow []DeliveryRecord
+	for _, result := range results {
+		rec := DeliveryRecord{
+			Collector:   result.Collector,
+			Key:         result.Key(),
+			Notifier:    notifier,
+			CollectedAt: result.Timestamp,
+			EvaluatedAt: evaluatedAt,
+			DeliveredAt: deliveredAt,
+			Latency:     deliveredAt.Sub(result.Timestamp),
+		}
+
+		stats.Deliveries++
+		stats.Last = rec.Latency
+		if rec.Latency > stats.Max {
+			stats.Max = rec.Latency
+		}
+		t.total[notifier] += rec.Latency
+		stats.Average = t.total[notifier] / time.Duration(stats.Deliveries)
+		stats.LastUpdated = deliveredAt
+
+		if t.bound > 0 && rec.Latency > t.bound {
+			stats.OverBound++
+			slow = append(slow, rec)
+		}
+
+		t.history = append(t.history, rec)
+	}
+
+	if len(t.history) > maxDeliveryHistory {
+		t.history = append([]DeliveryRecord(nil), t.history[len(t.history)-maxDeliveryHistory:]...)
+	}
+
+	return slow
+}
+
+// snapshot returns a copy of the per-notifier latency stats
+func (t *latencyTracker) snapshot() map[string]LatencyStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := make(map[string]LatencyStats, len(t.stats))
+	for name, stats := range t.stats {
+		out[name] = *stats
+	}
+	return out
+}
+
+// recent returns a copy of the most recent delivery records, oldest first
+func (t *latencyTracker) recent() []DeliveryRecord {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]DeliveryRecord(nil), t.history...)
+}
@@ -0,0 +1,56 @@
+// monitor/latest.go
+package monitor
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/devvspaces/simple-monit/collectors"
+)
+
+// latestResults keeps the most recent result of every target, healthy or not
+type latestResults struct {
+	results map[string]collectors.Result
+	mu      sync.Mutex
+}
+
+// newLatestResults creates an empty latest result tracker
+func newLatestResults() *latestResults {
+	return &latestResults{results: make(map[string]collectors.Result)}
+}
+
+// update records results as the latest of their targets
+func (l *latestResults) update(results []collectors.Result) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, result := range results {
+		l.results[result.Key()] = result
+	}
+}
+
+// forget drops the results of a collector's targets, e.g. when it is removed
+func (l *latestResults) forget(collector string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key := range l.results {
+		if key == collector || strings.HasPrefix(key, collector+"|") {
+			delete(l.results, key)
+		}
+	}
+}
+
+// list returns the latest results, ordered by target key
+func (l *latestResults) list() []collectors.Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	out := make([]collectors.Result, 0, len(l.results))
+	for _, result := range l.results {
+		out = append(out, result)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key() < out[j].Key() })
+	return out
+}
@@ -5,50 +5,199 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"sync"
 	"time"
 
-	"server-monitor/collectors"
-	"server-monitor/collectors/disk"
-	"server-monitor/collectors/memory"
-	"server-monitor/config"
-	"server-monitor/notifiers"
-	"server-monitor/notifiers/email"
+	"github.com/devvspaces/simple-monit/alerting"
+	"github.com/devvspaces/simple-monit/anomaly"
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/collectors/selfmonitor"
+	"github.com/devvspaces/simple-monit/config"
+	"github.com/devvspaces/simple-monit/evaluator"
+	"github.com/devvspaces/simple-monit/maintenance"
+	"github.com/devvspaces/simple-monit/mutes"
+	"github.com/devvspaces/simple-monit/notifiers"
+	"github.com/devvspaces/simple-monit/outputs"
+	"github.com/devvspaces/simple-monit/pipeline"
+	"github.com/devvspaces/simple-monit/secrets"
+	"github.com/devvspaces/simple-monit/storage"
+	"github.com/devvspaces/simple-monit/templates"
 
 	"go.uber.org/zap"
 )
 
+// collectorTask is a running collector loop
+type collectorTask struct {
+	cancel  context.CancelFunc
+	done    chan struct{}
+	trigger chan struct{} // Runs the collector now, off schedule
+	nextRun time.Time
+	mu      sync.Mutex
+}
+
+// setNextRun records when the task's timer fires next
+func (t *collectorTask) setNextRun(at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextRun = at
+}
+
+// next returns when the task's timer fires next
+func (t *collectorTask) next() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.nextRun
+}
+
 // MonitorService is the main service that orchestrates collectors and notifiers
 type MonitorService struct {
 	config            *config.Config
 	collectorRegistry *collectors.Registry
 	notifierRegistry  *notifiers.Registry
-	collectorTasks    map[string]context.CancelFunc
+	outputRegistry    *outputs.Registry
+	enabledNotifiers  []notifiers.Notifier
+	minSeverity       map[string]string
+	groupBy           map[string][]string
+	enabledOutputs    []outputs.Output
+	muter             *mutes.Muter
+	maintenance       *maintenance.Scheduler
+	inhibitRules      []inhibitRule
+	routes            []route
+	latency           *latencyTracker
+	dispatcher        *dispatcher
+	retry             retryPolicy
+	queue             *notificationQueue
+	history           *storage.Store
+	runLog            runLog
+	digest            *digest
+	quietHours        *quietHours
+	collectorTasks    map[string]*collectorTask
+	panicked          map[string]bool
+	paused            map[string]bool
+	activeAlerts      *activeAlerts
+	latest            *latestResults
+	stream            *broadcaster
+	alerting          *alerting.Machine
+	evaluator         *evaluator.Evaluator
+	anomalies         *anomaly.Detector
+	selfStats         *selfStats
+	secrets           *secrets.Resolver
+	secretsMu         sync.Mutex
+	rotation          sync.RWMutex // Held for writing while components are re-initialized with rotated secrets
+	pipeline          *pipeline.Pipeline
+	options           options
 	logger            *zap.Logger
 	wg                sync.WaitGroup
 	ctx               context.Context
 	cancel            context.CancelFunc
 	mu                sync.Mutex
+	reloadMu          sync.Mutex
 }
 
-// NewMonitorService creates a new monitoring service
+// NewMonitorService creates a new monitoring service with the built-in
+// components; New takes further options
 func NewMonitorService(logger *zap.Logger, cfg *config.Config) *MonitorService {
+	return New(cfg, WithLogger(logger))
+}
+
+// newMonitorService creates the service New configures
+func newMonitorService(logger *zap.Logger, cfg *config.Config) *MonitorService {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return &MonitorService{
+	s := &MonitorService{
 		config:            cfg,
 		collectorRegistry: collectors.NewRegistry(logger.Named("collectorRegistry")),
 		notifierRegistry:  notifiers.NewRegistry(logger.Named("notifierRegistry")),
-		collectorTasks:    make(map[string]context.CancelFunc),
+		outputRegistry:    outputs.NewRegistry(logger.Named("outputRegistry")),
+		collectorTasks:    make(map[string]*collectorTask),
+		panicked:          make(map[string]bool),
+		paused:            make(map[string]bool),
+		activeAlerts:      newActiveAlerts(),
+		latest:            newLatestResults(),
+		stream:            newBroadcaster(),
+		alerting:          alerting.NewMachine(),
+		evaluator:         evaluator.New(),
+		anomalies:         anomaly.NewDetector(),
+		selfStats:         newSelfStats(),
+		latency:           newLatencyTracker(time.Duration(cfg.Monitor.MaxAlertLatencySeconds) * time.Second),
+		retry:             newRetryPolicy(cfg.Notifications.Retry),
 		ctx:               ctx,
 		cancel:            cancel,
 		logger:            logger,
 	}
+	s.pipeline = s.newPipeline()
+	return s
+}
+
+// Hostname returns the name the service shows for its host: monitor.hostname,
+// or the system's
+func (s *MonitorService) Hostname() string {
+	if name := s.currentConfig().Monitor.Hostname; name != "" {
+		return name
+	}
+	return templates.Hostname()
 }
 
 // Start initializes and starts the monitoring service
 func (s *MonitorService) Start() error {
+	if err := s.Prepare(); err != nil {
+		return err
+	}
+
+	// Open the persistent notification queue
+	if queueCfg := s.config.Notifications.Queue; queueCfg.Enabled {
+		queue, err := openNotificationQueue(s.logger.Named("queue"), queueCfg)
+		if err != nil {
+			s.logger.Error("Failed to open notification queue", zap.Error(err))
+			return err
+		}
+		s.queue = queue
+
+		s.wg.Add(1)
+		go s.runQueue(time.Duration(queueCfg.RetryIntervalSeconds) * time.Second)
+	}
+
+	// Open the result history, restoring the alerts of the previous run
+	if historyCfg := s.config.History; historyCfg.Enabled {
+		if err := s.openHistory(historyCfg); err != nil {
+			return err
+		}
+
+		s.wg.Add(1)
+		go s.runHistory(time.Duration(historyCfg.RetentionHours) * time.Hour)
+	}
+
+	// Watch for the end of maintenance windows
+	if len(s.config.Maintenance.Windows) > 0 {
+		s.wg.Add(1)
+		go s.runMaintenanceSummaries()
+	}
+
+	// Send periodic "all OK" notifications
+	if s.config.Monitor.HeartbeatSeconds > 0 {
+		s.wg.Add(1)
+		go s.runHeartbeat(time.Duration(s.config.Monitor.HeartbeatSeconds)*time.Second, s.config.Monitor.HeartbeatNotifiers)
+	}
+
+	// Rotate the secrets the components were initialized with
+	if s.secrets != nil {
+		s.wg.Add(1)
+		go s.runSecretRefresh(time.Duration(s.config.Secrets.RefreshSeconds) * time.Second)
+	}
+
+	// Start collector tasks
+	if err := s.startCollectorTasks(); err != nil {
+		s.logger.Error("Failed to start collector tasks", zap.Error(err))
+		return err
+	}
+
+	s.logger.Info("Monitoring service started successfully")
+	return nil
+}
+
+// Prepare registers and initializes collectors, notifiers and outputs without
+// starting any collector tasks
+func (s *MonitorService) Prepare() error {
 	s.logger.Info("Initializing monitoring service...")
 
 	// Register collectors
@@ -63,6 +212,12 @@ func (s *MonitorService) Start() error {
 		return err
 	}
 
+	// Register outputs
+	if err := s.registerOutputs(); err != nil {
+		s.logger.Error("Failed to register outputs", zap.Error(err))
+		return err
+	}
+
 	// Initialize enabled collectors
 	if err := s.initializeCollectors(); err != nil {
 		s.logger.Error("Failed to initialize collectors", zap.Error(err))
@@ -75,16 +230,93 @@ func (s *MonitorService) Start() error {
 		return err
 	}
 
-	// Start collector tasks
-	if err := s.startCollectorTasks(); err != nil {
-		s.logger.Error("Failed to start collector tasks", zap.Error(err))
+	// Initialize enabled outputs
+	if err := s.initializeOutputs(); err != nil {
+		s.logger.Error("Failed to initialize outputs", zap.Error(err))
 		return err
 	}
 
-	s.logger.Info("Monitoring service started successfully")
+	// Load mute rules
+	muter, err := mutes.NewMuter(s.logger.Named("mutes"), s.config.Mutes)
+	if err != nil {
+		s.logger.Error("Failed to load mute rules", zap.Error(err))
+		return err
+	}
+	s.muter = muter
+
+	// Compile maintenance windows
+	scheduler, err := maintenance.NewScheduler(s.logger.Named("maintenance"), s.config.Maintenance)
+	if err != nil {
+		s.logger.Error("Failed to load maintenance windows", zap.Error(err))
+		return err
+	}
+	s.maintenance = scheduler
+
+	// Compile inhibition rules
+	inhibitRules, err := compileInhibitRules(s.config.InhibitRules)
+	if err != nil {
+		s.logger.Error("Failed to compile inhibition rules", zap.Error(err))
+		return err
+	}
+	s.inhibitRules = inhibitRules
+
+	// Compile notification routes
+	routes, err := compileRoutes(s.config.Routes)
+	if err != nil {
+		s.logger.Error("Failed to compile notification routes", zap.Error(err))
+		return err
+	}
+	s.routes = routes
+
+	// Start the notification dispatcher, batching the normal lane into
+	// digests and holding it back during quiet hours when enabled
+	normal := s.sendNotifications
+	if digestCfg := s.config.Notifications.Digest; digestCfg.Enabled {
+		s.digest = newDigest(s.logger.Named("digest"), time.Duration(digestCfg.WindowSeconds)*time.Second, s.sendNotifications)
+		normal = s.digest.add
+	}
+	if quietCfg := s.config.Notifications.QuietHours; quietCfg.Enabled {
+		s.quietHours = newQuietHours(s.logger.Named("quiet_hours"), quietCfg, normal, s.sendNotifications)
+		normal = s.quietHours.deliver
+	}
+	fast := func(ctx context.Context, results []collectors.Result, evaluatedAt time.Time) error {
+		// A critical result replaces any older state of the same target
+		// still waiting to be sent
+		if s.digest != nil {
+			s.digest.supersede(results)
+		}
+		if s.quietHours != nil {
+			s.quietHours.held.supersede(results)
+		}
+		return s.sendNotifications(ctx, results, evaluatedAt)
+	}
+	s.dispatcher = newDispatcher(s.logger.Named("dispatcher"), s.config.Notifications.Workers, fast, normal)
+
 	return nil
 }
 
+// Inject runs externally produced results through the same processing path as
+// collected ones: outputs, mute rules and notifications
+func (s *MonitorService) Inject(ctx context.Context, results []collectors.Result) error {
+	return s.processResults(ctx, results, 0)
+}
+
+// RunCollector runs a registered collector once through the full pipeline:
+// collection, outputs, mute and inhibition rules, and notifications
+func (s *MonitorService) RunCollector(ctx context.Context, name string) error {
+	collector, exists := s.collectorRegistry.Get(name)
+	if !exists {
+		return fmt.Errorf("collector %s is not registered", name)
+	}
+	return s.runCollector(ctx, collector)
+}
+
+// HasCollector reports whether a collector with the given name is registered
+func (s *MonitorService) HasCollector(name string) bool {
+	_, exists := s.collectorRegistry.Get(name)
+	return exists
+}
+
 // Stop gracefully stops the monitoring service
 func (s *MonitorService) Stop() {
 	s.logger.Info("Stopping monitoring service...")
@@ -95,6 +327,34 @@ func (s *MonitorService) Stop() {
 	// Wait for all tasks to complete
 	s.wg.Wait()
 
+	// Deliver notifications still queued on the normal lane
+	if s.dispatcher != nil {
+		s.dispatcher.close()
+	}
+
+	// Send the pending digests
+	if s.quietHours != nil {
+		s.quietHours.held.close()
+	}
+	if s.digest != nil {
+		s.digest.close()
+	}
+
+	// Close the notification queue; anything left is delivered after a restart
+	if s.queue != nil {
+		if err := s.queue.close(); err != nil {
+			s.logger.Error("Error closing notification queue", zap.Error(err))
+		}
+	}
+
+	// Save the active alerts for the next run
+	if s.history != nil {
+		s.closeHistory()
+	}
+	if err := s.runLog.close(); err != nil {
+		s.logger.Error("Error closing run audit file", zap.Error(err))
+	}
+
 	// Clean up collectors
 	for _, c := range s.collectorRegistry.GetAll() {
 		if err := c.Cleanup(); err != nil {
@@ -103,26 +363,46 @@ func (s *MonitorService) Stop() {
 	}
 
 	// Clean up notifiers
-	for _, n := range s.notifierRegistry.GetAll() {
+	for _, n := range s.enabledNotifiers {
 		if err := n.Close(); err != nil {
 			s.logger.Error("Error closing notifier", zap.String("notifier", n.Name()), zap.Error(err))
 		}
 	}
 
+	// Clean up outputs
+	for _, o := range s.enabledOutputs {
+		if err := o.Close(); err != nil {
+			s.logger.Error("Error closing output", zap.String("output", o.Name()), zap.Error(err))
+		}
+	}
+
+	// Stop renewing secrets providers' credentials
+	s.closeSecrets()
+
 	s.logger.Info("Monitoring service stopped")
 }
 
-// registerCollectors registers all available collectors
+// registerCollectors registers all collectors compiled into the binary and
+// those passed with WithCollector
 func (s *MonitorService) registerCollectors() error {
-	// Register disk space collector
-	if err := s.collectorRegistry.Register(disk.NewDiskCollector(s.logger.Named("diskCollector"))); err != nil {
-		s.logger.Error("Failed to register disk collector", zap.Error(err))
-		return err
+	var all []collectors.Collector
+	if s.options.builtins {
+		for _, factory := range collectorFactories {
+			all = append(all, factory.create(s.logger.Named(factory.loggerName)))
+		}
+		// The self-monitoring collector reads the service's own stats
+		all = append(all, selfmonitor.NewSelfMonitorCollector(s.logger.Named("selfMonitorCollector"), s.selfStats))
 	}
+	all = append(all, s.options.collectors...)
 
-	// Register memory collector
-	if err := s.collectorRegistry.Register(memory.NewMemoryCollector(s.logger.Named("memoryCollector"))); err != nil {
-		s.logger.Error("Failed to register memory collector", zap.Error(err))
+	for _, collector := range all {
+		if err := s.collectorRegistry.Register(collector); err != nil {
+			s.logger.Error("Failed to register collector", zap.String("collector", collector.Name()), zap.Error(err))
+			return err
+		}
+	}
+
+	if err := s.registerFactoryCollectors(s.config); err != nil {
 		return err
 	}
 
@@ -130,17 +410,93 @@ func (s *MonitorService) registerCollectors() error {
 	return nil
 }
 
-// registerNotifiers registers all available notifiers
+// registerFactoryCollectors creates, with the factories registered by type
+// (see collectors.RegisterFactory), every collector in cfg that is not
+// registered yet. A collector without a type uses the factory of its name.
+func (s *MonitorService) registerFactoryCollectors(cfg *config.Config) error {
+	for name, collectorCfg := range cfg.Collectors {
+		if _, exists := s.collectorRegistry.Get(name); exists {
+			continue
+		}
+		typeName := collectorCfg.Type
+		if typeName == "" {
+			typeName = name
+		}
+		factory, exists := collectors.LookupFactory(typeName)
+		if !exists {
+			if collectorCfg.Type != "" {
+				s.logger.Error("Collector type is not compiled into this binary", zap.String("collector", name), zap.String("type", typeName))
+			}
+			continue
+		}
+
+		collector := factory(s.logger.Named(typeName+"Collector"), name)
+		if err := s.collectorRegistry.Register(collector); err != nil {
+			s.logger.Error("Failed to register collector", zap.String("collector", name), zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
+// registerNotifiers registers all notifiers compiled into the binary and
+// those passed with WithNotifier
 func (s *MonitorService) registerNotifiers() error {
-	// Register email notifier
-	if err := s.notifierRegistry.Register(email.NewEmailNotifier(s.logger.Named("emailNotifier"))); err != nil {
-		s.logger.Error("Failed to register email notifier", zap.Error(err))
-		return err
+	var all []notifiers.Notifier
+	if s.options.builtins {
+		for _, factory := range notifierFactories {
+			all = append(all, factory.create(s.logger.Named(factory.loggerName)))
+		}
+	}
+	for _, custom := range s.options.notifiers {
+		all = append(all, custom.notifier)
 	}
 
-	// Register other notifiers here...
+	for _, notifier := range all {
+		if err := s.notifierRegistry.Register(notifier); err != nil {
+			s.logger.Error("Failed to register notifier", zap.String("notifier", notifier.Name()), zap.Error(err))
+			return err
+		}
+	}
 
-	log.Printf("Registered notifiers: %v", s.notifierRegistry.NotifierNames())
+	// Plugin notifiers register under their configured names
+	for name := range s.config.Notifications.Plugins {
+		if newPluginNotifier == nil {
+			s.logger.Error("Plugin notifiers are not compiled into this binary", zap.String("notifier", name))
+			continue
+		}
+		notifier := newPluginNotifier(s.logger.Named("pluginNotifier"), name)
+		if err := s.notifierRegistry.Register(notifier); err != nil {
+			s.logger.Error("Failed to register notifier", zap.String("notifier", name), zap.Error(err))
+			return err
+		}
+	}
+
+	s.logger.Info("Registered notifiers", zap.Strings("notifiers", s.notifierRegistry.NotifierNames()))
+	return nil
+}
+
+// registerOutputs registers all result outputs compiled into the binary and
+// those passed with WithOutput
+func (s *MonitorService) registerOutputs() error {
+	var all []outputs.Output
+	if s.options.builtins {
+		for _, factory := range outputFactories {
+			all = append(all, factory.create(s.logger.Named(factory.loggerName)))
+		}
+	}
+	for _, custom := range s.options.outputs {
+		all = append(all, custom.output)
+	}
+
+	for _, output := range all {
+		if err := s.outputRegistry.Register(output); err != nil {
+			s.logger.Error("Failed to register output", zap.String("output", output.Name()), zap.Error(err))
+			return err
+		}
+	}
+
+	s.logger.Info("Registered outputs", zap.Strings("outputs", s.outputRegistry.OutputNames()))
 	return nil
 }
 
@@ -158,17 +514,17 @@ func (s *MonitorService) initializeCollectors() error {
 			continue
 		}
 
-		settings := collectorCfg.Settings
-		if settings == nil {
-			settings = make(map[string]interface{})
+		settings, err := s.collectorSettings(s.config, name)
+		if err != nil {
+			s.logger.Error("Invalid collector settings", zap.String("collector", name), zap.Error(err))
+			return err
 		}
-
 		if err := collector.Init(settings); err != nil {
 			s.logger.Error("Failed to initialize collector", zap.String("collector", name), zap.Error(err))
 			return err
 		}
 
-		log.Printf("Collector %s initialized", name)
+		s.logger.Info("Collector initialized", zap.String("collector", name))
 	}
 
 	return nil
@@ -176,31 +532,90 @@ func (s *MonitorService) initializeCollectors() error {
 
 // initializeNotifiers initializes enabled notifiers
 func (s *MonitorService) initializeNotifiers() error {
-	// Initialize email notifier if enabled
-	if s.config.Notifications.Email.Enabled {
-		notifier, exists := s.notifierRegistry.Get("email")
+	s.minSeverity = s.config.Notifications.MinSeverities()
+	s.groupBy = s.config.Notifications.GroupBys()
+
+	for _, nc := range notifierConfigs(s.config.Notifications) {
+		if !nc.enabled {
+			continue
+		}
+
+		notifier, exists := s.notifierRegistry.Get(nc.name)
 		if !exists {
-			return errors.New("email notifier is enabled but not registered")
+			return fmt.Errorf("%s notifier is enabled but not registered", nc.name)
+		}
+
+		settings, err := s.resolveSecrets(nc.settings)
+		if err != nil {
+			s.logger.Error("Failed to resolve notifier secrets", zap.String("notifier", nc.name), zap.Error(err))
+			return err
 		}
+		if err := notifier.Init(settings); err != nil {
+			s.logger.Error("Failed to initialize notifier", zap.String("notifier", nc.name), zap.Error(err))
+			return err
+		}
+
+		s.enabledNotifiers = append(s.enabledNotifiers, notifier)
+		s.logger.Info("Notifier initialized", zap.String("notifier", nc.name))
+	}
+
+	// Notifiers passed with WithNotifier are always enabled
+	for _, custom := range s.options.notifiers {
+		if err := custom.notifier.Init(settingsOrEmpty(custom.settings)); err != nil {
+			s.logger.Error("Failed to initialize notifier", zap.String("notifier", custom.notifier.Name()), zap.Error(err))
+			return err
+		}
+		s.enabledNotifiers = append(s.enabledNotifiers, custom.notifier)
+		s.logger.Info("Notifier initialized", zap.String("notifier", custom.notifier.Name()))
+	}
 
-		emailCfg := s.config.Notifications.Email
+	return nil
+}
+
+// settingsOrEmpty returns settings, or an empty map when there are none
+func settingsOrEmpty(settings map[string]interface{}) map[string]interface{} {
+	if settings == nil {
+		return make(map[string]interface{})
+	}
+	return settings
+}
 
-		// Convert email config to map
-		config := map[string]interface{}{
-			"from":        emailCfg.From,
-			"to":          emailCfg.To,
-			"smtp_server": emailCfg.SMTPServer,
-			"smtp_port":   emailCfg.SMTPPort,
-			"username":    emailCfg.Username,
-			"password":    emailCfg.Password,
+// initializeOutputs initializes all enabled result outputs
+func (s *MonitorService) initializeOutputs() error {
+	for name, outputCfg := range s.config.Outputs {
+		if !outputCfg.Enabled {
+			s.logger.Debug("Output is disabled, skipping", zap.String("output", name))
+			continue
 		}
 
-		if err := notifier.Init(config); err != nil {
-			s.logger.Error("Failed to initialize email notifier", zap.Error(err))
+		output, exists := s.outputRegistry.Get(name)
+		if !exists {
+			s.logger.Error("Output is enabled but not registered", zap.String("output", name))
+			continue
+		}
+
+		settings, err := s.outputSettings(s.config, name)
+		if err != nil {
+			s.logger.Error("Invalid output settings", zap.String("output", name), zap.Error(err))
+			return err
+		}
+		if err := output.Init(settings); err != nil {
+			s.logger.Error("Failed to initialize output", zap.String("output", name), zap.Error(err))
 			return err
 		}
 
-		s.logger.Info("Email notifier initialized")
+		s.enabledOutputs = append(s.enabledOutputs, output)
+		s.logger.Info("Output initialized", zap.String("output", name))
+	}
+
+	// Outputs passed with WithOutput are always enabled
+	for _, custom := range s.options.outputs {
+		if err := custom.output.Init(settingsOrEmpty(custom.settings)); err != nil {
+			s.logger.Error("Failed to initialize output", zap.String("output", custom.output.Name()), zap.Error(err))
+			return err
+		}
+		s.enabledOutputs = append(s.enabledOutputs, custom.output)
+		s.logger.Info("Output initialized", zap.String("output", custom.output.Name()))
 	}
 
 	return nil
@@ -218,54 +633,127 @@ func (s *MonitorService) startCollectorTasks() error {
 			continue
 		}
 
-		interval := s.config.GetCollectorInterval(name)
-		if interval <= 0 {
+		if collectorCfg.Schedule == "" && s.config.GetCollectorInterval(name) <= 0 {
 			err := fmt.Errorf("invalid interval for collector %s", name)
 			s.logger.Error("Invalid collector interval", zap.String("collector", name), zap.Error(err))
 			return err
 		}
 
 		// Start collector task
-		if err := s.startCollectorTask(collector, interval); err != nil {
+		if err := s.startCollectorTask(collector, s.config); err != nil {
 			err := fmt.Errorf("failed to start collector task %s: %w", name, err)
 			s.logger.Error("Failed to start collector task", zap.String("collector", name), zap.Error(err))
 			return err
 		}
 
-		s.logger.Info("Collector task started", zap.String("collector", name), zap.Duration("interval", interval))
+		s.logger.Info("Collector task started", zap.String("collector", name), zap.String("schedule", describeSchedule(s.config, name)))
 	}
 
 	return nil
 }
 
-// startCollectorTask starts a collector task with the specified interval
-func (s *MonitorService) startCollectorTask(collector collectors.Collector, interval time.Duration) error {
+// startCollectorTask starts a collector task on the collector's schedule in cfg
+func (s *MonitorService) startCollectorTask(collector collectors.Collector, cfg *config.Config) error {
+	sched, runNow, err := collectorSchedule(cfg, collector.Name())
+	if err != nil {
+		s.logger.Error("Invalid collector schedule", zap.String("collector", collector.Name()), zap.Error(err))
+		return err
+	}
+
+	splay := cfg.GetSplay(collector.Name())
+
 	taskCtx, cancel := context.WithCancel(s.ctx)
+	task := &collectorTask{cancel: cancel, done: make(chan struct{}), trigger: make(chan struct{}, 1)}
 
 	s.mu.Lock()
-	s.collectorTasks[collector.Name()] = cancel
+	s.collectorTasks[collector.Name()] = task
 	s.mu.Unlock()
 
 	s.wg.Add(1)
 	go func() {
 		defer s.wg.Done()
-		ticker := time.NewTicker(interval)
-		defer ticker.Stop()
+		defer close(task.done)
 
-		// Run immediately on start
-		if err := s.runCollector(taskCtx, collector); err != nil {
-			log.Printf("Error collecting metrics for %s: %v", collector.Name(), err)
+		// Interval collectors run on start, and keep their cadence from
+		// there; cron collectors wait for their first scheduled time
+		next := time.Now()
+		if runNow {
+			next = next.Add(splayOffset(splay, sched))
+		} else {
+			next = sched.Next(next)
+		}
+		wait := untilRun(next, splay, runNow)
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		task.setNextRun(time.Now().Add(wait))
+		reset := func(wait time.Duration) {
+			task.setNextRun(time.Now().Add(wait))
+			timer.Reset(wait)
 		}
+		panics := 0
 
 		for {
 			select {
 			case <-taskCtx.Done():
-				log.Printf("Collector task %s stopping", collector.Name())
+				s.logger.Debug("Collector task stopping", zap.String("collector", collector.Name()))
 				return
-			case <-ticker.C:
-				if err := s.runCollector(taskCtx, collector); err != nil {
-					log.Printf("Error collecting metrics for %s: %v", collector.Name(), err)
+			case <-task.trigger:
+				// Run on request, paused or not, without moving the schedule
+				if panics > 0 {
+					s.reinitCollector(collector)
+				}
+				err := s.runCollector(taskCtx, collector)
+				if isPanic(err) {
+					panics++
+					continue
+				}
+				panics = 0
+				if err != nil {
+					s.logger.Error("Error collecting metrics", zap.String("collector", collector.Name()), zap.Error(err))
 				}
+			case <-timer.C:
+				// Skip scheduled runs while paused, keeping the cadence
+				if s.isPaused(collector.Name()) {
+					next = sched.Next(next)
+					if now := time.Now(); next.Before(now) {
+						next = sched.Next(now)
+					}
+					reset(untilRun(next, splay, runNow))
+					continue
+				}
+
+				// Restart a collector that panicked with a fresh Init
+				if panics > 0 {
+					s.reinitCollector(collector)
+				}
+
+				err := s.runCollector(taskCtx, collector)
+				if isPanic(err) {
+					panics++
+					backoff := panicBackoff(panics)
+					s.logger.Warn("Restarting collector task after panic",
+						zap.String("collector", collector.Name()),
+						zap.Int("consecutive_panics", panics),
+						zap.Duration("backoff", backoff))
+					reset(backoff)
+					continue
+				}
+				if err != nil {
+					s.logger.Error("Error collecting metrics", zap.String("collector", collector.Name()), zap.Error(err))
+				}
+
+				// Resume the schedule from a restart
+				if panics > 0 {
+					panics = 0
+					next = time.Now()
+				}
+
+				// Keep the cadence, skipping runs missed while collecting
+				next = sched.Next(next)
+				if now := time.Now(); next.Before(now) {
+					next = sched.Next(now)
+				}
+				reset(untilRun(next, splay, runNow))
 			}
 		}
 	}()
@@ -273,62 +761,204 @@ func (s *MonitorService) startCollectorTask(collector collectors.Collector, inte
 	return nil
 }
 
+// markPanicked records whether a collector's last run panicked and returns
+// whether it had panicked before
+func (s *MonitorService) markPanicked(name string, panicked bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	was := s.panicked[name]
+	if panicked {
+		s.panicked[name] = true
+	} else {
+		delete(s.panicked, name)
+	}
+	return was
+}
+
 // runCollector executes a collector and processes its results
 func (s *MonitorService) runCollector(ctx context.Context, collector collectors.Collector) error {
 	// Create a timeout context for the collection operation
 	collectionCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	// Collect metrics
-	results, err := collector.Collect(collectionCtx)
+	// Collect metrics, reporting a panic as an alert about the collector itself
+	started := time.Now()
+	results, err := safeCollect(collectionCtx, collector)
+	s.selfStats.recordCollection(collector.Name(), time.Since(started), err)
+	s.recordRun(collector.Name(), started, len(results), err)
+	var p *panicError
+	if errors.As(err, &p) {
+		s.logger.Error("Collector panicked",
+			zap.String("collector", collector.Name()),
+			zap.Any("panic", p.value),
+			zap.ByteString("stack", p.stack))
+		s.markPanicked(collector.Name(), true)
+		if perr := s.processResults(ctx, []collectors.Result{panicResult(collector.Name(), err, time.Now())}, 0); perr != nil {
+			s.logger.Error("Failed to report collector panic", zap.String("collector", collector.Name()), zap.Error(perr))
+		}
+		return err
+	}
 	if err != nil {
 		s.logger.Error("Failed to collect metrics", zap.String("collector", collector.Name()), zap.Error(err))
 		return err
 	}
 
-	// Process results
-	return s.processResults(ctx, results)
+	// Resolve the panic alert once the collector works again
+	if s.markPanicked(collector.Name(), false) {
+		results = append(results, panicResult(collector.Name(), nil, time.Now()))
+	}
+
+	// Guard against collectors emitting an unbounded number of targets
+	maxSeries := s.currentConfig().GetCollectorMaxSeries(collector.Name())
+	results, dropped := limitCardinality(results, maxSeries)
+	if dropped > 0 {
+		s.logger.Warn("Collector exceeded its series limit; extra targets dropped",
+			zap.String("collector", collector.Name()),
+			zap.Int("max_series", maxSeries),
+			zap.Int("dropped", dropped))
+	}
+
+	// Process results, holding new alerts back until their condition has
+	// lasted for_seconds
+	return s.processResults(ctx, results, s.currentConfig().GetFor(collector.Name()))
 }
 
-// processResults processes collector results and sends notifications if needed
-func (s *MonitorService) processResults(ctx context.Context, results []collectors.Result) error {
-	// Check if there are any unhealthy results
-	var unhealthyResults []collectors.Result
-	for _, result := range results {
-		if !result.IsHealthy {
-			unhealthyResults = append(unhealthyResults, result)
-			log.Printf("Unhealthy result from %s: %s", result.Collector, result.Message)
+// processResults publishes collector results to the result pipeline
+func (s *MonitorService) processResults(ctx context.Context, results []collectors.Result, hold time.Duration) error {
+	return s.pipeline.Publish(ctx, &pipeline.Batch{
+		Results:     results,
+		Hold:        hold,
+		EvaluatedAt: time.Now(),
+	})
+}
+
+// runMaintenanceSummaries notifies what each maintenance window suppressed
+// once it ends, checking every 30 seconds until the service stops
+func (s *MonitorService) runMaintenanceSummaries() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			var results []collectors.Result
+			for _, summary := range s.maintenance.Ended(now) {
+				s.logger.Info("Maintenance window ended",
+					zap.String("window", summary.Window),
+					zap.Int("suppressed", summary.Suppressed),
+					zap.Int("targets", summary.Targets))
+				results = append(results, summary.Result(now))
+			}
+			if len(results) == 0 {
+				continue
+			}
+			if err := s.dispatcher.dispatch(s.ctx, results, now); err != nil {
+				s.logger.Error("Failed to send maintenance summary", zap.Error(err))
+			}
 		}
 	}
+}
+
+// writeOutputs delivers results to all enabled outputs and to the notifiers
+// that observe every result
+func (s *MonitorService) writeOutputs(ctx context.Context, results []collectors.Result) {
+	if len(results) == 0 {
+		return
+	}
+
+	// Create a timeout context for output operations
+	outputCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
 
-	// If no unhealthy results, nothing to do
-	if len(unhealthyResults) == 0 {
-		return nil
+	s.rotation.RLock()
+	defer s.rotation.RUnlock()
+
+	for _, output := range s.enabledOutputs {
+		if err := output.Write(outputCtx, results); err != nil {
+			s.logger.Error("Output write failed", zap.String("output", output.Name()), zap.Error(err))
+		}
 	}
 
-	// Send notifications
-	return s.sendNotifications(ctx, unhealthyResults)
+	for _, notifier := range s.enabledNotifiers {
+		observer, ok := notifier.(notifiers.ResultObserver)
+		if !ok {
+			continue
+		}
+		if err := observer.Observe(outputCtx, results); err != nil {
+			s.logger.Error("Notifier failed to observe results", zap.String("notifier", notifier.Name()), zap.Error(err))
+		}
+	}
 }
 
-// sendNotifications sends notifications for unhealthy results
-func (s *MonitorService) sendNotifications(ctx context.Context, results []collectors.Result) error {
-	// Create a timeout context for notification operations
-	notifyCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+// notifyTransitions delivers alert lifecycle transitions to the notifiers
+// that implement notifiers.TransitionNotifier, for auditing; the notifiable
+// transitions reach Notify through sendNotifications
+func (s *MonitorService) notifyTransitions(ctx context.Context, events []alerting.Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	transitionCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
+	s.rotation.RLock()
+	defer s.rotation.RUnlock()
+
+	for _, notifier := range s.enabledNotifiers {
+		transitions, ok := notifier.(notifiers.TransitionNotifier)
+		if !ok {
+			continue
+		}
+		if err := transitions.NotifyTransitions(transitionCtx, events); err != nil {
+			s.logger.Error("Notifier failed to handle alert transitions", zap.String("notifier", notifier.Name()), zap.Error(err))
+		}
+	}
+}
+
+// sendNotifications sends notifications for unhealthy results and the
+// recoveries of notified alerts. Failed deliveries are retried per notifier
+// according to the retry policy, then handed to the persistent queue when it
+// is enabled.
+func (s *MonitorService) sendNotifications(ctx context.Context, results []collectors.Result, evaluatedAt time.Time) error {
 	// Send to all enabled notifiers
 	var errs []error
+	for _, notifier := range s.enabledNotifiers {
+		results := s.filterSeverity(notifier.Name(), s.filterRoutes(notifier.Name(), results))
+		if len(results) == 0 {
+			continue
+		}
+		results = groupResults(results, s.groupBy[notifier.Name()])
+
+		// Queue behind earlier undelivered notifications to keep their order
+		if s.queue != nil && s.queue.hasPending(notifier.Name()) {
+			if err := s.enqueue(notifier.Name(), results, evaluatedAt); err != nil {
+				errs = append(errs, fmt.Errorf("%s notification could not be queued: %w", notifier.Name(), err))
+			}
+			continue
+		}
 
-	// Check if email notifications are enabled
-	if s.config.Notifications.Email.Enabled {
-		notifier, exists := s.notifierRegistry.Get("email")
-		if exists {
-			if err := notifier.Notify(notifyCtx, results); err != nil {
-				errs = append(errs, fmt.Errorf("email notification failed: %w", err))
-			} else {
-				log.Printf("Email notification sent for %d issues", len(results))
+		// Each notifier gets its own timeout for all of its attempts
+		notifyCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		attempts, err := s.notifyWithRetry(notifyCtx, notifier, results)
+		cancel()
+		s.selfStats.recordNotification(notifier.Name(), err)
+		if err != nil {
+			if s.queue != nil && s.enqueue(notifier.Name(), results, evaluatedAt) == nil {
+				s.logger.Warn("Notification failed, will retry from the queue",
+					zap.String("notifier", notifier.Name()), zap.Int("attempts", attempts), zap.Error(err))
+				continue
 			}
+			errs = append(errs, fmt.Errorf("%s notification failed after %d attempt(s): %w", notifier.Name(), attempts, err))
+			continue
 		}
+
+		s.logger.Info("Notification sent", zap.String("notifier", notifier.Name()), zap.Int("issues", len(results)), zap.Int("attempts", attempts))
+		s.recordDelivery(notifier.Name(), results, evaluatedAt)
 	}
 
 	// Check if there were any errors
@@ -343,3 +973,62 @@ func (s *MonitorService) sendNotifications(ctx context.Context, results []collec
 
 	return nil
 }
+
+// filterSeverity keeps the results that meet a notifier's minimum severity
+func (s *MonitorService) filterSeverity(notifier string, results []collectors.Result) []collectors.Result {
+	minSeverity := s.minSeverity[notifier]
+	if minSeverity == "" {
+		return results
+	}
+
+	filtered := make([]collectors.Result, 0, len(results))
+	for _, result := range results {
+		if collectors.SeverityAtLeast(result.EffectiveSeverity(), minSeverity) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// recordDelivery tracks detection-to-delivery latency and warns when it exceeds the configured bound
+func (s *MonitorService) recordDelivery(notifier string, results []collectors.Result, evaluatedAt time.Time) {
+	for _, rec := range s.latency.record(notifier, results, evaluatedAt, time.Now()) {
+		s.logger.Warn("Alert delivery exceeded latency bound",
+			zap.String("collector", rec.Collector),
+			zap.String("notifier", rec.Notifier),
+			zap.Time("collected_at", rec.CollectedAt),
+			zap.Time("evaluated_at", rec.EvaluatedAt),
+			zap.Time("delivered_at", rec.DeliveredAt),
+			zap.Duration("latency", rec.Latency),
+			zap.Int("max_alert_latency_seconds", int(s.currentConfig().Monitor.MaxAlertLatencySeconds)))
+	}
+}
+
+// ActiveAlerts returns the targets currently alerting, including which rule and
+// alert inhibit them, if any
+func (s *MonitorService) ActiveAlerts() []ActiveAlert {
+	return s.activeAlerts.list()
+}
+
+// Subscribe returns a stream of every result and alert transition, as they
+// pass the outputs stage, and a function that ends the subscription. Events
+// are dropped for a subscriber that falls behind.
+func (s *MonitorService) Subscribe() (<-chan StreamEvent, func()) {
+	return s.stream.subscribe()
+}
+
+// LatestResults returns the most recent result of every target, healthy or
+// not, ordered by target
+func (s *MonitorService) LatestResults() []collectors.Result {
+	return s.latest.list()
+}
+
+// AlertLatencyStats returns detection-to-delivery latency stats per notifier
+func (s *MonitorService) AlertLatencyStats() map[string]LatencyStats {
+	return s.latency.snapshot()
+}
+
+// RecentDeliveries returns the timelines of the most recently delivered alerts
+func (s *MonitorService) RecentDeliveries() []DeliveryRecord {
+	return s.latency.recent()
+}
@@ -0,0 +1,234 @@
+// monitor/notifier_config.go
+package monitor
+
+import (
+	"sort"
+
+	"github.com/devvspaces/simple-monit/config"
+)
+
+// notifierConfig is the settings map handed to a notifier's Init
+type notifierConfig struct {
+	name     string
+	enabled  bool
+	settings map[string]interface{}
+}
+
+// notifierConfigs converts the typed notification configuration into the
+// settings maps the notifiers are initialized with
+func notifierConfigs(cfg config.NotificationsConfig) []notifierConfig {
+	emailCfg := cfg.Email
+	ntfyCfg := cfg.Ntfy
+	chaosCfg := cfg.Chaos
+	mqttCfg := cfg.MQTT
+	snsCfg := cfg.SNS
+	rocketChatCfg := cfg.RocketChat
+	signalCfg := cfg.Signal
+	fileCfg := cfg.File
+	alertmanagerCfg := cfg.Alertmanager
+	splunkOnCallCfg := cfg.SplunkOnCall
+	webexCfg := cfg.Webex
+	jiraCfg := cfg.Jira
+	emailAPICfg := cfg.EmailAPI
+	heartbeatCfg := cfg.Heartbeat
+
+	configs := []notifierConfig{
+		{
+			name:    "email",
+			enabled: emailCfg.Enabled,
+			settings: map[string]interface{}{
+				"from":        emailCfg.From,
+				"to":          emailCfg.To,
+				"smtp_server": emailCfg.SMTPServer,
+				"smtp_port":   emailCfg.SMTPPort,
+				"username":    emailCfg.Username,
+				"password":    emailCfg.Password,
+
+				"tls_mode":             emailCfg.TLSMode,
+				"tls_server_name":      emailCfg.TLSServerName,
+				"ca_file":              emailCfg.CAFile,
+				"insecure_skip_verify": emailCfg.InsecureSkipVerify,
+
+				"html_template":   emailCfg.HTMLTemplate,
+				"plain_text_only": emailCfg.PlainTextOnly,
+			},
+		},
+		{
+			name:    "ntfy",
+			enabled: ntfyCfg.Enabled,
+			settings: map[string]interface{}{
+				"server":    ntfyCfg.Server,
+				"topic":     ntfyCfg.Topic,
+				"token":     ntfyCfg.Token,
+				"username":  ntfyCfg.Username,
+				"password":  ntfyCfg.Password,
+				"priority":  ntfyCfg.Priority,
+				"tags":      ntfyCfg.Tags,
+				"click_url": ntfyCfg.ClickURL,
+			},
+		},
+		{
+			name:    "chaos",
+			enabled: chaosCfg.Enabled,
+			settings: map[string]interface{}{
+				"fail_rate":      chaosCfg.FailRate,
+				"delay_rate":     chaosCfg.DelayRate,
+				"max_delay_ms":   int(chaosCfg.MaxDelayMs),
+				"duplicate_rate": chaosCfg.DuplicateRate,
+				"seed":           chaosCfg.Seed,
+				"url":            chaosCfg.URL,
+			},
+		},
+		{
+			name:    "mqtt",
+			enabled: mqttCfg.Enabled,
+			settings: map[string]interface{}{
+				"broker":               mqttCfg.Broker,
+				"client_id":            mqttCfg.ClientID,
+				"username":             mqttCfg.Username,
+				"password":             mqttCfg.Password,
+				"topic":                mqttCfg.Topic,
+				"qos":                  mqttCfg.QoS,
+				"retained":             mqttCfg.Retained,
+				"timeout_seconds":      int(mqttCfg.TimeoutSeconds),
+				"insecure_skip_verify": mqttCfg.InsecureSkipVerify,
+			},
+		},
+		{
+			name:    "sns",
+			enabled: snsCfg.Enabled,
+			settings: map[string]interface{}{
+				"topic_arn": snsCfg.TopicARN,
+				"region":    snsCfg.Region,
+				"profile":   snsCfg.Profile,
+			},
+		},
+		{
+			name:    "rocketchat",
+			enabled: rocketChatCfg.Enabled,
+			settings: map[string]interface{}{
+				"webhook_url": rocketChatCfg.WebhookURL,
+				"channel":     rocketChatCfg.Channel,
+				"alias":       rocketChatCfg.Alias,
+				"emoji":       rocketChatCfg.Emoji,
+				"avatar":      rocketChatCfg.Avatar,
+			},
+		},
+		{
+			name:    "signal",
+			enabled: signalCfg.Enabled,
+			settings: map[string]interface{}{
+				"api":        signalCfg.API,
+				"url":        signalCfg.URL,
+				"number":     signalCfg.Number,
+				"recipients": signalCfg.Recipients,
+				"groups":     signalCfg.Groups,
+			},
+		},
+		{
+			name:    "file",
+			enabled: fileCfg.Enabled,
+			settings: map[string]interface{}{
+				"path":            fileCfg.Path,
+				"include_results": fileCfg.IncludeResults,
+				"max_size_mb":     fileCfg.MaxSizeMB,
+				"max_backups":     fileCfg.MaxBackups,
+			},
+		},
+		{
+			name:    "alertmanager",
+			enabled: alertmanagerCfg.Enabled,
+			settings: map[string]interface{}{
+				"urls":                    alertmanagerCfg.URLs,
+				"username":                alertmanagerCfg.Username,
+				"password":                alertmanagerCfg.Password,
+				"bearer_token":            alertmanagerCfg.BearerToken,
+				"labels":                  alertmanagerCfg.Labels,
+				"generator_url":           alertmanagerCfg.GeneratorURL,
+				"resend_interval_seconds": int(alertmanagerCfg.ResendInterval),
+			},
+		},
+		{
+			name:    "splunk_oncall",
+			enabled: splunkOnCallCfg.Enabled,
+			settings: map[string]interface{}{
+				"api_key":     splunkOnCallCfg.APIKey,
+				"routing_key": splunkOnCallCfg.RoutingKey,
+				"url":         splunkOnCallCfg.URL,
+			},
+		},
+		{
+			name:    "webex",
+			enabled: webexCfg.Enabled,
+			settings: map[string]interface{}{
+				"bot_token": webexCfg.BotToken,
+				"room_id":   webexCfg.RoomID,
+				"rooms":     webexCfg.Rooms,
+				"api_url":   webexCfg.APIURL,
+			},
+		},
+		{
+			name:    "jira",
+			enabled: jiraCfg.Enabled,
+			settings: map[string]interface{}{
+				"url":                   jiraCfg.URL,
+				"username":              jiraCfg.Username,
+				"api_token":             jiraCfg.APIToken,
+				"personal_access_token": jiraCfg.PersonalAccessToken,
+				"project":               jiraCfg.Project,
+				"issue_type":            jiraCfg.IssueType,
+				"labels":                jiraCfg.Labels,
+				"resolve_transition":    jiraCfg.ResolveTransition,
+			},
+		},
+		{
+			name:    "email_api",
+			enabled: emailAPICfg.Enabled,
+			settings: map[string]interface{}{
+				"provider":        emailAPICfg.Provider,
+				"api_key":         emailAPICfg.APIKey,
+				"domain":          emailAPICfg.Domain,
+				"region":          emailAPICfg.Region,
+				"from":            emailAPICfg.From,
+				"to":              emailAPICfg.To,
+				"html_template":   emailAPICfg.HTMLTemplate,
+				"plain_text_only": emailAPICfg.PlainTextOnly,
+			},
+		},
+		{
+			name:    "heartbeat",
+			enabled: heartbeatCfg.Enabled,
+			settings: map[string]interface{}{
+				"provider": heartbeatCfg.Provider,
+				"url":      heartbeatCfg.URL,
+				"fail_url": heartbeatCfg.FailURL,
+			},
+		},
+	}
+
+	// Plugin notifiers get their settings as configured, plus the program
+	names := make([]string, 0, len(cfg.Plugins))
+	for name := range cfg.Plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		plugin := cfg.Plugins[name]
+		settings := make(map[string]interface{}, len(plugin.Settings)+1)
+		for key, value := range plugin.Settings {
+			settings[key] = value
+		}
+		settings["command"] = plugin.Command
+		configs = append(configs, notifierConfig{name: name, enabled: plugin.Enabled, settings: settings})
+	}
+
+	// Settings shared by every notifier
+	templates := cfg.Templates()
+	for _, nc := range configs {
+		nc.settings["templates_dir"] = cfg.TemplatesDir
+		if fields, ok := templates[nc.name]; ok {
+			nc.settings["templates"] = fields
+		}
+	}
+	return configs
+}
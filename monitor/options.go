@@ -0,0 +1,90 @@
+// monitor/options.go
+package monitor
+
+import (
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/config"
+	"github.com/devvspaces/simple-monit/notifiers"
+	"github.com/devvspaces/simple-monit/outputs"
+
+	"go.uber.org/zap"
+)
+
+// Option configures a service created with New
+type Option func(*options)
+
+// options are the settings a service is built from
+type options struct {
+	logger     *zap.Logger
+	builtins   bool
+	collectors []collectors.Collector
+	notifiers  []customNotifier
+	outputs    []customOutput
+}
+
+// customNotifier is a notifier registered with WithNotifier
+type customNotifier struct {
+	notifier notifiers.Notifier
+	settings map[string]interface{}
+}
+
+// customOutput is an output registered with WithOutput
+type customOutput struct {
+	output   outputs.Output
+	settings map[string]interface{}
+}
+
+// WithLogger sets the logger of the service and its components. Without it
+// the service logs nothing.
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithoutBuiltins leaves out the collectors, notifiers and outputs compiled
+// into the binary, so that only the components passed as options are used
+func WithoutBuiltins() Option {
+	return func(o *options) {
+		o.builtins = false
+	}
+}
+
+// WithCollector registers a collector. Like a built-in collector, it is
+// initialized and scheduled from its entry under collectors in the
+// configuration, and only runs if that entry is enabled.
+func WithCollector(collector collectors.Collector) Option {
+	return func(o *options) {
+		o.collectors = append(o.collectors, collector)
+	}
+}
+
+// WithNotifier registers a notifier and enables it with the given settings,
+// which are passed to its Init
+func WithNotifier(notifier notifiers.Notifier, settings map[string]interface{}) Option {
+	return func(o *options) {
+		o.notifiers = append(o.notifiers, customNotifier{notifier: notifier, settings: settings})
+	}
+}
+
+// WithOutput registers an output and enables it with the given settings,
+// which are passed to its Init
+func WithOutput(output outputs.Output, settings map[string]interface{}) Option {
+	return func(o *options) {
+		o.outputs = append(o.outputs, customOutput{output: output, settings: settings})
+	}
+}
+
+// New creates a monitoring service from a validated configuration, e.g. one
+// from config.LoadConfig or one built in code and checked with
+// config.Validate. Start runs it and Stop stops it.
+func New(cfg *config.Config, opts ...Option) *MonitorService {
+	o := options{logger: zap.NewNop(), builtins: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	s := newMonitorService(o.logger, cfg)
+	s.options = o
+	return s
+}
@@ -0,0 +1,90 @@
+// monitor/panic.go
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"go.uber.org/zap"
+)
+
+// Backoff before restarting a collector task after consecutive panics
+const (
+	panicInitialBackoff = time.Second
+	panicMaxBackoff     = 5 * time.Minute
+)
+
+// panicError is a panic recovered from a collector
+type panicError struct {
+	value interface{}
+	stack []byte
+}
+
+func (e *panicError) Error() string {
+	return fmt.Sprintf("collector panicked: %v", e.value)
+}
+
+// isPanic reports whether err is a recovered collector panic
+func isPanic(err error) bool {
+	var p *panicError
+	return errors.As(err, &p)
+}
+
+// safeCollect runs a collection, turning a panic into a panicError so a
+// misbehaving collector cannot crash the service
+func safeCollect(ctx context.Context, collector collectors.Collector) (results []collectors.Result, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			results, err = nil, &panicError{value: r, stack: debug.Stack()}
+		}
+	}()
+	return collector.Collect(ctx)
+}
+
+// panicResult is the unhealthy result reported about a collector that
+// panicked, or the healthy one resolving it after the collector recovers
+func panicResult(name string, err error, now time.Time) collectors.Result {
+	result := collectors.Result{
+		IsHealthy: err == nil,
+		Collector: name,
+		Timestamp: now,
+		Message:   fmt.Sprintf("Collector %s is running again", name),
+		Metrics:   map[string]float64{},
+		Metadata:  map[string]interface{}{"failure": "panic"},
+	}
+	if err != nil {
+		result.Severity = collectors.SeverityCritical
+		result.Message = fmt.Sprintf("Collector %s crashed and is being restarted: %v", name, err)
+	}
+	return result
+}
+
+// panicBackoff returns the delay before restarting a collector after its
+// attempt-th consecutive panic
+func panicBackoff(attempt int) time.Duration {
+	backoff := panicInitialBackoff
+	for i := 1; i < attempt && backoff < panicMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > panicMaxBackoff {
+		backoff = panicMaxBackoff
+	}
+	return backoff
+}
+
+// reinitCollector initializes a collector again from the current
+// configuration, discarding state a panic may have left inconsistent
+func (s *MonitorService) reinitCollector(collector collectors.Collector) {
+	settings, err := s.collectorSettings(s.currentConfig(), collector.Name())
+	if err == nil {
+		err = collector.Init(settings)
+	}
+	if err != nil {
+		s.logger.Error("Failed to re-initialize collector after panic", zap.String("collector", collector.Name()), zap.Error(err))
+	}
+}
@@ -0,0 +1,294 @@
+// monitor/queue.go
+package monitor
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/config"
+
+	bolt "go.etcd.io/bbolt"
+	"go.uber.org/zap"
+)
+
+// queueBucket holds queued notifications keyed by a big-endian sequence
+// number, so iteration order is enqueue order
+var queueBucket = []byte("notifications")
+
+// queuedNotification is a notification waiting for one notifier to accept it
+type queuedNotification struct {
+	Notifier    string              `json:"notifier"`
+	Results     []collectors.Result `json:"results"`
+	EvaluatedAt time.Time           `json:"evaluated_at"`
+	QueuedAt    time.Time           `json:"queued_at"`
+}
+
+// notificationQueue is a persistent outbound queue for notifications a
+// notifier failed to deliver. Entries survive restarts and are retried in
+// order until delivered, expired by the TTL or evicted when the queue is full.
+type notificationQueue struct {
+	db      *bolt.DB
+	ttl     time.Duration
+	maxSize int
+	pending map[string]int
+	mu      sync.Mutex
+	logger  *zap.Logger
+}
+
+// openNotificationQueue opens (or creates) the queue database
+func openNotificationQueue(logger *zap.Logger, cfg config.QueueConfig) (*notificationQueue, error) {
+	db, err := bolt.Open(cfg.Path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open notification queue %s: %w", cfg.Path, err)
+	}
+
+	q := &notificationQueue{
+		db:      db,
+		ttl:     time.Duration(cfg.TTLSeconds) * time.Second,
+		maxSize: cfg.MaxSize,
+		pending: make(map[string]int),
+		logger:  logger,
+	}
+
+	// Count what is left over from before a restart
+	err = db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(queueBucket)
+		if err != nil {
+			return err
+		}
+		return bucket.ForEach(func(_, v []byte) error {
+			var n queuedNotification
+			if err := json.Unmarshal(v, &n); err == nil {
+				q.pending[n.Notifier]++
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to load notification queue %s: %w", cfg.Path, err)
+	}
+
+	if total := q.size(); total > 0 {
+		logger.Info("Loaded queued notifications", zap.Int("notifications", total), zap.String("path", cfg.Path))
+	}
+	return q, nil
+}
+
+// push appends a notification, evicting the oldest entries when the queue is full
+func (q *notificationQueue) push(n queuedNotification) error {
+	value, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(queueBucket)
+
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		if err := bucket.Put(sequenceKey(seq), value); err != nil {
+			return err
+		}
+		q.pending[n.Notifier]++
+
+		// Evict from the head until the queue fits
+		c := bucket.Cursor()
+		for k, v := c.First(); k != nil && q.total() > q.maxSize; k, v = c.Next() {
+			var evicted queuedNotification
+			if err := json.Unmarshal(v, &evicted); err == nil {
+				q.release(evicted.Notifier)
+				q.logger.Warn("Notification queue full, dropped oldest notification",
+					zap.String("notifier", evicted.Notifier),
+					zap.Int("results", len(evicted.Results)),
+					zap.Time("queued_at", evicted.QueuedAt))
+			}
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// queuedEntry is a stored notification and its key
+type queuedEntry struct {
+	key          []byte
+	notification queuedNotification
+}
+
+// drain tries to deliver every queued notification in order. Expired entries
+// are dropped. Once a delivery to a notifier fails, its later entries are left
+// for the next drain so per-notifier order is kept.
+func (q *notificationQueue) drain(deliver func(queuedNotification) error) {
+	var entries []queuedEntry
+	q.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(queueBucket).ForEach(func(k, v []byte) error {
+			entry := queuedEntry{key: append([]byte(nil), k...)}
+			if err := json.Unmarshal(v, &entry.notification); err != nil {
+				q.logger.Error("Dropping unreadable queued notification", zap.Error(err))
+			}
+			entries = append(entries, entry)
+			return nil
+		})
+	})
+
+	blocked := make(map[string]bool)
+	for _, entry := range entries {
+		n := entry.notification
+		if blocked[n.Notifier] {
+			continue
+		}
+
+		if n.Notifier != "" && q.ttl > 0 && time.Since(n.QueuedAt) > q.ttl {
+			q.logger.Warn("Queued notification expired",
+				zap.String("notifier", n.Notifier),
+				zap.Int("results", len(n.Results)),
+				zap.Time("queued_at", n.QueuedAt))
+		} else if n.Notifier != "" {
+			if err := deliver(n); err != nil {
+				blocked[n.Notifier] = true
+				continue
+			}
+		}
+
+		q.remove(entry)
+	}
+}
+
+// remove deletes a delivered, expired or unreadable entry
+func (q *notificationQueue) remove(entry queuedEntry) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	err := q.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(queueBucket)
+		if bucket.Get(entry.key) == nil {
+			return nil // already evicted
+		}
+		q.release(entry.notification.Notifier)
+		return bucket.Delete(entry.key)
+	})
+	if err != nil {
+		q.logger.Error("Failed to remove queued notification", zap.Error(err))
+	}
+}
+
+// hasPending reports whether notifications for a notifier are waiting
+func (q *notificationQueue) hasPending(notifier string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.pending[notifier] > 0
+}
+
+// size returns the number of queued notifications
+func (q *notificationQueue) size() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.total()
+}
+
+// total sums the per-notifier counts; the caller holds mu
+func (q *notificationQueue) total() int {
+	total := 0
+	for _, count := range q.pending {
+		total += count
+	}
+	return total
+}
+
+// release decrements a notifier's count; the caller holds mu
+func (q *notificationQueue) release(notifier string) {
+	if q.pending[notifier]--; q.pending[notifier] <= 0 {
+		delete(q.pending, notifier)
+	}
+}
+
+// close closes the queue database
+func (q *notificationQueue) close() error {
+	return q.db.Close()
+}
+
+// sequenceKey encodes a sequence number as a sortable key
+func sequenceKey(seq uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+	return key
+}
+
+// enqueue stores a notification for later delivery to one notifier
+func (s *MonitorService) enqueue(notifier string, results []collectors.Result, evaluatedAt time.Time) error {
+	err := s.queue.push(queuedNotification{
+		Notifier:    notifier,
+		Results:     results,
+		EvaluatedAt: evaluatedAt,
+		QueuedAt:    time.Now(),
+	})
+	if err != nil {
+		s.logger.Error("Failed to queue notification", zap.String("notifier", notifier), zap.Error(err))
+		return err
+	}
+
+	s.logger.Warn("Notification queued for later delivery", zap.String("notifier", notifier), zap.Int("issues", len(results)))
+	return nil
+}
+
+// runQueue retries queued notifications every interval until the service stops
+func (s *MonitorService) runQueue(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.flushQueue()
+		}
+	}
+}
+
+// flushQueue delivers queued notifications to notifiers that accept them again
+func (s *MonitorService) flushQueue() {
+	enabled := make(map[string]bool, len(s.enabledNotifiers))
+	for _, notifier := range s.enabledNotifiers {
+		enabled[notifier.Name()] = true
+	}
+
+	s.queue.drain(func(n queuedNotification) error {
+		notifier, exists := s.notifierRegistry.Get(n.Notifier)
+		if !exists || !enabled[n.Notifier] {
+			s.logger.Warn("Dropping queued notification for a notifier that is no longer enabled", zap.String("notifier", n.Notifier))
+			return nil
+		}
+
+		ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
+		defer cancel()
+		s.rotation.RLock()
+		err := notifier.Notify(ctx, n.Results)
+		s.rotation.RUnlock()
+		if err != nil {
+			s.logger.Debug("Queued notification still undeliverable", zap.String("notifier", n.Notifier), zap.Error(err))
+			return err
+		}
+
+		s.logger.Info("Queued notification delivered",
+			zap.String("notifier", n.Notifier),
+			zap.Int("issues", len(n.Results)),
+			zap.Duration("queued_for", time.Since(n.QueuedAt)))
+		s.recordDelivery(n.Notifier, n.Results, n.EvaluatedAt)
+		return nil
+	})
+}
@@ -0,0 +1,97 @@
+// monitor/quiet.go
+package monitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/config"
+
+	"go.uber.org/zap"
+)
+
+// quietHours holds back normal lane notifications during a daily quiet
+// period and sends them as one digest when it ends. Critical results take the
+// fast lane and are never held.
+type quietHours struct {
+	start    time.Duration // Offset of the start from local midnight
+	end      time.Duration // Offset of the end from local midnight
+	location *time.Location
+	held     *digest
+	next     deliverFunc
+	send     deliverFunc
+}
+
+// newQuietHours creates quiet hours that pass notifications outside the quiet
+// period on to next and deliver the held digest with flush
+func newQuietHours(logger *zap.Logger, cfg config.QuietHoursConfig, next, flush deliverFunc) *quietHours {
+	start, _ := time.Parse("15:04", cfg.Start)
+	end, _ := time.Parse("15:04", cfg.End)
+	location, _ := time.LoadLocation(cfg.Timezone)
+
+	q := &quietHours{
+		start:    time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute,
+		end:      time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute,
+		location: location,
+		next:     next,
+		send:     flush,
+	}
+	q.held = newDigestUntil(logger, q.remaining, flush)
+	return q
+}
+
+// sinceMidnight returns how far into its local day now is
+func (q *quietHours) sinceMidnight(now time.Time) time.Duration {
+	local := now.In(q.location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, q.location)
+	return local.Sub(midnight)
+}
+
+// active reports whether now is within quiet hours. The period may span
+// midnight, e.g. 23:00 to 07:00.
+func (q *quietHours) active(now time.Time) bool {
+	t := q.sinceMidnight(now)
+	if q.start < q.end {
+		return t >= q.start && t < q.end
+	}
+	return t >= q.start || t < q.end
+}
+
+// remaining returns the time until quiet hours end
+func (q *quietHours) remaining(now time.Time) time.Duration {
+	left := q.end - q.sinceMidnight(now)
+	if left <= 0 {
+		left += 24 * time.Hour
+	}
+	return left
+}
+
+// deliver holds results during quiet hours and passes them on otherwise.
+// Critical results queued behind earlier ones on the normal lane are still
+// sent straight away.
+func (q *quietHours) deliver(ctx context.Context, results []collectors.Result, evaluatedAt time.Time) error {
+	if !q.active(time.Now()) {
+		return q.next(ctx, results, evaluatedAt)
+	}
+
+	var critical, held []collectors.Result
+	for _, result := range results {
+		if result.EffectiveSeverity() == collectors.SeverityCritical {
+			critical = append(critical, result)
+		} else {
+			held = append(held, result)
+		}
+	}
+
+	if len(held) > 0 {
+		if err := q.held.add(ctx, held, evaluatedAt); err != nil {
+			return err
+		}
+	}
+	if len(critical) == 0 {
+		return nil
+	}
+	q.held.supersede(critical)
+	return q.send(ctx, critical, evaluatedAt)
+}
@@ -0,0 +1,228 @@
+// monitor/reload.go
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/config"
+	"github.com/devvspaces/simple-monit/pipeline"
+
+	"go.uber.org/zap"
+)
+
+// Policies for checks removed from the configuration while alerting
+const (
+	OnCheckRemovedResolve = "resolve"
+	OnCheckRemovedOrphan  = "orphan"
+)
+
+// Reload applies a new configuration to the running service. Collector changes
+// take effect immediately: removed or disabled collectors are stopped, new ones
+// are started and changed ones are re-initialized. Notifier, output, mute,
+// inhibition rule, maintenance window, route, API, gRPC, debug listener,
+// history and secrets provider changes require a restart and are ignored
+// with a warning.
+func (s *MonitorService) Reload(cfg *config.Config) error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	old := s.currentConfig()
+
+	if !reflect.DeepEqual(old.Notifications, cfg.Notifications) ||
+		!reflect.DeepEqual(old.Outputs, cfg.Outputs) ||
+		!reflect.DeepEqual(old.Mutes, cfg.Mutes) ||
+		!reflect.DeepEqual(old.InhibitRules, cfg.InhibitRules) ||
+		!reflect.DeepEqual(old.Maintenance, cfg.Maintenance) ||
+		!reflect.DeepEqual(old.Routes, cfg.Routes) ||
+		old.API != cfg.API ||
+		old.GRPC != cfg.GRPC ||
+		old.Debug != cfg.Debug ||
+		!reflect.DeepEqual(old.History, cfg.History) ||
+		old.Secrets != cfg.Secrets {
+		s.logger.Warn("Notification, output, mute, inhibition, maintenance, route, API, gRPC, debug, history and secrets changes require a restart; keeping the running settings")
+	}
+	cfg.Notifications = old.Notifications
+	cfg.Outputs = old.Outputs
+	cfg.Mutes = old.Mutes
+	cfg.InhibitRules = old.InhibitRules
+	cfg.Maintenance = old.Maintenance
+	cfg.Routes = old.Routes
+	cfg.API = old.API
+	cfg.GRPC = old.GRPC
+	cfg.Debug = old.Debug
+	cfg.History = old.History
+	cfg.Secrets = old.Secrets
+
+	// Stop collectors that were removed or disabled
+	for name, oldCollector := range old.Collectors {
+		if !oldCollector.Enabled {
+			continue
+		}
+
+		newCollector, exists := cfg.Collectors[name]
+		if exists && newCollector.Enabled {
+			continue
+		}
+
+		reason := "removed"
+		if exists {
+			reason = "disabled"
+		}
+
+		s.stopCollectorTask(name)
+		if collector, ok := s.collectorRegistry.Get(name); ok {
+			if err := collector.Cleanup(); err != nil {
+				s.logger.Error("Error cleaning up collector", zap.String("collector", name), zap.Error(err))
+			}
+		}
+		s.handleRemovedCheck(name, reason, cfg.Monitor.OnCheckRemoved)
+		s.logger.Info("Collector stopped by reload", zap.String("collector", name), zap.String("reason", reason))
+	}
+
+	s.mu.Lock()
+	s.config = cfg
+	s.mu.Unlock()
+
+	// Start new collectors and restart changed ones
+	var errs []error
+	if err := s.registerFactoryCollectors(cfg); err != nil {
+		errs = append(errs, err)
+	}
+	for name, newCollector := range cfg.Collectors {
+		if !newCollector.Enabled {
+			continue
+		}
+
+		oldCollector, existed := old.Collectors[name]
+		if existed && oldCollector.Enabled &&
+			reflect.DeepEqual(oldCollector, newCollector) &&
+			old.GetCollectorInterval(name) == cfg.GetCollectorInterval(name) {
+			continue
+		}
+
+		collector, exists := s.collectorRegistry.Get(name)
+		if !exists {
+			s.logger.Error("Collector is enabled but not registered", zap.String("collector", name))
+			continue
+		}
+
+		s.stopCollectorTask(name)
+
+		settings, err := s.collectorSettings(cfg, name)
+		if err == nil {
+			err = collector.Init(settings)
+		}
+		if err != nil {
+			s.logger.Error("Failed to re-initialize collector", zap.String("collector", name), zap.Error(err))
+			errs = append(errs, fmt.Errorf("collector %s: %w", name, err))
+			continue
+		}
+
+		if err := s.startCollectorTask(collector, cfg); err != nil {
+			errs = append(errs, fmt.Errorf("collector %s: %w", name, err))
+			continue
+		}
+		s.logger.Info("Collector task (re)started by reload", zap.String("collector", name), zap.String("schedule", describeSchedule(cfg, name)))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("reload completed with errors: %v", errs)
+	}
+
+	s.logger.Info("Configuration reloaded")
+	return nil
+}
+
+// stopCollectorTask cancels a running collector task and waits for it to exit
+func (s *MonitorService) stopCollectorTask(name string) {
+	s.mu.Lock()
+	task, running := s.collectorTasks[name]
+	delete(s.collectorTasks, name)
+	s.mu.Unlock()
+
+	if !running {
+		return
+	}
+
+	task.cancel()
+	<-task.done
+}
+
+// handleRemovedCheck applies the configured policy to the active alerts of a
+// collector that is no longer running. Alerts are either resolved with a
+// "check removed" reason, or left in place as orphans; both emit an audit event.
+func (s *MonitorService) handleRemovedCheck(name, reason, policy string) {
+	defer s.alerting.Forget(name)
+	defer s.latest.forget(name)
+
+	alerts := s.activeAlerts.forCollector(name)
+	if len(alerts) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(alerts))
+	for _, alert := range alerts {
+		keys = append(keys, alert.Key())
+	}
+
+	audit := s.logger.Named("audit")
+
+	if policy == OnCheckRemovedOrphan {
+		audit.Warn("Check with active alerts removed; alerts orphaned",
+			zap.String("collector", name),
+			zap.String("reason", reason),
+			zap.String("action", OnCheckRemovedOrphan),
+			zap.Strings("alerts", keys))
+		return
+	}
+
+	// Resolve every active alert so outputs and state close them out
+	now := time.Now()
+	resolved := make([]collectors.Result, 0, len(alerts))
+	for _, alert := range alerts {
+		alert.IsHealthy = true
+		alert.Timestamp = now
+		alert.Message = fmt.Sprintf("Resolved: check %s was %s from the configuration", name, reason)
+		resolved = append(resolved, alert)
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
+	defer cancel()
+
+	batch := &pipeline.Batch{Results: resolved, EvaluatedAt: now}
+	s.lifecycleStage(ctx, batch)
+	s.activeAlerts.update(batch.Results)
+	s.forgetTargets(keys)
+
+	s.writeOutputs(ctx, batch.Results)
+	s.notifyTransitions(ctx, batch.Events)
+	s.stream.publish(batch.Results, batch.Events)
+
+	// Recoveries of notified alerts reach the notifiers like any other
+	s.silenceStage(ctx, batch)
+	if err := s.dispatchStage(ctx, batch); err != nil {
+		s.logger.Error("Failed to notify resolved alerts", zap.String("collector", name), zap.Error(err))
+	}
+
+	audit.Info("Check with active alerts removed; alerts resolved",
+		zap.String("collector", name),
+		zap.String("reason", reason),
+		zap.String("action", OnCheckRemovedResolve),
+		zap.Strings("alerts", keys))
+}
+
+// forgetTargets drops per-target notification state for targets that no longer exist
+func (s *MonitorService) forgetTargets(keys []string) {
+	s.dispatcher.forget(keys)
+}
+
+// currentConfig returns the configuration currently in effect
+func (s *MonitorService) currentConfig() *config.Config {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.config
+}
@@ -0,0 +1,89 @@
+// monitor/retry.go
+package monitor
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/config"
+	"github.com/devvspaces/simple-monit/notifiers"
+
+	"go.uber.org/zap"
+)
+
+// retryPolicy controls how a failed notification is re-sent: up to attempts
+// tries in total, waiting an exponentially growing, jittered delay in between
+type retryPolicy struct {
+	attempts   int
+	initial    time.Duration
+	max        time.Duration
+	multiplier float64
+	jitter     float64
+}
+
+// newRetryPolicy converts the retry configuration into a policy
+func newRetryPolicy(cfg config.RetryConfig) retryPolicy {
+	return retryPolicy{
+		attempts:   cfg.MaxAttempts,
+		initial:    time.Duration(cfg.InitialBackoffMs) * time.Millisecond,
+		max:        time.Duration(cfg.MaxBackoffMs) * time.Millisecond,
+		multiplier: cfg.Multiplier,
+		jitter:     cfg.Jitter,
+	}
+}
+
+// backoff returns the delay before the given retry (1 for the first retry).
+// Jitter spreads the delay by up to ±jitter of its value so notifiers failing
+// together do not retry in lockstep.
+func (p retryPolicy) backoff(retry int) time.Duration {
+	delay := float64(p.initial) * math.Pow(p.multiplier, float64(retry-1))
+	if max := float64(p.max); p.max > 0 && delay > max {
+		delay = max
+	}
+	if p.jitter > 0 {
+		delay += delay * p.jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay)
+}
+
+// notifyWithRetry calls the notifier until it succeeds, the attempts are
+// used up or ctx is done. It returns the number of attempts made and the
+// last error.
+func (s *MonitorService) notifyWithRetry(ctx context.Context, notifier notifiers.Notifier, results []collectors.Result) (int, error) {
+	attempts := s.retry.attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		s.rotation.RLock()
+		err = notifier.Notify(ctx, results)
+		s.rotation.RUnlock()
+		if err == nil {
+			return attempt, nil
+		}
+		if attempt >= attempts || ctx.Err() != nil {
+			return attempt, err
+		}
+
+		delay := s.retry.backoff(attempt)
+		s.logger.Warn("Notification failed, retrying",
+			zap.String("notifier", notifier.Name()),
+			zap.Int("attempt", attempt),
+			zap.Int("max_attempts", attempts),
+			zap.Duration("backoff", delay),
+			zap.Error(err))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, err
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// monitor/routes.go
+package monitor
+
+import (
+	"fmt"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/config"
+)
+
+// route is a compiled routing rule
+type route struct {
+	name       string
+	collectors map[string]bool
+	severities map[string]bool
+	labels     labelMatcher
+	notifiers  map[string]bool
+	cont       bool
+}
+
+// compileRoutes compiles the configured routes
+func compileRoutes(routes []config.Route) ([]route, error) {
+	compiled := make([]route, 0, len(routes))
+	for i, r := range routes {
+		labels, err := newLabelMatcher(r.Match, r.MatchRE)
+		if err != nil {
+			return nil, fmt.Errorf("routes[%d]: %w", i, err)
+		}
+
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("routes[%d]", i)
+		}
+		compiled = append(compiled, route{
+			name:       name,
+			collectors: toSet(r.Collectors),
+			severities: toSet(r.Severities),
+			labels:     labels,
+			notifiers:  toSet(r.Notifiers),
+			cont:       r.Continue,
+		})
+	}
+	return compiled, nil
+}
+
+// toSet turns a list into a set; an empty list gives an empty set
+func toSet(items []string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
+
+// matches reports whether the route covers the result
+func (r route) matches(result collectors.Result) bool {
+	if len(r.collectors) > 0 && !r.collectors[result.Collector] {
+		return false
+	}
+	if len(r.severities) > 0 && !r.severities[result.EffectiveSeverity()] {
+		return false
+	}
+	return r.labels.matches(result.Labels())
+}
+
+// routedTo reports whether the result should be sent to the notifier. Routes
+// are evaluated in order and the first match decides, unless it sets
+// continue, in which case later matches add their notifiers too. A result no
+// route matches goes to every notifier.
+func routedTo(routes []route, result collectors.Result, notifier string) bool {
+	matched := false
+	for _, r := range routes {
+		if !r.matches(result) {
+			continue
+		}
+		if r.notifiers[notifier] {
+			return true
+		}
+		matched = true
+		if !r.cont {
+			break
+		}
+	}
+	return !matched
+}
+
+// filterRoutes keeps the results routed to a notifier
+func (s *MonitorService) filterRoutes(notifier string, results []collectors.Result) []collectors.Result {
+	if len(s.routes) == 0 {
+		return results
+	}
+
+	filtered := make([]collectors.Result, 0, len(results))
+	for _, result := range results {
+		if routedTo(s.routes, result, notifier) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,136 @@
+// monitor/runs.go
+package monitor
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/storage"
+
+	"go.uber.org/zap"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// runColumns are the CSV columns of a run audit
+var runColumns = []string{"started", "collector", "duration_seconds", "results", "error", "panicked"}
+
+// runLog appends collector runs as JSON lines to the run audit file. The
+// file is opened on the first run and reopened when its path changes on a
+// reload.
+type runLog struct {
+	mu   sync.Mutex
+	path string
+	file *lumberjack.Logger
+}
+
+// write appends a run to the file at path
+func (l *runLog) write(path string, run storage.Run) error {
+	line, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil || l.path != path {
+		if l.file != nil {
+			l.file.Close()
+		}
+		l.file = &lumberjack.Logger{Filename: path, MaxSize: 100}
+		l.path = path
+	}
+	_, err = l.file.Write(append(line, '\n'))
+	return err
+}
+
+// close closes the file, if open
+func (l *runLog) close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// recordRun records a collector run in the run audit channels enabled in
+// monitor.run_audit. Failing to record it is logged but does not fail the
+// run.
+func (s *MonitorService) recordRun(name string, started time.Time, results int, err error) {
+	audit := s.currentConfig().Monitor.RunAudit
+	if !audit.Log && audit.Path == "" && !audit.History {
+		return
+	}
+
+	run := storage.Run{
+		Collector:       name,
+		Started:         started,
+		DurationSeconds: time.Since(started).Seconds(),
+		Results:         results,
+	}
+	if err != nil {
+		run.Error = err.Error()
+		var p *panicError
+		run.Panicked = errors.As(err, &p)
+	}
+
+	if audit.Log {
+		s.logger.Named("runs").Info("Collector run",
+			zap.String("collector", run.Collector),
+			zap.Time("started", run.Started),
+			zap.Float64("duration_seconds", run.DurationSeconds),
+			zap.Int("results", run.Results),
+			zap.String("error", run.Error),
+			zap.Bool("panicked", run.Panicked))
+	}
+	if audit.Path != "" {
+		if err := s.runLog.write(audit.Path, run); err != nil {
+			s.logger.Error("Failed to write collector run", zap.String("path", audit.Path), zap.Error(err))
+		}
+	}
+	if audit.History && s.history != nil {
+		if err := s.history.RecordRun(run); err != nil {
+			s.logger.Error("Failed to store collector run", zap.String("collector", name), zap.Error(err))
+		}
+	}
+}
+
+// Runs returns the collector runs started from since up to until, oldest
+// first, optionally of one collector. A zero until means up to now.
+func (s *MonitorService) Runs(collector string, since, until time.Time) ([]storage.Run, error) {
+	if s.history == nil {
+		return nil, fmt.Errorf("result history is not enabled")
+	}
+	return s.history.Runs(collector, since, until)
+}
+
+// WriteRunsCSV writes collector runs as CSV with a header row
+func WriteRunsCSV(w io.Writer, runs []storage.Run) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(runColumns); err != nil {
+		return err
+	}
+	for _, run := range runs {
+		err := cw.Write([]string{
+			run.Started.Format(time.RFC3339),
+			run.Collector,
+			strconv.FormatFloat(run.DurationSeconds, 'f', 3, 64),
+			strconv.Itoa(run.Results),
+			run.Error,
+			strconv.FormatBool(run.Panicked),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,70 @@
+// monitor/schedule.go
+package monitor
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/devvspaces/simple-monit/config"
+
+	"github.com/robfig/cron/v3"
+)
+
+// schedule decides when a collector runs next
+type schedule interface {
+	// Next returns the first run time after t
+	Next(t time.Time) time.Time
+}
+
+// intervalSchedule runs a collector at a fixed interval
+type intervalSchedule time.Duration
+
+// Next returns t plus the interval
+func (i intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(i))
+}
+
+// collectorSchedule returns a collector's schedule: its cron expression when
+// set, or else its interval. runNow reports whether the collector should also
+// run as soon as its task starts, which interval schedules do.
+func collectorSchedule(cfg *config.Config, name string) (sched schedule, runNow bool, err error) {
+	if expr := cfg.Collectors[name].Schedule; expr != "" {
+		cronSchedule, err := cron.ParseStandard(expr)
+		if err != nil {
+			return nil, false, err
+		}
+		return cronSchedule, false, nil
+	}
+	return intervalSchedule(cfg.GetCollectorInterval(name)), true, nil
+}
+
+// describeSchedule returns a schedule for logging
+func describeSchedule(cfg *config.Config, name string) string {
+	if expr := cfg.Collectors[name].Schedule; expr != "" {
+		return expr
+	}
+	return "every " + cfg.GetCollectorInterval(name).String()
+}
+
+// splayOffset returns a random delay of up to splay, spreading out collectors
+// that would otherwise run in lockstep. Interval schedules cap it at their
+// interval, since the offset shifts every later run.
+func splayOffset(splay time.Duration, sched schedule) time.Duration {
+	if interval, ok := sched.(intervalSchedule); ok && time.Duration(interval) < splay {
+		splay = time.Duration(interval)
+	}
+	if splay <= 0 {
+		return 0
+	}
+	return rand.N(splay)
+}
+
+// untilRun returns how long to wait for the run due at next. Cron runs are
+// each delayed by a fresh splay offset; interval runs were offset once at start.
+func untilRun(next time.Time, splay time.Duration, interval bool) time.Duration {
+	wait := time.Until(next)
+	if !interval {
+		wait += splayOffset(splay, nil)
+	}
+	return wait
+}
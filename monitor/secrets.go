@@ -0,0 +1,226 @@
+// monitor/secrets.go
+package monitor
+
+import (
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/config"
+	"github.com/devvspaces/simple-monit/secrets"
+
+	"go.uber.org/zap"
+)
+
+// secretResolver returns the resolver of secret references, connecting to
+// the configured providers on first use
+func (s *MonitorService) secretResolver() (*secrets.Resolver, error) {
+	s.secretsMu.Lock()
+	defer s.secretsMu.Unlock()
+
+	if s.secrets != nil {
+		return s.secrets, nil
+	}
+
+	resolver := secrets.NewResolver(s.logger.Named("secrets"))
+	if vaultCfg := s.config.Secrets.Vault; vaultCfg.Enabled {
+		vault, err := secrets.NewVault(s.logger.Named("vault"), secrets.VaultOptions{
+			Address:            vaultCfg.Address,
+			Token:              vaultCfg.Token,
+			TokenFile:          vaultCfg.TokenFile,
+			Namespace:          vaultCfg.Namespace,
+			KVVersion:          vaultCfg.KVVersion,
+			CAFile:             vaultCfg.CAFile,
+			InsecureSkipVerify: vaultCfg.InsecureSkipVerify,
+			Timeout:            time.Duration(vaultCfg.TimeoutSeconds) * time.Second,
+		})
+		if err != nil {
+			s.logger.Error("Failed to connect to Vault", zap.Error(err))
+			return nil, err
+		}
+		resolver.Register("vault", vault)
+	}
+	s.secrets = resolver
+	return resolver, nil
+}
+
+// resolveSecrets returns settings with their secret references resolved.
+// Settings without references are returned as they are.
+func (s *MonitorService) resolveSecrets(settings map[string]interface{}) (map[string]interface{}, error) {
+	if len(secrets.Refs(settings)) == 0 {
+		return settings, nil
+	}
+	resolver, err := s.secretResolver()
+	if err != nil {
+		return nil, err
+	}
+	return resolver.Resolve(s.ctx, settings)
+}
+
+// collectorSettings returns the settings a collector is initialized with,
+// rejecting any its schema doesn't list
+func (s *MonitorService) collectorSettings(cfg *config.Config, name string) (map[string]interface{}, error) {
+	settings := cfg.CollectorSettings(name)
+	if collector, exists := s.collectorRegistry.Get(name); exists {
+		if err := checkSettings(collector, settings); err != nil {
+			return nil, err
+		}
+	}
+	return s.resolveSecrets(settings)
+}
+
+// outputSettings returns the settings an output is initialized with,
+// rejecting any its schema doesn't list
+func (s *MonitorService) outputSettings(cfg *config.Config, name string) (map[string]interface{}, error) {
+	settings := settingsOrEmpty(cfg.Outputs[name].Settings)
+	if output, exists := s.outputRegistry.Get(name); exists {
+		if err := checkSettings(output, settings); err != nil {
+			return nil, err
+		}
+	}
+	return s.resolveSecrets(settings)
+}
+
+// checkSettings rejects settings a component's schema doesn't list.
+// Components that don't describe their settings accept any.
+func checkSettings(component interface{}, settings map[string]interface{}) error {
+	if describer, ok := component.(collectors.Describer); ok {
+		return collectors.UnknownSettings(describer.Schema(), settings)
+	}
+	return nil
+}
+
+// closeSecrets stops renewing the secrets providers' credentials
+func (s *MonitorService) closeSecrets() {
+	s.secretsMu.Lock()
+	defer s.secretsMu.Unlock()
+
+	if s.secrets == nil {
+		return
+	}
+	if err := s.secrets.Close(); err != nil {
+		s.logger.Error("Error closing secrets providers", zap.Error(err))
+	}
+	s.secrets = nil
+}
+
+// runSecretRefresh periodically reads the referenced secrets again and
+// re-initializes the components whose secrets changed
+func (s *MonitorService) runSecretRefresh(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			resolver, err := s.secretResolver()
+			if err != nil {
+				continue
+			}
+			if changed := resolver.Refresh(s.ctx); len(changed) > 0 {
+				s.rotateSecrets(changed)
+			}
+		}
+	}
+}
+
+// usesChanged reports whether settings refer to one of the changed secrets
+func usesChanged(settings map[string]interface{}, changed map[string]bool) bool {
+	for _, ref := range secrets.Refs(settings) {
+		if changed[ref] {
+			return true
+		}
+	}
+	return false
+}
+
+// rotateSecrets re-initializes the enabled collectors, notifiers and
+// outputs whose settings refer to changed secrets. Notifiers and outputs
+// are closed and initialized again while no delivery is in progress.
+func (s *MonitorService) rotateSecrets(changed map[string]bool) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	cfg := s.currentConfig()
+	for name, collectorCfg := range cfg.Collectors {
+		if !collectorCfg.Enabled || !usesChanged(cfg.CollectorSettings(name), changed) {
+			continue
+		}
+		collector, exists := s.collectorRegistry.Get(name)
+		if !exists {
+			continue
+		}
+
+		s.stopCollectorTask(name)
+		settings, err := s.collectorSettings(cfg, name)
+		if err == nil {
+			err = collector.Init(settings)
+		}
+		if err != nil {
+			s.logger.Error("Failed to re-initialize collector with rotated secrets", zap.String("collector", name), zap.Error(err))
+			continue
+		}
+		if err := s.startCollectorTask(collector, cfg); err != nil {
+			s.logger.Error("Failed to restart collector after rotating secrets", zap.String("collector", name), zap.Error(err))
+			continue
+		}
+		s.logger.Info("Collector re-initialized with rotated secrets", zap.String("collector", name))
+	}
+
+	for _, nc := range notifierConfigs(cfg.Notifications) {
+		if !nc.enabled || !usesChanged(nc.settings, changed) {
+			continue
+		}
+		notifier, exists := s.notifierRegistry.Get(nc.name)
+		if !exists {
+			continue
+		}
+		settings, err := s.resolveSecrets(nc.settings)
+		if err != nil {
+			s.logger.Error("Failed to resolve rotated secrets", zap.String("notifier", nc.name), zap.Error(err))
+			continue
+		}
+
+		s.rotation.Lock()
+		if err := notifier.Close(); err != nil {
+			s.logger.Warn("Error closing notifier", zap.String("notifier", nc.name), zap.Error(err))
+		}
+		err = notifier.Init(settings)
+		s.rotation.Unlock()
+		if err != nil {
+			s.logger.Error("Failed to re-initialize notifier with rotated secrets", zap.String("notifier", nc.name), zap.Error(err))
+			continue
+		}
+		s.logger.Info("Notifier re-initialized with rotated secrets", zap.String("notifier", nc.name))
+	}
+
+	for name, outputCfg := range cfg.Outputs {
+		if !outputCfg.Enabled || !usesChanged(outputCfg.Settings, changed) {
+			continue
+		}
+		output, exists := s.outputRegistry.Get(name)
+		if !exists {
+			continue
+		}
+		settings, err := s.outputSettings(cfg, name)
+		if err != nil {
+			s.logger.Error("Failed to resolve rotated secrets", zap.String("output", name), zap.Error(err))
+			continue
+		}
+
+		s.rotation.Lock()
+		if err := output.Close(); err != nil {
+			s.logger.Warn("Error closing output", zap.String("output", name), zap.Error(err))
+		}
+		err = output.Init(settings)
+		s.rotation.Unlock()
+		if err != nil {
+			s.logger.Error("Failed to re-initialize output with rotated secrets", zap.String("output", name), zap.Error(err))
+			continue
+		}
+		s.logger.Info("Output re-initialized with rotated secrets", zap.String("output", name))
+	}
+}
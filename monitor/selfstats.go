@@ -0,0 +1,83 @@
+// monitor/selfstats.go
+package monitor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors/selfmonitor"
+)
+
+// selfStats records the monitor's own health for the selfmonitor collector
+type selfStats struct {
+	collections   map[string]selfmonitor.CollectionStats
+	notifications map[string]selfmonitor.NotificationStats
+	mu            sync.Mutex
+}
+
+// newSelfStats creates empty self-monitoring stats
+func newSelfStats() *selfStats {
+	return &selfStats{
+		collections:   make(map[string]selfmonitor.CollectionStats),
+		notifications: make(map[string]selfmonitor.NotificationStats),
+	}
+}
+
+// recordCollection records how long a collector run took and whether it failed
+func (st *selfStats) recordCollection(name string, duration time.Duration, err error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	stats := st.collections[name]
+	stats.Runs++
+	stats.LastRun = time.Now()
+	stats.LastDuration = duration
+	if err != nil {
+		stats.Failures++
+		stats.ConsecutiveErrors++
+		stats.LastError = err.Error()
+	} else {
+		stats.ConsecutiveErrors = 0
+		stats.LastError = ""
+	}
+	st.collections[name] = stats
+}
+
+// recordNotification records a notifier's delivery outcome
+func (st *selfStats) recordNotification(name string, err error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	stats := st.notifications[name]
+	if err != nil {
+		stats.Failures++
+	} else {
+		stats.Sent++
+	}
+	st.notifications[name] = stats
+}
+
+// collection returns the stats of one collector
+func (st *selfStats) collection(name string) selfmonitor.CollectionStats {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.collections[name]
+}
+
+// Snapshot returns a copy of the current stats
+func (st *selfStats) Snapshot() selfmonitor.Snapshot {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	snapshot := selfmonitor.Snapshot{
+		Collections:   make(map[string]selfmonitor.CollectionStats, len(st.collections)),
+		Notifications: make(map[string]selfmonitor.NotificationStats, len(st.notifications)),
+	}
+	for name, stats := range st.collections {
+		snapshot.Collections[name] = stats
+	}
+	for name, stats := range st.notifications {
+		snapshot.Notifications[name] = stats
+	}
+	return snapshot
+}
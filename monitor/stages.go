@@ -0,0 +1,252 @@
+// monitor/stages.go
+package monitor
+
+import (
+	"context"
+
+	"github.com/devvspaces/simple-monit/alerting"
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/pipeline"
+	"github.com/devvspaces/simple-monit/storage"
+
+	"go.uber.org/zap"
+)
+
+// Names of the built-in pipeline stages, in the order batches pass them.
+// Custom processors are inserted relative to these with AddProcessor.
+const (
+	StageEnrich     = "enrich"     // Stamps the hostname and the monitor's and collector's tags on results
+	StageEvaluate   = "evaluate"   // Decides health from metrics and thresholds
+	StageAnomaly    = "anomaly"    // Flags metrics deviating from their baselines
+	StageHysteresis = "hysteresis" // Keeps alerting targets unhealthy until they clear
+	StageLifecycle  = "lifecycle"  // Advances the alert state machine
+	StageTrack      = "track"      // Records active alerts and the latest results
+	StageHistory    = "history"    // Stores every result in the history, when enabled
+	StageOutputs    = "outputs"    // Writes every result to outputs, observers and stream subscribers
+	StageSilence    = "silence"    // Keeps alerts and recoveries not muted, in maintenance, inhibited or behind a failing dependency
+	StageDedup      = "dedup"      // Drops repeat notifications within the renotify cooldown
+	StageDispatch   = "dispatch"   // Hands alerts to the notification lanes
+)
+
+// newPipeline creates the result pipeline with the built-in stages
+func (s *MonitorService) newPipeline() *pipeline.Pipeline {
+	return pipeline.New(s.logger.Named("pipeline"),
+		pipeline.Func(StageEnrich, s.enrichStage),
+		pipeline.Func(StageEvaluate, s.evaluateStage),
+		pipeline.Func(StageAnomaly, s.anomalyStage),
+		pipeline.Func(StageHysteresis, s.hysteresisStage),
+		pipeline.Func(StageLifecycle, s.lifecycleStage),
+		pipeline.Func(StageTrack, s.trackStage),
+		pipeline.Func(StageHistory, s.historyStage),
+		pipeline.Func(StageOutputs, s.outputsStage),
+		pipeline.Func(StageSilence, s.silenceStage),
+		pipeline.Func(StageDedup, s.dedupStage),
+		pipeline.Func(StageDispatch, s.dispatchStage),
+	)
+}
+
+// AddProcessor inserts a custom processor into the result pipeline before
+// the named stage, e.g. StageDispatch for routing or StageSilence for
+// enrichment that mute rules should see, or at the end when before is empty
+func (s *MonitorService) AddProcessor(before string, processor pipeline.Processor) error {
+	return s.pipeline.Insert(before, processor)
+}
+
+// PipelineStages returns the names of the result pipeline's stages, in order
+func (s *MonitorService) PipelineStages() []string {
+	return s.pipeline.Stages()
+}
+
+// enrichStage stamps the hostname, the monitor's tags and the collector's
+// tags, which win over the monitor's, on every result. Tags a result already
+// has, e.g. the hostname of a pushed result, are kept.
+func (s *MonitorService) enrichStage(ctx context.Context, batch *pipeline.Batch) error {
+	cfg := s.currentConfig()
+	hostname := s.Hostname()
+	for i, result := range batch.Results {
+		collectorTags := cfg.Collectors[result.Collector].Tags
+		stamped := make(map[string]string, len(cfg.Monitor.Tags)+len(collectorTags)+len(result.Tags)+1)
+		stamped["hostname"] = hostname
+		for key, value := range cfg.Monitor.Tags {
+			stamped[key] = value
+		}
+		for key, value := range collectorTags {
+			stamped[key] = value
+		}
+		for key, value := range result.Tags {
+			stamped[key] = value
+		}
+		batch.Results[i].Tags = stamped
+	}
+	return nil
+}
+
+// evaluateStage decides the health of every result from its metrics, the
+// collector's thresholds and the ones configured for the collector
+func (s *MonitorService) evaluateStage(ctx context.Context, batch *pipeline.Batch) error {
+	cfg := s.currentConfig()
+	for i, result := range batch.Results {
+		batch.Results[i] = s.evaluator.Evaluate(result, cfg.Collectors[result.Collector].Thresholds)
+	}
+	return nil
+}
+
+// anomalyStage flags metrics deviating from their learned baselines
+func (s *MonitorService) anomalyStage(ctx context.Context, batch *pipeline.Batch) error {
+	cfg := s.currentConfig()
+	for i, result := range batch.Results {
+		if anomalies := cfg.Collectors[result.Collector].Anomaly; len(anomalies) > 0 {
+			batch.Results[i] = s.anomalies.Apply(result, anomalies)
+		}
+	}
+	return nil
+}
+
+// hysteresisStage keeps alerting targets unhealthy until their clear
+// thresholds are met
+func (s *MonitorService) hysteresisStage(ctx context.Context, batch *pipeline.Batch) error {
+	batch.Results = s.activeAlerts.applyHysteresis(batch.Results)
+	return nil
+}
+
+// lifecycleStage advances the alert lifecycle; new alerts stay pending for
+// the batch's hold. Results that start or resolve an alert are marked with
+// the transition, so the notifiers receive it with them.
+func (s *MonitorService) lifecycleStage(ctx context.Context, batch *pipeline.Batch) error {
+	batch.Results, batch.Events = s.alerting.Evaluate(batch.Results, batch.Hold, batch.EvaluatedAt)
+
+	transitions := make(map[string]string, len(batch.Events))
+	for _, event := range batch.Events {
+		switch event.To {
+		case alerting.StateFiring:
+			transitions[event.Key] = collectors.TransitionFiring
+		case alerting.StateResolved:
+			transitions[event.Key] = collectors.TransitionResolved
+		}
+	}
+	if len(transitions) == 0 {
+		return nil
+	}
+	for i, result := range batch.Results {
+		if transition, ok := transitions[result.Key()]; ok {
+			batch.Results[i].Transition = transition
+		}
+	}
+	return nil
+}
+
+// trackStage records which targets currently have active alerts, and the
+// latest result of every target
+func (s *MonitorService) trackStage(ctx context.Context, batch *pipeline.Batch) error {
+	s.activeAlerts.update(batch.Results)
+	s.latest.update(batch.Results)
+	return nil
+}
+
+// outputsStage sends every result to the outputs, healthy or not, and
+// lifecycle transitions to the notifiers that want them; both go to stream
+// subscribers
+func (s *MonitorService) outputsStage(ctx context.Context, batch *pipeline.Batch) error {
+	s.writeOutputs(ctx, batch.Results)
+	s.notifyTransitions(ctx, batch.Events)
+	s.stream.publish(batch.Results, batch.Events)
+	return nil
+}
+
+// silenceStage keeps the unhealthy results, and the recoveries of alerts that
+// were notified, that may notify: those not muted, in a maintenance window,
+// inhibited or behind a failing dependency
+func (s *MonitorService) silenceStage(ctx context.Context, batch *pipeline.Batch) error {
+	var alerts []collectors.Result
+	for _, result := range batch.Results {
+		if result.IsHealthy {
+			// A recovery goes to the notifiers with the severity they were
+			// told of, so it is routed and silenced like its alert
+			severity, notified := s.activeAlerts.recoveryDue(result.Key())
+			if result.Transition != collectors.TransitionResolved || !notified {
+				continue
+			}
+			result.Severity = severity
+			s.logger.Info("Alert resolved",
+				zap.String("collector", result.Collector),
+				zap.String("severity", severity),
+				zap.String("message", result.Message))
+		} else {
+			s.logger.Info("Unhealthy result",
+				zap.String("collector", result.Collector),
+				zap.String("severity", result.EffectiveSeverity()),
+				zap.String("message", result.Message))
+		}
+
+		if rule := s.muter.Match(result); rule != nil {
+			s.logger.Info("Notification muted",
+				zap.String("collector", result.Collector),
+				zap.String("severity", result.EffectiveSeverity()),
+				zap.String("rule", rule.ID),
+				zap.String("comment", rule.Comment))
+			if alert, first := s.activeAlerts.acknowledge(result.Key(), rule.ID); first {
+				s.recordIncidentEvents([]storage.IncidentEvent{acknowledgedEvent(alert, batch.EvaluatedAt)})
+			}
+			continue
+		}
+
+		if window := s.maintenance.Suppress(result, batch.EvaluatedAt); window != "" {
+			s.logger.Info("Notification suppressed during maintenance",
+				zap.String("collector", result.Collector),
+				zap.String("severity", result.EffectiveSeverity()),
+				zap.String("window", window))
+			continue
+		}
+
+		if rule, source := s.inhibitedBy(result); source != nil {
+			inhibitedBy := rule + ": " + source.Key()
+			s.activeAlerts.markInhibited(result.Key(), inhibitedBy)
+			s.logger.Info("Notification inhibited",
+				zap.String("collector", result.Collector),
+				zap.String("severity", result.EffectiveSeverity()),
+				zap.String("rule", rule),
+				zap.String("inhibited_by", source.Key()))
+			continue
+		}
+
+		if source := s.failingDependency(result); source != nil {
+			s.activeAlerts.markInhibited(result.Key(), "depends_on: "+source.Key())
+			s.logger.Info("Notification suppressed by failing dependency",
+				zap.String("collector", result.Collector),
+				zap.String("severity", result.EffectiveSeverity()),
+				zap.String("depends_on", source.Key()))
+			continue
+		}
+
+		alerts = append(alerts, result)
+	}
+	batch.Results = alerts
+	return nil
+}
+
+// dedupStage drops alerts already notified within their renotify cooldown,
+// unless their severity escalated
+func (s *MonitorService) dedupStage(ctx context.Context, batch *pipeline.Batch) error {
+	var due []collectors.Result
+	for _, result := range batch.Results {
+		renotifyAfter := s.currentConfig().GetRenotifyAfter(result.Collector)
+		if !s.activeAlerts.shouldNotify(result, renotifyAfter, batch.EvaluatedAt) {
+			s.logger.Debug("Repeat notification suppressed",
+				zap.String("collector", result.Collector),
+				zap.String("target", result.Key()),
+				zap.Duration("renotify_after", renotifyAfter))
+			continue
+		}
+		due = append(due, result)
+	}
+	batch.Results = due
+	return nil
+}
+
+// dispatchStage sends the remaining alerts through the priority lanes
+func (s *MonitorService) dispatchStage(ctx context.Context, batch *pipeline.Batch) error {
+	if len(batch.Results) == 0 {
+		return nil
+	}
+	return s.dispatcher.dispatch(ctx, batch.Results, batch.EvaluatedAt)
+}
@@ -0,0 +1,86 @@
+// monitor/stream.go
+package monitor
+
+import (
+	"sync"
+
+	"github.com/devvspaces/simple-monit/alerting"
+	"github.com/devvspaces/simple-monit/collectors"
+)
+
+// streamBuffer is how many events a subscriber may fall behind before
+// further events are dropped for it
+const streamBuffer = 256
+
+// StreamEvent is a result or an alert transition, as sent to subscribers
+type StreamEvent struct {
+	Type       string             `json:"type"` // "result" or "transition"
+	Result     *collectors.Result `json:"result,omitempty"`
+	Transition *alerting.Event    `json:"transition,omitempty"`
+}
+
+// Collector returns the collector the event is about
+func (e StreamEvent) Collector() string {
+	if e.Result != nil {
+		return e.Result.Collector
+	}
+	return e.Transition.Result.Collector
+}
+
+// broadcaster fans results and transitions out to subscribers. A subscriber
+// that does not keep up misses events rather than slowing the pipeline.
+type broadcaster struct {
+	subscribers map[chan StreamEvent]struct{}
+	mu          sync.Mutex
+}
+
+// newBroadcaster creates a broadcaster without subscribers
+func newBroadcaster() *broadcaster {
+	return &broadcaster{subscribers: make(map[chan StreamEvent]struct{})}
+}
+
+// subscribe returns a channel of events and a function that unsubscribes
+// and closes it
+func (b *broadcaster) subscribe() (<-chan StreamEvent, func()) {
+	ch := make(chan StreamEvent, streamBuffer)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// publish sends results and transitions to every subscriber
+func (b *broadcaster) publish(results []collectors.Result, events []alerting.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.subscribers) == 0 {
+		return
+	}
+	for i := range results {
+		b.send(StreamEvent{Type: "result", Result: &results[i]})
+	}
+	for i := range events {
+		b.send(StreamEvent{Type: "transition", Transition: &events[i]})
+	}
+}
+
+// send delivers an event to subscribers with room for it; b.mu must be held
+func (b *broadcaster) send(event StreamEvent) {
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
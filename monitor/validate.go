@@ -0,0 +1,173 @@
+// monitor/validate.go
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/notifiers"
+	"github.com/devvspaces/simple-monit/outputs"
+
+	"go.uber.org/zap"
+)
+
+// Validate checks the configuration against the components compiled in,
+// without starting anything: every enabled collector, notifier and output
+// must be registered and accept its settings. Components implementing
+// Validator check their settings themselves; the other built-in ones are
+// initialized on a throwaway instance, as their Init only reads settings.
+// Other components are only checked to be registered. Every problem is
+// returned, prefixed with where it is in the configuration.
+func (s *MonitorService) Validate() error {
+	if err := s.registerCollectors(); err != nil {
+		return err
+	}
+	if err := s.registerNotifiers(); err != nil {
+		return err
+	}
+	if err := s.registerOutputs(); err != nil {
+		return err
+	}
+	defer s.closeSecrets()
+
+	var errs []error
+	errs = append(errs, s.validateCollectors()...)
+	errs = append(errs, s.validateNotifiers()...)
+	errs = append(errs, s.validateOutputs()...)
+	return errors.Join(errs...)
+}
+
+// validateCollectors checks the enabled collectors, in name order
+func (s *MonitorService) validateCollectors() []error {
+	builtins := make(map[string]func() collectors.Collector)
+	if s.options.builtins {
+		for _, factory := range collectorFactories {
+			builtins[factory.create(zap.NewNop()).Name()] = func() collectors.Collector { return factory.create(zap.NewNop()) }
+		}
+	}
+
+	var errs []error
+	for _, name := range sortedKeys(s.config.Collectors) {
+		if !s.config.Collectors[name].Enabled {
+			continue
+		}
+		path := "collectors." + name
+		collector, exists := s.collectorRegistry.Get(name)
+		if !exists {
+			errs = append(errs, fmt.Errorf("%s: no collector named '%s' is compiled into this binary", path, name))
+			continue
+		}
+
+		settings, err := s.collectorSettings(s.config, name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s.settings: %w", path, err))
+			continue
+		}
+		if validator, ok := collector.(collectors.Validator); ok {
+			err = validator.Validate(settings)
+		} else if create, ok := builtins[name]; ok {
+			fresh := create()
+			err = fresh.Init(settings)
+			fresh.Cleanup()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s.settings: %w", path, err))
+		}
+	}
+	return errs
+}
+
+// validateNotifiers checks the enabled notifiers
+func (s *MonitorService) validateNotifiers() []error {
+	builtins := make(map[string]func() notifiers.Notifier)
+	if s.options.builtins {
+		for _, factory := range notifierFactories {
+			builtins[factory.create(zap.NewNop()).Name()] = func() notifiers.Notifier { return factory.create(zap.NewNop()) }
+		}
+	}
+
+	var errs []error
+	for _, nc := range notifierConfigs(s.config.Notifications) {
+		if !nc.enabled {
+			continue
+		}
+		path := "notifications." + nc.name
+		if _, plugin := s.config.Notifications.Plugins[nc.name]; plugin {
+			path = "notifications.plugins." + nc.name
+		}
+		notifier, exists := s.notifierRegistry.Get(nc.name)
+		if !exists {
+			errs = append(errs, fmt.Errorf("%s: the %s notifier is not compiled into this binary", path, nc.name))
+			continue
+		}
+
+		settings, err := s.resolveSecrets(nc.settings)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		if validator, ok := notifier.(notifiers.Validator); ok {
+			err = validator.Validate(settings)
+		} else if create, ok := builtins[nc.name]; ok {
+			fresh := create()
+			err = fresh.Init(settings)
+			fresh.Close()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+	}
+	return errs
+}
+
+// validateOutputs checks the enabled outputs, in name order
+func (s *MonitorService) validateOutputs() []error {
+	builtins := make(map[string]func() outputs.Output)
+	if s.options.builtins {
+		for _, factory := range outputFactories {
+			builtins[factory.create(zap.NewNop()).Name()] = func() outputs.Output { return factory.create(zap.NewNop()) }
+		}
+	}
+
+	var errs []error
+	for _, name := range sortedKeys(s.config.Outputs) {
+		if !s.config.Outputs[name].Enabled {
+			continue
+		}
+		path := "outputs." + name
+		output, exists := s.outputRegistry.Get(name)
+		if !exists {
+			errs = append(errs, fmt.Errorf("%s: no output named '%s' is compiled into this binary", path, name))
+			continue
+		}
+
+		settings, err := s.outputSettings(s.config, name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s.settings: %w", path, err))
+			continue
+		}
+		if validator, ok := output.(outputs.Validator); ok {
+			err = validator.Validate(settings)
+		} else if create, ok := builtins[name]; ok {
+			fresh := create()
+			err = fresh.Init(settings)
+			fresh.Close()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s.settings: %w", path, err))
+		}
+	}
+	return errs
+}
+
+// sortedKeys returns the keys of a map, sorted
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
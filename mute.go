@@ -0,0 +1,248 @@
+// mute.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/devvspaces/simple-monit/config"
+	"github.com/devvspaces/simple-monit/mutes"
+
+	"go.uber.org/zap"
+)
+
+// metadataFlag collects repeated key=value flags
+type metadataFlag map[string]string
+
+func (m metadataFlag) String() string {
+	var pairs []string
+	for k, v := range m {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (m metadataFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	m[key] = val
+	return nil
+}
+
+// runMute implements the "mute" subcommand which manages the mute rules file
+func runMute(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: server-monitor mute <list|add|remove> [flags]")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("mute "+args[0], flag.ContinueOnError)
+	configPath := fs.String("config", "config.yaml", "Path to configuration file")
+	filePath := fs.String("file", "", "Path to the mute rules file (default: mutes.file from the config)")
+
+	switch args[0] {
+	case "list":
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+		return muteList(*configPath, *filePath)
+
+	case "add":
+		collector := fs.String("collector", "", "Only mute results from this collector")
+		message := fs.String("message", "", "Only mute results whose message matches this regex")
+		expires := fs.String("expires", "", "Expiry as a duration (e.g. 72h) or date (2006-01-02 or RFC3339)")
+		comment := fs.String("comment", "", "Why the rule exists")
+		metadata := metadataFlag{}
+		fs.Var(metadata, "metadata", "Only mute results with this metadata key=value (repeatable)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+
+		rule := config.MuteRule{
+			ID:           mutes.NewID(),
+			Collector:    *collector,
+			MessageRegex: *message,
+			Comment:      *comment,
+		}
+		if len(metadata) > 0 {
+			rule.Metadata = metadata
+		}
+		if *expires != "" {
+			expiry, err := parseExpiry(*expires)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid -expires: %v\n", err)
+				return 2
+			}
+			rule.Expires = expiry
+		}
+		return muteAdd(*configPath, *filePath, rule)
+
+	case "remove":
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+		if fs.NArg() != 1 {
+			fmt.Fprintln(os.Stderr, "usage: server-monitor mute remove [flags] <id>")
+			return 2
+		}
+		return muteRemove(*configPath, *filePath, fs.Arg(0))
+	}
+
+	fmt.Fprintf(os.Stderr, "Unknown mute command %q\n", args[0])
+	return 2
+}
+
+// resolveMuteFile returns the rules file path and the config-defined rules
+func resolveMuteFile(configPath, filePath string) (string, []config.MuteRule, error) {
+	cfg, err := config.LoadConfig(zap.NewNop(), configPath)
+	if err != nil {
+		if filePath != "" {
+			return filePath, nil, nil
+		}
+		return "", nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if filePath == "" {
+		filePath = cfg.Mutes.File
+	}
+	if filePath == "" {
+		return "", nil, fmt.Errorf("no mute rules file: set mutes.file in %s or pass -file", configPath)
+	}
+	return filePath, cfg.Mutes.Rules, nil
+}
+
+func muteList(configPath, filePath string) int {
+	path, configRules, err := resolveMuteFile(configPath, filePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	fileRules, err := mutes.LoadFile(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSOURCE\tEXPIRES\tMATCH\tCOMMENT")
+	printRules := func(source string, rules []config.MuteRule) {
+		for _, rule := range rules {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", rule.ID, source, formatExpiry(rule.Expires), describeMatch(rule), rule.Comment)
+		}
+	}
+	printRules("config", configRules)
+	printRules("file", fileRules)
+	w.Flush()
+	return 0
+}
+
+func muteAdd(configPath, filePath string, rule config.MuteRule) int {
+	if err := config.ValidateMuteRule(rule); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	path, _, err := resolveMuteFile(configPath, filePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	rules, err := mutes.LoadFile(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if err := mutes.SaveFile(path, append(rules, rule)); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save mute rules: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Added mute rule %s\n", rule.ID)
+	return 0
+}
+
+func muteRemove(configPath, filePath, id string) int {
+	path, _, err := resolveMuteFile(configPath, filePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	rules, err := mutes.LoadFile(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	kept := rules[:0]
+	for _, rule := range rules {
+		if rule.ID != id {
+			kept = append(kept, rule)
+		}
+	}
+	if len(kept) == len(rules) {
+		fmt.Fprintf(os.Stderr, "No mute rule with ID %s in %s\n", id, path)
+		return 1
+	}
+
+	if err := mutes.SaveFile(path, kept); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save mute rules: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Removed mute rule %s\n", id)
+	return 0
+}
+
+// parseExpiry accepts a duration from now, a date, or an RFC3339 timestamp
+func parseExpiry(value string) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(d).Truncate(time.Second), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a duration, date or RFC3339 timestamp", value)
+}
+
+func formatExpiry(expires time.Time) string {
+	if expires.IsZero() {
+		return "never"
+	}
+	if time.Now().After(expires) {
+		return "expired " + expires.Format(time.RFC3339)
+	}
+	return expires.Format(time.RFC3339)
+}
+
+func describeMatch(rule config.MuteRule) string {
+	var parts []string
+	if rule.Collector != "" {
+		parts = append(parts, "collector="+rule.Collector)
+	}
+	if rule.MessageRegex != "" {
+		parts = append(parts, fmt.Sprintf("message=~/%s/", rule.MessageRegex))
+	}
+	keys := make([]string, 0, len(rule.Metadata))
+	for k := range rule.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts = append(parts, k+"="+rule.Metadata[k])
+	}
+	return strings.Join(parts, " ")
+}
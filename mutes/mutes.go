@@ -0,0 +1,232 @@
+// mutes/mutes.go
+package mutes
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/config"
+
+	"go.uber.org/zap"
+)
+
+// rule is a compiled mute rule
+type rule struct {
+	config.MuteRule
+	messageRegex *regexp.Regexp
+}
+
+// matches reports whether the rule applies to the result at the given time
+func (r *rule) matches(result collectors.Result, now time.Time) bool {
+	if !r.Expires.IsZero() && now.After(r.Expires) {
+		return false
+	}
+	if r.Collector != "" && r.Collector != result.Collector {
+		return false
+	}
+	if r.messageRegex != nil && !r.messageRegex.MatchString(result.Message) {
+		return false
+	}
+	for key, want := range r.Metadata {
+		if got, ok := result.Metadata[key]; !ok || fmt.Sprint(got) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// Muter decides whether notifications for a result are muted. Rules come from
+// the configuration and from an optional rules file, which is reloaded when it
+// changes so rules managed through the CLI apply without a restart.
+type Muter struct {
+	static    []*rule
+	file      string
+	fileRules []*rule
+	modTime   time.Time
+	mu        sync.Mutex
+	fileMu    sync.Mutex // Serializes changes to the rules file
+	logger    *zap.Logger
+}
+
+// NewMuter creates a muter from the mute configuration
+func NewMuter(logger *zap.Logger, cfg config.MutesConfig) (*Muter, error) {
+	static, err := compile(cfg.Rules)
+	if err != nil {
+		logger.Error("Invalid mute rules", zap.Error(err))
+		return nil, err
+	}
+
+	m := &Muter{
+		static: static,
+		file:   cfg.File,
+		logger: logger,
+	}
+
+	if err := m.reload(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Match returns the first active rule muting the result, or nil
+func (m *Muter) Match(result collectors.Result) *config.MuteRule {
+	if err := m.reload(); err != nil {
+		m.logger.Warn("Keeping previous mute rules", zap.Error(err))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for _, rules := range [][]*rule{m.static, m.fileRules} {
+		for _, r := range rules {
+			if r.matches(result, now) {
+				matched := r.MuteRule
+				return &matched
+			}
+		}
+	}
+	return nil
+}
+
+// Add validates a rule and appends it to the rules file, which the muter
+// picks up on its next match
+func (m *Muter) Add(rule config.MuteRule) error {
+	if m.file == "" {
+		return fmt.Errorf("no mute rules file: set mutes.file in the configuration")
+	}
+	if err := config.ValidateMuteRule(rule); err != nil {
+		return err
+	}
+
+	m.fileMu.Lock()
+	defer m.fileMu.Unlock()
+
+	rules, err := LoadFile(m.file)
+	if err != nil {
+		return err
+	}
+	if err := SaveFile(m.file, append(rules, rule)); err != nil {
+		m.logger.Error("Failed to save mute rules", zap.String("file", m.file), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// reload re-reads the rules file if it changed since the last load
+func (m *Muter) reload() error {
+	if m.file == "" {
+		return nil
+	}
+
+	info, err := os.Stat(m.file)
+	if errors.Is(err, os.ErrNotExist) {
+		m.mu.Lock()
+		m.fileRules = nil
+		m.modTime = time.Time{}
+		m.mu.Unlock()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	unchanged := info.ModTime().Equal(m.modTime)
+	m.mu.Unlock()
+	if unchanged {
+		return nil
+	}
+
+	fileRules, err := LoadFile(m.file)
+	if err != nil {
+		return err
+	}
+	compiled, err := compile(fileRules)
+	if err != nil {
+		return fmt.Errorf("invalid rule in %s: %w", m.file, err)
+	}
+
+	m.mu.Lock()
+	m.fileRules = compiled
+	m.modTime = info.ModTime()
+	m.mu.Unlock()
+
+	m.logger.Info("Loaded mute rules", zap.String("file", m.file), zap.Int("rules", len(compiled)))
+	return nil
+}
+
+// compile validates and compiles mute rules
+func compile(rules []config.MuteRule) ([]*rule, error) {
+	compiled := make([]*rule, 0, len(rules))
+	for _, r := range rules {
+		if err := config.ValidateMuteRule(r); err != nil {
+			return nil, err
+		}
+
+		c := &rule{MuteRule: r}
+		if r.MessageRegex != "" {
+			c.messageRegex = regexp.MustCompile(r.MessageRegex)
+		}
+		compiled = append(compiled, c)
+	}
+	return compiled, nil
+}
+
+// LoadFile reads mute rules from a JSON rules file. A missing file has no rules.
+func LoadFile(path string) ([]config.MuteRule, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []config.MuteRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("could not parse mute rules file %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// SaveFile atomically writes mute rules to a JSON rules file
+func SaveFile(path string, rules []config.MuteRule) error {
+	data, err := json.MarshalIndent(rules, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-"+filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// NewID returns a short random identifier for a mute rule
+func NewID() string {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,175 @@
+// nagios.go
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/devvspaces/simple-monit/collectors"
+)
+
+// Nagios plugin states, which are also the plugin's exit codes
+const (
+	nagiosOK       = 0
+	nagiosWarning  = 1
+	nagiosCritical = 2
+	nagiosUnknown  = 3
+)
+
+// nagiosStatus names the states in plugin output
+var nagiosStatus = []string{"OK", "WARNING", "CRITICAL", "UNKNOWN"}
+
+// nagiosState maps a result to a plugin state by its severity. Unhealthy
+// info results are OK, as Nagios has no lower level than WARNING.
+func nagiosState(result collectors.Result) int {
+	if result.IsHealthy {
+		return nagiosOK
+	}
+	switch result.EffectiveSeverity() {
+	case collectors.SeverityCritical:
+		return nagiosCritical
+	case collectors.SeverityWarning:
+		return nagiosWarning
+	case collectors.SeverityInfo:
+		return nagiosOK
+	}
+	return nagiosUnknown
+}
+
+// writeNagios writes results in the Nagios plugin output format and returns
+// the plugin state, the most severe of the results'. The first line is the
+// status, a summary and the performance data of every result; with several
+// results, a line per target follows, the unhealthy ones first.
+func writeNagios(w io.Writer, results []collectors.Result) int {
+	if len(results) == 0 {
+		fmt.Fprintln(w, "UNKNOWN - No results")
+		return nagiosUnknown
+	}
+
+	state, worst, unhealthy := nagiosOK, results[0], 0
+	for _, result := range results {
+		if !result.IsHealthy {
+			unhealthy++
+		}
+		if s := nagiosState(result); s > state || (worst.IsHealthy && !result.IsHealthy && s == state) {
+			state, worst = s, result
+		}
+	}
+
+	summary := nagiosMessage(worst)
+	if len(results) > 1 {
+		if unhealthy == 0 {
+			summary = fmt.Sprintf("%d targets healthy", len(results))
+		} else {
+			summary = fmt.Sprintf("%d of %d targets unhealthy: %s", unhealthy, len(results), summary)
+		}
+	}
+	var perfdata []string
+	for _, result := range results {
+		perfdata = append(perfdata, nagiosPerfdata(result, len(results) > 1)...)
+	}
+	line := nagiosStatus[state] + " - " + summary
+	if len(perfdata) > 0 {
+		line += " | " + strings.Join(perfdata, " ")
+	}
+	fmt.Fprintln(w, line)
+
+	if len(results) > 1 {
+		ordered := append([]collectors.Result(nil), results...)
+		sort.SliceStable(ordered, func(i, j int) bool { return !ordered[i].IsHealthy && ordered[j].IsHealthy })
+		for _, result := range ordered {
+			fmt.Fprintf(w, "[%s] %s: %s\n", nagiosStatus[nagiosState(result)], nagiosSanitize(result.Key()), nagiosMessage(result))
+		}
+	}
+	return state
+}
+
+// nagiosMessage returns a result's message, or its metrics when it has
+// none, on one line and without the | that starts performance data
+func nagiosMessage(result collectors.Result) string {
+	message := result.Message
+	if message == "" {
+		message = formatMetrics(result.Metrics)
+	}
+	return nagiosSanitize(message)
+}
+
+// nagiosSanitize keeps text on one line and out of the performance data
+func nagiosSanitize(text string) string {
+	return strings.NewReplacer("|", "/", "\r", " ", "\n", " ").Replace(text)
+}
+
+// nagiosPerfdata returns a result's metrics as performance data, with the
+// warning and critical ranges of their thresholds. Labels are prefixed with
+// the target when the results have several.
+func nagiosPerfdata(result collectors.Result, prefix bool) []string {
+	names := make([]string, 0, len(result.Metrics))
+	for name := range result.Metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var perfdata []string
+	for _, name := range names {
+		label := name
+		if prefix {
+			label = result.Key() + " " + name
+		}
+		label = strings.NewReplacer("|", " ", "=", ":", "'", "").Replace(label)
+
+		var warn, crit string
+		for _, threshold := range result.Thresholds {
+			if threshold.Metric != name {
+				continue
+			}
+			switch {
+			case threshold.Severity == collectors.SeverityCritical && crit == "":
+				crit = nagiosRange(threshold)
+			case threshold.Severity == collectors.SeverityWarning && warn == "":
+				warn = nagiosRange(threshold)
+			}
+		}
+
+		unit, bounds := "", ""
+		switch {
+		case strings.HasSuffix(name, "_percent"):
+			unit, bounds = "%", ";0;100"
+		case strings.HasSuffix(name, "_seconds"):
+			unit = "s"
+		case strings.HasSuffix(name, "_bytes"):
+			unit = "B"
+		}
+		value := nagiosNumber(result.Metrics[name])
+		entry := fmt.Sprintf("'%s'=%s%s;%s;%s%s", label, value, unit, warn, crit, bounds)
+		perfdata = append(perfdata, strings.TrimRight(entry, ";"))
+	}
+	return perfdata
+}
+
+// nagiosNumber formats a value for performance data: in decimal notation,
+// as plugins may not use exponents, to three decimals
+func nagiosNumber(value float64) string {
+	return strconv.FormatFloat(math.Round(value*1000)/1000, 'f', -1, 64)
+}
+
+// nagiosRange returns the Nagios range alerting like a threshold
+func nagiosRange(threshold collectors.Threshold) string {
+	value, upper := nagiosNumber(threshold.Value), nagiosNumber(threshold.Max)
+	switch threshold.Operator {
+	case "greater_than":
+		return "~:" + value
+	case "less_than":
+		return value + ":"
+	case "between":
+		return "@" + value + ":" + upper
+	case "outside_range":
+		return value + ":" + upper
+	case "equals":
+		return "@" + value + ":" + value
+	}
+	return ""
+}
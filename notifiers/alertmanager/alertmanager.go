@@ -0,0 +1,354 @@
+// notifiers/alertmanager/alertmanager.go
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/templates"
+
+	"go.uber.org/zap"
+)
+
+// invalidLabelChars matches characters not allowed in Prometheus label names
+var invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// AlertmanagerNotifier implements the Notifier interface by posting alerts to
+// Prometheus Alertmanager's v2 API, so simple-monit can feed an existing
+// routing tree. Alerts are resolved by re-posting them with endsAt set when
+// the target recovers, and re-posted while they fire so Alertmanager does not
+// resolve them after its resolve_timeout.
+type AlertmanagerNotifier struct {
+	urls         []string
+	username     string
+	password     string
+	bearerToken  string
+	extraLabels  map[string]string
+	generatorURL string
+	resend       time.Duration
+	templates    *templates.Set
+	client       *http.Client
+	firing       map[string]firingAlert
+	mu           sync.Mutex
+	logger       *zap.Logger
+}
+
+// firingAlert is an alert that was posted and not yet resolved
+type firingAlert struct {
+	labels   map[string]string
+	startsAt time.Time
+	postedAt time.Time
+}
+
+// alert is an Alertmanager v2 postable alert
+type alert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// defaultTemplates are the summary and description annotations of each alert
+var defaultTemplates = map[string]string{
+	"summary":     `{{.Result.Message}}`,
+	"description": `{{.Result.Collector}} on {{.Hostname}}: {{.Result.Message}}`,
+}
+
+// NewAlertmanagerNotifier creates a new Alertmanager notifier
+func NewAlertmanagerNotifier(logger *zap.Logger) *AlertmanagerNotifier {
+	return &AlertmanagerNotifier{
+		firing: make(map[string]firingAlert),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the notifier
+func (n *AlertmanagerNotifier) Name() string {
+	return "alertmanager"
+}
+
+// Schema describes the notifier and its settings
+func (n *AlertmanagerNotifier) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Alerts forwarded to Prometheus Alertmanager",
+		Settings: []collectors.Setting{
+			{Name: "urls", Type: "list", Required: true, Description: "Alertmanager base URLs"},
+			{Name: "username", Type: "string", Description: "Basic auth username"},
+			{Name: "password", Type: "string", Description: "Basic auth password"},
+			{Name: "password_file", Type: "string", Description: "File to read password from instead"},
+			{Name: "bearer_token", Type: "string", Description: "Bearer token"},
+			{Name: "bearer_token_file", Type: "string", Description: "File to read bearer_token from instead"},
+			{Name: "labels", Type: "map", Description: "Extra labels added to every alert"},
+			{Name: "generator_url", Type: "string", Description: "URL linked from the alerts"},
+			{Name: "resend_interval_seconds", Type: "duration", Default: "60", Description: "How often firing alerts are re-posted; keep it below Alertmanager's resolve_timeout"},
+			{Name: "templates", Type: "map", Description: "Templates replacing the summary and description annotations"},
+		},
+	}
+}
+
+// Init initializes the Alertmanager notifier with configuration
+func (n *AlertmanagerNotifier) Init(config map[string]interface{}) error {
+	// Init runs again when secrets rotate; start from a clean slate
+	n.urls = nil
+	n.extraLabels = nil
+
+	urls, err := collectors.GetStringSlice(config, "urls", nil)
+	if err != nil {
+		n.logger.Error("Failed to initialize alertmanager notifier", zap.Error(err))
+		return err
+	}
+	if len(urls) == 0 {
+		err := fmt.Errorf("missing 'urls' in alertmanager config")
+		n.logger.Error("Failed to initialize alertmanager notifier", zap.Error(err))
+		return err
+	}
+	// Post to every Alertmanager of an HA cluster; they deduplicate among themselves
+	for _, u := range urls {
+		n.urls = append(n.urls, strings.TrimRight(u, "/")+"/api/v2/alerts")
+	}
+
+	n.username = collectors.GetString(config, "username", "")
+	n.password = collectors.GetString(config, "password", "")
+	n.bearerToken = collectors.GetString(config, "bearer_token", "")
+	n.generatorURL = collectors.GetString(config, "generator_url", "")
+	if raw, ok := config["labels"]; ok {
+		labels, ok := raw.(map[string]interface{})
+		if !ok {
+			err := fmt.Errorf("alertmanager notifier 'labels' should be a map")
+			n.logger.Error("Failed to initialize alertmanager notifier", zap.Error(err))
+			return err
+		}
+		n.extraLabels = make(map[string]string, len(labels))
+		for name, value := range labels {
+			n.extraLabels[name] = fmt.Sprint(value)
+		}
+	}
+	n.resend, err = collectors.GetDuration(config, "resend_interval_seconds", time.Second, 0)
+	if err != nil {
+		n.logger.Error("Failed to initialize alertmanager notifier", zap.Error(err))
+		return err
+	}
+	if n.resend <= 0 {
+		n.resend = time.Minute
+	}
+	set, err := templates.FromSettings(n.logger, n.Name(), defaultTemplates, config)
+	if err != nil {
+		n.logger.Error("Failed to initialize alertmanager notifier", zap.Error(err))
+		return err
+	}
+	n.templates = set
+	n.client = &http.Client{Timeout: 10 * time.Second}
+
+	return nil
+}
+
+// Notify posts the unhealthy results as firing alerts
+func (n *AlertmanagerNotifier) Notify(ctx context.Context, results []collectors.Result) error {
+	now := time.Now()
+
+	n.mu.Lock()
+	var alerts []alert
+	for _, result := range results {
+		if result.IsHealthy {
+			continue
+		}
+
+		key := result.Key()
+		labels := n.labelsFor(result)
+		prev, wasFiring := n.firing[key]
+
+		startsAt := result.Timestamp
+		if wasFiring {
+			startsAt = prev.startsAt
+			// A severity change is a different label set; resolve the old one
+			if !reflect.DeepEqual(prev.labels, labels) {
+				alerts = append(alerts, alert{Labels: prev.labels, StartsAt: prev.startsAt, EndsAt: now})
+			}
+		}
+
+		n.firing[key] = firingAlert{labels: labels, startsAt: startsAt, postedAt: now}
+		alerts = append(alerts, alert{
+			Labels:       labels,
+			Annotations:  n.annotationsFor(result),
+			StartsAt:     startsAt,
+			GeneratorURL: n.generatorURL,
+		})
+	}
+	n.mu.Unlock()
+
+	return n.post(ctx, alerts)
+}
+
+// Observe resolves firing alerts whose targets are healthy again, and
+// re-posts the ones still unhealthy once the resend interval has passed,
+// whatever the renotify cooldown, since Alertmanager resolves alerts it has
+// not heard of for its resolve_timeout
+func (n *AlertmanagerNotifier) Observe(ctx context.Context, results []collectors.Result) error {
+	now := time.Now()
+
+	n.mu.Lock()
+	var alerts []alert
+	for _, result := range results {
+		key := result.Key()
+		prev, wasFiring := n.firing[key]
+		if !wasFiring {
+			continue
+		}
+
+		if !result.IsHealthy {
+			if now.Sub(prev.postedAt) < n.resend {
+				continue
+			}
+			prev.postedAt = now
+			n.firing[key] = prev
+			alerts = append(alerts, alert{
+				Labels:       prev.labels,
+				Annotations:  n.annotationsFor(result),
+				StartsAt:     prev.startsAt,
+				GeneratorURL: n.generatorURL,
+			})
+			continue
+		}
+		delete(n.firing, key)
+
+		alerts = append(alerts, alert{
+			Labels:       prev.labels,
+			Annotations:  n.annotationsFor(result),
+			StartsAt:     prev.startsAt,
+			EndsAt:       now,
+			GeneratorURL: n.generatorURL,
+		})
+	}
+	n.mu.Unlock()
+
+	return n.post(ctx, alerts)
+}
+
+// labelsFor derives the alert labels: alertname, instance, collector,
+// severity and every metadata key, plus the configured extra labels
+func (n *AlertmanagerNotifier) labelsFor(result collectors.Result) map[string]string {
+	labels := make(map[string]string)
+	for name, value := range n.extraLabels {
+		labels[name] = value
+	}
+	for name, value := range result.Labels() {
+		labels[labelName(name)] = value
+	}
+	labels["alertname"] = "SimpleMonit" + camelCase(result.Collector)
+	labels["fingerprint"] = result.Fingerprint()
+	if _, ok := labels["instance"]; !ok {
+		labels["instance"] = templates.HostOf(result)
+	}
+	return labels
+}
+
+// annotationsFor returns the summary, description and metric annotations of a result
+func (n *AlertmanagerNotifier) annotationsFor(result collectors.Result) map[string]string {
+	annotations := map[string]string{
+		"summary":     n.templates.RenderResult("summary", result),
+		"description": n.templates.RenderResult("description", result),
+	}
+
+	names := make([]string, 0, len(result.Metrics))
+	for name := range result.Metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var metrics []string
+	for _, name := range names {
+		metrics = append(metrics, fmt.Sprintf("%s=%.2f", name, result.Metrics[name]))
+	}
+	if len(metrics) > 0 {
+		annotations["metrics"] = strings.Join(metrics, ", ")
+	}
+	return annotations
+}
+
+// post sends alerts to every configured Alertmanager. It succeeds if at least
+// one of them accepted the alerts.
+func (n *AlertmanagerNotifier) post(ctx context.Context, alerts []alert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(alerts)
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	for _, url := range n.urls {
+		if err := n.postTo(ctx, url, body); err != nil {
+			n.logger.Error("Failed to post alerts to Alertmanager", zap.String("url", url), zap.Error(err))
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) == len(n.urls) {
+		return fmt.Errorf("alertmanager post failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// postTo posts an encoded alert list to one Alertmanager
+func (n *AlertmanagerNotifier) postTo(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+n.bearerToken)
+	} else if n.username != "" {
+		req.SetBasicAuth(n.username, n.password)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned %s: %s", url, resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+// labelName turns a metadata key into a valid Prometheus label name
+func labelName(name string) string {
+	name = invalidLabelChars.ReplaceAllString(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// camelCase turns a collector name like disk_space into DiskSpace
+func camelCase(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' || r == '.' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// Close performs any necessary cleanup
+func (n *AlertmanagerNotifier) Close() error {
+	// Firing alerts expire in Alertmanager after its resolve_timeout
+	return nil
+}
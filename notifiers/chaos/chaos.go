@@ -0,0 +1,210 @@
+// notifiers/chaos/chaos.go
+package chaos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"go.uber.org/zap"
+)
+
+// ChaosNotifier is a testing notifier that injects delivery failures. Each
+// delivery is randomly failed, delayed or duplicated at the configured rates,
+// so retry, ordering and failover behavior can be exercised in integration
+// tests and staging. Deliveries that get through are logged and, when a url
+// is configured, POSTed there as JSON so a mock receiver can observe them.
+type ChaosNotifier struct {
+	failRate      float64
+	delayRate     float64
+	duplicateRate float64
+	maxDelay      time.Duration
+	url           string
+	client        *http.Client
+	rng           *rand.Rand
+	sequence      int
+	mu            sync.Mutex
+	logger        *zap.Logger
+}
+
+// delivery is the JSON body POSTed to the configured url
+type delivery struct {
+	Sequence  int                 `json:"sequence"`
+	Duplicate bool                `json:"duplicate"`
+	SentAt    time.Time           `json:"sent_at"`
+	Results   []collectors.Result `json:"results"`
+}
+
+// NewChaosNotifier creates a new chaos notifier
+func NewChaosNotifier(logger *zap.Logger) *ChaosNotifier {
+	return &ChaosNotifier{
+		logger: logger,
+	}
+}
+
+// Name returns the name of the notifier
+func (n *ChaosNotifier) Name() string {
+	return "chaos"
+}
+
+// Schema describes the notifier and its settings
+func (n *ChaosNotifier) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Fault injection for testing notification delivery",
+		Settings: []collectors.Setting{
+			{Name: "fail_rate", Type: "float", Default: "0", Description: "Fraction of notifications that fail"},
+			{Name: "delay_rate", Type: "float", Default: "0", Description: "Fraction of notifications that are delayed"},
+			{Name: "duplicate_rate", Type: "float", Default: "0", Description: "Fraction of notifications that are sent twice"},
+			{Name: "max_delay_ms", Type: "duration", Default: "0", Description: "Longest delay"},
+			{Name: "url", Type: "string", Description: "Webhook deliveries are posted to (default: only logged)"},
+			{Name: "seed", Type: "int", Description: "Random seed, for reproducible runs"},
+		},
+	}
+}
+
+// Init initializes the chaos notifier with configuration
+func (n *ChaosNotifier) Init(config map[string]interface{}) error {
+	n.failRate = collectors.GetFloat(config, "fail_rate", 0)
+	n.delayRate = collectors.GetFloat(config, "delay_rate", 0)
+	n.duplicateRate = collectors.GetFloat(config, "duplicate_rate", 0)
+
+	for name, rate := range map[string]float64{
+		"fail_rate":      n.failRate,
+		"delay_rate":     n.delayRate,
+		"duplicate_rate": n.duplicateRate,
+	} {
+		if rate < 0 || rate > 1 {
+			err := fmt.Errorf("'%s' in chaos config must be between 0 and 1", name)
+			n.logger.Error("Failed to initialize chaos notifier", zap.Error(err))
+			return err
+		}
+	}
+
+	maxDelayMs := collectors.GetInt(config, "max_delay_ms", 0)
+	if maxDelayMs <= 0 {
+		maxDelayMs = 5000
+	}
+	n.maxDelay = time.Duration(maxDelayMs) * time.Millisecond
+	n.url = collectors.GetString(config, "url", "")
+	n.client = &http.Client{Timeout: 10 * time.Second}
+
+	// A fixed seed makes a run reproducible
+	seed := int64(collectors.GetInt(config, "seed", 0))
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	n.rng = rand.New(rand.NewSource(seed))
+
+	n.logger.Warn("Chaos notifier enabled; deliveries will be failed, delayed and duplicated on purpose",
+		zap.Float64("fail_rate", n.failRate),
+		zap.Float64("delay_rate", n.delayRate),
+		zap.Float64("duplicate_rate", n.duplicateRate),
+		zap.Int64("seed", seed))
+
+	return nil
+}
+
+// Notify delivers the results, subject to the configured failure injection
+func (n *ChaosNotifier) Notify(ctx context.Context, results []collectors.Result) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	if n.roll(n.delayRate) {
+		delay := n.randomDelay()
+		n.logger.Info("Chaos: delaying delivery", zap.Duration("delay", delay), zap.Int("results", len(results)))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if n.roll(n.failRate) {
+		n.logger.Info("Chaos: failing delivery", zap.Int("results", len(results)))
+		return fmt.Errorf("chaos: injected delivery failure")
+	}
+
+	if err := n.deliver(ctx, results, false); err != nil {
+		return err
+	}
+
+	if n.roll(n.duplicateRate) {
+		n.logger.Info("Chaos: duplicating delivery", zap.Int("results", len(results)))
+		return n.deliver(ctx, results, true)
+	}
+	return nil
+}
+
+// deliver records a delivery and forwards it to the url, if any
+func (n *ChaosNotifier) deliver(ctx context.Context, results []collectors.Result, duplicate bool) error {
+	n.mu.Lock()
+	n.sequence++
+	seq := n.sequence
+	n.mu.Unlock()
+
+	for _, result := range results {
+		n.logger.Info("Chaos: delivered",
+			zap.String("key", result.Key()),
+			zap.Bool("healthy", result.IsHealthy),
+			zap.Bool("duplicate", duplicate),
+			zap.Time("collected_at", result.Timestamp))
+	}
+
+	if n.url == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(delivery{Sequence: seq, Duplicate: duplicate, SentAt: time.Now(), Results: results})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("chaos receiver returned %s", resp.Status)
+	}
+	return nil
+}
+
+// roll returns true with the given probability
+func (n *ChaosNotifier) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.rng.Float64() < rate
+}
+
+// randomDelay returns a delay between zero and the configured maximum
+func (n *ChaosNotifier) randomDelay() time.Duration {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return time.Duration(n.rng.Int63n(int64(n.maxDelay)))
+}
+
+// Close performs any necessary cleanup
+func (n *ChaosNotifier) Close() error {
+	// No cleanup needed for chaos notifier
+	return nil
+}
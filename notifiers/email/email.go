@@ -2,13 +2,22 @@
 package email
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
 	"net/smtp"
+	"net/textproto"
 	"strings"
 	"time"
 
-	"server-monitor/collectors"
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/templates"
 
 	"go.uber.org/zap"
 )
@@ -22,6 +31,11 @@ type EmailNotifier struct {
 	username   string
 	password   string
 	auth       smtp.Auth
+	tlsMode    string
+	tlsConfig  *tls.Config
+	plainOnly  bool
+	tmpl       *template.Template // nil when plain text only
+	templates  *templates.Set
 	logger     *zap.Logger
 }
 
@@ -37,6 +51,28 @@ func (n *EmailNotifier) Name() string {
 	return "email"
 }
 
+// Schema describes the notifier and its settings
+func (n *EmailNotifier) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Email through an SMTP server",
+		Settings: []collectors.Setting{
+			{Name: "from", Type: "string", Required: true, Description: "Sender address"},
+			{Name: "to", Type: "list", Required: true, Description: "Recipient addresses"},
+			{Name: "smtp_server", Type: "string", Required: true, Description: "SMTP server host"},
+			{Name: "smtp_port", Type: "int", Required: true, Description: "SMTP server port"},
+			{Name: "username", Type: "string", Description: "SMTP username"},
+			{Name: "password", Type: "string", Description: "SMTP password"},
+			{Name: "password_file", Type: "string", Description: "File to read password from instead"},
+			{Name: "tls_mode", Type: "string", Description: "none, starttls or implicit (default: implicit on port 465, starttls with credentials)"},
+			{Name: "tls_server_name", Type: "string", Description: "Server name to verify the certificate against (default: smtp_server)"},
+			{Name: "insecure_skip_verify", Type: "bool", Default: "false", Description: "Skip verifying the server's certificate"},
+			{Name: "ca_file", Type: "string", Description: "PEM bundle of CAs to trust"},
+			{Name: "plain_text_only", Type: "bool", Default: "false", Description: "Send plain text messages only"},
+			{Name: "html_template", Type: "string", Description: "Path of a custom HTML template"},
+		},
+	}
+}
+
 // Init initializes the email notifier with configuration
 func (n *EmailNotifier) Init(config map[string]interface{}) error {
 	var ok bool
@@ -97,6 +133,32 @@ func (n *EmailNotifier) Init(config map[string]interface{}) error {
 		n.auth = smtp.PlainAuth("", n.username, n.password, host)
 	}
 
+	// Set up transport security
+	if err := n.initTLS(config); err != nil {
+		n.logger.Error("Failed to initialize email notifier", zap.Error(err))
+		return err
+	}
+
+	// Subject and plain text body templates
+	set, err := templates.FromSettings(n.logger, n.Name(), DefaultTemplates, config)
+	if err != nil {
+		n.logger.Error("Failed to initialize email notifier", zap.Error(err))
+		return err
+	}
+	n.templates = set
+
+	// HTML body template; a user template replaces the embedded default
+	n.plainOnly, _ = config["plain_text_only"].(bool)
+	if !n.plainOnly {
+		templatePath, _ := config["html_template"].(string)
+		tmpl, err := LoadTemplate(templatePath)
+		if err != nil {
+			n.logger.Error("Failed to initialize email notifier", zap.Error(err))
+			return err
+		}
+		n.tmpl = tmpl
+	}
+
 	return nil
 }
 
@@ -115,27 +177,15 @@ func (n *EmailNotifier) Notify(ctx context.Context, results []collectors.Result)
 		return nil
 	}
 
-	// Prepare email content
-	subject := fmt.Sprintf("Server Alert: %d issue(s) detected", len(unhealthyResults))
-	body := n.formatEmailBody(unhealthyResults)
+	// Prepare email content, with an HTML alternative unless disabled
+	content := Compose(n.logger, n.templates, n.tmpl, unhealthyResults)
 
-	// Compose the email
-	header := make(map[string]string)
-	header["From"] = n.from
-	header["To"] = strings.Join(n.to, ", ")
-	header["Subject"] = subject
-	header["MIME-Version"] = "1.0"
-	header["Content-Type"] = "text/plain; charset=\"utf-8\""
-	header["Content-Transfer-Encoding"] = "base64"
-
-	message := ""
-	for k, v := range header {
-		message += fmt.Sprintf("%s: %s\r\n", k, v)
+	message, err := n.buildMessage(content.Subject, content.Text, content.HTML)
+	if err != nil {
+		err := fmt.Errorf("failed to compose email: %w", err)
+		n.logger.Error("Failed to send email", zap.Error(err))
+		return err
 	}
-	message += "\r\n" + body
-
-	// Connect to the server, authenticate, and send the email
-	addr := fmt.Sprintf("%s:%d", n.smtpServer, n.smtpPort)
 
 	// Check if context is cancelled
 	select {
@@ -145,61 +195,8 @@ func (n *EmailNotifier) Notify(ctx context.Context, results []collectors.Result)
 		// Continue processing
 	}
 
-	// Send the email
-	var err error
-	if n.auth != nil {
-		err = smtp.SendMail(addr, n.auth, n.from, n.to, []byte(message))
-	} else {
-		// Connect to the server
-		client, err := smtp.Dial(addr)
-		if err != nil {
-			err := fmt.Errorf("failed to connect to SMTP server: %w", err)
-			n.logger.Error("Failed to send email", zap.Error(err))
-			return err
-		}
-		defer client.Close()
-
-		// Set the sender and recipients
-		if err := client.Mail(n.from); err != nil {
-			err := fmt.Errorf("failed to set sender: %w", err)
-			n.logger.Error("Failed to send email", zap.Error(err))
-			return err
-		}
-
-		for _, addr := range n.to {
-			if err := client.Rcpt(addr); err != nil {
-				err := fmt.Errorf("failed to set recipient: %w", err)
-				n.logger.Error("Failed to send email", zap.Error(err))
-				return err
-			}
-		}
-
-		// Send the email body
-		w, err := client.Data()
-		if err != nil {
-			err := fmt.Errorf("failed to start email data: %w", err)
-			n.logger.Error("Failed to send email", zap.Error(err))
-			return err
-		}
-
-		_, err = w.Write([]byte(message))
-		if err != nil {
-			err := fmt.Errorf("failed to write email body: %w", err)
-			n.logger.Error("Failed to send email", zap.Error(err))
-			return err
-		}
-
-		err = w.Close()
-		if err != nil {
-			err := fmt.Errorf("failed to close email data: %w", err)
-			n.logger.Error("Failed to send email", zap.Error(err))
-			return err
-		}
-
-		_ = client.Quit()
-	}
-
-	if err != nil {
+	// Connect to the server, authenticate, and send the email
+	if err := n.send(ctx, message); err != nil {
 		err := fmt.Errorf("failed to send email: %w", err)
 		n.logger.Error("Failed to send email", zap.Error(err))
 		return err
@@ -208,34 +205,86 @@ func (n *EmailNotifier) Notify(ctx context.Context, results []collectors.Result)
 	return nil
 }
 
-// formatEmailBody creates a formatted message body for the email
-func (n *EmailNotifier) formatEmailBody(results []collectors.Result) string {
-	var builder strings.Builder
-
-	builder.WriteString("The following issues were detected on the server:\n\n")
+// buildMessage composes the MIME message. With an HTML body it is sent as
+// multipart/alternative so clients without HTML support show the plain text.
+func (n *EmailNotifier) buildMessage(subject, plainBody, htmlBody string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "From: %s\r\n", n.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	if htmlBody == "" {
+		buf.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		if err := writeQuotedPrintable(&buf, plainBody); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
 
-	for i, result := range results {
-		builder.WriteString(fmt.Sprintf("%d. [%s] %s\n",
-			i+1,
-			result.Timestamp.Format(time.RFC1123),
-			result.Message))
+	mw := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", mw.Boundary())
 
-		// Add metrics if available
-		if len(result.Metrics) > 0 {
-			builder.WriteString("   Metrics:\n")
-			for key, value := range result.Metrics {
-				builder.WriteString(fmt.Sprintf("   - %s: %.2f\n", key, value))
-			}
+	parts := []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=\"utf-8\"", plainBody},
+		{"text/html; charset=\"utf-8\"", htmlBody},
+	}
+	for _, part := range parts {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.body); err != nil {
+			return nil, err
 		}
+	}
 
-		builder.WriteString("\n")
+	if err := mw.Close(); err != nil {
+		return nil, err
 	}
+	return buf.Bytes(), nil
+}
 
-	builder.WriteString("\n--\n")
-	builder.WriteString("This is an automated message from the server monitoring system.\n")
-	builder.WriteString("Please do not reply to this email.\n")
+// writeQuotedPrintable writes body to w in quoted-printable encoding
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
 
-	return builder.String()
+// DefaultTemplates are the subject and plain text body of alert emails. They
+// can be overridden with email.subject.tmpl and email.body.tmpl files in the
+// notifications templates directory.
+var DefaultTemplates = map[string]string{
+	"subject": `Server Alert: {{.Count}} issue(s) detected on {{.Hostname}}`,
+	"body": `The following issues were detected on {{.Hostname}}:
+{{range $i, $r := .Results}}
+{{add $i 1}}. [{{time "Mon, 02 Jan 2006 15:04:05 MST" $r.Timestamp}}] {{$r.Message}}
+{{- if $r.Tags}}
+   Tags:{{range $name, $value := $r.Tags}} {{$name}}={{$value}}{{end}}
+{{- end}}
+{{- if $r.Metrics}}
+   Metrics:
+{{- range $name, $value := $r.Metrics}}
+   - {{$name}}: {{metric $value}}
+{{- end}}
+{{- end}}
+{{end}}
+--
+This is an automated message from the server monitoring system.
+Please do not reply to this email.
+`,
 }
 
 // Close performs any necessary cleanup
@@ -0,0 +1,200 @@
+// notifiers/email/html.go
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"sort"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/templates"
+
+	"go.uber.org/zap"
+)
+
+//go:embed templates/alert.html
+var templateFS embed.FS
+
+// Severity colors used in the HTML body
+var severityColors = map[string]string{
+	collectors.SeverityCritical: "#d64541",
+	collectors.SeverityWarning:  "#e08e0b",
+	collectors.SeverityInfo:     "#2f80c2",
+}
+
+// htmlData is the data the HTML template is rendered with
+type htmlData struct {
+	Subject string
+	Results []htmlResult
+}
+
+// htmlResult is one result as shown in the HTML body
+type htmlResult struct {
+	Collector string
+	Severity  string
+	Color     string
+	Message   string
+	Timestamp string
+	Metrics   []htmlMetric
+	Metadata  []htmlMetadata
+}
+
+// htmlMetric is one row of a result's metrics table
+type htmlMetric struct {
+	Name      string
+	Value     string
+	Threshold string
+	Tripped   bool
+	Color     string
+}
+
+// htmlMetadata is one metadata or tag key/value pair
+type htmlMetadata struct {
+	Key   string
+	Value string
+}
+
+// Content is a rendered email: subject, plain text body and optional HTML body
+type Content struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Compose renders the email for unhealthy results. Subject and text come from
+// the notifier's template set; the HTML body is rendered from tmpl, or left
+// empty when tmpl is nil or fails to render. It is shared by the SMTP and HTTP
+// API email notifiers.
+func Compose(logger *zap.Logger, set *templates.Set, tmpl *template.Template, results []collectors.Result) Content {
+	data := templates.NewData(results)
+	content := Content{
+		Subject: set.Render("subject", data),
+		Text:    set.Render("body", data),
+	}
+
+	if tmpl != nil {
+		html, err := renderHTML(tmpl, content.Subject, results)
+		if err != nil {
+			logger.Error("Failed to render HTML email body, sending plain text only", zap.Error(err))
+		}
+		content.HTML = html
+	}
+	return content
+}
+
+// LoadTemplate parses the user's template file, or the embedded default
+func LoadTemplate(path string) (*template.Template, error) {
+	if path != "" {
+		tmpl, err := template.ParseFiles(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse email template %s: %w", path, err)
+		}
+		return tmpl, nil
+	}
+	return template.ParseFS(templateFS, "templates/alert.html")
+}
+
+// renderHTML renders the HTML body for the results
+func renderHTML(tmpl *template.Template, subject string, results []collectors.Result) (string, error) {
+	data := htmlData{Subject: subject}
+	for _, result := range results {
+		data.Results = append(data.Results, newHTMLResult(result))
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render email template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// newHTMLResult prepares a result for the template
+func newHTMLResult(result collectors.Result) htmlResult {
+	severity := result.EffectiveSeverity()
+	color := severityColors[severity]
+	if color == "" {
+		color = severityColors[collectors.SeverityCritical]
+	}
+
+	r := htmlResult{
+		Collector: result.Collector,
+		Severity:  severity,
+		Color:     color,
+		Message:   result.Message,
+		Timestamp: result.Timestamp.Format(time.RFC1123),
+	}
+
+	names := make([]string, 0, len(result.Metrics))
+	for name := range result.Metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		metric := htmlMetric{
+			Name:  name,
+			Value: fmt.Sprintf("%.2f", result.Metrics[name]),
+		}
+		for _, threshold := range result.Thresholds {
+			if threshold.Metric != name {
+				continue
+			}
+			metric.Threshold = fmt.Sprintf("%s %.2f", operatorSymbol(threshold.Operator), threshold.Value)
+			if threshold.Operator == "between" || threshold.Operator == "outside_range" {
+				metric.Threshold += fmt.Sprintf("–%.2f", threshold.Max)
+			}
+			if threshold.Tripped(result.Metrics) || threshold.Held {
+				metric.Tripped = true
+				metric.Color = severityColors[threshold.Severity]
+				if metric.Color == "" {
+					metric.Color = color
+				}
+				break
+			}
+		}
+		r.Metrics = append(r.Metrics, metric)
+	}
+
+	keys := make([]string, 0, len(result.Metadata))
+	for key := range result.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		r.Metadata = append(r.Metadata, htmlMetadata{Key: key, Value: fmt.Sprintf("%v", result.Metadata[key])})
+	}
+
+	// Tags follow, e.g. the hostname when several hosts mail one inbox
+	keys = keys[:0]
+	for key := range result.Tags {
+		if _, exists := result.Metadata[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		r.Metadata = append(r.Metadata, htmlMetadata{Key: key, Value: result.Tags[key]})
+	}
+
+	return r
+}
+
+// operatorSymbol renders a threshold operator for display
+func operatorSymbol(operator string) string {
+	switch operator {
+	case "less_than":
+		return "<"
+	case "greater_than":
+		return ">"
+	case "equals":
+		return "="
+	case "between":
+		return "in"
+	case "outside_range":
+		return "outside"
+	}
+	return operator
+}
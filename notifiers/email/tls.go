@@ -0,0 +1,141 @@
+// notifiers/email/tls.go
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Transport security modes for the SMTP connection
+const (
+	TLSModeNone     = "none"
+	TLSModeSTARTTLS = "starttls"
+	TLSModeImplicit = "implicit"
+)
+
+// dialTimeout bounds connecting to the SMTP server
+const dialTimeout = 30 * time.Second
+
+// initTLS reads the TLS settings. Without an explicit tls_mode, port 465 uses
+// implicit TLS and authenticated connections require STARTTLS, so credentials
+// are never sent in plaintext by default.
+func (n *EmailNotifier) initTLS(config map[string]interface{}) error {
+	n.tlsMode, _ = config["tls_mode"].(string)
+	if n.tlsMode == "" {
+		switch {
+		case n.smtpPort == 465:
+			n.tlsMode = TLSModeImplicit
+		case n.auth != nil:
+			n.tlsMode = TLSModeSTARTTLS
+		default:
+			n.tlsMode = TLSModeNone
+		}
+	}
+
+	switch n.tlsMode {
+	case TLSModeNone, TLSModeSTARTTLS, TLSModeImplicit:
+	default:
+		return fmt.Errorf("unknown 'tls_mode' %q, expected none, starttls or implicit", n.tlsMode)
+	}
+
+	serverName, _ := config["tls_server_name"].(string)
+	if serverName == "" {
+		serverName = n.smtpServer
+	}
+	insecure, _ := config["insecure_skip_verify"].(bool)
+
+	n.tlsConfig = &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: insecure,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	// A custom CA bundle, e.g. for internal mail relays
+	if caFile, _ := config["ca_file"].(string); caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return fmt.Errorf("failed to read 'ca_file': %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in 'ca_file' %s", caFile)
+		}
+		n.tlsConfig.RootCAs = pool
+	}
+
+	return nil
+}
+
+// send delivers a composed message over SMTP using the configured TLS mode
+func (n *EmailNotifier) send(ctx context.Context, message []byte) error {
+	addr := net.JoinHostPort(n.smtpServer, strconv.Itoa(n.smtpPort))
+
+	dialCtx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	var conn net.Conn
+	var err error
+	if n.tlsMode == TLSModeImplicit {
+		dialer := &tls.Dialer{Config: n.tlsConfig}
+		conn, err = dialer.DialContext(dialCtx, "tcp", addr)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(dialCtx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+
+	client, err := smtp.NewClient(conn, n.smtpServer)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if n.tlsMode == TLSModeSTARTTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("SMTP server does not support STARTTLS")
+		}
+		if err := client.StartTLS(n.tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	}
+
+	if n.auth != nil {
+		if err := client.Auth(n.auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	// Set the sender and recipients
+	if err := client.Mail(n.from); err != nil {
+		return fmt.Errorf("failed to set sender: %w", err)
+	}
+	for _, rcpt := range n.to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("failed to set recipient: %w", err)
+		}
+	}
+
+	// Send the email body
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to start email data: %w", err)
+	}
+	if _, err := w.Write(message); err != nil {
+		return fmt.Errorf("failed to write email body: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to close email data: %w", err)
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,293 @@
+// notifiers/emailapi/emailapi.go
+package emailapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/notifiers/email"
+	"github.com/devvspaces/simple-monit/templates"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+	"go.uber.org/zap"
+)
+
+// Supported email API providers
+const (
+	ProviderSendGrid = "sendgrid"
+	ProviderMailgun  = "mailgun"
+	ProviderSES      = "ses"
+)
+
+// EmailAPINotifier implements the Notifier interface by sending email through
+// a provider's HTTP API instead of SMTP, for hosts where outbound SMTP ports
+// are blocked. Messages are rendered exactly like the SMTP email notifier's.
+type EmailAPINotifier struct {
+	provider  string
+	apiKey    string
+	domain    string
+	region    string
+	from      string
+	to        []string
+	tmpl      *template.Template
+	templates *templates.Set
+	client    *http.Client
+	ses       *sesv2.Client
+	logger    *zap.Logger
+}
+
+// NewEmailAPINotifier creates a new email API notifier
+func NewEmailAPINotifier(logger *zap.Logger) *EmailAPINotifier {
+	return &EmailAPINotifier{
+		logger: logger,
+	}
+}
+
+// Name returns the name of the notifier
+func (n *EmailAPINotifier) Name() string {
+	return "email_api"
+}
+
+// Schema describes the notifier and its settings
+func (n *EmailAPINotifier) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Email through the HTTP API of SendGrid, Mailgun or SES",
+		Settings: []collectors.Setting{
+			{Name: "provider", Type: "string", Required: true, Description: "sendgrid, mailgun or ses"},
+			{Name: "api_key", Type: "string", Description: "API key (sendgrid and mailgun)"},
+			{Name: "api_key_file", Type: "string", Description: "File to read api_key from instead"},
+			{Name: "domain", Type: "string", Description: "Sending domain (mailgun)"},
+			{Name: "region", Type: "string", Description: "Region (mailgun: us or eu; ses: AWS region)"},
+			{Name: "from", Type: "string", Required: true, Description: "Sender address"},
+			{Name: "to", Type: "list", Required: true, Description: "Recipient addresses"},
+			{Name: "plain_text_only", Type: "bool", Default: "false", Description: "Send plain text messages only"},
+			{Name: "html_template", Type: "string", Description: "Path of a custom HTML template"},
+		},
+	}
+}
+
+// Init initializes the email API notifier with configuration
+func (n *EmailAPINotifier) Init(config map[string]interface{}) error {
+	n.provider = collectors.GetString(config, "provider", "")
+	n.apiKey = collectors.GetString(config, "api_key", "")
+	n.domain = collectors.GetString(config, "domain", "")
+	n.region = collectors.GetString(config, "region", "")
+
+	n.from = collectors.GetString(config, "from", "")
+	if n.from == "" {
+		err := fmt.Errorf("missing 'from' in email_api config")
+		n.logger.Error("Failed to initialize email_api notifier", zap.Error(err))
+		return err
+	}
+
+	to, err := collectors.GetStringSlice(config, "to", nil)
+	if err != nil || len(to) == 0 {
+		err := fmt.Errorf("no 'to' addresses in email_api config")
+		n.logger.Error("Failed to initialize email_api notifier", zap.Error(err))
+		return err
+	}
+	n.to = to
+
+	switch n.provider {
+	case ProviderSendGrid:
+		if n.apiKey == "" {
+			err := fmt.Errorf("sendgrid provider requires 'api_key'")
+			n.logger.Error("Failed to initialize email_api notifier", zap.Error(err))
+			return err
+		}
+	case ProviderMailgun:
+		if n.apiKey == "" || n.domain == "" {
+			err := fmt.Errorf("mailgun provider requires 'api_key' and 'domain'")
+			n.logger.Error("Failed to initialize email_api notifier", zap.Error(err))
+			return err
+		}
+	case ProviderSES:
+		// SES signs requests with credentials from the standard AWS chain
+		var opts []func(*awsconfig.LoadOptions) error
+		if n.region != "" {
+			opts = append(opts, awsconfig.WithRegion(n.region))
+		}
+		awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), opts...)
+		if err != nil {
+			err := fmt.Errorf("failed to load AWS configuration: %w", err)
+			n.logger.Error("Failed to initialize email_api notifier", zap.Error(err))
+			return err
+		}
+		n.ses = sesv2.NewFromConfig(awsCfg)
+	default:
+		err := fmt.Errorf("unknown email_api 'provider' %q, expected sendgrid, mailgun or ses", n.provider)
+		n.logger.Error("Failed to initialize email_api notifier", zap.Error(err))
+		return err
+	}
+
+	set, err := templates.FromSettings(n.logger, n.Name(), email.DefaultTemplates, config)
+	if err != nil {
+		n.logger.Error("Failed to initialize email_api notifier", zap.Error(err))
+		return err
+	}
+	n.templates = set
+
+	if !collectors.GetBool(config, "plain_text_only", false) {
+		tmpl, err := email.LoadTemplate(collectors.GetString(config, "html_template", ""))
+		if err != nil {
+			n.logger.Error("Failed to initialize email_api notifier", zap.Error(err))
+			return err
+		}
+		n.tmpl = tmpl
+	}
+
+	n.client = &http.Client{Timeout: 15 * time.Second}
+	return nil
+}
+
+// Notify sends one email for the unhealthy results
+func (n *EmailAPINotifier) Notify(ctx context.Context, results []collectors.Result) error {
+	var unhealthyResults []collectors.Result
+	for _, result := range results {
+		if !result.IsHealthy {
+			unhealthyResults = append(unhealthyResults, result)
+		}
+	}
+
+	if len(unhealthyResults) == 0 {
+		return nil
+	}
+
+	content := email.Compose(n.logger, n.templates, n.tmpl, unhealthyResults)
+
+	var err error
+	switch n.provider {
+	case ProviderSendGrid:
+		err = n.sendSendGrid(ctx, content)
+	case ProviderMailgun:
+		err = n.sendMailgun(ctx, content)
+	case ProviderSES:
+		err = n.sendSES(ctx, content)
+	}
+	if err != nil {
+		err := fmt.Errorf("failed to send email via %s: %w", n.provider, err)
+		n.logger.Error("Failed to send email", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// sendSendGrid sends through the SendGrid v3 mail send API
+func (n *EmailAPINotifier) sendSendGrid(ctx context.Context, content email.Content) error {
+	type address struct {
+		Email string `json:"email"`
+	}
+	type part struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+
+	recipients := make([]address, 0, len(n.to))
+	for _, addr := range n.to {
+		recipients = append(recipients, address{Email: addr})
+	}
+
+	parts := []part{{Type: "text/plain", Value: content.Text}}
+	if content.HTML != "" {
+		parts = append(parts, part{Type: "text/html", Value: content.HTML})
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"personalizations": []map[string]interface{}{{"to": recipients}},
+		"from":             address{Email: n.from},
+		"subject":          content.Subject,
+		"content":          parts,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.sendgrid.com/v3/mail/send", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+n.apiKey)
+	return n.do(req)
+}
+
+// sendMailgun sends through the Mailgun messages API
+func (n *EmailAPINotifier) sendMailgun(ctx context.Context, content email.Content) error {
+	host := "api.mailgun.net"
+	if n.region == "eu" {
+		host = "api.eu.mailgun.net"
+	}
+
+	form := url.Values{}
+	form.Set("from", n.from)
+	for _, addr := range n.to {
+		form.Add("to", addr)
+	}
+	form.Set("subject", content.Subject)
+	form.Set("text", content.Text)
+	if content.HTML != "" {
+		form.Set("html", content.HTML)
+	}
+
+	endpoint := fmt.Sprintf("https://%s/v3/%s/messages", host, url.PathEscape(n.domain))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("api", n.apiKey)
+	return n.do(req)
+}
+
+// sendSES sends through the Amazon SES v2 API
+func (n *EmailAPINotifier) sendSES(ctx context.Context, content email.Content) error {
+	body := &types.Body{Text: &types.Content{Data: aws.String(content.Text), Charset: aws.String("UTF-8")}}
+	if content.HTML != "" {
+		body.Html = &types.Content{Data: aws.String(content.HTML), Charset: aws.String("UTF-8")}
+	}
+
+	_, err := n.ses.SendEmail(ctx, &sesv2.SendEmailInput{
+		FromEmailAddress: aws.String(n.from),
+		Destination:      &types.Destination{ToAddresses: n.to},
+		Content: &types.EmailContent{
+			Simple: &types.Message{
+				Subject: &types.Content{Data: aws.String(content.Subject), Charset: aws.String("UTF-8")},
+				Body:    body,
+			},
+		},
+	})
+	return err
+}
+
+// do sends an HTTP request and checks the response status
+func (n *EmailAPINotifier) do(req *http.Request) error {
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned %s: %s", req.URL.Host, resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+// Close performs any necessary cleanup
+func (n *EmailAPINotifier) Close() error {
+	// No cleanup needed for email_api notifier
+	return nil
+}
@@ -0,0 +1,275 @@
+// notifiers/file/file.go
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/alerting"
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"go.uber.org/zap"
+)
+
+// Audit record event types
+const (
+	EventNotification = "notification"
+	EventResult       = "result"
+	EventTransition   = "transition"
+)
+
+// FileNotifier implements the Notifier interface by appending every
+// notification as a JSON line to a local audit file, so there is a durable
+// record of what was alerted even when other notifiers fail. The file is
+// rotated by size, keeping a fixed number of backups.
+type FileNotifier struct {
+	path           string
+	includeResults bool
+	maxSize        int64
+	maxBackups     int
+	file           *os.File
+	size           int64
+	mu             sync.Mutex
+	logger         *zap.Logger
+}
+
+// record is one line of the audit file
+type record struct {
+	Time        time.Time              `json:"time"`
+	Event       string                 `json:"event"`
+	From        string                 `json:"from,omitempty"`
+	To          string                 `json:"to,omitempty"`
+	Transition  string                 `json:"transition,omitempty"`
+	Collector   string                 `json:"collector"`
+	Key         string                 `json:"key"`
+	Fingerprint string                 `json:"fingerprint"`
+	Severity    string                 `json:"severity,omitempty"`
+	Healthy     bool                   `json:"healthy"`
+	Message     string                 `json:"message"`
+	Metrics     map[string]float64     `json:"metrics,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Tags        map[string]string      `json:"tags,omitempty"`
+	Timestamp   time.Time              `json:"timestamp"`
+}
+
+// NewFileNotifier creates a new file notifier
+func NewFileNotifier(logger *zap.Logger) *FileNotifier {
+	return &FileNotifier{
+		logger: logger,
+	}
+}
+
+// Name returns the name of the notifier
+func (n *FileNotifier) Name() string {
+	return "file"
+}
+
+// Schema describes the notifier and its settings
+func (n *FileNotifier) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Alerts appended to a JSON lines file",
+		Settings: []collectors.Setting{
+			{Name: "path", Type: "string", Required: true, Description: "File to append to"},
+			{Name: "include_results", Type: "bool", Default: "false", Description: "Include the results of the alert"},
+			{Name: "max_size_mb", Type: "int", Description: "Rotate the file when it grows beyond this many MB"},
+			{Name: "max_backups", Type: "int", Description: "Rotated files to keep"},
+		},
+	}
+}
+
+// Validate checks the settings without creating the audit file
+func (n *FileNotifier) Validate(config map[string]interface{}) error {
+	if collectors.GetString(config, "path", "") == "" {
+		return fmt.Errorf("missing 'path' in file notifier config")
+	}
+	return nil
+}
+
+// Init initializes the file notifier with configuration
+func (n *FileNotifier) Init(config map[string]interface{}) error {
+	n.path = collectors.GetString(config, "path", "")
+	if n.path == "" {
+		err := fmt.Errorf("missing 'path' in file notifier config")
+		n.logger.Error("Failed to initialize file notifier", zap.Error(err))
+		return err
+	}
+
+	n.includeResults = collectors.GetBool(config, "include_results", false)
+
+	maxSizeMB := collectors.GetInt(config, "max_size_mb", 0)
+	if maxSizeMB <= 0 {
+		maxSizeMB = 100
+	}
+	n.maxSize = int64(maxSizeMB) * 1024 * 1024
+
+	n.maxBackups = collectors.GetInt(config, "max_backups", 0)
+	if n.maxBackups <= 0 {
+		n.maxBackups = 5
+	}
+
+	if err := os.MkdirAll(filepath.Dir(n.path), 0o755); err != nil {
+		err := fmt.Errorf("failed to create audit directory: %w", err)
+		n.logger.Error("Failed to initialize file notifier", zap.Error(err))
+		return err
+	}
+
+	if err := n.open(); err != nil {
+		n.logger.Error("Failed to initialize file notifier", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Notify appends a notification record per alert and recovery
+func (n *FileNotifier) Notify(ctx context.Context, results []collectors.Result) error {
+	var notified []collectors.Result
+	for _, result := range results {
+		if !result.IsHealthy || result.Transition == collectors.TransitionResolved {
+			notified = append(notified, result)
+		}
+	}
+	return n.append(EventNotification, notified)
+}
+
+// Observe appends a result record for every collected result when
+// include_results is set
+func (n *FileNotifier) Observe(ctx context.Context, results []collectors.Result) error {
+	if !n.includeResults {
+		return nil
+	}
+	return n.append(EventResult, results)
+}
+
+// NotifyTransitions appends a transition record per alert lifecycle change
+func (n *FileNotifier) NotifyTransitions(ctx context.Context, events []alerting.Event) error {
+	records := make([]record, 0, len(events))
+	for _, event := range events {
+		rec := newRecord(EventTransition, event.Result)
+		rec.From = string(event.From)
+		rec.To = string(event.To)
+		records = append(records, rec)
+	}
+	return n.write(records)
+}
+
+// append writes one record per result and syncs the file
+func (n *FileNotifier) append(event string, results []collectors.Result) error {
+	records := make([]record, 0, len(results))
+	for _, result := range results {
+		records = append(records, newRecord(event, result))
+	}
+	return n.write(records)
+}
+
+// newRecord creates the audit record of a result
+func newRecord(event string, result collectors.Result) record {
+	return record{
+		Event:       event,
+		Collector:   result.Collector,
+		Key:         result.Key(),
+		Fingerprint: result.Fingerprint(),
+		Severity:    result.EffectiveSeverity(),
+		Transition:  result.Transition,
+		Healthy:     result.IsHealthy,
+		Message:     result.Message,
+		Metrics:     result.Metrics,
+		Metadata:    result.Metadata,
+		Tags:        result.Tags,
+		Timestamp:   result.Timestamp,
+	}
+}
+
+// write appends the records, stamped with the current time, and syncs the file
+func (n *FileNotifier) write(records []record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.file == nil {
+		return fmt.Errorf("audit file %s is closed", n.path)
+	}
+
+	now := time.Now()
+	for _, rec := range records {
+		rec.Time = now
+		line, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		line = append(line, '\n')
+
+		if n.size+int64(len(line)) > n.maxSize && n.size > 0 {
+			if err := n.rotate(); err != nil {
+				n.logger.Error("Failed to rotate audit file", zap.String("path", n.path), zap.Error(err))
+				return err
+			}
+		}
+
+		written, err := n.file.Write(line)
+		n.size += int64(written)
+		if err != nil {
+			n.logger.Error("Failed to write audit record", zap.String("path", n.path), zap.Error(err))
+			return err
+		}
+	}
+
+	return n.file.Sync()
+}
+
+// open opens the audit file for appending
+func (n *FileNotifier) open() error {
+	f, err := os.OpenFile(n.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to stat audit file: %w", err)
+	}
+
+	n.file = f
+	n.size = info.Size()
+	return nil
+}
+
+// rotate shifts path.1..path.N-1 up by one, moves the current file to path.1
+// and opens a fresh file
+func (n *FileNotifier) rotate() error {
+	if err := n.file.Close(); err != nil {
+		return err
+	}
+	n.file = nil
+
+	os.Remove(fmt.Sprintf("%s.%d", n.path, n.maxBackups))
+	for i := n.maxBackups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", n.path, i), fmt.Sprintf("%s.%d", n.path, i+1))
+	}
+	if err := os.Rename(n.path, n.path+".1"); err != nil {
+		return err
+	}
+
+	return n.open()
+}
+
+// Close closes the audit file
+func (n *FileNotifier) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.file == nil {
+		return nil
+	}
+	err := n.file.Close()
+	n.file = nil
+	return err
+}
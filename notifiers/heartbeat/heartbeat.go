@@ -0,0 +1,183 @@
+// notifiers/heartbeat/heartbeat.go
+package heartbeat
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"go.uber.org/zap"
+)
+
+// Supported heartbeat services
+const (
+	ProviderHealthchecks = "healthchecks"
+	ProviderSnitch       = "snitch"
+)
+
+// HeartbeatNotifier implements the Notifier interface as an external dead
+// man's switch for the monitor itself. After every collection cycle it pings
+// a healthchecks.io-style URL, or its /fail endpoint while any collector
+// reports unhealthy results. If the monitor stops running, the pings stop and
+// the external service alerts.
+type HeartbeatNotifier struct {
+	provider  string
+	url       string
+	failURL   string
+	client    *http.Client
+	unhealthy map[string]int
+	mu        sync.Mutex
+	logger    *zap.Logger
+}
+
+// NewHeartbeatNotifier creates a new heartbeat notifier
+func NewHeartbeatNotifier(logger *zap.Logger) *HeartbeatNotifier {
+	return &HeartbeatNotifier{
+		unhealthy: make(map[string]int),
+		logger:    logger,
+	}
+}
+
+// Name returns the name of the notifier
+func (n *HeartbeatNotifier) Name() string {
+	return "heartbeat"
+}
+
+// Schema describes the notifier and its settings
+func (n *HeartbeatNotifier) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Dead man's switch pinged after every collection cycle",
+		Settings: []collectors.Setting{
+			{Name: "url", Type: "string", Required: true, Description: "Ping URL"},
+			{Name: "provider", Type: "string", Default: "healthchecks", Description: "healthchecks or snitch"},
+			{Name: "fail_url", Type: "string", Description: "URL pinged while results are unhealthy"},
+		},
+	}
+}
+
+// Init initializes the heartbeat notifier with configuration
+func (n *HeartbeatNotifier) Init(config map[string]interface{}) error {
+	n.url = strings.TrimRight(collectors.GetString(config, "url", ""), "/")
+	if n.url == "" {
+		err := fmt.Errorf("missing 'url' in heartbeat config")
+		n.logger.Error("Failed to initialize heartbeat notifier", zap.Error(err))
+		return err
+	}
+
+	n.provider = collectors.GetString(config, "provider", "")
+	if n.provider == "" {
+		n.provider = ProviderHealthchecks
+	}
+
+	switch n.provider {
+	case ProviderHealthchecks:
+		n.failURL = collectors.GetString(config, "fail_url", "")
+		if n.failURL == "" {
+			n.failURL = n.url + "/fail"
+		}
+	case ProviderSnitch:
+		// Dead Man's Snitch has no failure signal; only check-ins
+	default:
+		err := fmt.Errorf("unknown heartbeat 'provider' %q, expected healthchecks or snitch", n.provider)
+		n.logger.Error("Failed to initialize heartbeat notifier", zap.Error(err))
+		return err
+	}
+
+	n.client = &http.Client{Timeout: 10 * time.Second}
+	return nil
+}
+
+// Notify does nothing; pings are sent from Observe after every cycle
+func (n *HeartbeatNotifier) Notify(ctx context.Context, results []collectors.Result) error {
+	return nil
+}
+
+// Observe records the outcome of a collection cycle and pings the service.
+// The ping is a failure while any collector's latest cycle was unhealthy, so
+// one collector recovering does not mask another one failing.
+func (n *HeartbeatNotifier) Observe(ctx context.Context, results []collectors.Result) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	// Count unhealthy results per collector in this cycle
+	cycle := make(map[string]int)
+	for _, result := range results {
+		if _, ok := cycle[result.Collector]; !ok {
+			cycle[result.Collector] = 0
+		}
+		if !result.IsHealthy {
+			cycle[result.Collector]++
+		}
+	}
+
+	n.mu.Lock()
+	for collector, count := range cycle {
+		if count > 0 {
+			n.unhealthy[collector] = count
+		} else {
+			delete(n.unhealthy, collector)
+		}
+	}
+	failing := make([]string, 0, len(n.unhealthy))
+	for collector, count := range n.unhealthy {
+		failing = append(failing, fmt.Sprintf("%s (%d)", collector, count))
+	}
+	n.mu.Unlock()
+	sort.Strings(failing)
+
+	if len(failing) == 0 {
+		return n.ping(ctx, n.url, "all checks healthy")
+	}
+
+	message := "unhealthy: " + strings.Join(failing, ", ")
+	if n.provider == ProviderSnitch {
+		return n.ping(ctx, n.url, message)
+	}
+	return n.ping(ctx, n.failURL, message)
+}
+
+// ping sends a check-in with a short status message
+func (n *HeartbeatNotifier) ping(ctx context.Context, target, message string) error {
+	var req *http.Request
+	var err error
+
+	if n.provider == ProviderSnitch {
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, target+"?m="+url.QueryEscape(message), nil)
+	} else {
+		// healthchecks.io shows the request body in the ping log
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, target, strings.NewReader(message))
+	}
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		n.logger.Error("Heartbeat ping failed", zap.String("url", target), zap.Error(err))
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode >= 300 {
+		err := fmt.Errorf("heartbeat ping returned %s", resp.Status)
+		n.logger.Error("Heartbeat ping failed", zap.String("url", target), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Close performs any necessary cleanup
+func (n *HeartbeatNotifier) Close() error {
+	// No cleanup needed for heartbeat notifier
+	return nil
+}
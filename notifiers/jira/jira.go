@@ -0,0 +1,374 @@
+// notifiers/jira/jira.go
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/templates"
+
+	"go.uber.org/zap"
+)
+
+// JiraNotifier implements the Notifier interface by tracking alerts as Jira
+// issues. Each alert fingerprint gets one issue, labelled so it can be found
+// again; repeat occurrences add comments and recovery transitions the issue.
+type JiraNotifier struct {
+	baseURL           string
+	username          string
+	apiToken          string
+	bearerToken       string
+	project           string
+	issueType         string
+	labels            []string
+	resolveTransition string
+	templates         *templates.Set
+	client            *http.Client
+	issues            map[string]string
+	searched          map[string]bool // Fingerprints looked up in Jira since the start
+	mu                sync.Mutex
+	logger            *zap.Logger
+}
+
+// defaultTemplates are the issue summary and description, the comment added
+// on repeat occurrences and the comment added on recovery
+var defaultTemplates = map[string]string{
+	"summary":     `[{{upper .Severity}}] {{.Result.Collector}}: {{.Result.Message}}`,
+	"description": descriptionTemplate,
+	"comment":     "Still failing at {{time \"2006-01-02T15:04:05Z07:00\" .Result.Timestamp}}:\n" + descriptionTemplate,
+	"recovery":    `Recovered at {{time "2006-01-02T15:04:05Z07:00" .Result.Timestamp}}: {{.Result.Message}}`,
+}
+
+// descriptionTemplate renders a result as plain text for descriptions and comments
+const descriptionTemplate = `{{.Result.Message}}
+
+Collector: {{.Result.Collector}}
+Severity: {{.Severity}}
+Target: {{key .Result}}
+Host: {{.Hostname}}
+{{if .Result.Metrics}}
+Metrics:
+{{range $name, $value := .Result.Metrics}}* {{$name}}: {{metric $value}}
+{{end}}{{end}}`
+
+// NewJiraNotifier creates a new Jira notifier
+func NewJiraNotifier(logger *zap.Logger) *JiraNotifier {
+	return &JiraNotifier{
+		issues:   make(map[string]string),
+		searched: make(map[string]bool),
+		logger:   logger,
+	}
+}
+
+// Name returns the name of the notifier
+func (n *JiraNotifier) Name() string {
+	return "jira"
+}
+
+// Schema describes the notifier and its settings
+func (n *JiraNotifier) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Jira issues opened on alerts and resolved with them",
+		Settings: []collectors.Setting{
+			{Name: "url", Type: "string", Required: true, Description: "Jira base URL"},
+			{Name: "project", Type: "string", Required: true, Description: "Project key"},
+			{Name: "username", Type: "string", Description: "Username for Jira Cloud"},
+			{Name: "api_token", Type: "string", Description: "API token for Jira Cloud"},
+			{Name: "api_token_file", Type: "string", Description: "File to read api_token from instead"},
+			{Name: "personal_access_token", Type: "string", Description: "Personal access token for Jira Server"},
+			{Name: "personal_access_token_file", Type: "string", Description: "File to read personal_access_token from instead"},
+			{Name: "issue_type", Type: "string", Description: "Issue type of new issues"},
+			{Name: "resolve_transition", Type: "string", Description: "Transition applied when the alert resolves"},
+		},
+	}
+}
+
+// Init initializes the Jira notifier with configuration
+func (n *JiraNotifier) Init(config map[string]interface{}) error {
+	n.baseURL = strings.TrimRight(collectors.GetString(config, "url", ""), "/")
+	n.project = collectors.GetString(config, "project", "")
+	if n.baseURL == "" || n.project == "" {
+		err := fmt.Errorf("missing 'url' or 'project' in jira config")
+		n.logger.Error("Failed to initialize jira notifier", zap.Error(err))
+		return err
+	}
+
+	// Jira Cloud uses email + API token; Server and Data Center personal access tokens
+	n.username = collectors.GetString(config, "username", "")
+	n.apiToken = collectors.GetString(config, "api_token", "")
+	n.bearerToken = collectors.GetString(config, "personal_access_token", "")
+	if n.bearerToken == "" && (n.username == "" || n.apiToken == "") {
+		err := fmt.Errorf("jira config needs 'username' and 'api_token', or 'personal_access_token'")
+		n.logger.Error("Failed to initialize jira notifier", zap.Error(err))
+		return err
+	}
+
+	n.issueType = collectors.GetString(config, "issue_type", "")
+	if n.issueType == "" {
+		n.issueType = "Bug"
+	}
+	n.resolveTransition = collectors.GetString(config, "resolve_transition", "")
+	if n.resolveTransition == "" {
+		n.resolveTransition = "Done"
+	}
+
+	labels, err := collectors.GetStringSlice(config, "labels", nil)
+	if err != nil {
+		n.logger.Error("Failed to initialize jira notifier", zap.Error(err))
+		return err
+	}
+	n.labels = labels
+	set, err := templates.FromSettings(n.logger, n.Name(), defaultTemplates, config)
+	if err != nil {
+		n.logger.Error("Failed to initialize jira notifier", zap.Error(err))
+		return err
+	}
+	n.templates = set
+	n.client = &http.Client{Timeout: 15 * time.Second}
+
+	return nil
+}
+
+// Notify opens an issue per new alert fingerprint and comments on known ones
+func (n *JiraNotifier) Notify(ctx context.Context, results []collectors.Result) error {
+	var errs []string
+	for _, result := range results {
+		if result.IsHealthy {
+			continue
+		}
+		if err := n.alert(ctx, result); err != nil {
+			n.logger.Error("Failed to update Jira", zap.String("collector", result.Collector), zap.Error(err))
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("jira update failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Observe resolves the issues of alerts whose targets are healthy again.
+// Issues not seen since the start, e.g. opened before a restart, are looked
+// up by their fingerprint label the first time their target is healthy.
+func (n *JiraNotifier) Observe(ctx context.Context, results []collectors.Result) error {
+	var errs []string
+	for _, result := range results {
+		if !result.IsHealthy {
+			continue
+		}
+
+		fp := fingerprint(result)
+		n.mu.Lock()
+		issueKey, open := n.issues[fp]
+		searched := n.searched[fp]
+		n.mu.Unlock()
+		if !open && !searched {
+			found, err := n.findOpenIssue(ctx, fp)
+			if err != nil {
+				n.logger.Error("Failed to find Jira issue", zap.String("label", fp), zap.Error(err))
+				errs = append(errs, err.Error())
+				continue
+			}
+			n.mu.Lock()
+			n.searched[fp] = true
+			n.mu.Unlock()
+			issueKey, open = found, found != ""
+		}
+		if !open {
+			continue
+		}
+
+		if err := n.resolve(ctx, issueKey, result); err != nil {
+			n.logger.Error("Failed to resolve Jira issue", zap.String("issue", issueKey), zap.Error(err))
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		n.mu.Lock()
+		delete(n.issues, fp)
+		n.mu.Unlock()
+		n.logger.Info("Jira issue resolved", zap.String("issue", issueKey))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("jira resolve failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// alert creates or comments on the issue for a result's fingerprint
+func (n *JiraNotifier) alert(ctx context.Context, result collectors.Result) error {
+	fp := fingerprint(result)
+
+	n.mu.Lock()
+	issueKey, known := n.issues[fp]
+	n.mu.Unlock()
+
+	// After a restart, find the open issue by its fingerprint label
+	if !known {
+		found, err := n.findOpenIssue(ctx, fp)
+		if err != nil {
+			return err
+		}
+		issueKey = found
+		n.mu.Lock()
+		n.searched[fp] = true
+		n.mu.Unlock()
+	}
+
+	if issueKey != "" {
+		comment := n.templates.RenderResult("comment", result)
+		if err := n.do(ctx, http.MethodPost, "/rest/api/2/issue/"+issueKey+"/comment", map[string]string{"body": comment}, nil); err != nil {
+			return err
+		}
+	} else {
+		created, err := n.createIssue(ctx, result, fp)
+		if err != nil {
+			return err
+		}
+		issueKey = created
+		n.logger.Info("Jira issue created", zap.String("issue", issueKey), zap.String("collector", result.Collector))
+	}
+
+	n.mu.Lock()
+	n.issues[fp] = issueKey
+	n.mu.Unlock()
+	return nil
+}
+
+// findOpenIssue returns the key of the unresolved issue with a fingerprint label
+func (n *JiraNotifier) findOpenIssue(ctx context.Context, fp string) (string, error) {
+	jql := fmt.Sprintf(`project = "%s" AND labels = "%s" AND statusCategory != Done ORDER BY created DESC`, n.project, fp)
+	path := "/rest/api/2/search?maxResults=1&fields=key&jql=" + url.QueryEscape(jql)
+
+	var resp struct {
+		Issues []struct {
+			Key string `json:"key"`
+		} `json:"issues"`
+	}
+	if err := n.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return "", err
+	}
+	if len(resp.Issues) == 0 {
+		return "", nil
+	}
+	return resp.Issues[0].Key, nil
+}
+
+// createIssue opens a new issue for a result
+func (n *JiraNotifier) createIssue(ctx context.Context, result collectors.Result, fp string) (string, error) {
+	labels := append(append([]string{}, n.labels...), "simple-monit", fp)
+
+	// Jira summaries are a single line of at most 255 characters
+	summary := strings.Join(strings.Fields(n.templates.RenderResult("summary", result)), " ")
+	if len(summary) > 250 {
+		summary = summary[:250]
+	}
+
+	body := map[string]interface{}{
+		"fields": map[string]interface{}{
+			"project":     map[string]string{"key": n.project},
+			"issuetype":   map[string]string{"name": n.issueType},
+			"summary":     summary,
+			"description": n.templates.RenderResult("description", result),
+			"labels":      labels,
+		},
+	}
+
+	var resp struct {
+		Key string `json:"key"`
+	}
+	if err := n.do(ctx, http.MethodPost, "/rest/api/2/issue", body, &resp); err != nil {
+		return "", err
+	}
+	return resp.Key, nil
+}
+
+// resolve comments on an issue and applies the resolve transition
+func (n *JiraNotifier) resolve(ctx context.Context, issueKey string, result collectors.Result) error {
+	comment := n.templates.RenderResult("recovery", result)
+	if err := n.do(ctx, http.MethodPost, "/rest/api/2/issue/"+issueKey+"/comment", map[string]string{"body": comment}, nil); err != nil {
+		return err
+	}
+
+	var resp struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"transitions"`
+	}
+	if err := n.do(ctx, http.MethodGet, "/rest/api/2/issue/"+issueKey+"/transitions", nil, &resp); err != nil {
+		return err
+	}
+
+	for _, t := range resp.Transitions {
+		if strings.EqualFold(t.Name, n.resolveTransition) {
+			body := map[string]interface{}{"transition": map[string]string{"id": t.ID}}
+			return n.do(ctx, http.MethodPost, "/rest/api/2/issue/"+issueKey+"/transitions", body, nil)
+		}
+	}
+	return fmt.Errorf("issue %s has no %q transition", issueKey, n.resolveTransition)
+}
+
+// do sends a Jira REST request and decodes the JSON response into out
+func (n *JiraNotifier) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, n.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if n.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+n.bearerToken)
+	} else {
+		req.SetBasicAuth(n.username, n.apiToken)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("jira %s %s returned %s: %s", method, path, resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+// fingerprint returns the issue label identifying a result's alert
+func fingerprint(result collectors.Result) string {
+	return "smon-" + result.Fingerprint()
+}
+
+// Close performs any necessary cleanup
+func (n *JiraNotifier) Close() error {
+	// No cleanup needed for jira notifier
+	return nil
+}
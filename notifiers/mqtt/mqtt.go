@@ -0,0 +1,240 @@
+// notifiers/mqtt/mqtt.go
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/templates"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"go.uber.org/zap"
+)
+
+// MQTTNotifier implements the Notifier interface by publishing results as
+// JSON to an MQTT topic, for Home Assistant, Node-RED and similar consumers
+type MQTTNotifier struct {
+	broker             string
+	clientID           string
+	username           string
+	password           string
+	topic              string
+	qos                byte
+	retained           bool
+	timeout            time.Duration
+	insecureSkipVerify bool
+	client             paho.Client
+	mu                 sync.Mutex
+	logger             *zap.Logger
+}
+
+// message is the JSON payload published for each result
+type message struct {
+	Host        string                 `json:"host"`
+	Collector   string                 `json:"collector"`
+	Fingerprint string                 `json:"fingerprint"`
+	Severity    string                 `json:"severity"`
+	Healthy     bool                   `json:"healthy"`
+	Transition  string                 `json:"transition,omitempty"`
+	Message     string                 `json:"message"`
+	Metrics     map[string]float64     `json:"metrics,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Tags        map[string]string      `json:"tags,omitempty"`
+	Timestamp   time.Time              `json:"timestamp"`
+}
+
+// NewMQTTNotifier creates a new MQTT notifier
+func NewMQTTNotifier(logger *zap.Logger) *MQTTNotifier {
+	return &MQTTNotifier{
+		logger: logger,
+	}
+}
+
+// Name returns the name of the notifier
+func (n *MQTTNotifier) Name() string {
+	return "mqtt"
+}
+
+// Schema describes the notifier and its settings
+func (n *MQTTNotifier) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Alerts published to an MQTT topic",
+		Settings: []collectors.Setting{
+			{Name: "broker", Type: "string", Required: true, Description: "Broker URL, e.g. tcp://localhost:1883"},
+			{Name: "client_id", Type: "string", Description: "Client ID"},
+			{Name: "username", Type: "string", Description: "Username"},
+			{Name: "password", Type: "string", Description: "Password"},
+			{Name: "password_file", Type: "string", Description: "File to read password from instead"},
+			{Name: "topic", Type: "string", Required: true, Description: "Topic to publish to"},
+			{Name: "qos", Type: "int", Default: "1", Description: "QoS level: 0, 1 or 2"},
+			{Name: "retained", Type: "bool", Default: "false", Description: "Publish retained messages"},
+			{Name: "timeout_seconds", Type: "duration", Default: "10", Description: "Timeout for connecting and publishing"},
+			{Name: "insecure_skip_verify", Type: "bool", Default: "false", Description: "Skip verifying the broker's TLS certificate"},
+		},
+	}
+}
+
+// Init initializes the MQTT notifier with configuration
+func (n *MQTTNotifier) Init(config map[string]interface{}) error {
+	n.broker = collectors.GetString(config, "broker", "")
+	if n.broker == "" {
+		err := fmt.Errorf("missing 'broker' in mqtt notifier config")
+		n.logger.Error("Failed to initialize mqtt notifier", zap.Error(err))
+		return err
+	}
+
+	n.clientID = collectors.GetString(config, "client_id", "")
+	if n.clientID == "" {
+		hostname, _ := os.Hostname()
+		n.clientID = "simple-monit-notifier-" + hostname
+	}
+	n.username = collectors.GetString(config, "username", "")
+	n.password = collectors.GetString(config, "password", "")
+	n.topic = collectors.GetString(config, "topic", "")
+	if n.topic == "" {
+		n.topic = "monit/{host}/{collector}"
+	}
+	n.retained = collectors.GetBool(config, "retained", false)
+	n.insecureSkipVerify = collectors.GetBool(config, "insecure_skip_verify", false)
+
+	qos := collectors.GetInt(config, "qos", 1)
+	if qos < 0 || qos > 2 {
+		err := fmt.Errorf("'qos' in mqtt notifier config must be 0, 1 or 2")
+		n.logger.Error("Failed to initialize mqtt notifier", zap.Error(err))
+		return err
+	}
+	n.qos = byte(qos)
+
+	timeoutSeconds := collectors.GetInt(config, "timeout_seconds", 0)
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = 10
+	}
+	n.timeout = time.Duration(timeoutSeconds) * time.Second
+
+	return nil
+}
+
+// Notify publishes each result to its topic
+func (n *MQTTNotifier) Notify(ctx context.Context, results []collectors.Result) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	client, err := n.connect(ctx)
+	if err != nil {
+		n.logger.Error("Failed to connect to MQTT broker", zap.String("broker", n.broker), zap.Error(err))
+		return err
+	}
+
+	var errs []string
+	for _, result := range results {
+		payload, err := json.Marshal(message{
+			Host:        templates.HostOf(result),
+			Collector:   result.Collector,
+			Fingerprint: result.Fingerprint(),
+			Severity:    result.EffectiveSeverity(),
+			Healthy:     result.IsHealthy,
+			Transition:  result.Transition,
+			Message:     result.Message,
+			Metrics:     result.Metrics,
+			Metadata:    result.Metadata,
+			Tags:        result.Tags,
+			Timestamp:   result.Timestamp,
+		})
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		topic := n.topicFor(result)
+		if err := n.wait(ctx, client.Publish(topic, n.qos, n.retained, payload)); err != nil {
+			n.logger.Error("Failed to publish MQTT notification", zap.String("topic", topic), zap.Error(err))
+			errs = append(errs, fmt.Sprintf("%s: %v", topic, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("mqtt publish failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// connect returns a connected client, connecting on first use. The client
+// reconnects automatically between notifications.
+func (n *MQTTNotifier) connect(ctx context.Context) (paho.Client, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.client != nil && n.client.IsConnectionOpen() {
+		return n.client, nil
+	}
+
+	if n.client == nil {
+		opts := paho.NewClientOptions().
+			AddBroker(n.broker).
+			SetClientID(n.clientID).
+			SetConnectTimeout(n.timeout).
+			SetAutoReconnect(true).
+			SetCleanSession(true)
+		if n.username != "" {
+			opts.SetUsername(n.username)
+			opts.SetPassword(n.password)
+		}
+		if n.insecureSkipVerify {
+			opts.SetTLSConfig(&tls.Config{InsecureSkipVerify: true})
+		}
+		n.client = paho.NewClient(opts)
+	}
+
+	if err := n.wait(ctx, n.client.Connect()); err != nil {
+		return nil, err
+	}
+	return n.client, nil
+}
+
+// topicFor expands the topic template for a result. {host} is the result's
+// host, the monitor's hostname unless it was pushed; {collector}, {severity}
+// and metadata keys come from the result.
+func (n *MQTTNotifier) topicFor(result collectors.Result) string {
+	topic := strings.ReplaceAll(n.topic, "{host}", templates.HostOf(result))
+	for name, value := range result.Labels() {
+		// Topic levels must not contain separators or wildcards
+		value = strings.NewReplacer("/", "_", "+", "_", "#", "_").Replace(value)
+		topic = strings.ReplaceAll(topic, "{"+name+"}", value)
+	}
+	return topic
+}
+
+// wait blocks until the token completes, the timeout expires or the context is cancelled
+func (n *MQTTNotifier) wait(ctx context.Context, token paho.Token) error {
+	timer := time.NewTimer(n.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-timer.C:
+		return fmt.Errorf("timed out after %s", n.timeout)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close disconnects from the broker
+func (n *MQTTNotifier) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.client != nil {
+		n.client.Disconnect(250)
+		n.client = nil
+	}
+	return nil
+}
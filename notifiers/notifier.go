@@ -4,7 +4,8 @@ package notifiers
 import (
 	"context"
 
-	"server-monitor/collectors"
+	"github.com/devvspaces/simple-monit/alerting"
+	"github.com/devvspaces/simple-monit/collectors"
 )
 
 // Notifier defines the interface that all notification methods must implement
@@ -15,9 +16,35 @@ type Notifier interface {
 	// Init initializes the notifier with its configuration
 	Init(config map[string]interface{}) error
 
-	// Notify sends an alert notification for the provided results
+	// Notify sends an alert notification for the provided results: unhealthy
+	// results, and healthy ones resolving an alert the notifier was sent.
+	// Results starting or resolving an alert carry the Transition.
 	Notify(ctx context.Context, results []collectors.Result) error
 
 	// Close performs any necessary cleanup operations
 	Close() error
 }
+
+// ResultObserver is implemented by notifiers that also want every collected
+// result, healthy or not and before mute and inhibition rules apply, e.g. to
+// keep an audit record or track recoveries. Observe is called after each
+// collector run in addition to Notify.
+type ResultObserver interface {
+	Observe(ctx context.Context, results []collectors.Result) error
+}
+
+// Validator is implemented by notifiers with side effects in Init, such as
+// the file notifier creating its directory. Validate only checks the
+// configuration.
+type Validator interface {
+	Validate(config map[string]interface{}) error
+}
+
+// TransitionNotifier is implemented by notifiers that audit every alert
+// lifecycle transition (pending, firing, resolved). Like Observe,
+// transitions are delivered before mute and inhibition rules apply, after
+// each collector run, so it is not for sending alerts: Notify receives the
+// firing and resolved results that pass the rules and routes.
+type TransitionNotifier interface {
+	NotifyTransitions(ctx context.Context, events []alerting.Event) error
+}
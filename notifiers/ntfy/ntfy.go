@@ -0,0 +1,221 @@
+// notifiers/ntfy/ntfy.go
+package ntfy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/templates"
+
+	"go.uber.org/zap"
+)
+
+// defaultServer is the public ntfy instance
+const defaultServer = "https://ntfy.sh"
+
+// NtfyNotifier implements the Notifier interface for ntfy push notifications.
+// It works with ntfy.sh as well as self-hosted servers.
+type NtfyNotifier struct {
+	server    string
+	topic     string
+	token     string
+	username  string
+	password  string
+	priority  int
+	tags      []string
+	clickURL  string
+	templates *templates.Set
+	client    *http.Client
+	logger    *zap.Logger
+}
+
+// NewNtfyNotifier creates a new ntfy notifier
+func NewNtfyNotifier(logger *zap.Logger) *NtfyNotifier {
+	return &NtfyNotifier{
+		logger: logger,
+	}
+}
+
+// Name returns the name of the notifier
+func (n *NtfyNotifier) Name() string {
+	return "ntfy"
+}
+
+// Schema describes the notifier and its settings
+func (n *NtfyNotifier) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Push notifications through ntfy",
+		Settings: []collectors.Setting{
+			{Name: "server", Type: "string", Default: "https://ntfy.sh", Description: "ntfy server URL"},
+			{Name: "topic", Type: "string", Required: true, Description: "Topic to publish to"},
+			{Name: "token", Type: "string", Description: "Access token"},
+			{Name: "token_file", Type: "string", Description: "File to read token from instead"},
+			{Name: "username", Type: "string", Description: "Username"},
+			{Name: "password", Type: "string", Description: "Password"},
+			{Name: "password_file", Type: "string", Description: "File to read password from instead"},
+			{Name: "priority", Type: "int", Description: "Priority from 1 to 5 (default: from the severity)"},
+			{Name: "tags", Type: "list", Description: "Tags or emoji shortcodes"},
+			{Name: "click_url", Type: "string", Description: "URL opened when the notification is clicked"},
+		},
+	}
+}
+
+// Init initializes the ntfy notifier with configuration
+func (n *NtfyNotifier) Init(config map[string]interface{}) error {
+	n.server = strings.TrimRight(collectors.GetString(config, "server", defaultServer), "/")
+
+	n.topic = strings.Trim(collectors.GetString(config, "topic", ""), "/")
+	if n.topic == "" {
+		err := fmt.Errorf("missing 'topic' in ntfy config")
+		n.logger.Error("Failed to initialize ntfy notifier", zap.Error(err))
+		return err
+	}
+
+	// Access token takes precedence over basic auth; both are optional
+	n.token = collectors.GetString(config, "token", "")
+	n.username = collectors.GetString(config, "username", "")
+	n.password = collectors.GetString(config, "password", "")
+
+	n.priority = collectors.GetInt(config, "priority", 0)
+	if n.priority < 0 || n.priority > 5 {
+		err := fmt.Errorf("'priority' in ntfy config must be between 1 and 5")
+		n.logger.Error("Failed to initialize ntfy notifier", zap.Error(err))
+		return err
+	}
+
+	tags, err := collectors.GetStringSlice(config, "tags", nil)
+	if err != nil {
+		n.logger.Error("Failed to initialize ntfy notifier", zap.Error(err))
+		return err
+	}
+	n.tags = tags
+
+	n.clickURL = collectors.GetString(config, "click_url", "")
+
+	set, err := templates.FromSettings(n.logger, n.Name(), defaultTemplates, config)
+	if err != nil {
+		n.logger.Error("Failed to initialize ntfy notifier", zap.Error(err))
+		return err
+	}
+	n.templates = set
+
+	n.client = &http.Client{Timeout: 10 * time.Second}
+	return nil
+}
+
+// defaultTemplates are the title and body of each ntfy message
+var defaultTemplates = map[string]string{
+	"title":   `Server Alert: {{.Result.Collector}} ({{.Severity}})`,
+	"message": `{{.Result.Message}}`,
+}
+
+// Notify publishes one ntfy message per unhealthy result
+func (n *NtfyNotifier) Notify(ctx context.Context, results []collectors.Result) error {
+	var errs []string
+	for _, result := range results {
+		if result.IsHealthy {
+			continue
+		}
+
+		if err := n.publish(ctx, result); err != nil {
+			n.logger.Error("Failed to publish ntfy message", zap.String("collector", result.Collector), zap.Error(err))
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("ntfy publish failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// publish sends a single result to the configured topic
+func (n *NtfyNotifier) publish(ctx context.Context, result collectors.Result) error {
+	url := n.server + "/" + n.topic
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(n.templates.RenderResult("message", result)))
+	if err != nil {
+		return err
+	}
+
+	severity := result.EffectiveSeverity()
+	req.Header.Set("X-Title", n.templates.RenderResult("title", result))
+	req.Header.Set("X-Priority", strconv.Itoa(n.priorityFor(severity)))
+	req.Header.Set("X-Tags", strings.Join(append([]string{severityEmoji(severity)}, n.tags...), ","))
+	if click := n.clickFor(result); click != "" {
+		req.Header.Set("X-Click", click)
+	}
+
+	if n.token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.token)
+	} else if n.username != "" {
+		req.SetBasicAuth(n.username, n.password)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("ntfy returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// priorityFor returns the configured priority, or one derived from severity
+func (n *NtfyNotifier) priorityFor(severity string) int {
+	if n.priority > 0 {
+		return n.priority
+	}
+
+	switch severity {
+	case collectors.SeverityCritical:
+		return 5
+	case collectors.SeverityWarning:
+		return 4
+	default:
+		return 3
+	}
+}
+
+// clickFor returns the click-through URL for a result. The click_url template
+// may reference result labels as {name}, e.g. https://grafana/d/x?var-path={path};
+// without a template the result's "url" metadata is used if present.
+func (n *NtfyNotifier) clickFor(result collectors.Result) string {
+	labels := result.Labels()
+	if n.clickURL == "" {
+		return labels["url"]
+	}
+
+	click := n.clickURL
+	for name, value := range labels {
+		click = strings.ReplaceAll(click, "{"+name+"}", value)
+	}
+	return click
+}
+
+// Close performs any necessary cleanup
+func (n *NtfyNotifier) Close() error {
+	// No cleanup needed for ntfy notifier
+	return nil
+}
+
+// severityEmoji maps a severity to an ntfy emoji tag
+func severityEmoji(severity string) string {
+	switch severity {
+	case collectors.SeverityCritical:
+		return "rotating_light"
+	case collectors.SeverityWarning:
+		return "warning"
+	default:
+		return "information_source"
+	}
+}
@@ -0,0 +1,252 @@
+// notifiers/plugin/plugin.go
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sync"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/notifiers"
+	"github.com/devvspaces/simple-monit/notifiers/plugin/notifierpb"
+
+	"github.com/hashicorp/go-hclog"
+	goplugin "github.com/hashicorp/go-plugin"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// Handshake is checked by both sides before a plugin is used. Bump
+// ProtocolVersion when notifier.proto changes incompatibly; plugins built for
+// another version are refused.
+var Handshake = goplugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "SIMPLE_MONIT_PLUGIN",
+	MagicCookieValue: "notifier",
+}
+
+// pluginName is the name the notifier is dispensed under
+const pluginName = "notifier"
+
+// Serve runs a notifier as a plugin; call it from the plugin program's main.
+// It does not return.
+func Serve(notifier notifiers.Notifier) {
+	goplugin.Serve(&goplugin.ServeConfig{
+		HandshakeConfig: Handshake,
+		Plugins:         goplugin.PluginSet{pluginName: &grpcPlugin{impl: notifier}},
+		GRPCServer:      goplugin.DefaultGRPCServer,
+	})
+}
+
+// grpcPlugin connects the Notifier gRPC service to go-plugin
+type grpcPlugin struct {
+	goplugin.Plugin
+	impl notifiers.Notifier // Set on the plugin side only
+}
+
+// GRPCServer registers the plugin's notifier
+func (p *grpcPlugin) GRPCServer(broker *goplugin.GRPCBroker, s *grpc.Server) error {
+	notifierpb.RegisterNotifierServer(s, &server{impl: p.impl})
+	return nil
+}
+
+// GRPCClient returns the monitor's client of the plugin's notifier
+func (p *grpcPlugin) GRPCClient(ctx context.Context, broker *goplugin.GRPCBroker, conn *grpc.ClientConn) (interface{}, error) {
+	return notifierpb.NewNotifierClient(conn), nil
+}
+
+// server serves a notifier inside the plugin program
+type server struct {
+	notifierpb.UnimplementedNotifierServer
+	impl notifiers.Notifier
+}
+
+// Init decodes the settings and initializes the notifier
+func (s *server) Init(ctx context.Context, req *notifierpb.InitRequest) (*notifierpb.InitResponse, error) {
+	settings := map[string]interface{}{}
+	if err := json.Unmarshal(req.GetSettingsJson(), &settings); err != nil {
+		return nil, fmt.Errorf("invalid settings: %w", err)
+	}
+	return &notifierpb.InitResponse{}, s.impl.Init(settings)
+}
+
+// Notify decodes the results and notifies
+func (s *server) Notify(ctx context.Context, req *notifierpb.NotifyRequest) (*notifierpb.NotifyResponse, error) {
+	var results []collectors.Result
+	if err := json.Unmarshal(req.GetResultsJson(), &results); err != nil {
+		return nil, fmt.Errorf("invalid results: %w", err)
+	}
+	return &notifierpb.NotifyResponse{}, s.impl.Notify(ctx, results)
+}
+
+// Close closes the notifier
+func (s *server) Close(ctx context.Context, req *notifierpb.CloseRequest) (*notifierpb.CloseResponse, error) {
+	return &notifierpb.CloseResponse{}, s.impl.Close()
+}
+
+// PluginNotifier is a notifier implemented by a plugin program, run as a
+// child process and called over gRPC. A plugin that crashes only fails the
+// notification in flight; it is started again, and re-initialized, on the
+// next one.
+type PluginNotifier struct {
+	name     string
+	command  string
+	settings []byte
+	client   *goplugin.Client
+	notifier notifierpb.NotifierClient
+	mu       sync.Mutex
+	logger   *zap.Logger
+}
+
+// NewPluginNotifier creates a plugin notifier registered under name
+func NewPluginNotifier(logger *zap.Logger, name string) *PluginNotifier {
+	return &PluginNotifier{name: name, logger: logger}
+}
+
+// Name returns the name of the notifier
+func (n *PluginNotifier) Name() string {
+	return n.name
+}
+
+// Schema describes the notifier and its settings
+func (n *PluginNotifier) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Notifications handled by an external plugin program",
+		Settings: []collectors.Setting{
+			{Name: "command", Type: "string", Required: true, Description: "Path of the plugin executable"},
+		},
+	}
+}
+
+// Validate checks that the plugin program exists without starting it
+func (n *PluginNotifier) Validate(config map[string]interface{}) error {
+	command, _ := config["command"].(string)
+	if command == "" {
+		return fmt.Errorf("plugin notifier %s has no command", n.name)
+	}
+	if _, err := exec.LookPath(command); err != nil {
+		return fmt.Errorf("invalid 'command': %w", err)
+	}
+	return nil
+}
+
+// Init starts the plugin program and initializes it with the notifier's
+// settings. The command setting is filled in from the configuration; the
+// plugin receives the other settings.
+func (n *PluginNotifier) Init(config map[string]interface{}) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.command, _ = config["command"].(string)
+	if n.command == "" {
+		err := fmt.Errorf("plugin notifier %s has no command", n.name)
+		n.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+	settings := make(map[string]interface{}, len(config))
+	for key, value := range config {
+		if key != "command" {
+			settings[key] = value
+		}
+	}
+	encoded, err := json.Marshal(settings)
+	if err != nil {
+		n.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+	n.settings = encoded
+
+	n.stop()
+	if err := n.start(); err != nil {
+		n.logger.Error("Init error", zap.String("command", n.command), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Notify sends the results to the plugin, restarting it first if it exited
+func (n *PluginNotifier) Notify(ctx context.Context, results []collectors.Result) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.client == nil || n.client.Exited() {
+		n.logger.Warn("Restarting plugin", zap.String("notifier", n.name), zap.String("command", n.command))
+		n.stop()
+		if err := n.start(); err != nil {
+			return err
+		}
+	}
+
+	encoded, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+	if _, err := n.notifier.Notify(ctx, &notifierpb.NotifyRequest{ResultsJson: encoded}); err != nil {
+		n.logger.Error("Plugin failed to notify", zap.String("notifier", n.name), zap.Error(err))
+		return fmt.Errorf("plugin %s: %w", n.name, err)
+	}
+	return nil
+}
+
+// Close closes the plugin's notifier and stops the program
+func (n *PluginNotifier) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.client == nil {
+		return nil
+	}
+	var err error
+	if !n.client.Exited() {
+		_, err = n.notifier.Close(context.Background(), &notifierpb.CloseRequest{})
+	}
+	n.stop()
+	return err
+}
+
+// start launches the program, checks its handshake and initializes it;
+// n.mu must be held
+func (n *PluginNotifier) start() error {
+	client := goplugin.NewClient(&goplugin.ClientConfig{
+		HandshakeConfig:  Handshake,
+		Plugins:          goplugin.PluginSet{pluginName: &grpcPlugin{}},
+		Cmd:              exec.Command(n.command),
+		AllowedProtocols: []goplugin.Protocol{goplugin.ProtocolGRPC},
+		Logger: hclog.New(&hclog.LoggerOptions{
+			Name:   "plugin." + n.name,
+			Output: zap.NewStdLog(n.logger).Writer(),
+			Level:  hclog.Info,
+		}),
+	})
+
+	rpcClient, err := client.Client()
+	if err != nil {
+		client.Kill()
+		return fmt.Errorf("failed to start plugin %s: %w", n.command, err)
+	}
+	raw, err := rpcClient.Dispense(pluginName)
+	if err != nil {
+		client.Kill()
+		return fmt.Errorf("plugin %s does not serve a notifier: %w", n.command, err)
+	}
+	n.client = client
+	n.notifier = raw.(notifierpb.NotifierClient)
+
+	if _, err := n.notifier.Init(context.Background(), &notifierpb.InitRequest{Name: n.name, SettingsJson: n.settings}); err != nil {
+		n.stop()
+		return fmt.Errorf("plugin %s failed to initialize: %w", n.name, err)
+	}
+	return nil
+}
+
+// stop kills the program; n.mu must be held
+func (n *PluginNotifier) stop() {
+	if n.client == nil {
+		return
+	}
+	n.client.Kill()
+	n.client = nil
+	n.notifier = nil
+}
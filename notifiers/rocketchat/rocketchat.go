@@ -0,0 +1,233 @@
+// notifiers/rocketchat/rocketchat.go
+package rocketchat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/templates"
+
+	"go.uber.org/zap"
+)
+
+// Attachment colors per severity
+var severityColors = map[string]string{
+	collectors.SeverityCritical: "#d9534f",
+	collectors.SeverityWarning:  "#f0ad4e",
+	collectors.SeverityInfo:     "#5bc0de",
+}
+
+// RocketChatNotifier implements the Notifier interface for Rocket.Chat
+// incoming webhook integrations
+type RocketChatNotifier struct {
+	webhookURL string
+	channel    string
+	alias      string
+	emoji      string
+	avatar     string
+	templates  *templates.Set
+	client     *http.Client
+	logger     *zap.Logger
+}
+
+// message is the body of a Rocket.Chat incoming webhook request
+type message struct {
+	Text        string       `json:"text"`
+	Channel     string       `json:"channel,omitempty"`
+	Alias       string       `json:"alias,omitempty"`
+	Emoji       string       `json:"emoji,omitempty"`
+	Avatar      string       `json:"avatar,omitempty"`
+	Attachments []attachment `json:"attachments"`
+}
+
+// attachment renders one result
+type attachment struct {
+	Title  string  `json:"title"`
+	Text   string  `json:"text"`
+	Color  string  `json:"color"`
+	TS     string  `json:"ts"`
+	Fields []field `json:"fields,omitempty"`
+}
+
+// field is a short key/value pair shown in an attachment
+type field struct {
+	Short bool   `json:"short"`
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// NewRocketChatNotifier creates a new Rocket.Chat notifier
+func NewRocketChatNotifier(logger *zap.Logger) *RocketChatNotifier {
+	return &RocketChatNotifier{
+		logger: logger,
+	}
+}
+
+// Name returns the name of the notifier
+func (n *RocketChatNotifier) Name() string {
+	return "rocketchat"
+}
+
+// Schema describes the notifier and its settings
+func (n *RocketChatNotifier) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Messages to a Rocket.Chat incoming webhook",
+		Settings: []collectors.Setting{
+			{Name: "webhook_url", Type: "string", Required: true, Description: "Incoming webhook URL"},
+			{Name: "webhook_url_file", Type: "string", Description: "File to read webhook_url from instead"},
+			{Name: "channel", Type: "string", Description: "Channel overriding the webhook's"},
+			{Name: "alias", Type: "string", Description: "Name the messages are posted as"},
+			{Name: "emoji", Type: "string", Description: "Emoji used as avatar"},
+			{Name: "avatar", Type: "string", Description: "URL of the avatar image"},
+		},
+	}
+}
+
+// Init initializes the Rocket.Chat notifier with configuration
+func (n *RocketChatNotifier) Init(config map[string]interface{}) error {
+	n.webhookURL = collectors.GetString(config, "webhook_url", "")
+	if n.webhookURL == "" {
+		err := fmt.Errorf("missing 'webhook_url' in rocketchat config")
+		n.logger.Error("Failed to initialize rocketchat notifier", zap.Error(err))
+		return err
+	}
+
+	n.channel = collectors.GetString(config, "channel", "")
+	n.alias = collectors.GetString(config, "alias", "")
+	if n.alias == "" {
+		n.alias = "simple-monit"
+	}
+	n.emoji = collectors.GetString(config, "emoji", "")
+	if n.emoji == "" {
+		n.emoji = ":rotating_light:"
+	}
+	n.avatar = collectors.GetString(config, "avatar", "")
+	set, err := templates.FromSettings(n.logger, n.Name(), defaultTemplates, config)
+	if err != nil {
+		n.logger.Error("Failed to initialize rocketchat notifier", zap.Error(err))
+		return err
+	}
+	n.templates = set
+	n.client = &http.Client{Timeout: 10 * time.Second}
+
+	return nil
+}
+
+// defaultTemplates are the message text and each result's attachment title and text
+var defaultTemplates = map[string]string{
+	"text":       `Server Alert: {{.Count}} issue(s) detected on {{.Hostname}}`,
+	"title":      `{{.Result.Collector}} ({{.Severity}})`,
+	"attachment": `{{.Result.Message}}`,
+}
+
+// Notify posts one message with an attachment per unhealthy result
+func (n *RocketChatNotifier) Notify(ctx context.Context, results []collectors.Result) error {
+	var unhealthyResults []collectors.Result
+	var attachments []attachment
+	for _, result := range results {
+		if !result.IsHealthy {
+			unhealthyResults = append(unhealthyResults, result)
+			attachments = append(attachments, n.attachmentFor(result))
+		}
+	}
+
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	msg := message{
+		Text:        n.templates.RenderResults("text", unhealthyResults),
+		Channel:     n.channel,
+		Alias:       n.alias,
+		Emoji:       n.emoji,
+		Avatar:      n.avatar,
+		Attachments: attachments,
+	}
+	// Rocket.Chat ignores emoji when an avatar is set
+	if n.avatar != "" {
+		msg.Emoji = ""
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		n.logger.Error("Failed to send Rocket.Chat message", zap.Error(err))
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err := fmt.Errorf("rocketchat returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+		n.logger.Error("Failed to send Rocket.Chat message", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// attachmentFor maps a result to an attachment: metrics and metadata become fields
+func (n *RocketChatNotifier) attachmentFor(result collectors.Result) attachment {
+	severity := result.EffectiveSeverity()
+
+	a := attachment{
+		Title: n.templates.RenderResult("title", result),
+		Text:  n.templates.RenderResult("attachment", result),
+		Color: severityColors[severity],
+		TS:    result.Timestamp.Format(time.RFC3339),
+	}
+
+	metricNames := make([]string, 0, len(result.Metrics))
+	for name := range result.Metrics {
+		metricNames = append(metricNames, name)
+	}
+	sort.Strings(metricNames)
+	for _, name := range metricNames {
+		a.Fields = append(a.Fields, field{Short: true, Title: name, Value: fmt.Sprintf("%.2f", result.Metrics[name])})
+	}
+
+	metaKeys := make([]string, 0, len(result.Metadata))
+	for key := range result.Metadata {
+		metaKeys = append(metaKeys, key)
+	}
+	sort.Strings(metaKeys)
+	for _, key := range metaKeys {
+		a.Fields = append(a.Fields, field{Short: true, Title: key, Value: fmt.Sprintf("%v", result.Metadata[key])})
+	}
+
+	tagKeys := make([]string, 0, len(result.Tags))
+	for key := range result.Tags {
+		if _, exists := result.Metadata[key]; !exists {
+			tagKeys = append(tagKeys, key)
+		}
+	}
+	sort.Strings(tagKeys)
+	for _, key := range tagKeys {
+		a.Fields = append(a.Fields, field{Short: true, Title: key, Value: result.Tags[key]})
+	}
+
+	return a
+}
+
+// Close performs any necessary cleanup
+func (n *RocketChatNotifier) Close() error {
+	// No cleanup needed for rocketchat notifier
+	return nil
+}
@@ -0,0 +1,235 @@
+// notifiers/signal/signal.go
+package signal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/templates"
+
+	"go.uber.org/zap"
+)
+
+// Supported signal-cli daemon APIs
+const (
+	APIRest    = "rest"
+	APIJSONRPC = "jsonrpc"
+)
+
+// SignalNotifier implements the Notifier interface for Signal messages sent
+// through a signal-cli daemon, either the signal-cli-rest-api wrapper or
+// signal-cli's own JSON-RPC HTTP endpoint
+type SignalNotifier struct {
+	api        string
+	url        string
+	number     string
+	recipients []string
+	groups     []string
+	templates  *templates.Set
+	client     *http.Client
+	rpcID      atomic.Int64
+	logger     *zap.Logger
+}
+
+// NewSignalNotifier creates a new Signal notifier
+func NewSignalNotifier(logger *zap.Logger) *SignalNotifier {
+	return &SignalNotifier{
+		logger: logger,
+	}
+}
+
+// Name returns the name of the notifier
+func (n *SignalNotifier) Name() string {
+	return "signal"
+}
+
+// Schema describes the notifier and its settings
+func (n *SignalNotifier) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Messages through a signal-cli REST or JSON-RPC API",
+		Settings: []collectors.Setting{
+			{Name: "api", Type: "string", Default: "rest", Description: "rest or jsonrpc"},
+			{Name: "url", Type: "string", Required: true, Description: "URL of the API"},
+			{Name: "number", Type: "string", Required: true, Description: "Registered number messages are sent from"},
+			{Name: "recipients", Type: "list", Description: "Phone numbers to message"},
+			{Name: "groups", Type: "list", Description: "Group IDs to message"},
+		},
+	}
+}
+
+// Init initializes the Signal notifier with configuration
+func (n *SignalNotifier) Init(config map[string]interface{}) error {
+	n.api = collectors.GetString(config, "api", "")
+	if n.api == "" {
+		n.api = APIRest
+	}
+	if n.api != APIRest && n.api != APIJSONRPC {
+		err := fmt.Errorf("unknown signal 'api' %q, expected %s or %s", n.api, APIRest, APIJSONRPC)
+		n.logger.Error("Failed to initialize signal notifier", zap.Error(err))
+		return err
+	}
+
+	n.url = strings.TrimRight(collectors.GetString(config, "url", ""), "/")
+	if n.url == "" {
+		err := fmt.Errorf("missing 'url' in signal config")
+		n.logger.Error("Failed to initialize signal notifier", zap.Error(err))
+		return err
+	}
+
+	n.number = collectors.GetString(config, "number", "")
+	if n.number == "" {
+		err := fmt.Errorf("missing 'number' in signal config")
+		n.logger.Error("Failed to initialize signal notifier", zap.Error(err))
+		return err
+	}
+
+	var err error
+	if n.recipients, err = collectors.GetStringSlice(config, "recipients", nil); err != nil {
+		n.logger.Error("Failed to initialize signal notifier", zap.Error(err))
+		return err
+	}
+	if n.groups, err = collectors.GetStringSlice(config, "groups", nil); err != nil {
+		n.logger.Error("Failed to initialize signal notifier", zap.Error(err))
+		return err
+	}
+	if len(n.recipients) == 0 && len(n.groups) == 0 {
+		err := fmt.Errorf("signal config needs at least one of 'recipients' or 'groups'")
+		n.logger.Error("Failed to initialize signal notifier", zap.Error(err))
+		return err
+	}
+
+	if n.templates, err = templates.FromSettings(n.logger, n.Name(), defaultTemplates, config); err != nil {
+		n.logger.Error("Failed to initialize signal notifier", zap.Error(err))
+		return err
+	}
+
+	n.client = &http.Client{Timeout: 15 * time.Second}
+	return nil
+}
+
+// defaultTemplates is the plain text message body
+var defaultTemplates = map[string]string{
+	"message": `Server Alert: {{.Count}} issue(s) detected on {{.Hostname}}
+{{range .Results}}
+[{{upper (severity .)}}] {{.Collector}}: {{.Message}}{{end}}`,
+}
+
+// Notify sends one Signal message summarizing the unhealthy results to every
+// recipient and group
+func (n *SignalNotifier) Notify(ctx context.Context, results []collectors.Result) error {
+	var unhealthyResults []collectors.Result
+	for _, result := range results {
+		if !result.IsHealthy {
+			unhealthyResults = append(unhealthyResults, result)
+		}
+	}
+
+	if len(unhealthyResults) == 0 {
+		return nil
+	}
+
+	text := n.templates.RenderResults("message", unhealthyResults)
+
+	var err error
+	if n.api == APIJSONRPC {
+		err = n.sendJSONRPC(ctx, text)
+	} else {
+		err = n.sendREST(ctx, text)
+	}
+	if err != nil {
+		n.logger.Error("Failed to send Signal message", zap.String("api", n.api), zap.Error(err))
+	}
+	return err
+}
+
+// sendREST sends the message through signal-cli-rest-api. Groups are
+// addressed by their "group.<id>" identifiers alongside phone numbers.
+func (n *SignalNotifier) sendREST(ctx context.Context, text string) error {
+	body := map[string]interface{}{
+		"message":    text,
+		"number":     n.number,
+		"recipients": append(append([]string{}, n.recipients...), n.groups...),
+	}
+	_, err := n.post(ctx, n.url+"/v2/send", body)
+	return err
+}
+
+// sendJSONRPC sends the message through signal-cli's JSON-RPC endpoint. A
+// group message is a separate call per group.
+func (n *SignalNotifier) sendJSONRPC(ctx context.Context, text string) error {
+	var calls []map[string]interface{}
+	if len(n.recipients) > 0 {
+		calls = append(calls, map[string]interface{}{"account": n.number, "recipient": n.recipients, "message": text})
+	}
+	for _, group := range n.groups {
+		calls = append(calls, map[string]interface{}{"account": n.number, "groupId": group, "message": text})
+	}
+
+	for _, params := range calls {
+		body := map[string]interface{}{
+			"jsonrpc": "2.0",
+			"method":  "send",
+			"params":  params,
+			"id":      n.rpcID.Add(1),
+		}
+
+		respBody, err := n.post(ctx, n.url+"/api/v1/rpc", body)
+		if err != nil {
+			return err
+		}
+
+		var resp struct {
+			Error *struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(respBody, &resp); err != nil {
+			return fmt.Errorf("invalid JSON-RPC response: %w", err)
+		}
+		if resp.Error != nil {
+			return fmt.Errorf("signal-cli error %d: %s", resp.Error.Code, resp.Error.Message)
+		}
+	}
+	return nil
+}
+
+// post sends a JSON body and returns the response body
+func (n *SignalNotifier) post(ctx context.Context, url string, body interface{}) ([]byte, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("signal daemon returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return respBody, nil
+}
+
+// Close performs any necessary cleanup
+func (n *SignalNotifier) Close() error {
+	// No cleanup needed for signal notifier
+	return nil
+}
@@ -0,0 +1,175 @@
+// notifiers/sns/sns.go
+package sns
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/templates"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"go.uber.org/zap"
+)
+
+// maxSubjectLength is the longest subject SNS accepts
+const maxSubjectLength = 100
+
+// SNSNotifier implements the Notifier interface by publishing alerts to an
+// Amazon SNS topic. Credentials come from the standard AWS credential chain:
+// environment, shared config and credentials files, or an instance/task role.
+type SNSNotifier struct {
+	topicARN  string
+	region    string
+	profile   string
+	fifo      bool
+	templates *templates.Set
+	client    *sns.Client
+	logger    *zap.Logger
+}
+
+// NewSNSNotifier creates a new SNS notifier
+func NewSNSNotifier(logger *zap.Logger) *SNSNotifier {
+	return &SNSNotifier{
+		logger: logger,
+	}
+}
+
+// Name returns the name of the notifier
+func (n *SNSNotifier) Name() string {
+	return "sns"
+}
+
+// Schema describes the notifier and its settings
+func (n *SNSNotifier) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Alerts published to an AWS SNS topic",
+		Settings: []collectors.Setting{
+			{Name: "topic_arn", Type: "string", Required: true, Description: "ARN of the topic"},
+			{Name: "region", Type: "string", Description: "AWS region (default: from the topic ARN)"},
+			{Name: "profile", Type: "string", Description: "AWS shared config profile"},
+		},
+	}
+}
+
+// Init initializes the SNS notifier with configuration
+func (n *SNSNotifier) Init(config map[string]interface{}) error {
+	n.topicARN = collectors.GetString(config, "topic_arn", "")
+	if n.topicARN == "" {
+		err := fmt.Errorf("missing 'topic_arn' in sns config")
+		n.logger.Error("Failed to initialize sns notifier", zap.Error(err))
+		return err
+	}
+	n.fifo = strings.HasSuffix(n.topicARN, ".fifo")
+
+	n.region = collectors.GetString(config, "region", "")
+	n.profile = collectors.GetString(config, "profile", "")
+
+	var opts []func(*awsconfig.LoadOptions) error
+	if n.region != "" {
+		opts = append(opts, awsconfig.WithRegion(n.region))
+	}
+	if n.profile != "" {
+		opts = append(opts, awsconfig.WithSharedConfigProfile(n.profile))
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		err := fmt.Errorf("failed to load AWS configuration: %w", err)
+		n.logger.Error("Failed to initialize sns notifier", zap.Error(err))
+		return err
+	}
+	if awsCfg.Region == "" {
+		err := fmt.Errorf("no AWS region: set 'region' in sns config or AWS_REGION")
+		n.logger.Error("Failed to initialize sns notifier", zap.Error(err))
+		return err
+	}
+
+	set, err := templates.FromSettings(n.logger, n.Name(), defaultTemplates, config)
+	if err != nil {
+		n.logger.Error("Failed to initialize sns notifier", zap.Error(err))
+		return err
+	}
+	n.templates = set
+
+	n.client = sns.NewFromConfig(awsCfg)
+	return nil
+}
+
+// defaultTemplates are the subject and body of each SNS message
+var defaultTemplates = map[string]string{
+	"subject": `Server Alert: {{.Result.Collector}} ({{.Severity}})`,
+	"message": `{{.Result.Message}}`,
+}
+
+// Notify publishes one SNS message per unhealthy result
+func (n *SNSNotifier) Notify(ctx context.Context, results []collectors.Result) error {
+	var errs []string
+	for _, result := range results {
+		if result.IsHealthy {
+			continue
+		}
+
+		severity := result.EffectiveSeverity()
+		input := &sns.PublishInput{
+			TopicArn: aws.String(n.topicARN),
+			Subject:  aws.String(n.subject(result)),
+			Message:  aws.String(n.templates.RenderResult("message", result)),
+			MessageAttributes: map[string]types.MessageAttributeValue{
+				"severity": {
+					DataType:    aws.String("String"),
+					StringValue: aws.String(severity),
+				},
+				"collector": {
+					DataType:    aws.String("String"),
+					StringValue: aws.String(result.Collector),
+				},
+			},
+		}
+
+		// FIFO topics order messages per group and deduplicate by ID
+		if n.fifo {
+			input.MessageGroupId = aws.String(result.Collector)
+			input.MessageDeduplicationId = aws.String(deduplicationID(result))
+		}
+
+		if _, err := n.client.Publish(ctx, input); err != nil {
+			n.logger.Error("Failed to publish SNS message", zap.String("collector", result.Collector), zap.Error(err))
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("sns publish failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// subject builds the message subject used by email subscriptions. SNS only
+// accepts a single line, so line breaks from the template are replaced.
+func (n *SNSNotifier) subject(result collectors.Result) string {
+	s := strings.Join(strings.Fields(n.templates.RenderResult("subject", result)), " ")
+	if len(s) > maxSubjectLength {
+		s = s[:maxSubjectLength]
+	}
+	return s
+}
+
+// deduplicationID identifies one evaluation of one target, within the 128
+// character limit SNS allows
+func deduplicationID(result collectors.Result) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s@%d", result.Key(), result.Timestamp.UnixNano())))
+	return hex.EncodeToString(sum[:])
+}
+
+// Close performs any necessary cleanup
+func (n *SNSNotifier) Close() error {
+	// No cleanup needed for sns notifier
+	return nil
+}
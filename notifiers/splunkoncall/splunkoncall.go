@@ -0,0 +1,232 @@
+// notifiers/splunkoncall/splunkoncall.go
+package splunkoncall
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/templates"
+
+	"go.uber.org/zap"
+)
+
+// defaultURL is the Splunk On-Call REST endpoint base
+const defaultURL = "https://alert.victorops.com/integrations/generic/20131114/alert"
+
+// Splunk On-Call message types
+const (
+	MessageCritical = "CRITICAL"
+	MessageWarning  = "WARNING"
+	MessageInfo     = "INFO"
+	MessageRecovery = "RECOVERY"
+)
+
+// SplunkOnCallNotifier implements the Notifier interface for the Splunk
+// On-Call (formerly VictorOps) REST alert endpoint. The result key is used as
+// entity_id, so repeated alerts for a target update one incident, and a
+// RECOVERY message resolves it when the target is healthy again.
+type SplunkOnCallNotifier struct {
+	endpoint  string
+	templates *templates.Set
+	client    *http.Client
+	open      map[string]bool
+	mu        sync.Mutex
+	logger    *zap.Logger
+}
+
+// defaultTemplates are the incident title and its state message
+var defaultTemplates = map[string]string{
+	"entity_display_name": `{{.Result.Collector}}: {{.Result.Message}}`,
+	"state_message":       `{{.Result.Message}}`,
+}
+
+// alert is the body of a Splunk On-Call REST alert
+type alert struct {
+	MessageType       string `json:"message_type"`
+	EntityID          string `json:"entity_id"`
+	EntityDisplayName string `json:"entity_display_name"`
+	StateMessage      string `json:"state_message"`
+	StateStartTime    int64  `json:"state_start_time"`
+	MonitoringTool    string `json:"monitoring_tool"`
+	Collector         string `json:"collector"`
+	Severity          string `json:"severity,omitempty"`
+}
+
+// NewSplunkOnCallNotifier creates a new Splunk On-Call notifier
+func NewSplunkOnCallNotifier(logger *zap.Logger) *SplunkOnCallNotifier {
+	return &SplunkOnCallNotifier{
+		open:   make(map[string]bool),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the notifier
+func (n *SplunkOnCallNotifier) Name() string {
+	return "splunk_oncall"
+}
+
+// Schema describes the notifier and its settings
+func (n *SplunkOnCallNotifier) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Incidents in Splunk On-Call (VictorOps)",
+		Settings: []collectors.Setting{
+			{Name: "api_key", Type: "string", Required: true, Description: "REST endpoint API key"},
+			{Name: "api_key_file", Type: "string", Description: "File to read api_key from instead"},
+			{Name: "routing_key", Type: "string", Required: true, Description: "Routing key"},
+			{Name: "url", Type: "string", Description: "REST endpoint URL overriding the default"},
+		},
+	}
+}
+
+// Init initializes the Splunk On-Call notifier with configuration
+func (n *SplunkOnCallNotifier) Init(config map[string]interface{}) error {
+	apiKey := collectors.GetString(config, "api_key", "")
+	if apiKey == "" {
+		err := fmt.Errorf("missing 'api_key' in splunk_oncall config")
+		n.logger.Error("Failed to initialize splunk_oncall notifier", zap.Error(err))
+		return err
+	}
+
+	routingKey := collectors.GetString(config, "routing_key", "")
+	if routingKey == "" {
+		err := fmt.Errorf("missing 'routing_key' in splunk_oncall config")
+		n.logger.Error("Failed to initialize splunk_oncall notifier", zap.Error(err))
+		return err
+	}
+
+	base := strings.TrimRight(collectors.GetString(config, "url", ""), "/")
+	if base == "" {
+		base = defaultURL
+	}
+	n.endpoint = base + "/" + url.PathEscape(apiKey) + "/" + url.PathEscape(routingKey)
+	set, err := templates.FromSettings(n.logger, n.Name(), defaultTemplates, config)
+	if err != nil {
+		n.logger.Error("Failed to initialize splunk_oncall notifier", zap.Error(err))
+		return err
+	}
+	n.templates = set
+	n.client = &http.Client{Timeout: 10 * time.Second}
+
+	return nil
+}
+
+// Notify sends an alert per unhealthy result
+func (n *SplunkOnCallNotifier) Notify(ctx context.Context, results []collectors.Result) error {
+	var errs []string
+	for _, result := range results {
+		if result.IsHealthy {
+			continue
+		}
+
+		if err := n.send(ctx, result, messageType(result.EffectiveSeverity())); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		n.mu.Lock()
+		n.open[result.Key()] = true
+		n.mu.Unlock()
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("splunk on-call alert failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Observe sends a RECOVERY for open incidents whose targets are healthy again
+func (n *SplunkOnCallNotifier) Observe(ctx context.Context, results []collectors.Result) error {
+	var errs []string
+	for _, result := range results {
+		if !result.IsHealthy {
+			continue
+		}
+
+		key := result.Key()
+		n.mu.Lock()
+		open := n.open[key]
+		n.mu.Unlock()
+		if !open {
+			continue
+		}
+
+		if err := n.send(ctx, result, MessageRecovery); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		n.mu.Lock()
+		delete(n.open, key)
+		n.mu.Unlock()
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("splunk on-call recovery failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// send posts one alert for a result
+func (n *SplunkOnCallNotifier) send(ctx context.Context, result collectors.Result, msgType string) error {
+	body, err := json.Marshal(alert{
+		MessageType:       msgType,
+		EntityID:          result.Fingerprint(),
+		EntityDisplayName: n.templates.RenderResult("entity_display_name", result),
+		StateMessage:      n.templates.RenderResult("state_message", result),
+		StateStartTime:    result.Timestamp.Unix(),
+		MonitoringTool:    "simple-monit",
+		Collector:         result.Collector,
+		Severity:          result.EffectiveSeverity(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		n.logger.Error("Failed to send Splunk On-Call alert", zap.String("message_type", msgType), zap.Error(err))
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err := fmt.Errorf("splunk on-call returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+		n.logger.Error("Failed to send Splunk On-Call alert", zap.String("message_type", msgType), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// messageType maps a severity to a Splunk On-Call message type
+func messageType(severity string) string {
+	switch severity {
+	case collectors.SeverityWarning:
+		return MessageWarning
+	case collectors.SeverityInfo:
+		return MessageInfo
+	default:
+		return MessageCritical
+	}
+}
+
+// Close performs any necessary cleanup
+func (n *SplunkOnCallNotifier) Close() error {
+	// No cleanup needed for splunk_oncall notifier
+	return nil
+}
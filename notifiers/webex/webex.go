@@ -0,0 +1,174 @@
+// notifiers/webex/webex.go
+package webex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/templates"
+
+	"go.uber.org/zap"
+)
+
+// defaultAPIURL is the Webex messages API
+const defaultAPIURL = "https://webexapis.com/v1/messages"
+
+// WebexNotifier implements the Notifier interface for Cisco Webex rooms,
+// posting markdown messages as a bot. Alerts can be routed to a different
+// room per severity.
+type WebexNotifier struct {
+	apiURL    string
+	token     string
+	roomID    string
+	rooms     map[string]string
+	templates *templates.Set
+	client    *http.Client
+	logger    *zap.Logger
+}
+
+// NewWebexNotifier creates a new Webex notifier
+func NewWebexNotifier(logger *zap.Logger) *WebexNotifier {
+	return &WebexNotifier{
+		logger: logger,
+	}
+}
+
+// Name returns the name of the notifier
+func (n *WebexNotifier) Name() string {
+	return "webex"
+}
+
+// Schema describes the notifier and its settings
+func (n *WebexNotifier) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Messages to Cisco Webex rooms",
+		Settings: []collectors.Setting{
+			{Name: "bot_token", Type: "string", Required: true, Description: "Bot access token"},
+			{Name: "bot_token_file", Type: "string", Description: "File to read bot_token from instead"},
+			{Name: "room_id", Type: "string", Description: "Room messaged by default"},
+			{Name: "rooms", Type: "map", Description: "Rooms by severity, overriding room_id"},
+			{Name: "api_url", Type: "string", Description: "API URL overriding the default"},
+		},
+	}
+}
+
+// Init initializes the Webex notifier with configuration
+func (n *WebexNotifier) Init(config map[string]interface{}) error {
+	n.token = collectors.GetString(config, "bot_token", "")
+	if n.token == "" {
+		err := fmt.Errorf("missing 'bot_token' in webex config")
+		n.logger.Error("Failed to initialize webex notifier", zap.Error(err))
+		return err
+	}
+
+	n.roomID = collectors.GetString(config, "room_id", "")
+	n.rooms, _ = config["rooms"].(map[string]string)
+	if n.roomID == "" && len(n.rooms) == 0 {
+		err := fmt.Errorf("webex config needs a 'room_id' or per-severity 'rooms'")
+		n.logger.Error("Failed to initialize webex notifier", zap.Error(err))
+		return err
+	}
+
+	n.apiURL = collectors.GetString(config, "api_url", "")
+	if n.apiURL == "" {
+		n.apiURL = defaultAPIURL
+	}
+	set, err := templates.FromSettings(n.logger, n.Name(), defaultTemplates, config)
+	if err != nil {
+		n.logger.Error("Failed to initialize webex notifier", zap.Error(err))
+		return err
+	}
+	n.templates = set
+	n.client = &http.Client{Timeout: 10 * time.Second}
+
+	return nil
+}
+
+// defaultTemplates is the markdown message body
+var defaultTemplates = map[string]string{
+	"markdown": `**Server Alert: {{.Count}} issue(s) detected on {{.Hostname}}**
+{{range .Results}}{{$severity := severity .}}
+{{if eq $severity "critical"}}🔴{{else if eq $severity "warning"}}🟠{{else}}🔵{{end}} **{{upper $severity}}** ` + "`{{.Collector}}`" + ` — {{.Message}}
+{{range $name, $value := .Metrics}}- {{$name}}: {{metric $value}}
+{{end}}{{end}}`,
+}
+
+// Notify posts one markdown message per destination room
+func (n *WebexNotifier) Notify(ctx context.Context, results []collectors.Result) error {
+	byRoom := make(map[string][]collectors.Result)
+	for _, result := range results {
+		if result.IsHealthy {
+			continue
+		}
+
+		room := n.roomFor(result.EffectiveSeverity())
+		if room == "" {
+			n.logger.Warn("No Webex room for severity, alert not sent",
+				zap.String("collector", result.Collector),
+				zap.String("severity", result.EffectiveSeverity()))
+			continue
+		}
+		byRoom[room] = append(byRoom[room], result)
+	}
+
+	var errs []string
+	for room, roomResults := range byRoom {
+		if err := n.post(ctx, room, n.templates.RenderResults("markdown", roomResults)); err != nil {
+			n.logger.Error("Failed to post Webex message", zap.String("room", room), zap.Error(err))
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("webex post failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// roomFor returns the room for a severity, falling back to the default room
+func (n *WebexNotifier) roomFor(severity string) string {
+	if room, ok := n.rooms[severity]; ok && room != "" {
+		return room
+	}
+	return n.roomID
+}
+
+// post sends a markdown message to a room
+func (n *WebexNotifier) post(ctx context.Context, room, markdown string) error {
+	body, err := json.Marshal(map[string]string{"roomId": room, "markdown": markdown})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.apiURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+n.token)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webex returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+// Close performs any necessary cleanup
+func (n *WebexNotifier) Close() error {
+	// No cleanup needed for webex notifier
+	return nil
+}
@@ -0,0 +1,227 @@
+// outputs/grafana/grafana.go
+package grafana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"go.uber.org/zap"
+)
+
+// GrafanaOutput pushes alert open/resolve events to Grafana as annotations.
+// An annotation is created when a target becomes unhealthy and its end time is
+// set when the target recovers, so alert windows show up as shaded regions.
+type GrafanaOutput struct {
+	url          string
+	apiToken     string
+	dashboardUID string
+	panelID      int
+	tags         []string
+	client       *http.Client
+	open         map[string]int64
+	mu           sync.Mutex
+	logger       *zap.Logger
+}
+
+// annotationRequest is the body of a Grafana annotation create request
+type annotationRequest struct {
+	DashboardUID string   `json:"dashboardUID,omitempty"`
+	PanelID      int      `json:"panelId,omitempty"`
+	Time         int64    `json:"time"`
+	Tags         []string `json:"tags"`
+	Text         string   `json:"text"`
+}
+
+// NewGrafanaOutput creates a new Grafana annotation output
+func NewGrafanaOutput(logger *zap.Logger) *GrafanaOutput {
+	return &GrafanaOutput{
+		open:   make(map[string]int64),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the output
+func (o *GrafanaOutput) Name() string {
+	return "grafana"
+}
+
+// Schema describes the output and its settings
+func (o *GrafanaOutput) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Grafana annotations for alerts",
+		Settings: []collectors.Setting{
+			{Name: "url", Type: "string", Required: true, Description: "Grafana base URL"},
+			{Name: "api_token", Type: "string", Required: true, Description: "Service account token"},
+			{Name: "api_token_file", Type: "string", Description: "File to read api_token from instead"},
+			{Name: "dashboard_uid", Type: "string", Description: "Dashboard the annotations are added to"},
+			{Name: "panel_id", Type: "int", Description: "Panel the annotations are added to"},
+			{Name: "tags", Type: "list", Description: "Tags added to every annotation"},
+			{Name: "timeout_seconds", Type: "duration", Default: "10", Description: "Request timeout"},
+		},
+	}
+}
+
+// Init initializes the Grafana output with configuration
+func (o *GrafanaOutput) Init(settings map[string]interface{}) error {
+	o.url = strings.TrimRight(collectors.GetString(settings, "url", ""), "/")
+	if o.url == "" {
+		err := fmt.Errorf("missing 'url' configuration for grafana output")
+		o.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+
+	apiToken, err := collectors.GetSecret(settings, "api_token")
+	if err != nil {
+		o.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+	o.apiToken = apiToken
+	if o.apiToken == "" {
+		err := fmt.Errorf("missing 'api_token' configuration for grafana output")
+		o.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+
+	o.dashboardUID = collectors.GetString(settings, "dashboard_uid", "")
+	o.panelID = collectors.GetInt(settings, "panel_id", 0)
+
+	tags, err := collectors.GetStringSlice(settings, "tags", nil)
+	if err != nil {
+		o.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+	o.tags = tags
+
+	timeout, err := collectors.GetDuration(settings, "timeout_seconds", time.Second, 10*time.Second)
+	if err != nil {
+		o.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+	o.client = &http.Client{Timeout: timeout}
+
+	return nil
+}
+
+// Write opens annotations for newly unhealthy targets and closes them on recovery
+func (o *GrafanaOutput) Write(ctx context.Context, results []collectors.Result) error {
+	var errs []string
+
+	for _, result := range results {
+		key := result.Key()
+
+		o.mu.Lock()
+		id, isOpen := o.open[key]
+		o.mu.Unlock()
+
+		switch {
+		case !result.IsHealthy && !isOpen:
+			id, err := o.createAnnotation(ctx, result)
+			if err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			o.mu.Lock()
+			o.open[key] = id
+			o.mu.Unlock()
+
+		case result.IsHealthy && isOpen:
+			if err := o.closeAnnotation(ctx, id, result.Timestamp); err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			o.mu.Lock()
+			delete(o.open, key)
+			o.mu.Unlock()
+		}
+	}
+
+	if len(errs) > 0 {
+		err := fmt.Errorf("grafana annotation errors: %s", strings.Join(errs, "; "))
+		o.logger.Error("Failed to push annotations", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// createAnnotation opens an annotation for an unhealthy result and returns its ID
+func (o *GrafanaOutput) createAnnotation(ctx context.Context, result collectors.Result) (int64, error) {
+	tags := []string{"simple-monit", result.Collector, result.EffectiveSeverity()}
+	tags = append(tags, o.tags...)
+
+	body, err := json.Marshal(annotationRequest{
+		DashboardUID: o.dashboardUID,
+		PanelID:      o.panelID,
+		Time:         result.Timestamp.UnixMilli(),
+		Tags:         tags,
+		Text:         result.Message,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	if err := o.do(ctx, http.MethodPost, o.url+"/api/annotations", body, &created); err != nil {
+		return 0, fmt.Errorf("failed to create annotation for %s: %w", result.Collector, err)
+	}
+	return created.ID, nil
+}
+
+// closeAnnotation sets the end time of an open annotation, turning it into a region
+func (o *GrafanaOutput) closeAnnotation(ctx context.Context, id int64, resolvedAt time.Time) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"timeEnd": resolvedAt.UnixMilli(),
+	})
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/api/annotations/%d", o.url, id)
+	if err := o.do(ctx, http.MethodPatch, endpoint, body, nil); err != nil {
+		return fmt.Errorf("failed to close annotation %d: %w", id, err)
+	}
+	return nil
+}
+
+// do sends an authenticated JSON request and optionally decodes the response
+func (o *GrafanaOutput) do(ctx context.Context, method, endpoint string, body []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+o.apiToken)
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+// Close performs any necessary cleanup
+func (o *GrafanaOutput) Close() error {
+	// Open annotations are left open; they are closed when the target recovers
+	return nil
+}
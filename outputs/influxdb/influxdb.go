@@ -0,0 +1,261 @@
+// outputs/influxdb/influxdb.go
+package influxdb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/templates"
+
+	"go.uber.org/zap"
+)
+
+// InfluxDBOutput writes the metrics of every result to InfluxDB in line
+// protocol, one point per result, so they can be graphed over the long term.
+// The collector, host name, result tags and scalar metadata become tags; the
+// metrics and the healthy flag become fields.
+type InfluxDBOutput struct {
+	writeURL    string
+	token       string
+	username    string
+	password    string
+	measurement string
+	tags        map[string]string
+	client      *http.Client
+	logger      *zap.Logger
+}
+
+// NewInfluxDBOutput creates a new InfluxDB output
+func NewInfluxDBOutput(logger *zap.Logger) *InfluxDBOutput {
+	return &InfluxDBOutput{logger: logger}
+}
+
+// Name returns the name of the output
+func (o *InfluxDBOutput) Name() string {
+	return "influxdb"
+}
+
+// Schema describes the output and its settings
+func (o *InfluxDBOutput) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Metrics written to InfluxDB",
+		Settings: []collectors.Setting{
+			{Name: "url", Type: "string", Required: true, Description: "InfluxDB base URL"},
+			{Name: "version", Type: "int", Default: "2", Description: "API version: 1 or 2"},
+			{Name: "org", Type: "string", Description: "Organization (v2)"},
+			{Name: "bucket", Type: "string", Description: "Bucket (v2)"},
+			{Name: "token", Type: "string", Description: "API token (v2)"},
+			{Name: "token_file", Type: "string", Description: "File to read token from instead"},
+			{Name: "database", Type: "string", Description: "Database (v1)"},
+			{Name: "retention_policy", Type: "string", Description: "Retention policy (v1)"},
+			{Name: "username", Type: "string", Description: "Username (v1)"},
+			{Name: "password", Type: "string", Description: "Password (v1)"},
+			{Name: "password_file", Type: "string", Description: "File to read password from instead"},
+			{Name: "measurement", Type: "string", Default: "simple_monit", Description: "Measurement name"},
+			{Name: "tags", Type: "map", Description: "Tags added to every point"},
+			{Name: "timeout_seconds", Type: "duration", Default: "10", Description: "Request timeout"},
+		},
+	}
+}
+
+// Init initializes the InfluxDB output with configuration. Version 2 writes
+// to an organization's bucket with a token; version 1 writes to a database,
+// optionally with a username and password.
+func (o *InfluxDBOutput) Init(settings map[string]interface{}) error {
+	base := strings.TrimRight(collectors.GetString(settings, "url", ""), "/")
+	if base == "" {
+		err := fmt.Errorf("missing 'url' configuration for influxdb output")
+		o.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+
+	query := url.Values{"precision": {"ns"}}
+	switch version := collectors.GetInt(settings, "version", 2); version {
+	case 2:
+		org := collectors.GetString(settings, "org", "")
+		bucket := collectors.GetString(settings, "bucket", "")
+		token, err := collectors.GetSecret(settings, "token")
+		if err != nil {
+			o.logger.Error("Init error", zap.Error(err))
+			return err
+		}
+		o.token = token
+		if org == "" || bucket == "" || o.token == "" {
+			err := fmt.Errorf("influxdb output version 2 needs 'org', 'bucket' and 'token'")
+			o.logger.Error("Init error", zap.Error(err))
+			return err
+		}
+		query.Set("org", org)
+		query.Set("bucket", bucket)
+		o.writeURL = base + "/api/v2/write?" + query.Encode()
+	case 1:
+		database := collectors.GetString(settings, "database", "")
+		if database == "" {
+			err := fmt.Errorf("influxdb output version 1 needs 'database'")
+			o.logger.Error("Init error", zap.Error(err))
+			return err
+		}
+		query.Set("db", database)
+		if rp := collectors.GetString(settings, "retention_policy", ""); rp != "" {
+			query.Set("rp", rp)
+		}
+		o.username = collectors.GetString(settings, "username", "")
+		password, err := collectors.GetSecret(settings, "password")
+		if err != nil {
+			o.logger.Error("Init error", zap.Error(err))
+			return err
+		}
+		o.password = password
+		o.writeURL = base + "/write?" + query.Encode()
+	default:
+		err := fmt.Errorf("influxdb output 'version' must be 1 or 2, got %d", version)
+		o.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+
+	o.measurement = collectors.GetString(settings, "measurement", "simple_monit")
+
+	o.tags = make(map[string]string)
+	if raw, ok := settings["tags"]; ok {
+		tags, ok := raw.(map[string]interface{})
+		if !ok {
+			err := fmt.Errorf("influxdb output 'tags' should be a map")
+			o.logger.Error("Init error", zap.Error(err))
+			return err
+		}
+		for key, value := range tags {
+			o.tags[key] = fmt.Sprint(value)
+		}
+	}
+
+	timeout, err := collectors.GetDuration(settings, "timeout_seconds", time.Second, 10*time.Second)
+	if err != nil {
+		o.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+	o.client = &http.Client{Timeout: timeout}
+
+	return nil
+}
+
+// Write sends the results of a collector run as one batch of points
+func (o *InfluxDBOutput) Write(ctx context.Context, results []collectors.Result) error {
+	var body bytes.Buffer
+	for _, result := range results {
+		o.writePoint(&body, result)
+	}
+	if body.Len() == 0 {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.writeURL, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if o.token != "" {
+		req.Header.Set("Authorization", "Token "+o.token)
+	} else if o.username != "" {
+		req.SetBasicAuth(o.username, o.password)
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		o.logger.Error("Failed to write points", zap.Error(err))
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err := fmt.Errorf("influxdb write failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+		o.logger.Error("Failed to write points", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// writePoint appends a result as a line protocol point
+func (o *InfluxDBOutput) writePoint(buf *bytes.Buffer, result collectors.Result) {
+	tags := map[string]string{
+		"collector": result.Collector,
+		"hostname":  templates.HostOf(result),
+	}
+	for key, value := range o.tags {
+		tags[key] = value
+	}
+	for key, value := range result.Tags {
+		tags[key] = value
+	}
+	for key, value := range result.Metadata {
+		switch value.(type) {
+		case string, bool, int, int64, float64:
+			tags[key] = fmt.Sprint(value)
+		}
+	}
+	if !result.IsHealthy {
+		tags["severity"] = result.EffectiveSeverity()
+	}
+
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	buf.WriteString(escape(o.measurement, ", "))
+	for _, key := range keys {
+		if tags[key] == "" {
+			continue
+		}
+		buf.WriteString("," + escape(key, ",= ") + "=" + escape(tags[key], ",= "))
+	}
+
+	names := make([]string, 0, len(result.Metrics))
+	for name := range result.Metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf.WriteString(" healthy=" + strconv.FormatBool(result.IsHealthy))
+	for _, name := range names {
+		value := result.Metrics[name]
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+		buf.WriteString("," + escape(name, ",= ") + "=" + strconv.FormatFloat(value, 'f', -1, 64))
+	}
+
+	buf.WriteString(" " + strconv.FormatInt(result.Timestamp.UnixNano(), 10) + "\n")
+}
+
+// escape backslash-escapes the characters special in a line protocol
+// element; newlines, which cannot be escaped, become spaces
+func escape(s, special string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r == '\n' {
+			r = ' '
+		}
+		if strings.ContainsRune(special, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Close performs any necessary cleanup
+func (o *InfluxDBOutput) Close() error {
+	return nil
+}
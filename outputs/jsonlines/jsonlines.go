@@ -0,0 +1,115 @@
+// outputs/jsonlines/jsonlines.go
+package jsonlines
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"go.uber.org/zap"
+)
+
+// JSONLinesOutput writes every result as one line of JSON to standard output
+// or a file, for piping into jq, Vector or Fluent Bit
+type JSONLinesOutput struct {
+	path   string
+	out    io.Writer
+	file   *os.File
+	mu     sync.Mutex
+	logger *zap.Logger
+}
+
+// NewJSONLinesOutput creates a new JSON lines output
+func NewJSONLinesOutput(logger *zap.Logger) *JSONLinesOutput {
+	return &JSONLinesOutput{logger: logger}
+}
+
+// Name returns the name of the output
+func (o *JSONLinesOutput) Name() string {
+	return "json"
+}
+
+// Schema describes the output and its settings
+func (o *JSONLinesOutput) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Results written as JSON lines",
+		Settings: []collectors.Setting{
+			{Name: "path", Type: "string", Default: "-", Description: "File to append to, or - for standard output"},
+		},
+	}
+}
+
+// Validate checks that the directory of the output file exists, without
+// creating the file
+func (o *JSONLinesOutput) Validate(settings map[string]interface{}) error {
+	path := collectors.GetString(settings, "path", "-")
+	if path == "-" {
+		return nil
+	}
+	if info, err := os.Stat(filepath.Dir(path)); err != nil || !info.IsDir() {
+		return fmt.Errorf("json output file %s: directory %s does not exist", path, filepath.Dir(path))
+	}
+	return nil
+}
+
+// Init initializes the output with configuration. A path of "-" (the
+// default) writes to standard output; anything else is a file appended to.
+func (o *JSONLinesOutput) Init(settings map[string]interface{}) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.path = collectors.GetString(settings, "path", "-")
+	if o.file != nil {
+		o.file.Close()
+		o.file = nil
+	}
+
+	if o.path == "-" {
+		o.out = os.Stdout
+		return nil
+	}
+
+	file, err := os.OpenFile(o.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		err = fmt.Errorf("failed to open json output file %s: %w", o.path, err)
+		o.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+	o.file = file
+	o.out = file
+	return nil
+}
+
+// Write writes one line per result
+func (o *JSONLinesOutput) Write(ctx context.Context, results []collectors.Result) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	encoder := json.NewEncoder(o.out)
+	for _, result := range results {
+		if err := encoder.Encode(result); err != nil {
+			o.logger.Error("Failed to write result", zap.String("path", o.path), zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the file, if one is open
+func (o *JSONLinesOutput) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.file == nil {
+		return nil
+	}
+	err := o.file.Close()
+	o.file = nil
+	return err
+}
@@ -0,0 +1,30 @@
+// outputs/output.go
+package outputs
+
+import (
+	"context"
+
+	"github.com/devvspaces/simple-monit/collectors"
+)
+
+// Output defines the interface that all result outputs must implement.
+// Unlike notifiers, outputs receive every result, healthy or not.
+type Output interface {
+	// Name returns the unique name of the output
+	Name() string
+
+	// Init initializes the output with its configuration
+	Init(settings map[string]interface{}) error
+
+	// Write delivers the results of a single collector run
+	Write(ctx context.Context, results []collectors.Result) error
+
+	// Close performs any necessary cleanup operations
+	Close() error
+}
+
+// Validator is implemented by outputs that open files or listeners in Init;
+// Validate checks their settings without touching either
+type Validator interface {
+	Validate(settings map[string]interface{}) error
+}
@@ -0,0 +1,79 @@
+// outputs/registry.go
+package outputs
+
+import (
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Registry manages the available outputs
+type Registry struct {
+	outputs map[string]Output
+	mu      sync.RWMutex
+	logger  *zap.Logger
+}
+
+// NewRegistry creates a new output registry
+func NewRegistry(logger *zap.Logger) *Registry {
+	return &Registry{
+		outputs: make(map[string]Output),
+		logger:  logger,
+	}
+}
+
+// Register adds an output to the registry
+func (r *Registry) Register(output Output) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := output.Name()
+	if name == "" {
+		err := fmt.Errorf("output has empty name")
+		r.logger.Error("Failed to register output", zap.Error(err))
+		return err
+	}
+
+	if _, exists := r.outputs[name]; exists {
+		err := fmt.Errorf("output with name '%s' already registered", name)
+		r.logger.Error("Failed to register output", zap.Error(err))
+		return err
+	}
+
+	r.outputs[name] = output
+	return nil
+}
+
+// Get returns an output by name
+func (r *Registry) Get(name string) (Output, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	output, exists := r.outputs[name]
+	return output, exists
+}
+
+// GetAll returns a list of all registered outputs
+func (r *Registry) GetAll() []Output {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []Output
+	for _, output := range r.outputs {
+		result = append(result, output)
+	}
+	return result
+}
+
+// OutputNames returns a list of all registered output names
+func (r *Registry) OutputNames() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var names []string
+	for name := range r.outputs {
+		names = append(names, name)
+	}
+	return names
+}
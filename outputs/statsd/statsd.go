@@ -0,0 +1,215 @@
+// outputs/statsd/statsd.go
+package statsd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/templates"
+
+	"go.uber.org/zap"
+)
+
+// StatsDOutput emits every metric as a gauge named
+// <prefix><collector>.<metric>, and every unhealthy result as an increment of
+// <prefix><collector>.unhealthy, to a StatsD server over UDP. With DogStatsD
+// tags enabled, the collector, host name, result tags, scalar metadata and
+// severity are also sent as Datadog-style tags.
+type StatsDOutput struct {
+	address       string
+	prefix        string
+	dogstatsd     bool
+	tags          []string
+	maxPacketSize int
+	conn          net.Conn
+	logger        *zap.Logger
+}
+
+// NewStatsDOutput creates a new StatsD output
+func NewStatsDOutput(logger *zap.Logger) *StatsDOutput {
+	return &StatsDOutput{logger: logger}
+}
+
+// Name returns the name of the output
+func (o *StatsDOutput) Name() string {
+	return "statsd"
+}
+
+// Schema describes the output and its settings
+func (o *StatsDOutput) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Metrics sent to StatsD or DogStatsD",
+		Settings: []collectors.Setting{
+			{Name: "address", Type: "string", Default: "127.0.0.1:8125", Description: "UDP address of the daemon"},
+			{Name: "prefix", Type: "string", Default: "simple_monit.", Description: "Prefix of the metric names"},
+			{Name: "dogstatsd", Type: "bool", Default: "true", Description: "Send DogStatsD tags"},
+			{Name: "tags", Type: "map", Description: "Tags added to every metric"},
+			{Name: "max_packet_size", Type: "int", Default: "1432", Description: "Largest UDP packet sent"},
+		},
+	}
+}
+
+// Validate checks the settings without resolving the address
+func (o *StatsDOutput) Validate(settings map[string]interface{}) error {
+	address := collectors.GetString(settings, "address", "127.0.0.1:8125")
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return fmt.Errorf("invalid statsd address %s: %w", address, err)
+	}
+	if raw, ok := settings["tags"]; ok {
+		if _, ok := raw.(map[string]interface{}); !ok {
+			return fmt.Errorf("statsd output 'tags' should be a map")
+		}
+	}
+	return nil
+}
+
+// Init initializes the StatsD output with configuration
+func (o *StatsDOutput) Init(settings map[string]interface{}) error {
+	o.address = collectors.GetString(settings, "address", "127.0.0.1:8125")
+	o.prefix = collectors.GetString(settings, "prefix", "simple_monit.")
+	o.dogstatsd = collectors.GetBool(settings, "dogstatsd", true)
+	o.maxPacketSize = collectors.GetInt(settings, "max_packet_size", 1432)
+
+	o.tags = nil
+	if raw, ok := settings["tags"]; ok {
+		tags, ok := raw.(map[string]interface{})
+		if !ok {
+			err := fmt.Errorf("statsd output 'tags' should be a map")
+			o.logger.Error("Init error", zap.Error(err))
+			return err
+		}
+		for key, value := range tags {
+			o.tags = append(o.tags, tag(key, fmt.Sprint(value)))
+		}
+		sort.Strings(o.tags)
+	}
+
+	// UDP is connectionless; dialing only resolves the address
+	conn, err := net.Dial("udp", o.address)
+	if err != nil {
+		err = fmt.Errorf("failed to resolve statsd address %s: %w", o.address, err)
+		o.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+	if o.conn != nil {
+		o.conn.Close()
+	}
+	o.conn = conn
+
+	return nil
+}
+
+// Write emits the results of a collector run, packing lines into packets of
+// at most max_packet_size bytes
+func (o *StatsDOutput) Write(ctx context.Context, results []collectors.Result) error {
+	var lines []string
+	for _, result := range results {
+		lines = append(lines, o.lines(result)...)
+	}
+
+	var packet strings.Builder
+	var errs []string
+	flush := func() {
+		if packet.Len() == 0 {
+			return
+		}
+		if _, err := o.conn.Write([]byte(packet.String())); err != nil {
+			errs = append(errs, err.Error())
+		}
+		packet.Reset()
+	}
+	for _, line := range lines {
+		if packet.Len() > 0 && packet.Len()+1+len(line) > o.maxPacketSize {
+			flush()
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+	}
+	flush()
+
+	if len(errs) > 0 {
+		err := fmt.Errorf("statsd write errors: %s", strings.Join(errs, "; "))
+		o.logger.Error("Failed to emit metrics", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// lines returns the gauges of a result's metrics and, for an unhealthy
+// result, an increment of the unhealthy counter
+func (o *StatsDOutput) lines(result collectors.Result) []string {
+	var tags []string
+	name := o.prefix + sanitize(result.Collector) + "."
+	if o.dogstatsd {
+		labels := map[string]string{"collector": result.Collector, "hostname": templates.HostOf(result)}
+		for key, value := range result.Tags {
+			labels[key] = value
+		}
+		for key, value := range result.Metadata {
+			switch value.(type) {
+			case string, bool, int, int64, float64:
+				labels[key] = fmt.Sprint(value)
+			}
+		}
+		for key, value := range labels {
+			tags = append(tags, tag(key, value))
+		}
+		tags = append(tags, o.tags...)
+		sort.Strings(tags)
+	}
+
+	suffix := ""
+	if len(tags) > 0 {
+		suffix = "|#" + strings.Join(tags, ",")
+	}
+
+	metrics := make([]string, 0, len(result.Metrics))
+	for metric := range result.Metrics {
+		metrics = append(metrics, metric)
+	}
+	sort.Strings(metrics)
+
+	var lines []string
+	for _, metric := range metrics {
+		value := result.Metrics[metric]
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+		lines = append(lines, name+sanitize(metric)+":"+strconv.FormatFloat(value, 'f', -1, 64)+"|g"+suffix)
+	}
+
+	if !result.IsHealthy {
+		counter := name + "unhealthy:1|c"
+		if o.dogstatsd {
+			counter += "|#" + strings.Join(append(tags, tag("severity", result.EffectiveSeverity())), ",")
+		}
+		lines = append(lines, counter)
+	}
+	return lines
+}
+
+// tag formats a DogStatsD tag
+func tag(key, value string) string {
+	return sanitize(key) + ":" + strings.NewReplacer(",", "_", "|", "_", "\n", " ").Replace(value)
+}
+
+// sanitize replaces the characters StatsD uses as separators
+func sanitize(s string) string {
+	return strings.NewReplacer(":", "_", "|", "_", "@", "_", "#", "_", ",", "_", " ", "_", "\n", "_").Replace(s)
+}
+
+// Close closes the UDP socket
+func (o *StatsDOutput) Close() error {
+	if o.conn == nil {
+		return nil
+	}
+	return o.conn.Close()
+}
@@ -0,0 +1,374 @@
+// outputs/statuspage/statuspage.go
+package statuspage
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/templates"
+
+	"go.uber.org/zap"
+)
+
+//go:embed templates/status.html
+var templateFS embed.FS
+
+// Check states shown on the status page
+const (
+	StateUp      = "up"
+	StateDown    = "down"
+	StateUnknown = "unknown"
+)
+
+// CheckStatus is the public view of a single check
+type CheckStatus struct {
+	Collector     string    `json:"collector"`
+	Name          string    `json:"name"`
+	State         string    `json:"state"`
+	Message       string    `json:"message,omitempty"`
+	LastChecked   time.Time `json:"last_checked"`
+	UptimePercent float64   `json:"uptime_percent"`
+	Samples       int       `json:"samples"`
+}
+
+// Page is the document rendered to index.html and status.json
+type Page struct {
+	Title       string        `json:"title"`
+	Hostname    string        `json:"hostname"` // Of the monitor generating the page, from the hostname of its checks' results
+	AllUp       bool          `json:"all_up"`
+	GeneratedAt time.Time     `json:"generated_at"`
+	Checks      []CheckStatus `json:"checks"`
+}
+
+// checkState tracks the recent history of a check
+type checkState struct {
+	name        string
+	healthy     bool
+	seen        bool
+	host        string
+	message     string
+	lastChecked time.Time
+	samples     []bool
+}
+
+// StatusPageOutput renders a minimal public status page to a directory
+type StatusPageOutput struct {
+	directory   string
+	title       string
+	historySize int
+	order       []string
+	checks      map[string]*checkState
+	tmpl        *template.Template
+	server      *http.Server
+	mu          sync.Mutex
+	logger      *zap.Logger
+}
+
+// NewStatusPageOutput creates a new status page output
+func NewStatusPageOutput(logger *zap.Logger) *StatusPageOutput {
+	return &StatusPageOutput{
+		logger: logger,
+	}
+}
+
+// Name returns the name of the output
+func (o *StatusPageOutput) Name() string {
+	return "status_page"
+}
+
+// Schema describes the output and its settings
+func (o *StatusPageOutput) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Static public status page",
+		Settings: []collectors.Setting{
+			{Name: "directory", Type: "string", Required: true, Description: "Directory the page is written to"},
+			{Name: "title", Type: "string", Default: "Service Status", Description: "Page title"},
+			{Name: "history_size", Type: "int", Default: "288", Description: "Results of each check kept for the history bar"},
+			{Name: "listen", Type: "string", Description: "Address to serve the page on"},
+			{Name: "checks", Type: "list", Required: true, Description: "Checks shown on the page", Fields: []collectors.Setting{
+				{Name: "collector", Type: "string", Required: true, Description: "Collector whose results are shown"},
+				{Name: "name", Type: "string", Description: "Name shown on the page (default: the collector)"},
+			}},
+		},
+	}
+}
+
+// Validate checks the settings without creating the directory or listening
+func (o *StatusPageOutput) Validate(settings map[string]interface{}) error {
+	if collectors.GetString(settings, "directory", "") == "" {
+		return fmt.Errorf("missing 'directory' configuration for status page output")
+	}
+	if collectors.GetInt(settings, "history_size", 288) <= 0 {
+		return fmt.Errorf("'history_size' must be greater than 0")
+	}
+	checksRaw, ok := settings["checks"].([]interface{})
+	if !ok || len(checksRaw) == 0 {
+		return fmt.Errorf("'checks' should be a non-empty array")
+	}
+	for _, checkRaw := range checksRaw {
+		checkMap, ok := checkRaw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("each check should be an object")
+		}
+		if collectors.GetString(checkMap, "collector", "") == "" {
+			return fmt.Errorf("each check requires a 'collector'")
+		}
+	}
+	if listen := collectors.GetString(settings, "listen", ""); listen != "" {
+		if _, _, err := net.SplitHostPort(listen); err != nil {
+			return fmt.Errorf("invalid 'listen' address %s: %w", listen, err)
+		}
+	}
+	return nil
+}
+
+// Init initializes the status page output with configuration
+func (o *StatusPageOutput) Init(settings map[string]interface{}) error {
+	o.directory = collectors.GetString(settings, "directory", "")
+	if o.directory == "" {
+		err := fmt.Errorf("missing 'directory' configuration for status page output")
+		o.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+
+	if err := os.MkdirAll(o.directory, 0o755); err != nil {
+		err := fmt.Errorf("could not create status page directory %s: %w", o.directory, err)
+		o.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+
+	o.title = collectors.GetString(settings, "title", "Service Status")
+	o.historySize = collectors.GetInt(settings, "history_size", 288)
+	if o.historySize <= 0 {
+		err := fmt.Errorf("'history_size' must be greater than 0")
+		o.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+
+	// Only explicitly configured checks are published
+	checksRaw, ok := settings["checks"].([]interface{})
+	if !ok || len(checksRaw) == 0 {
+		err := fmt.Errorf("'checks' should be a non-empty array")
+		o.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+
+	o.checks = make(map[string]*checkState)
+	o.order = nil
+	for _, checkRaw := range checksRaw {
+		checkMap, ok := checkRaw.(map[string]interface{})
+		if !ok {
+			err := fmt.Errorf("each check should be an object")
+			o.logger.Error("Init error", zap.Error(err))
+			return err
+		}
+
+		collector := collectors.GetString(checkMap, "collector", "")
+		if collector == "" {
+			err := fmt.Errorf("each check requires a 'collector'")
+			o.logger.Error("Init error", zap.Error(err))
+			return err
+		}
+
+		// A collector listed twice keeps its first entry
+		if _, ok := o.checks[collector]; ok {
+			continue
+		}
+		o.order = append(o.order, collector)
+		o.checks[collector] = &checkState{
+			name: collectors.GetString(checkMap, "name", collector),
+		}
+	}
+
+	tmpl, err := template.ParseFS(templateFS, "templates/status.html")
+	if err != nil {
+		o.logger.Error("Failed to parse status page template", zap.Error(err))
+		return err
+	}
+	o.tmpl = tmpl
+
+	// Render an initial page so the directory is never empty
+	if err := o.render(); err != nil {
+		return err
+	}
+
+	// Optionally serve the directory on a separate unauthenticated listener
+	if listen := collectors.GetString(settings, "listen", ""); listen != "" {
+		o.server = &http.Server{
+			Addr:              listen,
+			Handler:           http.FileServer(http.Dir(o.directory)),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			if err := o.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				o.logger.Error("Status page listener failed", zap.String("listen", listen), zap.Error(err))
+			}
+		}()
+		o.logger.Info("Serving status page", zap.String("listen", listen))
+	}
+
+	return nil
+}
+
+// Write records the outcome of a collector run and refreshes the page
+func (o *StatusPageOutput) Write(ctx context.Context, results []collectors.Result) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	o.mu.Lock()
+	state, published := o.checks[results[0].Collector]
+	if !published {
+		o.mu.Unlock()
+		return nil
+	}
+
+	healthy := true
+	var messages []string
+	for _, result := range results {
+		if !result.IsHealthy {
+			healthy = false
+			messages = append(messages, result.Message)
+		}
+	}
+
+	state.seen = true
+	state.host = templates.HostOf(results[0])
+	state.healthy = healthy
+	state.message = strings.Join(messages, "; ")
+	state.lastChecked = results[0].Timestamp
+	state.samples = append(state.samples, healthy)
+	if len(state.samples) > o.historySize {
+		state.samples = state.samples[len(state.samples)-o.historySize:]
+	}
+	o.mu.Unlock()
+
+	return o.render()
+}
+
+// snapshot builds the page document from the current check states
+func (o *StatusPageOutput) snapshot() Page {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	page := Page{
+		Title:       o.title,
+		Hostname:    templates.Hostname(),
+		AllUp:       true,
+		GeneratedAt: time.Now(),
+	}
+	for _, collector := range o.order {
+		if host := o.checks[collector].host; host != "" {
+			page.Hostname = host
+			break
+		}
+	}
+
+	for _, collector := range o.order {
+		state := o.checks[collector]
+		status := CheckStatus{
+			Collector:   collector,
+			Name:        state.name,
+			State:       StateUnknown,
+			Message:     state.message,
+			LastChecked: state.lastChecked,
+			Samples:     len(state.samples),
+		}
+
+		if state.seen {
+			status.State = StateUp
+			if !state.healthy {
+				status.State = StateDown
+				page.AllUp = false
+			}
+		}
+
+		if len(state.samples) > 0 {
+			up := 0
+			for _, sample := range state.samples {
+				if sample {
+					up++
+				}
+			}
+			status.UptimePercent = float64(up) / float64(len(state.samples)) * 100
+		}
+
+		page.Checks = append(page.Checks, status)
+	}
+
+	return page
+}
+
+// render writes index.html and status.json to the output directory
+func (o *StatusPageOutput) render() error {
+	page := o.snapshot()
+
+	var html bytes.Buffer
+	if err := o.tmpl.Execute(&html, page); err != nil {
+		o.logger.Error("Failed to render status page", zap.Error(err))
+		return err
+	}
+
+	data, err := json.MarshalIndent(page, "", "  ")
+	if err != nil {
+		o.logger.Error("Failed to encode status page", zap.Error(err))
+		return err
+	}
+
+	if err := writeFileAtomic(filepath.Join(o.directory, "index.html"), html.Bytes()); err != nil {
+		o.logger.Error("Failed to write status page", zap.Error(err))
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(o.directory, "status.json"), data); err != nil {
+		o.logger.Error("Failed to write status page", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// writeFileAtomic replaces path with data so readers never see a partial file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-"+filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Close stops the status page listener if one was started
+func (o *StatusPageOutput) Close() error {
+	if o.server == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return o.server.Shutdown(ctx)
+}
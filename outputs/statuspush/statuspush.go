@@ -0,0 +1,313 @@
+// outputs/statuspush/statuspush.go
+package statuspush
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"go.uber.org/zap"
+)
+
+// Supported status page providers
+const (
+	ProviderUptimeKuma = "uptime_kuma"
+	ProviderCachet     = "cachet"
+	ProviderGeneric    = "generic"
+)
+
+// Cachet component status codes
+const (
+	cachetOperational = 1
+	cachetMajorOutage = 4
+)
+
+// CheckConfig maps a collector to its status page target
+type CheckConfig struct {
+	Collector   string `json:"collector"`
+	PushURL     string `json:"push_url"`
+	ComponentID int    `json:"component_id"`
+}
+
+// StatusPushOutput pushes per-check status to an external status page
+type StatusPushOutput struct {
+	provider string
+	url      string
+	apiToken string
+	checks   map[string]CheckConfig
+	client   *http.Client
+	logger   *zap.Logger
+}
+
+// NewStatusPushOutput creates a new status page push output
+func NewStatusPushOutput(logger *zap.Logger) *StatusPushOutput {
+	return &StatusPushOutput{
+		logger: logger,
+	}
+}
+
+// Name returns the name of the output
+func (o *StatusPushOutput) Name() string {
+	return "status_push"
+}
+
+// Schema describes the output and its settings
+func (o *StatusPushOutput) Schema() collectors.Schema {
+	return collectors.Schema{
+		Description: "Component statuses pushed to Uptime Kuma, Cachet or a webhook",
+		Settings: []collectors.Setting{
+			{Name: "provider", Type: "string", Default: "generic", Description: "uptime_kuma, cachet or generic"},
+			{Name: "url", Type: "string", Description: "Webhook or Cachet base URL"},
+			{Name: "api_token", Type: "string", Description: "Cachet API token"},
+			{Name: "api_token_file", Type: "string", Description: "File to read api_token from instead"},
+			{Name: "timeout_seconds", Type: "duration", Default: "10", Description: "Request timeout"},
+			{Name: "checks", Type: "list", Description: "Collectors mapped to status page components", Fields: []collectors.Setting{
+				{Name: "collector", Type: "string", Required: true, Description: "Collector whose results are pushed"},
+				{Name: "push_url", Type: "string", Description: "Uptime Kuma push URL"},
+				{Name: "component_id", Type: "int", Description: "Cachet component ID"},
+			}},
+		},
+	}
+}
+
+// Init initializes the status push output with configuration
+func (o *StatusPushOutput) Init(settings map[string]interface{}) error {
+	o.provider = collectors.GetString(settings, "provider", ProviderGeneric)
+	o.url = strings.TrimRight(collectors.GetString(settings, "url", ""), "/")
+	apiToken, err := collectors.GetSecret(settings, "api_token")
+	if err != nil {
+		o.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+	o.apiToken = apiToken
+
+	timeout, err := collectors.GetDuration(settings, "timeout_seconds", time.Second, 10*time.Second)
+	if err != nil {
+		o.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+	o.client = &http.Client{Timeout: timeout}
+
+	o.checks = make(map[string]CheckConfig)
+	if checksRaw, ok := settings["checks"]; ok {
+		checksArray, ok := checksRaw.([]interface{})
+		if !ok {
+			err := fmt.Errorf("'checks' should be an array")
+			o.logger.Error("Init error", zap.Error(err))
+			return err
+		}
+
+		for _, checkRaw := range checksArray {
+			checkMap, ok := checkRaw.(map[string]interface{})
+			if !ok {
+				err := fmt.Errorf("each check should be an object")
+				o.logger.Error("Init error", zap.Error(err))
+				return err
+			}
+
+			check := CheckConfig{
+				Collector:   collectors.GetString(checkMap, "collector", ""),
+				PushURL:     collectors.GetString(checkMap, "push_url", ""),
+				ComponentID: collectors.GetInt(checkMap, "component_id", 0),
+			}
+			if check.Collector == "" {
+				err := fmt.Errorf("each check requires a 'collector'")
+				o.logger.Error("Init error", zap.Error(err))
+				return err
+			}
+			o.checks[check.Collector] = check
+		}
+	}
+
+	switch o.provider {
+	case ProviderUptimeKuma:
+		for name, check := range o.checks {
+			if check.PushURL == "" {
+				err := fmt.Errorf("check '%s' requires a 'push_url' for uptime_kuma", name)
+				o.logger.Error("Init error", zap.Error(err))
+				return err
+			}
+		}
+	case ProviderCachet:
+		if o.url == "" || o.apiToken == "" {
+			err := fmt.Errorf("cachet provider requires 'url' and 'api_token'")
+			o.logger.Error("Init error", zap.Error(err))
+			return err
+		}
+		for name, check := range o.checks {
+			if check.ComponentID <= 0 {
+				err := fmt.Errorf("check '%s' requires a 'component_id' for cachet", name)
+				o.logger.Error("Init error", zap.Error(err))
+				return err
+			}
+		}
+	case ProviderGeneric:
+		if o.url == "" {
+			err := fmt.Errorf("generic provider requires 'url'")
+			o.logger.Error("Init error", zap.Error(err))
+			return err
+		}
+	default:
+		err := fmt.Errorf("unknown status page provider '%s'", o.provider)
+		o.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+
+	if o.provider != ProviderGeneric && len(o.checks) == 0 {
+		err := fmt.Errorf("no 'checks' configured for status push output")
+		o.logger.Error("Init error", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Write pushes the combined status of a collector run to the status page
+func (o *StatusPushOutput) Write(ctx context.Context, results []collectors.Result) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	collector := results[0].Collector
+	// Generic endpoints receive every collector unless checks narrow it down
+	check, configured := o.checks[collector]
+	if !configured && len(o.checks) > 0 {
+		return nil
+	}
+
+	// A check is up only if every result from the run is healthy
+	healthy := true
+	var messages []string
+	for _, result := range results {
+		if !result.IsHealthy {
+			healthy = false
+			messages = append(messages, result.Message)
+		}
+	}
+	message := "OK"
+	if !healthy {
+		message = strings.Join(messages, "; ")
+	}
+
+	var err error
+	switch o.provider {
+	case ProviderUptimeKuma:
+		err = o.pushUptimeKuma(ctx, check, healthy, message)
+	case ProviderCachet:
+		err = o.pushCachet(ctx, check, healthy)
+	case ProviderGeneric:
+		err = o.pushGeneric(ctx, collector, healthy, message, results)
+	}
+
+	if err != nil {
+		err := fmt.Errorf("failed to push status for %s: %w", collector, err)
+		o.logger.Error("Status push failed", zap.String("provider", o.provider), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// pushUptimeKuma calls an Uptime Kuma push monitor URL
+func (o *StatusPushOutput) pushUptimeKuma(ctx context.Context, check CheckConfig, healthy bool, message string) error {
+	pushURL, err := url.Parse(check.PushURL)
+	if err != nil {
+		return fmt.Errorf("invalid push_url: %w", err)
+	}
+
+	status := "up"
+	if !healthy {
+		status = "down"
+	}
+
+	query := pushURL.Query()
+	query.Set("status", status)
+	query.Set("msg", message)
+	pushURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pushURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	return o.do(req)
+}
+
+// pushCachet updates the status of a Cachet component
+func (o *StatusPushOutput) pushCachet(ctx context.Context, check CheckConfig, healthy bool) error {
+	status := cachetOperational
+	if !healthy {
+		status = cachetMajorOutage
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"status": status})
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v1/components/%d", o.url, check.ComponentID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Cachet-Token", o.apiToken)
+	return o.do(req)
+}
+
+// pushGeneric posts a status JSON document to a generic endpoint
+func (o *StatusPushOutput) pushGeneric(ctx context.Context, collector string, healthy bool, message string, results []collectors.Result) error {
+	status := "up"
+	if !healthy {
+		status = "down"
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"check":     collector,
+		"status":    status,
+		"message":   message,
+		"timestamp": time.Now().UTC(),
+		"results":   results,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.apiToken != "" {
+		req.Header.Set("Authorization", "Bearer "+o.apiToken)
+	}
+	return o.do(req)
+}
+
+// do sends the request and treats any non-2xx response as an error
+func (o *StatusPushOutput) do(req *http.Request) error {
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// Close performs any necessary cleanup
+func (o *StatusPushOutput) Close() error {
+	// No cleanup needed for status push output
+	return nil
+}
@@ -0,0 +1,137 @@
+// overrides.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/config"
+)
+
+// envPrefix starts the names of environment variables overriding settings
+const envPrefix = "MONIT_"
+
+// setting is a configuration value that can be overridden at startup from
+// the environment, as MONIT_<env>, and optionally a command line flag, so
+// containers can adjust it without mounting a modified configuration file.
+// A flag wins over the environment, which wins over the file.
+type setting struct {
+	env   string
+	flag  string // Empty for settings only overridden from the environment
+	usage string // Of the flag
+	apply func(cfg *config.Config, value string) error
+}
+
+// overridable are the settings that can be overridden
+var overridable = []setting{
+	{env: "LOG_LEVEL", flag: "log-level", usage: "Log level: debug, info, warn or error (logging.level)", apply: func(cfg *config.Config, value string) error {
+		cfg.Logging.Level = value
+		return nil
+	}},
+	{env: "INTERVAL", flag: "interval", usage: "Default check interval, e.g. 30s or 5m (monitor.default_interval_seconds)", apply: func(cfg *config.Config, value string) error {
+		d, err := collectors.ParseDuration(value, time.Second)
+		if err != nil {
+			return err
+		}
+		if d%time.Second != 0 {
+			return fmt.Errorf("duration %q is not a whole number of seconds", value)
+		}
+		cfg.Monitor.DefaultIntervalSeconds = config.Seconds(d / time.Second)
+		return nil
+	}},
+	{env: "API_LISTEN", flag: "api-listen", usage: "Address the HTTP API listens on, enabling it (api.listen)", apply: func(cfg *config.Config, value string) error {
+		cfg.API.Enabled = true
+		cfg.API.Listen = value
+		return nil
+	}},
+	{env: "MODE", apply: func(cfg *config.Config, value string) error {
+		cfg.Monitor.Mode = value
+		return nil
+	}},
+	{env: "HOSTNAME", apply: func(cfg *config.Config, value string) error {
+		cfg.Monitor.Hostname = value
+		return nil
+	}},
+	{env: "API_TOKEN", apply: func(cfg *config.Config, value string) error {
+		cfg.API.Token = value
+		return nil
+	}},
+	{env: "EMAIL_TO", apply: func(cfg *config.Config, value string) error {
+		cfg.Notifications.Email.To = splitList(value)
+		return nil
+	}},
+	{env: "EMAIL_FROM", apply: func(cfg *config.Config, value string) error {
+		cfg.Notifications.Email.From = value
+		return nil
+	}},
+	{env: "SMTP_SERVER", apply: func(cfg *config.Config, value string) error {
+		cfg.Notifications.Email.SMTPServer = value
+		return nil
+	}},
+	{env: "SMTP_PASSWORD", apply: func(cfg *config.Config, value string) error {
+		cfg.Notifications.Email.Password = value
+		return nil
+	}},
+	{env: "NTFY_SERVER", apply: func(cfg *config.Config, value string) error {
+		cfg.Notifications.Ntfy.Server = value
+		return nil
+	}},
+	{env: "NTFY_TOPIC", apply: func(cfg *config.Config, value string) error {
+		cfg.Notifications.Ntfy.Topic = value
+		return nil
+	}},
+	{env: "NTFY_TOKEN", apply: func(cfg *config.Config, value string) error {
+		cfg.Notifications.Ntfy.Token = value
+		return nil
+	}},
+}
+
+// overrideFlags holds the values of the override flags, by flag name
+var overrideFlags = make(map[string]*string)
+
+// registerOverrideFlags defines the override flags on the service's command
+// line
+func registerOverrideFlags() {
+	for _, s := range overridable {
+		if s.flag != "" {
+			overrideFlags[s.flag] = flag.String(s.flag, "", s.usage+"; also "+envPrefix+s.env)
+		}
+	}
+}
+
+// configOverrides returns the overrides set in the environment or, after
+// the command line is parsed, with flags
+func configOverrides() []config.Override {
+	var overrides []config.Override
+	for _, s := range overridable {
+		source, value := envPrefix+s.env, os.Getenv(envPrefix+s.env)
+		if s.flag != "" && flagPassed(s.flag) {
+			source, value = "-"+s.flag, *overrideFlags[s.flag]
+		}
+		if value == "" {
+			continue
+		}
+		overrides = append(overrides, func(cfg *config.Config) error {
+			if err := s.apply(cfg, value); err != nil {
+				return fmt.Errorf("%s: %w", source, err)
+			}
+			return nil
+		})
+	}
+	return overrides
+}
+
+// splitList splits a comma separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,126 @@
+// pipeline/pipeline.go
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/alerting"
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"go.uber.org/zap"
+)
+
+// Batch is a set of results published by one collector run, or injected,
+// travelling through the pipeline's stages
+type Batch struct {
+	Results     []collectors.Result
+	Hold        time.Duration    // How long new alerts stay pending
+	EvaluatedAt time.Time        // When the batch entered the pipeline
+	Events      []alerting.Event // Lifecycle transitions, once evaluated
+}
+
+// Processor is one stage of the pipeline. A stage may rewrite, enrich or
+// drop the batch's results; later stages only see what it leaves. An error
+// stops the batch from reaching the remaining stages.
+type Processor interface {
+	Name() string
+	Process(ctx context.Context, batch *Batch) error
+}
+
+// ProcessorFunc adapts a function into a named Processor
+type ProcessorFunc struct {
+	name string
+	fn   func(ctx context.Context, batch *Batch) error
+}
+
+// Func creates a Processor from a function
+func Func(name string, fn func(ctx context.Context, batch *Batch) error) ProcessorFunc {
+	return ProcessorFunc{name: name, fn: fn}
+}
+
+// Name returns the name of the stage
+func (p ProcessorFunc) Name() string {
+	return p.name
+}
+
+// Process runs the function
+func (p ProcessorFunc) Process(ctx context.Context, batch *Batch) error {
+	return p.fn(ctx, batch)
+}
+
+// Pipeline carries batches from the collectors through an ordered list of
+// processors, e.g. dedup, enrichment and routing, and finally to the
+// notifiers. Processors can be added while batches are flowing; a batch
+// runs through the stages that were in place when it was published.
+type Pipeline struct {
+	stages []Processor
+	mu     sync.RWMutex
+	logger *zap.Logger
+}
+
+// New creates a pipeline with the given stages, in order
+func New(logger *zap.Logger, stages ...Processor) *Pipeline {
+	return &Pipeline{stages: stages, logger: logger}
+}
+
+// Insert adds a stage before the named one, or at the end when before is
+// empty
+func (p *Pipeline) Insert(before string, stage Processor) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, existing := range p.stages {
+		if existing.Name() == stage.Name() {
+			err := fmt.Errorf("pipeline stage %s already exists", stage.Name())
+			p.logger.Error("Failed to add pipeline stage", zap.Error(err))
+			return err
+		}
+	}
+
+	if before == "" {
+		p.stages = append(p.stages, stage)
+		return nil
+	}
+	for i, existing := range p.stages {
+		if existing.Name() == before {
+			p.stages = append(p.stages[:i], append([]Processor{stage}, p.stages[i:]...)...)
+			return nil
+		}
+	}
+
+	err := fmt.Errorf("pipeline stage %s not found", before)
+	p.logger.Error("Failed to add pipeline stage", zap.String("stage", stage.Name()), zap.Error(err))
+	return err
+}
+
+// Stages returns the names of the stages, in order
+func (p *Pipeline) Stages() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	names := make([]string, len(p.stages))
+	for i, stage := range p.stages {
+		names[i] = stage.Name()
+	}
+	return names
+}
+
+// Publish runs a batch through every stage in order. Stages see the batch
+// as left by the previous one; a batch whose results were all dropped still
+// runs through the rest, so stages can react to empty runs.
+func (p *Pipeline) Publish(ctx context.Context, batch *Batch) error {
+	p.mu.RLock()
+	stages := append([]Processor(nil), p.stages...)
+	p.mu.RUnlock()
+
+	for _, stage := range stages {
+		if err := stage.Process(ctx, batch); err != nil {
+			p.logger.Debug("Pipeline stage failed", zap.String("stage", stage.Name()), zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// query.go
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/devvspaces/simple-monit/query"
+)
+
+// runQuery implements the "query" subcommand, which evaluates a query over
+// the service's result history through its API, e.g.
+// max(used_percent{collector="memory"}) over the last week
+func runQuery(args []string) int {
+	fs := flag.NewFlagSet("query", flag.ContinueOnError)
+	configPath := fs.String("config", "config.yaml", "Path to configuration file")
+	apiURL := fs.String("api", "", "Base URL of the service's API (default: from api.listen in the config)")
+	token := fs.String("token", "", "API token (default: api.token from the config)")
+	since := fs.String("since", "1h", "Start of the range, a duration back from now or an RFC 3339 time")
+	until := fs.String("until", "", "End of the range, a duration back from now or an RFC 3339 time (default: now)")
+	format := fs.String("format", "table", "Output format: table or json")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, `usage: server-monitor query [flags] 'max(used_percent{collector="memory"})'`)
+		return 2
+	}
+	if *format != "table" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "Unknown format %q: expected table or json\n", *format)
+		return 2
+	}
+	if _, err := query.Parse(fs.Arg(0)); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid query: %v\n", err)
+		return 2
+	}
+
+	base, bearer, err := apiEndpoint(*configPath, *apiURL, *token)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	params := url.Values{"query": {fs.Arg(0)}, "since": {*since}}
+	if *until != "" {
+		params.Set("until", *until)
+	}
+
+	var series []query.Series
+	if err := apiRequest(http.MethodGet, base+"/api/v1/query?"+params.Encode(), bearer, &series); err != nil {
+		fmt.Fprintf(os.Stderr, "Query failed: %v\n", err)
+		return 1
+	}
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(series)
+		return 0
+	}
+	if len(series) == 0 {
+		fmt.Println("No matching results")
+		return 0
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TARGET\tVALUE\tSAMPLES")
+	for _, s := range series {
+		fmt.Fprintf(w, "%s\t%s\t%d\n", s.Key, strconv.FormatFloat(s.Value, 'f', -1, 64), s.Samples)
+	}
+	w.Flush()
+	return 0
+}
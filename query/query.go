@@ -0,0 +1,301 @@
+// query/query.go
+package query
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/devvspaces/simple-monit/collectors"
+)
+
+// Aggregations over the samples of a series in the time range
+var aggregations = map[string]bool{
+	"avg":   true, // Mean of the samples
+	"min":   true,
+	"max":   true,
+	"sum":   true,
+	"count": true, // Number of samples
+	"last":  true, // Most recent sample
+	"rate":  true, // Per-second increase, allowing for counter resets
+}
+
+// Matcher selects results by the value of a label: = and != compare it,
+// =~ and !~ match it against an anchored regex
+type Matcher struct {
+	Label string
+	Op    string
+	Value string
+	re    *regexp.Regexp
+}
+
+// matches reports whether the matcher holds for the labels. A missing label
+// has an empty value.
+func (m Matcher) matches(labels map[string]string) bool {
+	value := labels[m.Label]
+	switch m.Op {
+	case "=":
+		return value == m.Value
+	case "!=":
+		return value != m.Value
+	case "=~":
+		return m.re.MatchString(value)
+	default:
+		return !m.re.MatchString(value)
+	}
+}
+
+// Query is a parsed query: an aggregation of one metric over the results
+// matching every matcher, e.g. max(used_percent{collector="memory"})
+type Query struct {
+	Aggregation string
+	Metric      string
+	Matchers    []Matcher
+}
+
+// Collector returns the collector an equality matcher restricts the query
+// to, or "" when it may match any, so only its history needs reading
+func (q *Query) Collector() string {
+	for _, m := range q.Matchers {
+		if m.Label == "collector" && m.Op == "=" {
+			return m.Value
+		}
+	}
+	return ""
+}
+
+// Parse parses a query of the form aggregation(metric{label="value", ...}),
+// where the braces are optional and matchers use =, !=, =~ or !~ with a
+// double-quoted value
+func Parse(expr string) (*Query, error) {
+	p := &parser{input: expr}
+	q := &Query{}
+
+	q.Aggregation = p.identifier()
+	if !aggregations[q.Aggregation] {
+		return nil, fmt.Errorf("expected an aggregation (avg, min, max, sum, count, last or rate) at offset %d", p.start)
+	}
+	if !p.consume("(") {
+		return nil, p.expected("(")
+	}
+	if q.Metric = p.identifier(); q.Metric == "" {
+		return nil, p.expected("a metric name")
+	}
+	if p.consume("{") {
+		for !p.consume("}") {
+			if len(q.Matchers) > 0 && !p.consume(",") {
+				return nil, p.expected(", or }")
+			}
+			if p.consume("}") {
+				break // Trailing comma
+			}
+			m, err := p.matcher()
+			if err != nil {
+				return nil, err
+			}
+			q.Matchers = append(q.Matchers, m)
+		}
+	}
+	if !p.consume(")") {
+		return nil, p.expected(")")
+	}
+	if p.skipSpace(); p.pos < len(p.input) {
+		return nil, fmt.Errorf("unexpected %q at offset %d", p.input[p.pos:], p.pos)
+	}
+	return q, nil
+}
+
+// parser scans a query
+type parser struct {
+	input string
+	pos   int
+	start int // Of the last token
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+	p.start = p.pos
+}
+
+// identifier scans a label, metric or aggregation name
+func (p *parser) identifier() string {
+	p.skipSpace()
+	end := p.pos
+	for end < len(p.input) {
+		c := p.input[end]
+		if c != '_' && c != '.' && c != '-' && !unicode.IsLetter(rune(c)) && !unicode.IsDigit(rune(c)) {
+			break
+		}
+		end++
+	}
+	name := p.input[p.pos:end]
+	p.pos = end
+	return name
+}
+
+// consume skips token if it comes next
+func (p *parser) consume(token string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.input[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *parser) expected(what string) error {
+	return fmt.Errorf("expected %s at offset %d", what, p.start)
+}
+
+// matcher scans label op "value"
+func (p *parser) matcher() (Matcher, error) {
+	m := Matcher{Label: p.identifier()}
+	if m.Label == "" {
+		return m, p.expected("a label name")
+	}
+	for _, op := range []string{"=~", "!~", "!=", "="} {
+		if p.consume(op) {
+			m.Op = op
+			break
+		}
+	}
+	if m.Op == "" {
+		return m, p.expected("=, !=, =~ or !~")
+	}
+
+	p.skipSpace()
+	if p.pos >= len(p.input) || p.input[p.pos] != '"' {
+		return m, p.expected("a double-quoted value")
+	}
+	end := p.pos + 1
+	for end < len(p.input) && p.input[end] != '"' {
+		if p.input[end] == '\\' {
+			end++
+		}
+		end++
+	}
+	if end >= len(p.input) {
+		return m, fmt.Errorf("unterminated value at offset %d", p.start)
+	}
+	value, err := strconv.Unquote(p.input[p.pos : end+1])
+	if err != nil {
+		return m, fmt.Errorf("invalid value at offset %d: %w", p.start, err)
+	}
+	m.Value = value
+	p.pos = end + 1
+
+	if m.Op == "=~" || m.Op == "!~" {
+		re, err := regexp.Compile("^(?:" + m.Value + ")$")
+		if err != nil {
+			return m, fmt.Errorf("invalid regex for label '%s': %w", m.Label, err)
+		}
+		m.re = re
+	}
+	return m, nil
+}
+
+// Series is the aggregated value of one target's metric
+type Series struct {
+	Key     string            `json:"key"`
+	Labels  map[string]string `json:"labels"`
+	Value   float64           `json:"value"`
+	Samples int               `json:"samples"`
+	First   time.Time         `json:"first"` // Of the samples aggregated
+	Last    time.Time         `json:"last"`
+}
+
+// series accumulates the samples of a target
+type series struct {
+	Series
+	sum      float64
+	min, max float64
+	last     float64
+	increase float64 // For rate, summed across counter resets
+}
+
+// Evaluator aggregates results fed to it, oldest first per target
+type Evaluator struct {
+	query  *Query
+	series map[string]*series
+}
+
+// NewEvaluator returns an evaluator of the query
+func NewEvaluator(q *Query) *Evaluator {
+	return &Evaluator{query: q, series: make(map[string]*series)}
+}
+
+// Add aggregates a result if it matches the query and has its metric.
+// Results of a target must be added in time order.
+func (e *Evaluator) Add(result collectors.Result) {
+	value, ok := result.Metrics[e.query.Metric]
+	if !ok || math.IsNaN(value) {
+		return
+	}
+	labels := result.Labels()
+	for _, m := range e.query.Matchers {
+		if !m.matches(labels) {
+			return
+		}
+	}
+
+	key := result.Key()
+	s, ok := e.series[key]
+	if !ok {
+		// Severity changes from sample to sample, so it does not identify
+		// the series
+		delete(labels, "severity")
+		s = &series{Series: Series{Key: key, Labels: labels, First: result.Timestamp}, min: value, max: value}
+		e.series[key] = s
+	} else {
+		if value >= s.last {
+			s.increase += value - s.last
+		} else {
+			s.increase += value // The counter reset; count it from zero
+		}
+	}
+	s.Samples++
+	s.sum += value
+	s.min = math.Min(s.min, value)
+	s.max = math.Max(s.max, value)
+	s.last = value
+	s.Last = result.Timestamp
+}
+
+// Result returns the aggregated series, by key. Rates need two samples
+// spanning some time, so series with fewer are left out of them.
+func (e *Evaluator) Result() []Series {
+	out := make([]Series, 0, len(e.series))
+	for _, s := range e.series {
+		result := s.Series
+		switch e.query.Aggregation {
+		case "avg":
+			result.Value = s.sum / float64(s.Samples)
+		case "min":
+			result.Value = s.min
+		case "max":
+			result.Value = s.max
+		case "sum":
+			result.Value = s.sum
+		case "count":
+			result.Value = float64(s.Samples)
+		case "last":
+			result.Value = s.last
+		case "rate":
+			elapsed := s.Last.Sub(s.First).Seconds()
+			if s.Samples < 2 || elapsed <= 0 {
+				continue
+			}
+			result.Value = s.increase / elapsed
+		}
+		out = append(out, result)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
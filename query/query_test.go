@@ -0,0 +1,205 @@
+// query/query_test.go
+package query
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+)
+
+var start = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// sample returns a result of collector with one metric, offset seconds after
+// start
+func sample(collector string, metadata map[string]interface{}, metric string, value float64, offset int) collectors.Result {
+	return collectors.Result{
+		IsHealthy: true,
+		Collector: collector,
+		Timestamp: start.Add(time.Duration(offset) * time.Second),
+		Metrics:   map[string]float64{metric: value},
+		Metadata:  metadata,
+	}
+}
+
+// evaluate parses expr and aggregates results with it
+func evaluate(t *testing.T, expr string, results []collectors.Result) []Series {
+	t.Helper()
+	q, err := Parse(expr)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", expr, err)
+	}
+	evaluator := NewEvaluator(q)
+	for _, result := range results {
+		evaluator.Add(result)
+	}
+	return evaluator.Result()
+}
+
+func TestAggregations(t *testing.T) {
+	var results []collectors.Result
+	for i, value := range []float64{40, 70, 10, 60} {
+		results = append(results, sample("memory", nil, "used_percent", value, i*60))
+	}
+
+	tests := []struct {
+		aggregation string
+		want        float64
+	}{
+		{"avg", 45},
+		{"min", 10},
+		{"max", 70},
+		{"sum", 180},
+		{"count", 4},
+		{"last", 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.aggregation, func(t *testing.T) {
+			series := evaluate(t, tt.aggregation+"(used_percent)", results)
+			if len(series) != 1 {
+				t.Fatalf("got %d series, want 1", len(series))
+			}
+			if series[0].Value != tt.want {
+				t.Errorf("got %v, want %v", series[0].Value, tt.want)
+			}
+			if series[0].Samples != 4 {
+				t.Errorf("got %d samples, want 4", series[0].Samples)
+			}
+		})
+	}
+}
+
+func TestRate(t *testing.T) {
+	counter := func(values ...float64) []collectors.Result {
+		var results []collectors.Result
+		for i, value := range values {
+			results = append(results, sample("haproxy", nil, "requests_total", value, i*10))
+		}
+		return results
+	}
+
+	tests := []struct {
+		name    string
+		results []collectors.Result
+		want    float64
+		series  int
+	}{
+		{"steady", counter(100, 200, 300), 10, 1},                    // 200 over 20s
+		{"counter reset", counter(100, 200, 50, 150), 250.0 / 30, 1}, // 100 + 50 + 100 over 30s
+		{"single sample", counter(100), 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := evaluate(t, "rate(requests_total)", tt.results)
+			if len(series) != tt.series {
+				t.Fatalf("got %d series, want %d", len(series), tt.series)
+			}
+			if tt.series > 0 && math.Abs(series[0].Value-tt.want) > 1e-9 {
+				t.Errorf("got %v, want %v", series[0].Value, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeriesPerTarget(t *testing.T) {
+	root := map[string]interface{}{"path": "/"}
+	data := map[string]interface{}{"path": "/data"}
+	results := []collectors.Result{
+		sample("disk_space", root, "used_percent", 50, 0),
+		sample("disk_space", data, "used_percent", 80, 0),
+		sample("disk_space", root, "used_percent", 70, 60),
+		sample("disk_space", data, "used_percent", 90, 60),
+	}
+
+	series := evaluate(t, "max(used_percent)", results)
+	if len(series) != 2 {
+		t.Fatalf("got %d series, want 2", len(series))
+	}
+	// Sorted by key: "disk_space|path=/" before "disk_space|path=/data"
+	if series[0].Labels["path"] != "/" || series[0].Value != 70 {
+		t.Errorf("got %v = %v, want / = 70", series[0].Labels["path"], series[0].Value)
+	}
+	if series[1].Labels["path"] != "/data" || series[1].Value != 90 {
+		t.Errorf("got %v = %v, want /data = 90", series[1].Labels["path"], series[1].Value)
+	}
+}
+
+func TestMatchers(t *testing.T) {
+	results := []collectors.Result{
+		sample("disk_space", map[string]interface{}{"path": "/"}, "used_percent", 50, 0),
+		sample("disk_space", map[string]interface{}{"path": "/data"}, "used_percent", 80, 0),
+		sample("disk_space", map[string]interface{}{"path": "/var/log"}, "used_percent", 30, 0),
+		sample("memory", nil, "used_percent", 20, 0),
+	}
+
+	tests := []struct {
+		expr  string
+		paths []string
+	}{
+		{`max(used_percent{collector="disk_space", path="/data"})`, []string{"/data"}},
+		{`max(used_percent{collector="disk_space",path!="/"})`, []string{"/data", "/var/log"}},
+		{`max(used_percent{path=~"/(data|var/.*)"})`, []string{"/data", "/var/log"}},
+		{`max(used_percent{collector="disk_space", path!~"/var/.*",})`, []string{"/", "/data"}},
+		{`max(used_percent{collector!="disk_space"})`, []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			series := evaluate(t, tt.expr, results)
+			if len(series) != len(tt.paths) {
+				t.Fatalf("got %d series, want %d", len(series), len(tt.paths))
+			}
+			for i, path := range tt.paths {
+				if series[i].Labels["path"] != path {
+					t.Errorf("series %d has path %q, want %q", i, series[i].Labels["path"], path)
+				}
+			}
+		})
+	}
+}
+
+func TestSkipsResultsWithoutTheMetric(t *testing.T) {
+	results := []collectors.Result{
+		sample("memory", nil, "used_percent", 20, 0),
+		sample("memory", nil, "free_gb", 3, 0),
+		sample("memory", nil, "used_percent", math.NaN(), 60),
+	}
+	series := evaluate(t, "count(used_percent)", results)
+	if len(series) != 1 || series[0].Value != 1 {
+		t.Fatalf("got %+v, want one series counting 1 sample", series)
+	}
+}
+
+func TestParse(t *testing.T) {
+	q, err := Parse(` avg ( used_percent { collector = "memory" , host =~ "web\\d+" } ) `)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if q.Aggregation != "avg" || q.Metric != "used_percent" || len(q.Matchers) != 2 {
+		t.Fatalf("got %+v", q)
+	}
+	if q.Collector() != "memory" {
+		t.Errorf("Collector() = %q, want memory", q.Collector())
+	}
+	if m := q.Matchers[1]; m.Label != "host" || m.Op != "=~" || m.Value != `web\d+` {
+		t.Errorf("got matcher %+v", m)
+	}
+
+	for _, expr := range []string{
+		"",
+		"median(used_percent)",
+		"max used_percent",
+		"max()",
+		"max(used_percent",
+		`max(used_percent{collector})`,
+		`max(used_percent{collector="memory"`,
+		`max(used_percent{collector=memory})`,
+		`max(used_percent{collector="memory})`,
+		`max(used_percent{path=~"("})`,
+		`max(used_percent) extra`,
+	} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", expr)
+		}
+	}
+}
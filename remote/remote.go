@@ -0,0 +1,248 @@
+// remote/remote.go
+package remote
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Local is the host name that stands for the machine the monitor runs on
+const Local = "local"
+
+// Host is a remote machine reached over SSH
+type Host struct {
+	Name                  string
+	Address               string // host:port
+	User                  string
+	KeyFile               string
+	KnownHostsFile        string
+	InsecureIgnoreHostKey bool
+	Timeout               time.Duration
+}
+
+// Target is a machine a collector checks: the local one, or a remote host
+// with a client to run commands on it
+type Target struct {
+	Name   string
+	Client *Client // nil for the local machine
+}
+
+// IsLocal reports whether the target is the machine the monitor runs on
+func (t Target) IsLocal() bool {
+	return t.Client == nil
+}
+
+// Targets parses a collector's "hosts" setting into targets. Entries are
+// "local" or host settings as resolved by config.Config.CollectorSettings.
+// Without the setting, only the local machine is checked.
+func Targets(settings map[string]interface{}) ([]Target, error) {
+	raw, ok := settings["hosts"]
+	if !ok {
+		return []Target{{Name: Local}}, nil
+	}
+	entries, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("'hosts' should be an array")
+	}
+
+	var targets []Target
+	for _, entry := range entries {
+		switch entry := entry.(type) {
+		case string:
+			if entry != Local {
+				return nil, fmt.Errorf("host %s is not defined in the hosts section", entry)
+			}
+			targets = append(targets, Target{Name: Local})
+		case map[string]interface{}:
+			host := Host{
+				Name:                  collectors.GetString(entry, "name", ""),
+				Address:               collectors.GetString(entry, "address", ""),
+				User:                  collectors.GetString(entry, "user", ""),
+				KeyFile:               collectors.GetString(entry, "key_file", ""),
+				KnownHostsFile:        collectors.GetString(entry, "known_hosts_file", ""),
+				InsecureIgnoreHostKey: collectors.GetBool(entry, "insecure_ignore_host_key", false),
+				Timeout:               time.Duration(collectors.GetInt(entry, "timeout_seconds", 10)) * time.Second,
+			}
+			client, err := NewClient(host)
+			if err != nil {
+				CloseAll(targets)
+				return nil, err
+			}
+			targets = append(targets, Target{Name: host.Name, Client: client})
+		default:
+			return nil, fmt.Errorf("each entry in 'hosts' should be a host name")
+		}
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("'hosts' must not be empty")
+	}
+	return targets, nil
+}
+
+// CloseAll closes the clients of remote targets
+func CloseAll(targets []Target) {
+	for _, target := range targets {
+		if target.Client != nil {
+			target.Client.Close()
+		}
+	}
+}
+
+// Client runs commands on a remote host over SSH. The connection is opened
+// on first use and kept open; a broken connection is replaced on the next
+// command.
+type Client struct {
+	host   Host
+	config *ssh.ClientConfig
+	conn   *ssh.Client
+	mu     sync.Mutex
+}
+
+// NewClient prepares a client for the host, loading its private key and
+// known hosts. It does not connect yet.
+func NewClient(host Host) (*Client, error) {
+	if host.Address == "" || host.User == "" || host.KeyFile == "" {
+		return nil, fmt.Errorf("host %s needs an address, user and key_file", host.Name)
+	}
+	if _, _, err := net.SplitHostPort(host.Address); err != nil {
+		host.Address = net.JoinHostPort(host.Address, "22")
+	}
+
+	key, err := os.ReadFile(expandHome(host.KeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SSH key for host %s: %w", host.Name, err)
+	}
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SSH key for host %s: %w", host.Name, err)
+	}
+
+	hostKeyCallback := ssh.InsecureIgnoreHostKey()
+	if !host.InsecureIgnoreHostKey {
+		file := host.KnownHostsFile
+		if file == "" {
+			file = "~/.ssh/known_hosts"
+		}
+		hostKeyCallback, err = knownhosts.New(expandHome(file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load known hosts for host %s: %w", host.Name, err)
+		}
+	}
+
+	return &Client{
+		host: host,
+		config: &ssh.ClientConfig{
+			User:            host.User,
+			Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+			HostKeyCallback: hostKeyCallback,
+			Timeout:         host.Timeout,
+		},
+	}, nil
+}
+
+// Run runs a command on the host and returns its standard output. The
+// command is killed when ctx is done.
+func (c *Client) Run(ctx context.Context, command string) ([]byte, error) {
+	session, err := c.session()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	var stdout, stderr bytes.Buffer
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+
+	done := make(chan error, 1)
+	go func() { done <- session.Run(command) }()
+
+	select {
+	case <-ctx.Done():
+		session.Signal(ssh.SIGKILL)
+		session.Close()
+		return nil, ctx.Err()
+	case err := <-done:
+		if err != nil {
+			return nil, fmt.Errorf("command %q on host %s failed: %w: %s", command, c.host.Name, err, strings.TrimSpace(stderr.String()))
+		}
+		return stdout.Bytes(), nil
+	}
+}
+
+// session opens a session, connecting or reconnecting first when needed
+func (c *Client) session() (*ssh.Session, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != nil {
+		if session, err := c.conn.NewSession(); err == nil {
+			return session, nil
+		}
+		// The connection broke; reconnect below
+		c.conn.Close()
+		c.conn = nil
+	}
+
+	conn, err := ssh.Dial("tcp", c.host.Address, c.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to host %s (%s): %w", c.host.Name, c.host.Address, err)
+	}
+	c.conn = conn
+
+	session, err := conn.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SSH session on host %s: %w", c.host.Name, err)
+	}
+	return session, nil
+}
+
+// Close closes the connection, if open
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// Quote quotes a value for a POSIX shell command line
+func Quote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// expandHome expands a leading ~ to the user's home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// HostsSetting describes the "hosts" setting of collectors that can check
+// remote hosts
+var HostsSetting = collectors.Setting{
+	Name:        "hosts",
+	Type:        "list",
+	Default:     Local,
+	Description: "Hosts to check: " + Local + " or names from the hosts section",
+}
@@ -0,0 +1,258 @@
+// secrets/secrets.go
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Scheme starts the settings values that refer to a secret
+const Scheme = "secret://"
+
+// Provider reads secrets from a secrets backend
+type Provider interface {
+	// Read returns the keys and values of the secret at path
+	Read(ctx context.Context, path string) (map[string]string, error)
+	// Close stops any background work, e.g. renewing credentials
+	Close() error
+}
+
+// Ref refers to the key of a secret kept by a provider
+type Ref struct {
+	Provider string
+	Path     string
+	Key      string
+}
+
+// ParseRef parses a reference such as secret://vault/kv/monit#smtp_password.
+// It returns false for values that are not references.
+func ParseRef(value string) (Ref, bool, error) {
+	rest, ok := strings.CutPrefix(value, Scheme)
+	if !ok {
+		return Ref{}, false, nil
+	}
+	rest, key, _ := strings.Cut(rest, "#")
+	provider, path, _ := strings.Cut(rest, "/")
+	if provider == "" || path == "" || key == "" {
+		return Ref{}, true, fmt.Errorf("invalid secret reference %q, expected %s<provider>/<path>#<key>", value, Scheme)
+	}
+	return Ref{Provider: provider, Path: path, Key: key}, true, nil
+}
+
+// String returns the reference as written in the configuration
+func (r Ref) String() string {
+	return Scheme + r.Provider + "/" + r.Path + "#" + r.Key
+}
+
+// Refs returns the references in settings, sorted. Malformed references are
+// skipped; Resolve reports them.
+func Refs(settings map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	walk(settings, func(value string) {
+		if ref, ok, err := ParseRef(value); ok && err == nil {
+			seen[ref.String()] = true
+		}
+	})
+	refs := make([]string, 0, len(seen))
+	for ref := range seen {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	return refs
+}
+
+// walk calls fn with every string in a settings value
+func walk(value interface{}, fn func(string)) {
+	switch v := value.(type) {
+	case string:
+		fn(v)
+	case []string:
+		for _, item := range v {
+			fn(item)
+		}
+	case map[string]string:
+		for _, item := range v {
+			fn(item)
+		}
+	case []interface{}:
+		for _, item := range v {
+			walk(item, fn)
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			walk(item, fn)
+		}
+	}
+}
+
+// pathKey identifies a secret of a provider
+type pathKey struct {
+	provider string
+	path     string
+}
+
+// Resolver replaces references in settings with the secrets they refer to.
+// It remembers the secrets it has read, so Refresh can tell which of them
+// changed since.
+type Resolver struct {
+	providers map[string]Provider
+	secrets   map[pathKey]map[string]string
+	mu        sync.Mutex
+	logger    *zap.Logger
+}
+
+// NewResolver creates a resolver without providers
+func NewResolver(logger *zap.Logger) *Resolver {
+	return &Resolver{
+		providers: make(map[string]Provider),
+		secrets:   make(map[pathKey]map[string]string),
+		logger:    logger,
+	}
+}
+
+// Register makes a provider available to references under name
+func (r *Resolver) Register(name string, provider Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[name] = provider
+}
+
+// Resolve returns a copy of settings with every reference replaced by its
+// secret. Secrets already read are reused until Refresh reads them again.
+func (r *Resolver) Resolve(ctx context.Context, settings map[string]interface{}) (map[string]interface{}, error) {
+	resolved, err := r.resolve(ctx, settings)
+	if err != nil {
+		return nil, err
+	}
+	out, _ := resolved.(map[string]interface{})
+	return out, nil
+}
+
+// resolve replaces the references in a settings value
+func (r *Resolver) resolve(ctx context.Context, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return r.resolveString(ctx, v)
+	case []string:
+		out := make([]string, len(v))
+		for i, item := range v {
+			s, err := r.resolveString(ctx, item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = s
+		}
+		return out, nil
+	case map[string]string:
+		out := make(map[string]string, len(v))
+		for key, item := range v {
+			s, err := r.resolveString(ctx, item)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = s
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			resolved, err := r.resolve(ctx, item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = resolved
+		}
+		return out, nil
+	case map[string]interface{}:
+		if v == nil {
+			return v, nil
+		}
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			resolved, err := r.resolve(ctx, item)
+			if err != nil {
+				return nil, fmt.Errorf("'%s': %w", key, err)
+			}
+			out[key] = resolved
+		}
+		return out, nil
+	}
+	return value, nil
+}
+
+// resolveString returns the secret a value refers to, or the value itself
+func (r *Resolver) resolveString(ctx context.Context, value string) (string, error) {
+	ref, ok, err := ParseRef(value)
+	if !ok || err != nil {
+		return value, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	at := pathKey{ref.Provider, ref.Path}
+	secret, cached := r.secrets[at]
+	if !cached {
+		provider, exists := r.providers[ref.Provider]
+		if !exists {
+			return "", fmt.Errorf("%s: no secrets provider named '%s' is configured", ref, ref.Provider)
+		}
+		if secret, err = provider.Read(ctx, ref.Path); err != nil {
+			r.logger.Error("Failed to read secret", zap.String("provider", ref.Provider), zap.String("path", ref.Path), zap.Error(err))
+			return "", fmt.Errorf("%s: %w", ref, err)
+		}
+		r.secrets[at] = secret
+	}
+
+	resolved, exists := secret[ref.Key]
+	if !exists {
+		return "", fmt.Errorf("%s: the secret has no key '%s'", ref, ref.Key)
+	}
+	return resolved, nil
+}
+
+// Refresh reads every secret resolved so far again and returns the
+// references whose values changed, or disappeared. A secret that cannot be
+// read keeps its previous values.
+func (r *Resolver) Refresh(ctx context.Context) map[string]bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changed := make(map[string]bool)
+	for at, old := range r.secrets {
+		secret, err := r.providers[at.provider].Read(ctx, at.path)
+		if err != nil {
+			r.logger.Warn("Failed to refresh secret, keeping the previous values", zap.String("provider", at.provider), zap.String("path", at.path), zap.Error(err))
+			continue
+		}
+		for key, value := range old {
+			if current, exists := secret[key]; !exists || current != value {
+				changed[Ref{Provider: at.provider, Path: at.path, Key: key}.String()] = true
+			}
+		}
+		r.secrets[at] = secret
+	}
+	return changed
+}
+
+// Close closes the providers
+func (r *Resolver) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []string
+	for name, provider := range r.providers {
+		if err := provider.Close(); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to close secrets providers: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
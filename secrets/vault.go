@@ -0,0 +1,295 @@
+// secrets/vault.go
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Vault token renewal timing
+const (
+	vaultRenewRetry    = 30 * time.Second
+	vaultMinRenewDelay = 5 * time.Second
+)
+
+// VaultOptions configures the connection to a HashiCorp Vault server
+type VaultOptions struct {
+	Address            string
+	Token              string
+	TokenFile          string // Read again before every renewal, e.g. a Vault Agent sink
+	Namespace          string
+	KVVersion          int // Of the key/value secrets engines, 1 or 2
+	CAFile             string
+	InsecureSkipVerify bool
+	Timeout            time.Duration
+}
+
+// Vault reads secrets from the key/value secrets engines of a HashiCorp
+// Vault server. The first segment of a path is the engine's mount, so with
+// version 2 kv/monit reads kv/data/monit. A renewable token is renewed in the
+// background before its TTL runs out.
+type Vault struct {
+	options VaultOptions
+	client  *http.Client
+	token   string
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	done    chan struct{}
+	logger  *zap.Logger
+}
+
+// vaultResponse is the part of Vault's responses the provider reads
+type vaultResponse struct {
+	Data json.RawMessage `json:"data"`
+	Auth struct {
+		LeaseDuration int  `json:"lease_duration"`
+		Renewable     bool `json:"renewable"`
+	} `json:"auth"`
+	Errors []string `json:"errors"`
+}
+
+// NewVault connects to Vault, checks the token and starts renewing it
+func NewVault(logger *zap.Logger, options VaultOptions) (*Vault, error) {
+	if options.Address == "" {
+		options.Address = os.Getenv("VAULT_ADDR")
+	}
+	options.Address = strings.TrimRight(options.Address, "/")
+	if options.Address == "" {
+		return nil, fmt.Errorf("missing vault 'address'")
+	}
+	if options.KVVersion == 0 {
+		options.KVVersion = 2
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 10 * time.Second
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: options.InsecureSkipVerify, MinVersion: tls.VersionTLS12}
+	if options.CAFile != "" {
+		pem, err := os.ReadFile(options.CAFile)
+		if err != nil {
+			logger.Error("Failed to read vault CA file", zap.String("ca_file", options.CAFile), zap.Error(err))
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in vault 'ca_file' %s", options.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	v := &Vault{
+		options: options,
+		client: &http.Client{
+			Timeout:   options.Timeout,
+			Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig},
+		},
+		done:   make(chan struct{}),
+		logger: logger,
+	}
+	if err := v.loadToken(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), options.Timeout)
+	defer cancel()
+	var lookup vaultResponse
+	if err := v.do(ctx, http.MethodGet, "auth/token/lookup-self", nil, &lookup); err != nil {
+		logger.Error("Failed to look up vault token", zap.String("address", options.Address), zap.Error(err))
+		return nil, fmt.Errorf("vault token lookup: %w", err)
+	}
+
+	var token struct {
+		TTL       int  `json:"ttl"`
+		Renewable bool `json:"renewable"`
+	}
+	if err := json.Unmarshal(lookup.Data, &token); err != nil {
+		return nil, fmt.Errorf("invalid vault token lookup response: %w", err)
+	}
+
+	renewCtx, stop := context.WithCancel(context.Background())
+	v.cancel = stop
+	go v.renew(renewCtx, token.Renewable, time.Duration(token.TTL)*time.Second)
+	return v, nil
+}
+
+// loadToken reads the token from the options, the token file or VAULT_TOKEN
+func (v *Vault) loadToken() error {
+	token := v.options.Token
+	if v.options.TokenFile != "" {
+		data, err := os.ReadFile(v.options.TokenFile)
+		if err != nil {
+			v.logger.Error("Failed to read vault token file", zap.String("token_file", v.options.TokenFile), zap.Error(err))
+			return err
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	if token == "" {
+		return fmt.Errorf("missing vault 'token' or 'token_file'")
+	}
+
+	v.mu.Lock()
+	v.token = token
+	v.mu.Unlock()
+	return nil
+}
+
+// renew renews a renewable token when two thirds of its TTL have passed,
+// until ctx is done. Tokens without a TTL never expire and are not renewed.
+func (v *Vault) renew(ctx context.Context, renewable bool, ttl time.Duration) {
+	defer close(v.done)
+	if ttl <= 0 || (!renewable && v.options.TokenFile == "") {
+		return
+	}
+
+	delay := renewDelay(ttl)
+	for {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// A token file is kept fresh by whoever writes it
+		if v.options.TokenFile != "" {
+			if err := v.loadToken(); err != nil {
+				delay = vaultRenewRetry
+				continue
+			}
+		}
+		if !renewable {
+			delay = vaultRenewRetry
+			continue
+		}
+
+		requestCtx, cancel := context.WithTimeout(ctx, v.options.Timeout)
+		var renewed vaultResponse
+		err := v.do(requestCtx, http.MethodPost, "auth/token/renew-self", []byte("{}"), &renewed)
+		cancel()
+		if err != nil {
+			v.logger.Warn("Failed to renew vault token, retrying", zap.Duration("retry_in", vaultRenewRetry), zap.Error(err))
+			delay = vaultRenewRetry
+			continue
+		}
+		renewable = renewed.Auth.Renewable
+		ttl = time.Duration(renewed.Auth.LeaseDuration) * time.Second
+		v.logger.Debug("Renewed vault token", zap.Duration("ttl", ttl))
+		if ttl <= 0 {
+			return
+		}
+		delay = renewDelay(ttl)
+	}
+}
+
+// renewDelay returns how long to wait before renewing a token with ttl left
+func renewDelay(ttl time.Duration) time.Duration {
+	if delay := ttl * 2 / 3; delay > vaultMinRenewDelay {
+		return delay
+	}
+	return vaultMinRenewDelay
+}
+
+// Read returns the keys and values of the secret at path
+func (v *Vault) Read(ctx context.Context, path string) (map[string]string, error) {
+	path = strings.Trim(path, "/")
+	if v.options.KVVersion == 2 {
+		mount, rest, ok := strings.Cut(path, "/")
+		if !ok {
+			return nil, fmt.Errorf("vault path %q has no secret below the mount", path)
+		}
+		path = mount + "/data/" + rest
+	}
+
+	var response vaultResponse
+	if err := v.do(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return nil, err
+	}
+
+	// Version 1 returns the secret as the data, version 2 nests it
+	raw := response.Data
+	if v.options.KVVersion == 2 {
+		var versioned struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(raw, &versioned); err != nil {
+			return nil, fmt.Errorf("invalid vault response: %w", err)
+		}
+		raw = versioned.Data
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil || fields == nil {
+		return nil, fmt.Errorf("vault path %q holds no key/value secret", path)
+	}
+
+	secret := make(map[string]string, len(fields))
+	for key, value := range fields {
+		if s, ok := value.(string); ok {
+			secret[key] = s
+		} else {
+			secret[key] = fmt.Sprint(value)
+		}
+	}
+	return secret, nil
+}
+
+// do sends a request to the Vault API and decodes the response into out
+func (v *Vault) do(ctx context.Context, method, path string, body []byte, out *vaultResponse) error {
+	req, err := http.NewRequestWithContext(ctx, method, v.options.Address+"/v1/"+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	v.mu.Lock()
+	req.Header.Set("X-Vault-Token", v.token)
+	v.mu.Unlock()
+	if v.options.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.options.Namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, out); err != nil && resp.StatusCode < 300 {
+		return fmt.Errorf("invalid vault response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		if len(out.Errors) > 0 {
+			return fmt.Errorf("vault returned %s: %s", resp.Status, strings.Join(out.Errors, "; "))
+		}
+		return fmt.Errorf("vault returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Close stops renewing the token
+func (v *Vault) Close() error {
+	v.cancel()
+	<-v.done
+	return nil
+}
@@ -0,0 +1,26 @@
+//go:build !windows
+
+// service_other.go
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// runService implements the "service" subcommand, which manages the service
+// on Windows only; elsewhere use the system's service manager, e.g. systemd
+func runService(args []string) int {
+	fmt.Fprintln(os.Stderr, "The service command is only available on Windows; use your system's service manager, e.g. systemd")
+	return 1
+}
+
+// isWindowsService reports whether the process was started by the Windows
+// service manager, never the case here
+func isWindowsService() bool { return false }
+
+// runWindowsService is only called on Windows
+func runWindowsService(serve func(control serviceControl)) int {
+	serve(serviceControl{})
+	return 0
+}
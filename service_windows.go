@@ -0,0 +1,284 @@
+//go:build windows
+
+// service_windows.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/eventlog"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// serviceName is the name the service is installed under, and the source of
+// its event log entries
+const serviceName = "simple-monit"
+
+// runService implements the "service" subcommand, which installs, removes,
+// starts and stops the Windows service
+func runService(args []string) int {
+	usage := "usage: server-monitor service install|uninstall|start|stop [flags]"
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	var err error
+	switch args[0] {
+	case "install":
+		fs := flag.NewFlagSet("service install", flag.ContinueOnError)
+		configPath := fs.String("config", "config.yaml", "Path to configuration file")
+		configDir := fs.String("config-dir", "", "Directory of YAML fragments merged into the configuration")
+		profile := fs.String("profile", "", "Profile whose overlay is merged over the configuration file")
+		watch := fs.Bool("watch-config", false, "Reload the configuration automatically when the file or directory changes")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+		err = installService(*configPath, *profile, *configDir, *watch)
+	case "uninstall":
+		err = uninstallService()
+	case "start":
+		err = controlService(func(s *mgr.Service) error { return s.Start() })
+	case "stop":
+		err = controlService(stopService)
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to %s service %s: %v\n", args[0], serviceName, err)
+		return 1
+	}
+	fmt.Printf("Service %s: %s done\n", serviceName, args[0])
+	return 0
+}
+
+// installService registers the running binary as an automatically started
+// service with the given configuration, and the event log source it logs
+// to. Paths are made absolute, as services start in the system directory.
+func installService(configPath, profile, configDir string, watch bool) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	configPath, err = filepath.Abs(configPath)
+	if err != nil {
+		return err
+	}
+	args := []string{"-config", configPath}
+	if profile != "" {
+		args = append(args, "-profile", profile)
+	}
+	if configDir != "" {
+		if configDir, err = filepath.Abs(configDir); err != nil {
+			return err
+		}
+		args = append(args, "-config-dir", configDir)
+	}
+	if watch {
+		args = append(args, "-watch-config")
+	}
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+	if s, err := m.OpenService(serviceName); err == nil {
+		s.Close()
+		return fmt.Errorf("already installed; uninstall it first")
+	}
+	s, err := m.CreateService(serviceName, exe, mgr.Config{
+		DisplayName: "simple-monit",
+		Description: "Monitors this server and sends alerts",
+		StartType:   mgr.StartAutomatic,
+	}, args...)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	if err := eventlog.InstallAsEventCreate(serviceName, eventlog.Error|eventlog.Warning|eventlog.Info); err != nil {
+		s.Delete()
+		return fmt.Errorf("failed to register the event log source: %w", err)
+	}
+	return nil
+}
+
+// uninstallService removes the service and its event log source
+func uninstallService() error {
+	if err := controlService(func(s *mgr.Service) error { return s.Delete() }); err != nil {
+		return err
+	}
+	if err := eventlog.Remove(serviceName); err != nil {
+		return fmt.Errorf("failed to remove the event log source: %w", err)
+	}
+	return nil
+}
+
+// controlService applies action to the installed service
+func controlService(action func(s *mgr.Service) error) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+	s, err := m.OpenService(serviceName)
+	if err != nil {
+		return fmt.Errorf("not installed: %w", err)
+	}
+	defer s.Close()
+	return action(s)
+}
+
+// stopService asks the service to stop and waits up to 30 seconds for it
+// to do so
+func stopService(s *mgr.Service) error {
+	status, err := s.Control(svc.Stop)
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(30 * time.Second)
+	for status.State != svc.Stopped {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for it to stop")
+		}
+		time.Sleep(300 * time.Millisecond)
+		if status, err = s.Query(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isWindowsService reports whether the process was started by the service
+// manager
+func isWindowsService() bool {
+	is, err := svc.IsWindowsService()
+	return err == nil && is
+}
+
+// runWindowsService runs serve under the service manager, logging to the
+// event log too, and returns the exit code
+func runWindowsService(serve func(control serviceControl)) int {
+	events, err := eventlog.Open(serviceName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open the event log: %v\n", err)
+		return 1
+	}
+	defer events.Close()
+
+	if err := svc.Run(serviceName, &windowsService{serve: serve, events: events}); err != nil {
+		events.Error(1, fmt.Sprintf("Service failed: %v", err))
+		return 1
+	}
+	return 0
+}
+
+// windowsService handles the service manager's requests
+type windowsService struct {
+	serve  func(control serviceControl)
+	events *eventlog.Log
+}
+
+// Execute runs the service until the service manager stops it. A parameter
+// change request reloads the configuration.
+func (ws *windowsService) Execute(args []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	status <- svc.Status{State: svc.StartPending}
+
+	stop := make(chan struct{})
+	reload := make(chan struct{}, 1)
+	done := make(chan struct{})
+	running := svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown | svc.AcceptParamChange}
+	go func() {
+		defer close(done)
+		ws.serve(serviceControl{
+			stop:    stop,
+			reload:  reload,
+			ready:   func() { status <- running },
+			options: []zap.Option{zap.WrapCore(ws.withEventLog)},
+		})
+	}()
+
+	for {
+		select {
+		case <-done:
+			return false, 0
+		case request := <-requests:
+			switch request.Cmd {
+			case svc.Interrogate:
+				status <- request.CurrentStatus
+			case svc.ParamChange:
+				select {
+				case reload <- struct{}{}:
+				default: // A reload is already pending
+				}
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending}
+				close(stop)
+				<-done
+				return false, 0
+			}
+		}
+	}
+}
+
+// withEventLog tees the entries of core at info and above into the event
+// log
+func (ws *windowsService) withEventLog(core zapcore.Core) zapcore.Core {
+	enabled := zap.LevelEnablerFunc(func(level zapcore.Level) bool {
+		return level >= zapcore.InfoLevel && core.Enabled(level)
+	})
+	encoder := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	return zapcore.NewTee(core, &eventLogCore{LevelEnabler: enabled, encoder: encoder, events: ws.events})
+}
+
+// eventLogCore writes log entries to the event log as JSON, as information,
+// warning or error events by level
+type eventLogCore struct {
+	zapcore.LevelEnabler
+	encoder zapcore.Encoder
+	events  *eventlog.Log
+}
+
+func (c *eventLogCore) With(fields []zapcore.Field) zapcore.Core {
+	encoder := c.encoder.Clone()
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+	return &eventLogCore{LevelEnabler: c.LevelEnabler, encoder: encoder, events: c.events}
+}
+
+func (c *eventLogCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *eventLogCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return err
+	}
+	message := buf.String()
+	buf.Free()
+
+	switch {
+	case entry.Level >= zapcore.ErrorLevel:
+		return c.events.Error(1, message)
+	case entry.Level == zapcore.WarnLevel:
+		return c.events.Warning(1, message)
+	default:
+		return c.events.Info(1, message)
+	}
+}
+
+func (c *eventLogCore) Sync() error { return nil }
@@ -0,0 +1,197 @@
+// simulate.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/monitor"
+
+	"go.uber.org/zap"
+)
+
+// metricsFlag collects metric=value pairs, comma separated or repeated
+type metricsFlag map[string]float64
+
+func (m metricsFlag) String() string {
+	var pairs []string
+	for k, v := range m {
+		pairs = append(pairs, fmt.Sprintf("%s=%g", k, v))
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (m metricsFlag) Set(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		key, raw, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || key == "" {
+			return fmt.Errorf("expected metric=value, got %q", pair)
+		}
+		val, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("metric %s: %w", key, err)
+		}
+		m[key] = val
+	}
+	return nil
+}
+
+// runSimulate implements the "simulate" subcommand. It pushes a fake result,
+// clearly marked as simulated, to the running service, where it goes
+// through the live pipeline with its dedup, active alerts, inhibition,
+// dependencies, escalation and history, so routing and on-call response can
+// be rehearsed. With -local, it builds the configured pipeline in-process
+// instead and injects the result there.
+func runSimulate(args []string) int {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	configPath := fs.String("config", "config.yaml", "Path to configuration file")
+	apiURL := fs.String("api", "", "Base URL of the service's API (default: from api.listen in the config)")
+	token := fs.String("token", "", "API token (default: api.token from the config)")
+	local := fs.Bool("local", false, "Inject the result into a pipeline built in-process instead of the running service")
+	configDir := fs.String("config-dir", "", "Directory of YAML fragments merged into the configuration")
+	profile := fs.String("profile", os.Getenv(envPrefix+"PROFILE"), "Profile whose overlay, e.g. config.prod.yaml, is merged over the configuration file; also "+envPrefix+"PROFILE")
+	collectorName := fs.String("collector", "", "Collector the simulated result appears to come from")
+	message := fs.String("message", "", "Result message (default: generated from the metrics)")
+	healthy := fs.Bool("healthy", false, "Simulate a healthy result, e.g. to rehearse recoveries")
+	severity := fs.String("severity", "", "Severity of the unhealthy result: info, warning or critical (default: derived from the metrics)")
+	metrics := metricsFlag{}
+	fs.Var(metrics, "metrics", "Metric values as metric=value, comma separated or repeated")
+	metadata := metadataFlag{}
+	fs.Var(metadata, "metadata", "Result metadata as key=value (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if *collectorName == "" {
+		fmt.Fprintln(os.Stderr, "usage: server-monitor simulate -collector <name> [-metrics metric=value,...] [flags]")
+		return 2
+	}
+	if *severity != "" && !collectors.ValidSeverity(*severity) {
+		fmt.Fprintf(os.Stderr, "Unknown severity %q, expected info, warning or critical\n", *severity)
+		return 2
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "can't initialize zap logger: %v\n", err)
+		return 1
+	}
+	defer logger.Sync()
+
+	result := collectors.Result{
+		IsHealthy: *healthy,
+		Collector: *collectorName,
+		Timestamp: time.Now(),
+		Message:   "[SIMULATED] " + simulatedMessage(*collectorName, *message, *healthy, metrics),
+		Metrics:   metrics,
+		Metadata:  map[string]interface{}{"simulated": true},
+	}
+	for k, v := range metadata {
+		result.Metadata[k] = v
+	}
+	if !*healthy {
+		result.Severity = *severity
+	}
+	state := "unhealthy"
+	if *healthy {
+		state = "healthy"
+	}
+
+	if !*local && *apiURL != "" {
+		return simulateRemote(logger, *apiURL, *token, result, state)
+	}
+	cfg, err := loadConfigFiles(logger.Named("config"), *configPath, *profile, *configDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+	if !*local {
+		base, bearer, err := configEndpoint(cfg, *configPath, *token)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v; pass -local to simulate without a running service\n", err)
+			return 1
+		}
+		return simulateRemote(logger, base, bearer, result, state)
+	}
+
+	service := monitor.NewMonitorService(logger.Named("monitor"), cfg)
+	if err := service.Prepare(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to prepare monitoring pipeline: %v\n", err)
+		return 1
+	}
+	defer service.Stop()
+
+	if !service.HasCollector(*collectorName) {
+		logger.Warn("Simulating a result for an unregistered collector", zap.String("collector", *collectorName))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	logger.Info("Injecting simulated result", zap.Any("result", result))
+	if err := service.Inject(ctx, []collectors.Result{result}); err != nil {
+		fmt.Fprintf(os.Stderr, "Simulated result was not fully delivered: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Simulated %s result for %s injected\n", state, *collectorName)
+	return 0
+}
+
+// simulateRemote pushes a simulated result to the running service's API
+func simulateRemote(logger *zap.Logger, base, token string, result collectors.Result, state string) int {
+	body := map[string]interface{}{
+		"simulated":  true,
+		"collector":  result.Collector,
+		"is_healthy": result.IsHealthy,
+		"message":    result.Message,
+		"severity":   result.Severity,
+		"timestamp":  result.Timestamp,
+		"metrics":    result.Metrics,
+		"metadata":   result.Metadata,
+	}
+
+	logger.Info("Pushing simulated result", zap.String("api", base), zap.Any("result", result))
+	var accepted map[string]int
+	if err := apiSend(http.MethodPost, base+"/api/v1/results", token, body, &accepted); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to push the simulated result: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Simulated %s result for %s sent to %s\n", state, result.Collector, base)
+	return 0
+}
+
+// simulatedMessage returns the message for a simulated result
+func simulatedMessage(collector, message string, healthy bool, metrics map[string]float64) string {
+	if message != "" {
+		return message
+	}
+
+	kind := "alert"
+	if healthy {
+		kind = "recovery"
+	}
+	if len(metrics) == 0 {
+		return fmt.Sprintf("Simulated %s from %s", kind, collector)
+	}
+
+	keys := make([]string, 0, len(metrics))
+	for k := range metrics {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%.2f", k, metrics[k]))
+	}
+	return fmt.Sprintf("Simulated %s from %s: %s", kind, collector, strings.Join(pairs, ", "))
+}
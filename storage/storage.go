@@ -0,0 +1,734 @@
+// storage/storage.go
+package storage
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+
+	bolt "go.etcd.io/bbolt"
+	"go.uber.org/zap"
+)
+
+// The results bucket holds one nested bucket per collector, keyed by the
+// result's big-endian timestamp and a sequence number, so a cursor walks a
+// collector's results in time order. The alerts bucket holds the alert
+// state saved for restarts, keyed by target. The downtime bucket holds ended
+// downtimes in nested collector buckets keyed like results, by start; the
+// down bucket holds the downtimes still going on, keyed by target. The
+// incidents bucket holds alert lifecycle events in nested collector buckets,
+// keyed like results, by time.
+var (
+	resultsBucket   = []byte("results")
+	alertsBucket    = []byte("alerts")
+	downtimeBucket  = []byte("downtime")
+	downBucket      = []byte("down")
+	incidentsBucket = []byte("incidents")
+	runsBucket      = []byte("runs")
+)
+
+// ErrInvalidCursor is returned for a cursor QueryPage did not return
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// pruneBatch bounds the deletions per transaction while pruning
+const pruneBatch = 10000
+
+// AlertState is the saved state of an active alert, restored after a restart
+// so recoveries still notify and repeat notifications stay deduplicated
+type AlertState struct {
+	Result           collectors.Result `json:"result"`
+	Since            time.Time         `json:"since"`
+	NotifiedAt       time.Time         `json:"notified_at,omitempty"`
+	NotifiedSeverity string            `json:"notified_severity,omitempty"`
+	AcknowledgedBy   string            `json:"acknowledged_by,omitempty"`
+}
+
+// Downtime is a period a target was unhealthy, from its first unhealthy
+// result to its next healthy one
+type Downtime struct {
+	Key       string    `json:"key"`
+	Collector string    `json:"collector"`
+	Severity  string    `json:"severity"` // Most severe during the downtime
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end,omitempty"` // Zero while the target is still down
+}
+
+// Incident events, in the order an alert goes through them
+const (
+	IncidentOpened       = "opened"       // The target started firing
+	IncidentAcknowledged = "acknowledged" // A mute rule silenced the alert
+	IncidentResolved     = "resolved"     // The target recovered
+)
+
+// IncidentEvent is a step in the life of an alert, for incident timelines
+type IncidentEvent struct {
+	Time            time.Time `json:"time"`
+	Event           string    `json:"event"`
+	Key             string    `json:"key"`
+	Collector       string    `json:"collector"`
+	Fingerprint     string    `json:"fingerprint"`
+	Severity        string    `json:"severity,omitempty"`
+	Message         string    `json:"message"`
+	Opened          time.Time `json:"opened"`
+	DurationSeconds float64   `json:"duration_seconds"`          // Since the alert opened
+	AcknowledgedBy  string    `json:"acknowledged_by,omitempty"` // ID of the mute rule
+}
+
+// Run is one execution of a collector, for the run audit
+type Run struct {
+	Collector       string    `json:"collector"`
+	Started         time.Time `json:"started"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	Results         int       `json:"results"`         // Produced by the run, before the series limit
+	Error           string    `json:"error,omitempty"` // Why the run failed, if it did
+	Panicked        bool      `json:"panicked,omitempty"`
+}
+
+// Store persists results and alert state in a BoltDB file
+type Store struct {
+	db     *bolt.DB
+	logger *zap.Logger
+}
+
+// Open opens (or creates) the history database at path
+func Open(logger *zap.Logger, path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{resultsBucket, alertsBucket, downtimeBucket, downBucket, incidentsBucket, runsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize history %s: %w", path, err)
+	}
+
+	return &Store{db: db, logger: logger}, nil
+}
+
+// Record stores results, and starts or ends the downtime of their targets
+func (s *Store) Record(results []collectors.Result) error {
+	if len(results) == 0 {
+		return nil
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		root := tx.Bucket(resultsBucket)
+		for _, result := range results {
+			value, err := json.Marshal(result)
+			if err != nil {
+				return err
+			}
+			bucket, err := root.CreateBucketIfNotExists([]byte(result.Collector))
+			if err != nil {
+				return err
+			}
+			seq, err := bucket.NextSequence()
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(resultKey(result.Timestamp, seq), value); err != nil {
+				return err
+			}
+			if err := recordDowntime(tx, result); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// recordDowntime starts a downtime on a target's first unhealthy result,
+// raises its severity on later ones, and ends it on the next healthy result
+func recordDowntime(tx *bolt.Tx, result collectors.Result) error {
+	down := tx.Bucket(downBucket)
+	key := []byte(result.Key())
+
+	var downtime Downtime
+	if raw := down.Get(key); raw != nil {
+		if err := json.Unmarshal(raw, &downtime); err != nil {
+			return err
+		}
+	}
+	ongoing := !downtime.Start.IsZero()
+
+	switch {
+	case !result.IsHealthy:
+		severity := result.EffectiveSeverity()
+		if ongoing && collectors.SeverityAtLeast(downtime.Severity, severity) {
+			return nil
+		}
+		if !ongoing {
+			downtime = Downtime{Key: result.Key(), Collector: result.Collector, Start: result.Timestamp}
+		}
+		downtime.Severity = severity
+		value, err := json.Marshal(downtime)
+		if err != nil {
+			return err
+		}
+		return down.Put(key, value)
+
+	case ongoing:
+		downtime.End = result.Timestamp
+		value, err := json.Marshal(downtime)
+		if err != nil {
+			return err
+		}
+		bucket, err := tx.Bucket(downtimeBucket).CreateBucketIfNotExists([]byte(downtime.Collector))
+		if err != nil {
+			return err
+		}
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		if err := bucket.Put(resultKey(downtime.Start, seq), value); err != nil {
+			return err
+		}
+		return down.Delete(key)
+	}
+	return nil
+}
+
+// Downtimes returns the downtimes of a collector's targets, or of every
+// collector's when collector is empty, that overlap since up to until,
+// ordered by start. Ongoing downtimes have a zero End. A zero until means up
+// to now.
+func (s *Store) Downtimes(collector string, since, until time.Time) ([]Downtime, error) {
+	overlaps := func(d Downtime) bool {
+		if collector != "" && d.Collector != collector {
+			return false
+		}
+		if !until.IsZero() && d.Start.After(until) {
+			return false
+		}
+		return d.End.IsZero() || d.End.After(since)
+	}
+
+	var downtimes []Downtime
+	err := s.db.View(func(tx *bolt.Tx) error {
+		collect := func(_, v []byte) error {
+			var downtime Downtime
+			if err := json.Unmarshal(v, &downtime); err != nil {
+				s.logger.Warn("Skipping unreadable stored downtime", zap.Error(err))
+				return nil
+			}
+			if overlaps(downtime) {
+				downtimes = append(downtimes, downtime)
+			}
+			return nil
+		}
+
+		if err := tx.Bucket(downBucket).ForEach(collect); err != nil {
+			return err
+		}
+		root := tx.Bucket(downtimeBucket)
+		if collector != "" {
+			if bucket := root.Bucket([]byte(collector)); bucket != nil {
+				return bucket.ForEach(collect)
+			}
+			return nil
+		}
+		return root.ForEachBucket(func(name []byte) error {
+			return root.Bucket(name).ForEach(collect)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(downtimes, func(i, j int) bool { return downtimes[i].Start.Before(downtimes[j].Start) })
+	return downtimes, nil
+}
+
+// RecordIncidentEvents stores alert lifecycle events
+func (s *Store) RecordIncidentEvents(events []IncidentEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		root := tx.Bucket(incidentsBucket)
+		for _, event := range events {
+			bucket, err := root.CreateBucketIfNotExists([]byte(event.Collector))
+			if err != nil {
+				return err
+			}
+			seq, err := bucket.NextSequence()
+			if err != nil {
+				return err
+			}
+			value, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(resultKey(event.Time, seq), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record incident events: %w", err)
+	}
+	return nil
+}
+
+// IncidentEvents returns a collector's alert lifecycle events from since up
+// to until, or those of every collector when collector is empty, oldest
+// first. A zero until means up to now.
+func (s *Store) IncidentEvents(collector string, since, until time.Time) ([]IncidentEvent, error) {
+	var events []IncidentEvent
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(incidentsBucket)
+		query := func(bucket *bolt.Bucket) error {
+			c := bucket.Cursor()
+			for k, v := c.Seek(timeKey(since)); k != nil; k, v = c.Next() {
+				if !until.IsZero() && bytes.Compare(k[:8], timeKey(until)) > 0 {
+					break
+				}
+				var event IncidentEvent
+				if err := json.Unmarshal(v, &event); err != nil {
+					s.logger.Warn("Skipping unreadable stored incident event", zap.Error(err))
+					continue
+				}
+				events = append(events, event)
+			}
+			return nil
+		}
+
+		if collector != "" {
+			if bucket := root.Bucket([]byte(collector)); bucket != nil {
+				return query(bucket)
+			}
+			return nil
+		}
+		return root.ForEachBucket(func(name []byte) error {
+			return query(root.Bucket(name))
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events, nil
+}
+
+// Query returns a collector's results from since up to until, oldest first,
+// or those of every collector when collector is empty. A zero until means
+// up to now.
+func (s *Store) Query(collector string, since, until time.Time) ([]collectors.Result, error) {
+	var results []collectors.Result
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(resultsBucket)
+		query := func(bucket *bolt.Bucket) error {
+			c := bucket.Cursor()
+			for k, v := c.Seek(timeKey(since)); k != nil; k, v = c.Next() {
+				if !until.IsZero() && bytes.Compare(k[:8], timeKey(until)) > 0 {
+					break
+				}
+				var result collectors.Result
+				if err := json.Unmarshal(v, &result); err != nil {
+					s.logger.Warn("Skipping unreadable stored result", zap.Error(err))
+					continue
+				}
+				results = append(results, result)
+			}
+			return nil
+		}
+
+		if collector != "" {
+			if bucket := root.Bucket([]byte(collector)); bucket != nil {
+				return query(bucket)
+			}
+			return nil
+		}
+		return root.ForEachBucket(func(name []byte) error {
+			return query(root.Bucket(name))
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Timestamp.Before(results[j].Timestamp) })
+	return results, nil
+}
+
+// Each calls fn with a collector's results from since up to until, or those
+// of every collector when collector is empty, without loading them all at
+// once. Each collector's results come in time order, one collector after
+// the other. A zero until means up to now.
+func (s *Store) Each(collector string, since, until time.Time, fn func(collectors.Result)) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(resultsBucket)
+		each := func(bucket *bolt.Bucket) error {
+			c := bucket.Cursor()
+			for k, v := c.Seek(timeKey(since)); k != nil; k, v = c.Next() {
+				if !until.IsZero() && bytes.Compare(k[:8], timeKey(until)) > 0 {
+					break
+				}
+				var result collectors.Result
+				if err := json.Unmarshal(v, &result); err != nil {
+					s.logger.Warn("Skipping unreadable stored result", zap.Error(err))
+					continue
+				}
+				fn(result)
+			}
+			return nil
+		}
+
+		if collector != "" {
+			if bucket := root.Bucket([]byte(collector)); bucket != nil {
+				return each(bucket)
+			}
+			return nil
+		}
+		return root.ForEachBucket(func(name []byte) error {
+			return each(root.Bucket(name))
+		})
+	})
+}
+
+// QueryPage returns up to limit results like Query, continuing after cursor,
+// a position returned by a previous page, or from since when it is empty.
+// Results are ordered by time, then collector. The returned cursor continues
+// with the next page, and is empty after the last one.
+func (s *Store) QueryPage(collector string, since, until time.Time, cursor string, limit int) ([]collectors.Result, string, error) {
+	var after []byte
+	if cursor != "" {
+		decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil || len(decoded) < 16 {
+			return nil, "", ErrInvalidCursor
+		}
+		after = decoded
+	}
+
+	// Each collector's bucket contributes up to one more result than the
+	// page holds, which tells whether there is a next page
+	type entry struct {
+		position []byte // Key followed by the collector, as in cursors
+		result   collectors.Result
+	}
+	var entries []entry
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(resultsBucket)
+		query := func(name []byte, bucket *bolt.Bucket) error {
+			start := timeKey(since)
+			if after != nil && bytes.Compare(after[:8], start) > 0 {
+				start = after[:8]
+			}
+			c := bucket.Cursor()
+			found := 0
+			for k, v := c.Seek(start); k != nil && found <= limit; k, v = c.Next() {
+				if !until.IsZero() && bytes.Compare(k[:8], timeKey(until)) > 0 {
+					break
+				}
+				position := append(append([]byte(nil), k...), name...)
+				if after != nil && comparePositions(position, after) <= 0 {
+					continue
+				}
+				var result collectors.Result
+				if err := json.Unmarshal(v, &result); err != nil {
+					s.logger.Warn("Skipping unreadable stored result", zap.Error(err))
+					continue
+				}
+				entries = append(entries, entry{position: position, result: result})
+				found++
+			}
+			return nil
+		}
+
+		if collector != "" {
+			if bucket := root.Bucket([]byte(collector)); bucket != nil {
+				return query([]byte(collector), bucket)
+			}
+			return nil
+		}
+		return root.ForEachBucket(func(name []byte) error {
+			return query(name, root.Bucket(name))
+		})
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return comparePositions(entries[i].position, entries[j].position) < 0 })
+	next := ""
+	if len(entries) > limit {
+		entries = entries[:limit]
+		next = base64.RawURLEncoding.EncodeToString(entries[limit-1].position)
+	}
+	results := make([]collectors.Result, len(entries))
+	for i, e := range entries {
+		results[i] = e.result
+	}
+	return results, next, nil
+}
+
+// comparePositions orders positions in the results by time, collector, then
+// sequence number
+func comparePositions(a, b []byte) int {
+	if c := bytes.Compare(a[:8], b[:8]); c != 0 {
+		return c
+	}
+	if c := bytes.Compare(a[16:], b[16:]); c != 0 {
+		return c
+	}
+	return bytes.Compare(a[8:16], b[8:16])
+}
+
+// Prune deletes results older than before and returns how many it deleted
+func (s *Store) Prune(before time.Time) (int, error) {
+	var names [][]byte
+	s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(resultsBucket).ForEachBucket(func(name []byte) error {
+			names = append(names, append([]byte(nil), name...))
+			return nil
+		})
+	})
+
+	pruned := 0
+	limit := timeKey(before)
+	for _, name := range names {
+		for {
+			deleted := 0
+			err := s.db.Update(func(tx *bolt.Tx) error {
+				bucket := tx.Bucket(resultsBucket).Bucket(name)
+				if bucket == nil {
+					return nil
+				}
+				var keys [][]byte
+				c := bucket.Cursor()
+				for k, _ := c.First(); k != nil && bytes.Compare(k[:8], limit) < 0 && len(keys) < pruneBatch; k, _ = c.Next() {
+					keys = append(keys, append([]byte(nil), k...))
+				}
+				for _, k := range keys {
+					if err := bucket.Delete(k); err != nil {
+						return err
+					}
+				}
+				deleted = len(keys)
+				return nil
+			})
+			if err != nil {
+				return pruned, fmt.Errorf("failed to prune history of %s: %w", name, err)
+			}
+			pruned += deleted
+			if deleted < pruneBatch {
+				break
+			}
+		}
+	}
+	return pruned, nil
+}
+
+// PruneDowntimes deletes the downtimes that ended before before
+func (s *Store) PruneDowntimes(before time.Time) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		root := tx.Bucket(downtimeBucket)
+		return root.ForEachBucket(func(name []byte) error {
+			bucket := root.Bucket(name)
+			var keys [][]byte
+			bucket.ForEach(func(k, v []byte) error {
+				var downtime Downtime
+				if json.Unmarshal(v, &downtime) != nil || downtime.End.Before(before) {
+					keys = append(keys, append([]byte(nil), k...))
+				}
+				return nil
+			})
+			for _, k := range keys {
+				if err := bucket.Delete(k); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to prune downtimes: %w", err)
+	}
+	return nil
+}
+
+// PruneIncidentEvents deletes the incident events from before before
+func (s *Store) PruneIncidentEvents(before time.Time) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		root := tx.Bucket(incidentsBucket)
+		return root.ForEachBucket(func(name []byte) error {
+			bucket := root.Bucket(name)
+			c := bucket.Cursor()
+			for k, _ := c.First(); k != nil && bytes.Compare(k[:8], timeKey(before)) < 0; k, _ = c.First() {
+				if err := bucket.Delete(k); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to prune incident events: %w", err)
+	}
+	return nil
+}
+
+// RecordRun stores a collector run
+func (s *Store) RecordRun(run Run) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.Bucket(runsBucket).CreateBucketIfNotExists([]byte(run.Collector))
+		if err != nil {
+			return err
+		}
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		value, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		return bucket.Put(resultKey(run.Started, seq), value)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record collector run: %w", err)
+	}
+	return nil
+}
+
+// Runs returns the collector runs started from since up to until, oldest
+// first, optionally of one collector. A zero until means up to now.
+func (s *Store) Runs(collector string, since, until time.Time) ([]Run, error) {
+	var runs []Run
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(runsBucket)
+		query := func(bucket *bolt.Bucket) error {
+			c := bucket.Cursor()
+			for k, v := c.Seek(timeKey(since)); k != nil; k, v = c.Next() {
+				if !until.IsZero() && bytes.Compare(k[:8], timeKey(until)) > 0 {
+					break
+				}
+				var run Run
+				if err := json.Unmarshal(v, &run); err != nil {
+					s.logger.Warn("Skipping unreadable stored collector run", zap.Error(err))
+					continue
+				}
+				runs = append(runs, run)
+			}
+			return nil
+		}
+
+		if collector != "" {
+			if bucket := root.Bucket([]byte(collector)); bucket != nil {
+				return query(bucket)
+			}
+			return nil
+		}
+		return root.ForEachBucket(func(name []byte) error {
+			return query(root.Bucket(name))
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].Started.Before(runs[j].Started) })
+	return runs, nil
+}
+
+// PruneRuns deletes collector runs started before the given time
+func (s *Store) PruneRuns(before time.Time) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		root := tx.Bucket(runsBucket)
+		return root.ForEachBucket(func(name []byte) error {
+			bucket := root.Bucket(name)
+			c := bucket.Cursor()
+			for k, _ := c.First(); k != nil && bytes.Compare(k[:8], timeKey(before)) < 0; k, _ = c.First() {
+				if err := bucket.Delete(k); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to prune collector runs: %w", err)
+	}
+	return nil
+}
+
+// SaveAlerts replaces the saved alert state
+func (s *Store) SaveAlerts(alerts []AlertState) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(alertsBucket); err != nil {
+			return err
+		}
+		bucket, err := tx.CreateBucket(alertsBucket)
+		if err != nil {
+			return err
+		}
+		for _, alert := range alerts {
+			value, err := json.Marshal(alert)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put([]byte(alert.Result.Key()), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// LoadAlerts returns the saved alert state
+func (s *Store) LoadAlerts() ([]AlertState, error) {
+	var alerts []AlertState
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(alertsBucket).ForEach(func(_, v []byte) error {
+			var alert AlertState
+			if err := json.Unmarshal(v, &alert); err != nil {
+				s.logger.Warn("Skipping unreadable saved alert", zap.Error(err))
+				return nil
+			}
+			alerts = append(alerts, alert)
+			return nil
+		})
+	})
+	return alerts, err
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// timeKey encodes a time so keys sort chronologically. Times before the
+// Unix epoch, including the zero time, encode as the smallest key.
+func timeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	if t.After(time.Unix(0, 0)) {
+		binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	}
+	return key
+}
+
+// resultKey is a result's time key followed by a sequence number, so results
+// with the same timestamp do not overwrite each other
+func resultKey(t time.Time, seq uint64) []byte {
+	key := make([]byte, 16)
+	copy(key, timeKey(t))
+	binary.BigEndian.PutUint64(key[8:], seq)
+	return key
+}
@@ -0,0 +1,299 @@
+// templates/templates.go
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+
+	"go.uber.org/zap"
+)
+
+// Data is what notification templates are rendered with
+type Data struct {
+	Results    []collectors.Result // Results in the notification
+	Result     collectors.Result   // First result; per-result messages render one result at a time
+	Count      int                 // Number of results
+	Severity   string              // Most severe effective severity of the results
+	Collectors []string            // Distinct collector names, sorted
+	Hostname   string              // Host of the first result: the monitor's, or the sender's for a pushed result
+	Tags       map[string]string   // Labels every result shares, e.g. collector for a single run
+	Time       time.Time           // Render time
+}
+
+// severityRank orders severities from least to most severe
+var severityRank = map[string]int{
+	collectors.SeverityInfo:     1,
+	collectors.SeverityWarning:  2,
+	collectors.SeverityCritical: 3,
+}
+
+var (
+	hostnameOnce sync.Once
+	hostname     string
+)
+
+// Hostname returns the system's host name, which a monitor shows unless its
+// monitor.hostname is set
+func Hostname() string {
+	hostnameOnce.Do(func() {
+		hostname, _ = os.Hostname()
+		if hostname == "" {
+			hostname = "unknown"
+		}
+	})
+	return hostname
+}
+
+// HostOf returns the host a result comes from: its hostname tag, which the
+// monitor stamps with its own unless the result was pushed with one, or else
+// the system's
+func HostOf(result collectors.Result) string {
+	if name := result.Tags["hostname"]; name != "" {
+		return name
+	}
+	return Hostname()
+}
+
+// NewData builds the template data for results
+func NewData(results []collectors.Result) Data {
+	data := Data{
+		Results:  results,
+		Count:    len(results),
+		Hostname: Hostname(),
+		Time:     time.Now(),
+	}
+	if len(results) == 0 {
+		return data
+	}
+	data.Result = results[0]
+	data.Hostname = HostOf(results[0])
+
+	seen := make(map[string]bool)
+	for i, result := range results {
+		if severity := result.EffectiveSeverity(); severityRank[severity] > severityRank[data.Severity] {
+			data.Severity = severity
+		}
+
+		if !seen[result.Collector] {
+			seen[result.Collector] = true
+			data.Collectors = append(data.Collectors, result.Collector)
+		}
+
+		// Keep only the labels with the same value on every result
+		labels := result.Labels()
+		if i == 0 {
+			data.Tags = labels
+			continue
+		}
+		for k, v := range data.Tags {
+			if labels[k] != v {
+				delete(data.Tags, k)
+			}
+		}
+	}
+	sort.Strings(data.Collectors)
+	return data
+}
+
+// funcs are the helpers available to every template
+var funcs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	"add": func(a, b int) int {
+		return a + b
+	},
+	"join": func(sep string, items []string) string {
+		return strings.Join(items, sep)
+	},
+	"truncate": func(n int, s string) string {
+		if len(s) <= n {
+			return s
+		}
+		return s[:n]
+	},
+	"severity": func(r collectors.Result) string {
+		return r.EffectiveSeverity()
+	},
+	"labels": func(r collectors.Result) map[string]string {
+		return r.Labels()
+	},
+	"key": func(r collectors.Result) string {
+		return r.Key()
+	},
+	"fingerprint": func(r collectors.Result) string {
+		return r.Fingerprint()
+	},
+	"time": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+	"metric": func(v float64) string {
+		return fmt.Sprintf("%.2f", v)
+	},
+	"default": func(fallback, value string) string {
+		if value == "" {
+			return fallback
+		}
+		return value
+	},
+}
+
+// Set holds a notifier's named templates, e.g. subject and body. Every field
+// has a built-in default that can be replaced by a file named
+// <notifier>.<field>.tmpl in the templates directory.
+type Set struct {
+	notifier  string
+	defaults  map[string]*template.Template
+	overrides map[string]*template.Template
+	logger    *zap.Logger
+}
+
+// New parses a notifier's default templates and any overrides found in dir
+func New(logger *zap.Logger, notifier string, defaults map[string]string, dir string) (*Set, error) {
+	s := &Set{
+		notifier:  notifier,
+		defaults:  make(map[string]*template.Template, len(defaults)),
+		overrides: make(map[string]*template.Template),
+		logger:    logger,
+	}
+
+	for field, text := range defaults {
+		tmpl, err := parse(notifier+"."+field, text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default %s template: %w", field, err)
+		}
+		s.defaults[field] = tmpl
+
+		if dir == "" {
+			continue
+		}
+
+		path := filepath.Join(dir, notifier+"."+field+".tmpl")
+		content, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %w", path, err)
+		}
+
+		override, err := parse(filepath.Base(path), string(content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", path, err)
+		}
+		s.overrides[field] = override
+		logger.Info("Using custom notification template", zap.String("notifier", notifier), zap.String("field", field), zap.String("path", path))
+	}
+
+	return s, nil
+}
+
+// FromSettings creates a notifier's templates from its Init settings: the
+// defaults, replaced by the files found in templates_dir and, over those, by
+// the notifier's templates setting. Its keys are fields, with the template
+// text, or fields suffixed with _file, with the path of a template file.
+func FromSettings(logger *zap.Logger, notifier string, defaults map[string]string, settings map[string]interface{}) (*Set, error) {
+	s, err := New(logger, notifier, defaults, collectors.GetString(settings, "templates_dir", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	var configured map[string]string
+	switch raw := settings["templates"].(type) {
+	case map[string]string:
+		configured = raw
+	case map[string]interface{}:
+		configured = make(map[string]string, len(raw))
+		for key, value := range raw {
+			configured[key] = fmt.Sprint(value)
+		}
+	}
+
+	for key, value := range configured {
+		field, isFile := strings.CutSuffix(key, "_file")
+		if _, known := defaults[field]; !known {
+			fields := make([]string, 0, len(defaults))
+			for name := range defaults {
+				fields = append(fields, name)
+			}
+			sort.Strings(fields)
+			return nil, fmt.Errorf("unknown template '%s', the %s notifier's fields are: %s", key, notifier, strings.Join(fields, ", "))
+		}
+
+		name, text := notifier+"."+field, value
+		if isFile {
+			content, err := os.ReadFile(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read template %s: %w", value, err)
+			}
+			name, text = filepath.Base(value), string(content)
+		}
+		tmpl, err := parse(name, text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s template: %w", key, err)
+		}
+		s.overrides[field] = tmpl
+		logger.Info("Using configured notification template", zap.String("notifier", notifier), zap.String("field", field))
+	}
+
+	return s, nil
+}
+
+// parse parses a template with the shared helpers
+func parse(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(funcs).Option("missingkey=zero").Parse(text)
+}
+
+// Render renders a field. A custom template that fails to render falls back
+// to the default, so a template mistake never drops an alert.
+func (s *Set) Render(field string, data Data) string {
+	if tmpl, ok := s.overrides[field]; ok {
+		out, err := execute(tmpl, data)
+		if err == nil {
+			return out
+		}
+		s.logger.Error("Failed to render custom notification template, using the default",
+			zap.String("notifier", s.notifier), zap.String("field", field), zap.Error(err))
+	}
+
+	tmpl, ok := s.defaults[field]
+	if !ok {
+		s.logger.Error("Unknown notification template field", zap.String("notifier", s.notifier), zap.String("field", field))
+		return ""
+	}
+
+	out, err := execute(tmpl, data)
+	if err != nil {
+		s.logger.Error("Failed to render notification template", zap.String("notifier", s.notifier), zap.String("field", field), zap.Error(err))
+	}
+	return out
+}
+
+// RenderResults renders a field for a batch of results
+func (s *Set) RenderResults(field string, results []collectors.Result) string {
+	return s.Render(field, NewData(results))
+}
+
+// RenderResult renders a field for a single result
+func (s *Set) RenderResult(field string, result collectors.Result) string {
+	return s.Render(field, NewData([]collectors.Result{result}))
+}
+
+// execute renders tmpl to a string
+func execute(tmpl *template.Template, data Data) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,219 @@
+// top.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/devvspaces/simple-monit/collectors"
+	"github.com/devvspaces/simple-monit/config"
+	"github.com/devvspaces/simple-monit/monitor"
+
+	"go.uber.org/zap"
+)
+
+// ANSI sequences used by the live view
+const (
+	ansiClear      = "\033[H\033[2J"
+	ansiHideCursor = "\033[?25l"
+	ansiShowCursor = "\033[?25h"
+	ansiRed        = "\033[31m"
+	ansiYellow     = "\033[33m"
+	ansiGreen      = "\033[32m"
+	ansiBold       = "\033[1m"
+	ansiReset      = "\033[0m"
+)
+
+// topStatus is what the live view shows; it matches /api/v1/status
+type topStatus struct {
+	Hostname     string                   `json:"hostname"`
+	ActiveAlerts int                      `json:"active_alerts"`
+	Collectors   []monitor.CollectorState `json:"collectors"`
+	Results      []collectors.Result      `json:"results"`
+}
+
+// runTop implements the "top" subcommand: a live view of every collector and
+// target, read from the running service's API or, with -local, from
+// collectors run in-process with notifications and outputs disabled
+func runTop(args []string) int {
+	fs := flag.NewFlagSet("top", flag.ContinueOnError)
+	configPath := fs.String("config", "config.yaml", "Path to configuration file")
+	apiURL := fs.String("api", "", "Base URL of the service's API (default: from api.listen in the config)")
+	token := fs.String("token", "", "API token (default: api.token from the config)")
+	local := fs.Bool("local", false, "Run the configured collectors in-process instead of connecting to the service")
+	refresh := fs.Duration("refresh", 2*time.Second, "How often the view is redrawn")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	var status func() (topStatus, error)
+	if *local {
+		service, err := startLocalService(*configPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		defer service.Stop()
+		status = func() (topStatus, error) {
+			return topStatus{
+				Hostname:     service.Hostname(),
+				ActiveAlerts: len(service.ActiveAlerts()),
+				Collectors:   service.Collectors(),
+				Results:      service.LatestResults(),
+			}, nil
+		}
+	} else {
+		base, bearer, err := apiEndpoint(*configPath, *apiURL, *token)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		status = func() (topStatus, error) {
+			var s topStatus
+			err := apiRequest(http.MethodGet, base+"/api/v1/status", bearer, &s)
+			return s, err
+		}
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	ticker := time.NewTicker(*refresh)
+	defer ticker.Stop()
+
+	os.Stdout.WriteString(ansiHideCursor)
+	defer os.Stdout.WriteString(ansiShowCursor)
+
+	for {
+		s, err := status()
+		os.Stdout.Write(renderTop(s, err, time.Now()))
+
+		select {
+		case <-sigChan:
+			return 0
+		case <-ticker.C:
+		}
+	}
+}
+
+// startLocalService starts the configured collectors in-process without
+// notifiers, outputs, history or the API, so the view can run ad hoc
+func startLocalService(configPath string) (*monitor.MonitorService, error) {
+	cfg, err := config.LoadConfig(zap.NewNop(), configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	cfg.Notifications = config.NotificationsConfig{}
+	cfg.Outputs = nil
+	cfg.History = config.HistoryConfig{}
+	cfg.Monitor.HeartbeatSeconds = 0
+
+	service := monitor.New(cfg)
+	if err := service.Start(); err != nil {
+		service.Stop()
+		return nil, fmt.Errorf("failed to start collectors: %w", err)
+	}
+	return service, nil
+}
+
+// renderTop draws one frame of the live view
+func renderTop(s topStatus, err error, now time.Time) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(ansiClear)
+
+	fmt.Fprintf(&buf, "%sserver-monitor top%s  %s  %s", ansiBold, ansiReset, s.Hostname, now.Format("15:04:05"))
+	switch {
+	case err != nil:
+		fmt.Fprintf(&buf, "  %sunreachable: %v%s\n\n", ansiRed, err, ansiReset)
+		return buf.Bytes()
+	case s.ActiveAlerts == 1:
+		fmt.Fprintf(&buf, "  %s1 active alert%s\n\n", ansiRed, ansiReset)
+	case s.ActiveAlerts > 1:
+		fmt.Fprintf(&buf, "  %s%d active alerts%s\n\n", ansiRed, s.ActiveAlerts, ansiReset)
+	default:
+		fmt.Fprintf(&buf, "  %sall OK%s\n\n", ansiGreen, ansiReset)
+	}
+
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "COLLECTOR\tSTATE\tLAST RUN\tNEXT RUN\tDURATION\tFAILURES\tLAST ERROR")
+	for _, c := range s.Collectors {
+		if !c.Running && c.Runs == 0 {
+			continue
+		}
+		lastRun, nextRun := "-", "-"
+		if c.LastRun != nil {
+			lastRun = now.Sub(*c.LastRun).Round(time.Second).String() + " ago"
+		}
+		if c.NextRun != nil && c.Running {
+			nextRun = "in " + c.NextRun.Sub(now).Round(time.Second).String()
+			if c.Paused {
+				nextRun = "paused"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%dms\t%d/%d\t%s\n",
+			c.Name, describeState(c), lastRun, nextRun, c.LastDurationMs, c.Failures, c.Runs, truncate(c.LastError, 40))
+	}
+	w.Flush()
+	buf.WriteString("\n")
+
+	// Padded by hand; tabwriter would count the color codes as text
+	width := len("TARGET")
+	for _, result := range s.Results {
+		width = max(width, len(result.Key()))
+	}
+	fmt.Fprintf(&buf, "%-*s  %-8s  %-6s  %s\n", width, "TARGET", "STATUS", "AGE", "VALUES")
+	for _, result := range s.Results {
+		fmt.Fprintf(&buf, "%-*s  %s  %-6s  %s\n",
+			width, result.Key(), resultStatus(result), now.Sub(result.Timestamp).Round(time.Second), truncate(formatMetrics(result.Metrics), 80))
+	}
+
+	if len(s.Results) == 0 {
+		buf.WriteString("No results yet\n")
+	}
+	return buf.Bytes()
+}
+
+// resultStatus is a result's health padded to eight columns, colored by
+// severity
+func resultStatus(result collectors.Result) string {
+	if result.IsHealthy {
+		return ansiGreen + fmt.Sprintf("%-8s", "ok") + ansiReset
+	}
+	severity := result.EffectiveSeverity()
+	color := ansiYellow
+	if severity == collectors.SeverityCritical {
+		color = ansiRed
+	}
+	return color + fmt.Sprintf("%-8s", severity) + ansiReset
+}
+
+// formatMetrics lists metrics as name=value, by name
+func formatMetrics(metrics map[string]float64) string {
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=%.2f", name, metrics[name]))
+	}
+	return strings.Join(pairs, " ")
+}
+
+// truncate shortens s to at most n characters
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-1] + "…"
+}
@@ -0,0 +1,42 @@
+// validate.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/devvspaces/simple-monit/monitor"
+
+	"go.uber.org/zap"
+)
+
+// runValidate implements the "validate" subcommand. It loads the
+// configuration and checks every enabled component against this binary
+// without starting anything, e.g. before deploying a change.
+func runValidate(args []string) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	configPath := fs.String("config", "config.yaml", "Path to configuration file")
+	configDir := fs.String("config-dir", "", "Directory of YAML fragments merged into the configuration")
+	profile := fs.String("profile", os.Getenv(envPrefix+"PROFILE"), "Profile whose overlay, e.g. config.prod.yaml, is merged over the configuration file; also "+envPrefix+"PROFILE")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := loadConfigFiles(zap.NewNop(), *configPath, *profile, *configDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", *configPath, err)
+		return 1
+	}
+
+	if err := monitor.New(cfg).Validate(); err != nil {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", *configPath, line)
+		}
+		return 1
+	}
+
+	fmt.Printf("%s is valid\n", *configPath)
+	return 0
+}
@@ -0,0 +1,156 @@
+// watch.go
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/devvspaces/simple-monit/config"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// watchDebounce is how long the configuration must stay unchanged before a
+// change is reported, so editors writing in several steps reload once
+const watchDebounce = 500 * time.Millisecond
+
+// configWatcher reports changes to the contents of the configuration file
+// and of the fragments in the configuration directory
+type configWatcher struct {
+	path    string
+	overlay string // Of the profile, if any
+	dir     string
+	watcher *fsnotify.Watcher
+	changes chan struct{}
+	logger  *zap.Logger
+}
+
+// watchConfig starts watching the configuration file. The directory is
+// watched rather than the file, so files replaced by rename (editors) or
+// symlink swaps (Kubernetes ConfigMaps) keep being followed; a change is
+// only reported when the file's contents differ. With a configuration
+// directory, fragments being added, changed or removed are reported too, and
+// with a profile, changes of its overlay, which sits next to the file.
+func watchConfig(logger *zap.Logger, path, profile, dir string) (*configWatcher, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		logger.Error("Failed to create configuration watcher", zap.Error(err))
+		return nil, err
+	}
+	if err := watcher.Add(filepath.Dir(abs)); err != nil {
+		watcher.Close()
+		logger.Error("Failed to watch configuration directory", zap.String("path", abs), zap.Error(err))
+		return nil, err
+	}
+
+	w := &configWatcher{
+		path:    abs,
+		watcher: watcher,
+		changes: make(chan struct{}, 1),
+		logger:  logger,
+	}
+	if profile != "" {
+		w.overlay = config.ProfilePath(abs, profile)
+	}
+	if dir != "" {
+		if w.dir, err = filepath.Abs(dir); err != nil {
+			watcher.Close()
+			return nil, err
+		}
+		if err := watcher.Add(w.dir); err != nil {
+			watcher.Close()
+			logger.Error("Failed to watch configuration directory", zap.String("dir", w.dir), zap.Error(err))
+			return nil, err
+		}
+	}
+	go w.run()
+	logger.Info("Watching configuration for changes", zap.String("path", abs), zap.String("dir", w.dir))
+	return w, nil
+}
+
+// Changes receives a value after each change of the configuration's contents
+func (w *configWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+// Close stops watching
+func (w *configWatcher) Close() error {
+	return w.watcher.Close()
+}
+
+func (w *configWatcher) run() {
+	last := w.digest()
+	debounce := time.NewTimer(watchDebounce)
+	debounce.Stop()
+
+	for {
+		select {
+		case _, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			// Any event in the directory may be the file changing, e.g.
+			// a ConfigMap's ..data symlink being replaced
+			debounce.Reset(watchDebounce)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			w.logger.Warn("Configuration watcher error", zap.Error(err))
+		case <-debounce.C:
+			current := w.digest()
+			if current == nil || bytes.Equal(current, last) {
+				continue
+			}
+			last = current
+			select {
+			case w.changes <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// digest hashes the contents of the file and overlay and the fragments'
+// names and contents, or returns nil while they cannot be read, e.g.
+// mid-replacement. Without a directory the file must exist; with one it may
+// be absent.
+func (w *configWatcher) digest() []byte {
+	hash := sha256.New()
+	data, err := os.ReadFile(w.path)
+	if err != nil && (w.dir == "" || !os.IsNotExist(err)) {
+		return nil
+	}
+	hash.Write(data)
+	if w.overlay != "" {
+		data, err := os.ReadFile(w.overlay)
+		if err != nil {
+			return nil
+		}
+		hash.Write(data)
+	}
+
+	if w.dir != "" {
+		fragments, err := config.FragmentFiles(w.dir)
+		if err != nil {
+			return nil
+		}
+		for _, fragment := range fragments {
+			data, err := os.ReadFile(fragment)
+			if err != nil {
+				return nil
+			}
+			hash.Write([]byte(fragment))
+			hash.Write(data)
+		}
+	}
+	return hash.Sum(nil)
+}